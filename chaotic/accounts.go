@@ -0,0 +1,139 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AccountSpec is one account of a GenerateLedger run. The zero overrides
+// keep the settings of the shared configuration.
+type AccountSpec struct {
+	ID    string // account ID set on every entry of the account, unique within the run
+	Steps int    // number of entries generated for the account, positive
+
+	Volatility *float64      `json:",omitempty"` // overrides ChaoticConfig.Volatility
+	MinValue   *int          `json:",omitempty"` // overrides ChaoticConfig.MinValue
+	MaxValue   *int          `json:",omitempty"` // overrides ChaoticConfig.MaxValue
+	Interval   time.Duration `json:",omitempty"` // overrides Timing.Interval, changing the arrival rate
+}
+
+// LedgerResult is the merged ledger of several accounts generated by
+// GenerateLedger. It marshals like a run document without metadata, so it
+// can be written with WriteJSON and read back with LoadSequenceFromJSON,
+// and its Sequence can be written with SaveToCSV.
+type LedgerResult struct {
+	Statistics Statistics            `json:"statistics"` // over the whole ledger, with ByAccount filled in
+	Sequence   []LogEntry            `json:"sequence"`   // every account's entries, in timestamp order
+	Accounts   map[string]Statistics `json:"accounts"`   // over each account's own entries, keyed by account ID
+}
+
+// GenerateLedger generates a sequence for each account and merges them into
+// a single ledger ordered by timestamp, so config must have a Timing. Each
+// account runs its own chaotic process with config and the account's
+// overrides applied; with a Seed, account i is seeded with
+// PathSeed(*config.Seed, i), as path i of GenerateParallel would be, and an
+// explicit Source is drawn from by one account after the other.
+//
+// Every entry gets its account's ID as AccountID, replacing any account
+// pool of config.Entities. The merge is stable: entries with the same
+// timestamp keep the order of their accounts in accounts, and entries of
+// one account keep their order. Steps are then renumbered from 0 across the
+// ledger, while the per-account statistics are computed before the merge.
+// The merge needs every entry's timestamp and value, so the config's Faults
+// are not applied to the accounts.
+func GenerateLedger(accounts []AccountSpec, config ChaoticConfig) (LedgerResult, error) {
+	if len(accounts) == 0 {
+		return LedgerResult{}, errors.New("a ledger needs at least one account")
+	}
+	if config.Timing == nil {
+		return LedgerResult{}, fmt.Errorf("%w: the accounts are merged by timestamp, so the config needs a Timing", ErrInvalidTiming)
+	}
+	seen := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		if account.ID == "" {
+			return LedgerResult{}, errors.New("every account needs an ID")
+		}
+		if seen[account.ID] {
+			return LedgerResult{}, fmt.Errorf("account ID %q is used twice", account.ID)
+		}
+		seen[account.ID] = true
+	}
+	config.Faults = nil
+
+	result := LedgerResult{Accounts: make(map[string]Statistics, len(accounts))}
+	var merged []ledgerEntry
+	for i, account := range accounts {
+		log, err := ChaoticTransactionSequence(account.Steps, account.config(config, i))
+		if err != nil {
+			return LedgerResult{}, fmt.Errorf("account %q: %w", account.ID, err)
+		}
+		for j := range log {
+			log[j].AccountID = account.ID
+		}
+		if result.Accounts[account.ID], err = ComputeStatistics(log); err != nil {
+			return LedgerResult{}, fmt.Errorf("account %q: %w", account.ID, err)
+		}
+
+		for _, entry := range log {
+			at, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+			if err != nil {
+				return LedgerResult{}, fmt.Errorf("account %q, step %d: invalid timestamp: %w", account.ID, entry.Step, err)
+			}
+			merged = append(merged, ledgerEntry{at, entry})
+		}
+	}
+
+	// Accounts were appended in order, so a stable sort keeps their order on ties
+	sort.SliceStable(merged, func(a, b int) bool { return merged[a].at.Before(merged[b].at) })
+	result.Sequence = make([]LogEntry, len(merged))
+	for i, m := range merged {
+		result.Sequence[i] = m.entry
+		result.Sequence[i].Step = i
+	}
+
+	var err error
+	if result.Statistics, err = ComputeStatistics(result.Sequence); err != nil {
+		return LedgerResult{}, err
+	}
+	result.Statistics.ByAccount = CountByAccount(result.Sequence)
+	return result, nil
+}
+
+// ledgerEntry is an entry waiting to be merged, with its parsed timestamp
+type ledgerEntry struct {
+	at    time.Time
+	entry LogEntry
+}
+
+// config returns the configuration of account number i of a ledger
+// generated with shared
+func (a AccountSpec) config(shared ChaoticConfig, i int) ChaoticConfig {
+	config := shared
+	if a.Volatility != nil {
+		config.Volatility = *a.Volatility
+	}
+	if a.MinValue != nil {
+		config.MinValue = *a.MinValue
+	}
+	if a.MaxValue != nil {
+		config.MaxValue = *a.MaxValue
+	}
+	if a.Interval != 0 {
+		timing := *shared.Timing
+		timing.Interval = a.Interval
+		config.Timing = &timing
+	}
+	if shared.Entities != nil {
+		// The account ID is set from the spec, so no pool is drawn from
+		entities := *shared.Entities
+		entities.Accounts = 0
+		config.Entities = &entities
+	}
+	if shared.Seed != nil {
+		seed := PathSeed(*shared.Seed, i)
+		config.Seed = &seed
+	}
+	return config
+}
@@ -0,0 +1,82 @@
+package chaotic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// ledgerConfig returns a seeded config with a fixed gap of a minute from
+// the start of 2024
+func ledgerConfig(seed int64) ChaoticConfig {
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.Timing = &Timing{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Interval: time.Minute}
+	return config
+}
+
+func TestLedgerMergeIsStable(t *testing.T) {
+	config := ledgerConfig(45)
+	accounts := []AccountSpec{
+		{ID: "b", Steps: 5},
+		{ID: "a", Steps: 5},
+		{ID: "c", Steps: 3, Interval: 2 * time.Minute},
+	}
+	result, err := GenerateLedger(accounts, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every minute b and a tie, and every other minute c ties with them;
+	// ties keep the order of the accounts, not of their IDs
+	want := []string{"b0", "a0", "c0", "b1", "a1", "b2", "a2", "c1", "b3", "a3", "b4", "a4", "c2"}
+	runs := make(map[string][]LogEntry)
+	for i, account := range accounts {
+		log, err := ChaoticTransactionSequence(account.Steps, account.config(config, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runs[account.ID] = log
+		stats, err := ComputeStatistics(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(result.Accounts[account.ID], stats) {
+			t.Errorf("account %q: statistics differ from those of its own run", account.ID)
+		}
+	}
+	if len(result.Sequence) != len(want) {
+		t.Fatalf("%d entries in the ledger, want %d", len(result.Sequence), len(want))
+	}
+	for i, entry := range result.Sequence {
+		var id string
+		var step int
+		fmt.Sscanf(want[i], "%1s%d", &id, &step)
+		original := runs[id][step]
+		if entry.Step != i || entry.AccountID != id || entry.Value != original.Value || entry.Timestamp != original.Timestamp {
+			t.Errorf("ledger entry %d is step %d of %q with value %d at %s, want %s with value %d at %s",
+				i, entry.Step, entry.AccountID, entry.Value, entry.Timestamp, want[i], original.Value, original.Timestamp)
+		}
+	}
+	if got := result.Statistics.ByAccount; got["a"] != 5 || got["b"] != 5 || got["c"] != 3 {
+		t.Errorf("entries by account %v", got)
+	}
+}
+
+func TestLedgerIgnoresFaults(t *testing.T) {
+	accounts := []AccountSpec{{ID: "a", Steps: 200}, {ID: "b", Steps: 200}}
+	config := ledgerConfig(46)
+	plain, err := GenerateLedger(accounts, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Faults = &DeliveryFaults{DropRate: 0.2, DuplicateRate: 0.2}
+	faulty, err := GenerateLedger(accounts, config)
+	if err != nil {
+		t.Fatalf("a ledger with faults failed: %v", err)
+	}
+	if !reflect.DeepEqual(faulty, plain) {
+		t.Error("the faults changed the ledger")
+	}
+}
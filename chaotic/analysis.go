@@ -0,0 +1,419 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrConstantSequence is returned by analyses that are undefined when every
+// value in the sequence is the same
+var ErrConstantSequence = errors.New("sequence has zero variance")
+
+// ComputeACF returns the sample autocorrelation of values at lags 1 through
+// maxLag (element i holds lag i+1). Each lag uses the overall mean and
+// variance, the standard biased estimator. A constant sequence has no
+// defined autocorrelation and returns ErrConstantSequence.
+func ComputeACF(values []int, maxLag int) ([]float64, error) {
+	if maxLag < 1 || maxLag >= len(values) {
+		return nil, fmt.Errorf("maxLag must be between 1 and len(values)-1, got %d for %d values", maxLag, len(values))
+	}
+
+	mean := calculateMean(values)
+	var den float64
+	for _, v := range values {
+		diff := float64(v) - mean
+		den += diff * diff
+	}
+	if den == 0 {
+		return nil, ErrConstantSequence
+	}
+
+	acf := make([]float64, maxLag)
+	for lag := 1; lag <= maxLag; lag++ {
+		var num float64
+		for i := lag; i < len(values); i++ {
+			num += (float64(values[i]) - mean) * (float64(values[i-lag]) - mean)
+		}
+		acf[lag-1] = num / den
+	}
+	return acf, nil
+}
+
+// ComputePACF returns the sample partial autocorrelation of values at lags 1
+// through maxLag (element i holds lag i+1), computed from the ACF with the
+// Durbin-Levinson recursion. It fails like ComputeACF on bad lags and
+// constant sequences, and when the recursion degenerates because the series
+// is perfectly predictable from its earlier lags.
+func ComputePACF(values []int, maxLag int) ([]float64, error) {
+	acf, err := ComputeACF(values, maxLag)
+	if err != nil {
+		return nil, err
+	}
+	// r(k) is the autocorrelation at lag k
+	r := func(k int) float64 { return acf[k-1] }
+
+	pacf := make([]float64, maxLag)
+	phi := make([]float64, maxLag+1) // phi[j] holds phi(k-1, j) at the start of lag k
+	next := make([]float64, maxLag+1)
+	for k := 1; k <= maxLag; k++ {
+		num, den := r(k), 1.0
+		for j := 1; j < k; j++ {
+			num -= phi[j] * r(k-j)
+			den -= phi[j] * r(j)
+		}
+		if den == 0 {
+			return nil, fmt.Errorf("partial autocorrelation is undefined beyond lag %d", k-1)
+		}
+
+		phiKK := num / den
+		for j := 1; j < k; j++ {
+			next[j] = phi[j] - phiKK*phi[k-j]
+		}
+		next[k] = phiKK
+		phi, next = next, phi
+		pacf[k-1] = phiKK
+	}
+	return pacf, nil
+}
+
+// minHurstLength is the shortest sequence EstimateHurst accepts; below it
+// too few window sizes fit for the fitted slope to mean anything
+const minHurstLength = 64
+
+// minHurstWindow is the smallest window used in rescaled-range analysis
+const minHurstWindow = 8
+
+// EstimateHurst estimates the Hurst exponent of the path traced by values
+// using rescaled-range (R/S) analysis of its step-to-step changes. About 0.5
+// means the changes are uncorrelated, as in a random walk; higher values
+// indicate persistent trends and lower values mean reversion. Sequences
+// shorter than 64 values return an error, as do paths whose changes never
+// vary.
+func EstimateHurst(values []int) (float64, error) {
+	if len(values) < minHurstLength {
+		return 0, fmt.Errorf("need at least %d values to estimate the Hurst exponent, got %d", minHurstLength, len(values))
+	}
+
+	changes := make([]float64, len(values)-1)
+	for i := range changes {
+		changes[i] = float64(values[i+1]) - float64(values[i])
+	}
+
+	var logSizes, logRS []float64
+	for size := minHurstWindow; size <= len(changes); size *= 2 {
+		if rs, ok := meanRescaledRange(changes, size); ok {
+			logSizes = append(logSizes, math.Log(float64(size)))
+			logRS = append(logRS, math.Log(rs))
+		}
+	}
+	if len(logSizes) < 2 {
+		return 0, ErrConstantSequence
+	}
+	return slope(logSizes, logRS), nil
+}
+
+// meanRescaledRange averages R/S over the non-overlapping windows of the
+// given size, skipping windows with no variation. ok is false when every
+// window was skipped.
+func meanRescaledRange(series []float64, size int) (rs float64, ok bool) {
+	var sum float64
+	count := 0
+	for start := 0; start+size <= len(series); start += size {
+		window := series[start : start+size]
+
+		var mean float64
+		for _, x := range window {
+			mean += x
+		}
+		mean /= float64(size)
+
+		var cumulative, lowest, highest, sumSq float64
+		for _, x := range window {
+			diff := x - mean
+			cumulative += diff
+			lowest = math.Min(lowest, cumulative)
+			highest = math.Max(highest, cumulative)
+			sumSq += diff * diff
+		}
+		stdev := math.Sqrt(sumSq / float64(size))
+		if stdev == 0 {
+			continue
+		}
+		sum += (highest - lowest) / stdev
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// slope fits y = a + b*x by least squares and returns b
+func slope(x, y []float64) float64 {
+	n := float64(len(x))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
+
+// SampleEntropy computes the sample entropy (SampEn) of values: the negative
+// log of the chance that runs matching for m points, within tolerance r,
+// still match at point m+1. Regular sequences score near 0 and irregular
+// ones higher. When no runs match, the logarithm is undefined and the result
+// is the largest value measurable at this length, as if one pair had
+// matched. It returns 0 when m < 1 or there are not enough values for two
+// runs of length m+1.
+func SampleEntropy(values []int, m int, r float64) float64 {
+	templates := len(values) - m
+	if m < 1 || templates < 2 {
+		return 0
+	}
+
+	var matchesM, matchesM1 int
+	for i := 0; i < templates; i++ {
+		for j := i + 1; j < templates; j++ {
+			if !withinTolerance(values[i:i+m], values[j:j+m], r) {
+				continue
+			}
+			matchesM++
+			if math.Abs(float64(values[i+m])-float64(values[j+m])) <= r {
+				matchesM1++
+			}
+		}
+	}
+	if matchesM == 0 || matchesM1 == 0 {
+		pairs := float64(templates) * float64(templates-1) / 2
+		return math.Log(pairs)
+	}
+	return math.Log(float64(matchesM) / float64(matchesM1))
+}
+
+// withinTolerance reports whether every pair of points in a and b differs by at most r
+func withinTolerance(a, b []int, r float64) bool {
+	for k := range a {
+		if math.Abs(float64(a[k])-float64(b[k])) > r {
+			return false
+		}
+	}
+	return true
+}
+
+// maxPermutationOrder bounds the embedding order so the pattern table stays small
+const maxPermutationOrder = 8
+
+// PermutationEntropy computes the normalized Shannon entropy of the ordinal
+// patterns formed by each run of order consecutive values: 0 for a monotone
+// sequence, approaching 1 when every pattern is equally likely. Equal values
+// rank by position, earlier first. Orders 3 to 5 are typical; order must be
+// between 2 and 8 and no longer than the sequence.
+func PermutationEntropy(values []int, order int) (float64, error) {
+	if order < 2 || order > maxPermutationOrder {
+		return 0, fmt.Errorf("order must be between 2 and %d, got %d", maxPermutationOrder, order)
+	}
+	if len(values) < order {
+		return 0, fmt.Errorf("need at least %d values for order %d, got %d", order, order, len(values))
+	}
+
+	factorials := make([]int, order+1)
+	factorials[0] = 1
+	for i := 1; i <= order; i++ {
+		factorials[i] = factorials[i-1] * i
+	}
+
+	// Each pattern is identified by its Lehmer code: for each position, how
+	// many later values in the window rank below it
+	counts := make([]int, factorials[order])
+	windows := len(values) - order + 1
+	for start := 0; start < windows; start++ {
+		window := values[start : start+order]
+		code := 0
+		for i := range window {
+			smaller := 0
+			for j := i + 1; j < order; j++ {
+				if window[j] < window[i] {
+					smaller++
+				}
+			}
+			code += smaller * factorials[order-1-i]
+		}
+		counts[code]++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(windows)
+			entropy -= p * math.Log(p)
+		}
+	}
+	return entropy / math.Log(float64(factorials[order])), nil
+}
+
+// minLyapunovLength is the shortest sequence EstimateLyapunov accepts
+const minLyapunovLength = 200
+
+// LyapunovOptions tunes EstimateLyapunov. Zero fields take the defaults
+// noted on each.
+type LyapunovOptions struct {
+	Dimension  int // embedding dimension (default 3)
+	Delay      int // embedding delay in steps (default 1)
+	Separation int // minimum step distance between neighbours (default Dimension*Delay)
+	FitSteps   int // divergence steps fitted for the slope (default 5)
+}
+
+// withDefaults fills unset options with their defaults
+func (o LyapunovOptions) withDefaults() LyapunovOptions {
+	if o.Dimension == 0 {
+		o.Dimension = 3
+	}
+	if o.Delay == 0 {
+		o.Delay = 1
+	}
+	if o.Separation == 0 {
+		o.Separation = o.Dimension * o.Delay
+	}
+	if o.FitSteps == 0 {
+		o.FitSteps = 5
+	}
+	return o
+}
+
+// EstimateLyapunov estimates the largest Lyapunov exponent of values, per
+// step, with Rosenstein's method: the values are delay-embedded, each point
+// is paired with its nearest neighbour at least Separation steps away, and
+// the exponent is the slope of the mean log distance between the pairs over
+// the following FitSteps steps. A positive exponent means nearby states
+// diverge exponentially, the signature of chaos.
+//
+// The estimate needs the early, exponential part of the divergence: on short
+// or coarsely quantized sequences neighbours are far apart and saturate
+// quickly, biasing the slope low. Sequences shorter than 200 values return
+// an error. Finding neighbours is quadratic in the sequence length.
+func EstimateLyapunov(values []int, opts LyapunovOptions) (float64, error) {
+	opts = opts.withDefaults()
+	if opts.Dimension < 1 || opts.Delay < 1 || opts.Separation < 0 || opts.FitSteps < 1 {
+		return 0, fmt.Errorf("invalid Lyapunov options %+v", opts)
+	}
+	if len(values) < minLyapunovLength {
+		return 0, fmt.Errorf("need at least %d values to estimate the Lyapunov exponent, got %d", minLyapunovLength, len(values))
+	}
+
+	points := len(values) - (opts.Dimension-1)*opts.Delay
+	if points <= opts.FitSteps+opts.Separation+1 {
+		return 0, fmt.Errorf("sequence too short for embedding options %+v", opts)
+	}
+	distance := func(i, j int) float64 {
+		var sum float64
+		for d := 0; d < opts.Dimension; d++ {
+			diff := float64(values[i+d*opts.Delay]) - float64(values[j+d*opts.Delay])
+			sum += diff * diff
+		}
+		return math.Sqrt(sum)
+	}
+
+	// Nearest neighbour of each point that leaves room to follow the pair
+	// for FitSteps steps
+	usable := points - opts.FitSteps
+	neighbours := make([]int, usable)
+	for i := 0; i < usable; i++ {
+		neighbours[i] = -1
+		best := math.Inf(1)
+		for j := 0; j < usable; j++ {
+			if abs(i-j) <= opts.Separation {
+				continue
+			}
+			if d := distance(i, j); d > 0 && d < best {
+				best, neighbours[i] = d, j
+			}
+		}
+	}
+
+	steps := make([]float64, 0, opts.FitSteps+1)
+	divergence := make([]float64, 0, opts.FitSteps+1)
+	for k := 0; k <= opts.FitSteps; k++ {
+		var sum float64
+		count := 0
+		for i, j := range neighbours {
+			if j < 0 {
+				continue
+			}
+			if d := distance(i+k, j+k); d > 0 {
+				sum += math.Log(d)
+				count++
+			}
+		}
+		if count > 0 {
+			steps = append(steps, float64(k))
+			divergence = append(divergence, sum/float64(count))
+		}
+	}
+	if len(steps) < 2 {
+		return 0, ErrConstantSequence
+	}
+	return slope(steps, divergence), nil
+}
+
+// abs returns the absolute value of an int
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// sturgesBins is Sturges' rule for the number of histogram bins for n values
+func sturgesBins(n int) int {
+	return int(math.Ceil(math.Log2(float64(n)))) + 1
+}
+
+// DistributionEntropy measures how evenly values spread over [lo, hi]: the
+// Shannon entropy of their histogram, normalized so values piled into one
+// bin score 0 and a perfectly flat histogram scores 1. bins of 0 uses
+// Sturges' rule. Bins split the integers in the range evenly, and no more
+// bins are used than there are integers to fill them, so a range of one
+// value always scores 0. Pass a config's MinValue and MaxValue to measure
+// spread over the allowed range rather than the observed one.
+func DistributionEntropy(values []int, bins, lo, hi int) (float64, error) {
+	if len(values) == 0 {
+		return 0, errors.New("empty sequence")
+	}
+	if bins < 0 {
+		return 0, fmt.Errorf("bins must not be negative, got %d", bins)
+	}
+	if lo > hi {
+		return 0, fmt.Errorf("%w: lo %d must not exceed hi %d", ErrInvalidRange, lo, hi)
+	}
+	if bins == 0 {
+		bins = sturgesBins(len(values))
+	}
+	width := float64(hi) - float64(lo) + 1
+	bins = int(math.Min(float64(bins), width))
+	if bins <= 1 {
+		return 0, nil
+	}
+
+	counts := make([]int, bins)
+	for _, v := range values {
+		if v < lo || v > hi {
+			return 0, fmt.Errorf("value %d is outside [%d, %d]", v, lo, hi)
+		}
+		// Rounding can push the largest values of a huge range one bin too far
+		bin := min(int((float64(v)-float64(lo))*float64(bins)/width), bins-1)
+		counts[bin]++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(len(values))
+			entropy -= p * math.Log(p)
+		}
+	}
+	return entropy / math.Log(float64(bins)), nil
+}
@@ -0,0 +1,357 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ar1 returns n values of x(t) = phi·x(t-1) + e(t) with standard normal
+// noise, scaled by 1000 and rounded so the ints keep the correlation
+func ar1(n int, phi float64, seed int64) []int {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]int, n)
+	var x float64
+	for i := range values {
+		x = phi*x + rng.NormFloat64()
+		values[i] = int(math.Round(1000 * x))
+	}
+	return values
+}
+
+func TestComputeACFOfAR1(t *testing.T) {
+	// An AR(1) process has autocorrelation phi^k at lag k
+	const phi = 0.7
+	values := ar1(20000, phi, 1)
+	acf, err := ComputeACF(values, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 1; k <= len(acf); k++ {
+		if want := math.Pow(phi, float64(k)); !closeTo(acf[k-1], want, 0.03) {
+			t.Errorf("lag %d: autocorrelation %.4f, want about %.4f", k, acf[k-1], want)
+		}
+	}
+
+	stats, err := ComputeStatistics(entriesOf(values...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(stats.Lag1Autocorrelation, acf[0], 1e-12) {
+		t.Errorf("lag1_autocorrelation %v differs from ComputeACF's %v", stats.Lag1Autocorrelation, acf[0])
+	}
+}
+
+func TestComputeACFErrors(t *testing.T) {
+	if _, err := ComputeACF([]int{4, 4, 4, 4}, 2); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence returned %v, want ErrConstantSequence", err)
+	}
+	for _, maxLag := range []int{0, 4, 5} {
+		if _, err := ComputeACF([]int{1, 2, 3, 4}, maxLag); err == nil {
+			t.Errorf("maxLag %d for 4 values succeeded", maxLag)
+		}
+	}
+}
+
+func TestComputePACFOfWhiteNoise(t *testing.T) {
+	values := ar1(10000, 0, 2)
+	pacf, err := ComputePACF(values, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// About four standard errors of 1/√n
+	bound := 4 / math.Sqrt(float64(len(values)))
+	for k, p := range pacf {
+		if math.Abs(p) > bound {
+			t.Errorf("lag %d: partial autocorrelation %.4f of white noise exceeds %.4f", k+1, p, bound)
+		}
+	}
+}
+
+func TestComputePACFOfTrendingSequence(t *testing.T) {
+	// A steady climb with noise is almost fully explained by its last value,
+	// so lag 1 is near 1 and the later lags add little
+	rng := rand.New(rand.NewSource(3))
+	values := make([]int, 2000)
+	for i := range values {
+		values[i] = 10*i + rng.Intn(50)
+	}
+	pacf, err := ComputePACF(values, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pacf[0] < 0.95 {
+		t.Errorf("lag 1: partial autocorrelation %.4f of a trend, want near 1", pacf[0])
+	}
+	for k := 1; k < len(pacf); k++ {
+		if math.Abs(pacf[k]) >= pacf[0]/2 {
+			t.Errorf("lag %d: partial autocorrelation %.4f rivals lag 1", k+1, pacf[k])
+		}
+	}
+
+	// On AR(1) it cuts off after lag 1 at phi
+	ar, err := ComputePACF(ar1(20000, 0.6, 4), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(ar[0], 0.6, 0.03) {
+		t.Errorf("AR(1) lag 1: %.4f, want about 0.6", ar[0])
+	}
+	for k := 1; k < len(ar); k++ {
+		if math.Abs(ar[k]) > 0.03 {
+			t.Errorf("AR(1) lag %d: %.4f, want about 0", k+1, ar[k])
+		}
+	}
+}
+
+func TestComputePACFErrors(t *testing.T) {
+	if _, err := ComputePACF([]int{3, 3, 3, 3, 3}, 2); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence returned %v, want ErrConstantSequence", err)
+	}
+	if _, err := ComputePACF([]int{1, 5, 2, 8}, 4); err == nil {
+		t.Error("maxLag equal to the length succeeded")
+	}
+}
+
+// randomWalk returns n positions of a walk with uniform steps in [-100, 100]
+func randomWalk(n int, seed int64) []int {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]int, n)
+	x := 0
+	for i := range values {
+		x += rng.Intn(201) - 100
+		values[i] = x
+	}
+	return values
+}
+
+func TestEstimateHurstOfRandomWalk(t *testing.T) {
+	// R/S reads a little high on small windows, so allow 0.1
+	for seed := int64(1); seed <= 5; seed++ {
+		h, err := EstimateHurst(randomWalk(10000, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeTo(h, 0.5, 0.1) {
+			t.Errorf("seed %d: Hurst exponent %.3f of a random walk, want about 0.5", seed, h)
+		}
+	}
+}
+
+func TestEstimateHurstErrors(t *testing.T) {
+	if _, err := EstimateHurst(randomWalk(63, 1)); err == nil {
+		t.Error("63 values succeeded")
+	}
+	if _, err := EstimateHurst(randomWalk(64, 1)); err != nil {
+		t.Errorf("64 values: %v", err)
+	}
+	// A steady ramp has changes that never vary
+	ramp := make([]int, 100)
+	for i := range ramp {
+		ramp[i] = 3 * i
+	}
+	if _, err := EstimateHurst(ramp); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("ramp returned %v, want ErrConstantSequence", err)
+	}
+}
+
+func TestSampleEntropyOfPeriodicAndGeneratedSequences(t *testing.T) {
+	pattern := []int{10, 40, 25, 70, 55, 90, 15, 60}
+	periodic := make([]int, 1000)
+	for i := range periodic {
+		periodic[i] = pattern[i%len(pattern)]
+	}
+	periodicStats, err := ComputeStatistics(entriesOf(periodic...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if periodicStats.SampleEntropy > 0.01 {
+		t.Errorf("sample entropy %.4f of a periodic sequence, want near 0", periodicStats.SampleEntropy)
+	}
+
+	seed := int64(5)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(1000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clamping at the bounds makes the default config fairly regular, but
+	// still far from periodic
+	if stats.SampleEntropy < 0.2 {
+		t.Errorf("sample entropy %.4f of generator output, want at least 0.2", stats.SampleEntropy)
+	}
+}
+
+func TestSampleEntropyWithoutMatches(t *testing.T) {
+	// Strictly increasing values never match within 0, so the result is
+	// capped at the log of the number of template pairs: 4 templates, 6 pairs
+	got := SampleEntropy([]int{1, 2, 3, 4, 5, 6}, 2, 0)
+	if math.IsInf(got, 0) || math.IsNaN(got) || !closeTo(got, math.Log(6), 1e-12) {
+		t.Errorf("sample entropy %v without matches, want ln 6", got)
+	}
+	if got := SampleEntropy([]int{1, 2, 3}, 2, 1); got != 0 {
+		t.Errorf("sample entropy %v of too few values, want 0", got)
+	}
+}
+
+func TestPermutationEntropy(t *testing.T) {
+	ramp := make([]int, 1000)
+	for i := range ramp {
+		ramp[i] = i
+	}
+	falling := make([]int, len(ramp))
+	for i := range falling {
+		falling[i] = -i
+	}
+	shuffled := append([]int(nil), ramp...)
+	rand.New(rand.NewSource(6)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	for order := 3; order <= 5; order++ {
+		for name, values := range map[string][]int{"rising": ramp, "falling": falling} {
+			if got, err := PermutationEntropy(values, order); err != nil || got != 0 {
+				t.Errorf("order %d, %s: entropy %v, %v; want 0", order, name, got, err)
+			}
+		}
+		got, err := PermutationEntropy(shuffled, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got < 0.95 || got > 1 {
+			t.Errorf("order %d, shuffled: entropy %.4f, want near 1", order, got)
+		}
+	}
+
+	// Ties rank by position, so a constant sequence is one pattern
+	if got, err := PermutationEntropy([]int{7, 7, 7, 7, 7}, 3); err != nil || got != 0 {
+		t.Errorf("constant sequence: entropy %v, %v; want 0", got, err)
+	}
+}
+
+func TestPermutationEntropyErrors(t *testing.T) {
+	if _, err := PermutationEntropy([]int{1, 2}, 3); err == nil {
+		t.Error("a sequence shorter than the order succeeded")
+	}
+	if _, err := PermutationEntropy([]int{1, 3, 2}, 3); err != nil {
+		t.Errorf("a sequence as long as the order: %v", err)
+	}
+	for _, order := range []int{1, 9} {
+		if _, err := PermutationEntropy([]int{5, 1, 4, 2, 8, 3, 9, 6, 7, 0}, order); err == nil {
+			t.Errorf("order %d succeeded", order)
+		}
+	}
+}
+
+// logisticMap returns n iterates of x ← 4x(1-x) from x0, scaled to ints in
+// [0, 1e9]
+func logisticMap(n int, x0 float64) []int {
+	values := make([]int, n)
+	x := x0
+	for i := range values {
+		x = 4 * x * (1 - x)
+		values[i] = int(x * 1e9)
+	}
+	return values
+}
+
+func TestEstimateLyapunovOfLogisticMap(t *testing.T) {
+	// The logistic map at r = 4 has exponent ln 2 per step
+	for _, opts := range []LyapunovOptions{{}, {Dimension: 1}, {FitSteps: 3}} {
+		got, err := EstimateLyapunov(logisticMap(1000, 0.3), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeTo(got, math.Ln2, 0.03) {
+			t.Errorf("options %+v: exponent %.4f, want about ln 2 = %.4f", opts, got, math.Ln2)
+		}
+	}
+}
+
+func TestEstimateLyapunovErrors(t *testing.T) {
+	if _, err := EstimateLyapunov(logisticMap(199, 0.3), LyapunovOptions{}); err == nil {
+		t.Error("199 values succeeded")
+	}
+	if _, err := EstimateLyapunov(logisticMap(200, 0.3), LyapunovOptions{}); err != nil {
+		t.Errorf("200 values: %v", err)
+	}
+	for _, opts := range []LyapunovOptions{{Dimension: -1}, {Delay: -2}, {FitSteps: -1}, {Dimension: 100, Delay: 2}} {
+		if _, err := EstimateLyapunov(logisticMap(200, 0.3), opts); err == nil {
+			t.Errorf("options %+v succeeded", opts)
+		}
+	}
+}
+
+func TestDistributionEntropy(t *testing.T) {
+	uniform := make([]int, 5000)
+	rng := rand.New(rand.NewSource(7))
+	for i := range uniform {
+		uniform[i] = rng.Intn(1000)
+	}
+	got, err := DistributionEntropy(uniform, 0, 0, 999)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got < 0.98 || got > 1 {
+		t.Errorf("uniform values: entropy %.4f, want near 1", got)
+	}
+
+	// Nine in ten values pinned at the bounds
+	clamped := make([]int, 5000)
+	for i := range clamped {
+		switch {
+		case i%10 < 6:
+			clamped[i] = 999
+		case i%10 < 9:
+			clamped[i] = 0
+		default:
+			clamped[i] = rng.Intn(1000)
+		}
+	}
+	low, err := DistributionEntropy(clamped, 0, 0, 999)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if low > 0.5 {
+		t.Errorf("clamped values: entropy %.4f, want well below uniform", low)
+	}
+
+	// Most bins are empty here and must add nothing
+	if got, err := DistributionEntropy([]int{0, 999}, 10, 0, 999); err != nil || !closeTo(got, math.Log(2)/math.Log(10), 1e-12) {
+		t.Errorf("two values in two of ten bins: entropy %v, %v", got, err)
+	}
+}
+
+func TestDistributionEntropyOfConstantSequence(t *testing.T) {
+	if got, err := DistributionEntropy([]int{42, 42, 42}, 0, 0, 100); err != nil || got != 0 {
+		t.Errorf("constant values over a range: entropy %v, %v; want exactly 0", got, err)
+	}
+	stats, err := ComputeStatistics(entriesOf(42, 42, 42, 42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.DistributionEntropy != 0 {
+		t.Errorf("distribution_entropy %v of a constant sequence, want exactly 0", stats.DistributionEntropy)
+	}
+}
+
+func TestDistributionEntropyErrors(t *testing.T) {
+	if _, err := DistributionEntropy(nil, 0, 0, 10); err == nil {
+		t.Error("empty sequence succeeded")
+	}
+	if _, err := DistributionEntropy([]int{1}, -1, 0, 10); err == nil {
+		t.Error("negative bins succeeded")
+	}
+	if _, err := DistributionEntropy([]int{1}, 0, 10, 0); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("lo above hi returned %v, want ErrInvalidRange", err)
+	}
+	if _, err := DistributionEntropy([]int{1, 2, 3, 11}, 0, 0, 10); err == nil {
+		t.Error("a value outside the range succeeded")
+	}
+}
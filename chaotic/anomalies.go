@@ -0,0 +1,176 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// AnomalyConfig injects labelled anomalies into the values, giving anomaly
+// detectors a sequence with known ground truth. Each step starts an anomaly
+// with Rate: a spike displaces the value of that step alone, and a level
+// shift, a LevelShifts share of the anomalies, displaces the values of
+// Duration steps from it, unless a later level shift takes over; a spike
+// within a shift replaces it for its step. An anomaly moves the value up or
+// down with equal chance, by Delta when that is set and otherwise by
+// multiplying or dividing it by Multiplier, which leaves a value of 0 where
+// it is, and the result is brought into the range by the BoundaryMode.
+// Anomalous entries are marked Anomaly with their AnomalyKind.
+//
+// The anomalies are laid over the process rather than fed into it: the next
+// steps build on the value before the anomaly, as an OnStep hook sees it,
+// while the currency, timestamp, entity and ledger layers see the anomalous
+// value. As with Jumps, the placements are not drawn from the value source:
+// with a Seed they are derived from the seed, so a seeded run reproduces
+// them, and otherwise they come from a source of the configured
+// RandomnessMode.
+type AnomalyConfig struct {
+	Rate        float64 // 0.0 to 1.0 - chance that a step starts an anomaly, e.g. 0.01
+	Multiplier  float64 `json:",omitempty"` // above 1 - the factor an anomaly scales the value by, e.g. 3; 0 with Delta
+	Delta       int     `json:",omitempty"` // positive - the amount an anomaly adds or takes; 0 with Multiplier
+	Duration    int     `json:",omitempty"` // positive with LevelShifts - steps a level shift lasts
+	LevelShifts float64 `json:",omitempty"` // 0.0 to 1.0 - share of the anomalies that are level shifts; the rest are spikes
+}
+
+// The kinds of anomaly, as recorded in LogEntry.AnomalyKind
+const (
+	AnomalySpike      = "spike"
+	AnomalyLevelShift = "level_shift"
+)
+
+// Validate reports whether the anomalies are usable, returning an error
+// wrapping ErrInvalidAnomalies if not
+func (a AnomalyConfig) Validate() error {
+	if !(a.Rate >= 0 && a.Rate <= 1) {
+		return fmt.Errorf("%w: Rate %v must be between 0.0 and 1.0", ErrInvalidAnomalies, a.Rate)
+	}
+	switch {
+	case a.Delta < 0:
+		return fmt.Errorf("%w: Delta %d must not be negative", ErrInvalidAnomalies, a.Delta)
+	case a.Delta > 0 && a.Multiplier != 0:
+		return fmt.Errorf("%w: set either Multiplier or Delta, not both", ErrInvalidAnomalies)
+	case a.Delta == 0 && !(a.Multiplier > 1 && !math.IsInf(a.Multiplier, 1)):
+		return fmt.Errorf("%w: Multiplier %v must be finite and above 1, or Delta set instead", ErrInvalidAnomalies, a.Multiplier)
+	}
+	if !(a.LevelShifts >= 0 && a.LevelShifts <= 1) {
+		return fmt.Errorf("%w: LevelShifts %v must be between 0.0 and 1.0", ErrInvalidAnomalies, a.LevelShifts)
+	}
+	if a.Duration < 0 || a.LevelShifts > 0 && a.Duration < 1 {
+		return fmt.Errorf("%w: Duration %d must be positive for level shifts", ErrInvalidAnomalies, a.Duration)
+	}
+	return nil
+}
+
+// anomalyStream separates the anomaly draws from other uses of a seed
+const anomalyStream = 0x616e6f6d616c79 // "anomaly"
+
+// The forks of the anomaly draws deciding the kind and the direction of an
+// anomaly, apart from whether a step starts one
+const (
+	anomalyKindStream = 1
+	anomalySignStream = 2
+)
+
+// anomalyInjector places the anomalies of one run
+type anomalyInjector struct {
+	anomalies AnomalyConfig
+	draws     sideDraws // whether a step starts an anomaly
+	kinds     sideDraws // spike or level shift
+	signs     sideDraws // up or down
+	next      int       // the step the injector expects next
+	shiftEnd  int       // the step the level shift under way ends before
+	shiftUp   bool      // the direction of that shift
+}
+
+// newAnomalyInjector prepares the anomalies of a run with config, which must
+// have Anomalies
+func newAnomalyInjector(config ChaoticConfig) *anomalyInjector {
+	draws := newSideDraws(config, anomalyStream)
+	return &anomalyInjector{
+		anomalies: *config.Anomalies,
+		draws:     draws,
+		kinds:     draws.fork(anomalyKindStream),
+		signs:     draws.fork(anomalySignStream),
+	}
+}
+
+// anomaly returns the kind of anomaly at step, "" for none, and whether it
+// moves the value up. Steps are expected in order; with derived draws, a
+// run resumed or extended at a later step first recovers any level shift
+// still under way from the steps before it.
+func (a *anomalyInjector) anomaly(step int) (string, bool) {
+	if step != a.next && a.draws.derived() {
+		a.shiftEnd = 0
+		for i := max(step-a.anomalies.Duration+1, 0); i < step; i++ {
+			a.place(i)
+		}
+	}
+	return a.place(step)
+}
+
+// place draws whether step starts an anomaly and returns the anomaly at it
+func (a *anomalyInjector) place(step int) (string, bool) {
+	a.next = step + 1
+	if a.draws.uniform(step) < a.anomalies.Rate {
+		// Both draws are made for every anomaly, so an unseeded run
+		// consumes its source the same way whichever the kind
+		shift := a.kinds.uniform(step) < a.anomalies.LevelShifts
+		up := a.signs.uniform(step) < 0.5
+		if !shift {
+			return AnomalySpike, up
+		}
+		a.shiftEnd, a.shiftUp = step+a.anomalies.Duration, up
+		return AnomalyLevelShift, up
+	}
+	if step < a.shiftEnd {
+		return AnomalyLevelShift, a.shiftUp
+	}
+	return "", false
+}
+
+// displace returns value moved up or down by the magnitude of the anomalies
+func (a *anomalyInjector) displace(value int, up bool, currency *Currency) int {
+	switch {
+	case a.anomalies.Delta > 0 && up:
+		return addSat(value, a.anomalies.Delta)
+	case a.anomalies.Delta > 0:
+		return subSat(value, a.anomalies.Delta)
+	case up:
+		return roundValue(float64(value)*a.anomalies.Multiplier, currency)
+	}
+	return roundValue(float64(value)/a.anomalies.Multiplier, currency)
+}
+
+// AnomalySteps returns the steps of the entries of sequence marked Anomaly,
+// the ground truth of a run with AnomalyConfig, or nil if there are none
+func AnomalySteps(sequence []LogEntry) []int {
+	var steps []int
+	for _, entry := range sequence {
+		if entry.Anomaly {
+			steps = append(steps, entry.Step)
+		}
+	}
+	return steps
+}
+
+// ExcludeAnomalies returns the entries of sequence not marked Anomaly, for
+// computing statistics of the process without the injected anomalies. The
+// changes into the entries after a dropped one are then measured from the
+// last entry kept. sequence itself is returned when it has no anomalies.
+func ExcludeAnomalies(sequence []LogEntry) []LogEntry {
+	kept := 0
+	for _, entry := range sequence {
+		if !entry.Anomaly {
+			kept++
+		}
+	}
+	if kept == len(sequence) {
+		return sequence
+	}
+	clean := make([]LogEntry, 0, kept)
+	for _, entry := range sequence {
+		if !entry.Anomaly {
+			clean = append(clean, entry)
+		}
+	}
+	return clean
+}
@@ -0,0 +1,134 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// BoundaryMode selects how a value that leaves the range is brought back
+// into it
+type BoundaryMode string
+
+const (
+	// BoundaryClamp replaces the value with the nearest bound (the default).
+	// Volatile configs can stick at a bound for long stretches with it.
+	BoundaryClamp BoundaryMode = "clamp"
+	// BoundaryReflect bounces the excess back off the bound it crossed, as
+	// often as it takes to land inside the range
+	BoundaryReflect BoundaryMode = "reflect"
+	// BoundaryWrap carries the excess on from the other bound, so the range
+	// behaves like a circle
+	BoundaryWrap BoundaryMode = "wrap"
+	// BoundarySoft squashes values approaching either bound with a logistic
+	// curve: values within softMargin of the span from a bound are drawn
+	// towards it so that no value, however far out, passes it
+	BoundarySoft BoundaryMode = "soft"
+)
+
+// softMargin is the fraction of the span next to each bound over which
+// BoundarySoft squashes the values; those further in are left as they are
+const softMargin = 0.1
+
+// bound brings value into [lo, hi] as mode says, treating an empty mode as
+// BoundaryClamp. An empty interval, as the enhanced range of a negative
+// MaxValue can be, is always clamped as it has been, and so is one too wide
+// to measure in an int, as the enhanced range of a huge MaxValue can be.
+func bound(value, lo, hi int, mode BoundaryMode) int {
+	if hi < lo || hi-lo+1 <= 0 {
+		return clamp(value, lo, hi)
+	}
+	switch mode {
+	case BoundaryReflect:
+		span := hi - lo
+		if span == 0 {
+			return lo
+		}
+		// The distance from lo, mirrored at lo, then folded back and
+		// forth over the span
+		d := subSat(value, lo)
+		if d < 0 {
+			d = subSat(0, d)
+		}
+		if span <= math.MaxInt/2 {
+			d %= 2 * span
+		}
+		if d > span {
+			d = span - (d - span)
+		}
+		return lo + d
+
+	case BoundaryWrap:
+		width := hi - lo + 1
+		d := subSat(value, lo) % width
+		if d < 0 {
+			d += width
+		}
+		return lo + d
+
+	case BoundarySoft:
+		margin := softMargin * (float64(hi) - float64(lo))
+		inner, outer := float64(hi)-margin, float64(lo)+margin
+		switch x := float64(value); {
+		case margin == 0:
+		case x > inner:
+			// tanh is the logistic function rescaled to (-1, 1), leaving
+			// the slope 1 where the squashing starts
+			return clamp(toIntSat(math.Floor(inner+margin*math.Tanh((x-inner)/margin))), lo, hi)
+		case x < outer:
+			return clamp(toIntSat(math.Ceil(outer-margin*math.Tanh((outer-x)/margin))), lo, hi)
+		}
+	}
+	return clamp(value, lo, hi)
+}
+
+// clampSide returns the bound value left [lo, hi] by, ClampedLow or
+// ClampedHigh, or "" if it is within it
+func clampSide(value, lo, hi int) string {
+	switch {
+	case value < lo:
+		return ClampedLow
+	case value > hi:
+		return ClampedHigh
+	}
+	return ""
+}
+
+// countClamped returns the number of entries of sequence that left the range
+// by its low and by its high bound
+func countClamped(sequence []LogEntry) (int, int) {
+	low, high := 0, 0
+	for _, entry := range sequence {
+		switch entry.Clamped {
+		case ClampedLow:
+			low++
+		case ClampedHigh:
+			high++
+		}
+	}
+	return low, high
+}
+
+// ErrSaturated is wrapped by the warning a Generator gives through Warnings
+// when too many values left the range
+var ErrSaturated = errors.New("sequence saturated at the range bounds")
+
+// DefaultSaturationThreshold is the share of entries leaving the range above
+// which a Generator warns that the range, not the config, shapes the
+// distribution
+const DefaultSaturationThreshold = 0.2
+
+// saturationWarning returns an error wrapping ErrSaturated if more than
+// threshold of the entries of sequence left the range, and nil otherwise
+func saturationWarning(sequence []LogEntry, threshold float64) error {
+	if len(sequence) == 0 {
+		return nil
+	}
+	low, high := countClamped(sequence)
+	rate := float64(low+high) / float64(len(sequence))
+	if !(rate > threshold) {
+		return nil
+	}
+	return fmt.Errorf("%w: %.1f%% of the values left the range (%d below, %d above), more than %.1f%%",
+		ErrSaturated, 100*rate, low, high, 100*threshold)
+}
@@ -0,0 +1,212 @@
+package chaotic
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// boundaryHits returns the number of pairs of consecutive entries of
+// sequence that are both at a bound of [lo, hi], and the longest stretch of
+// entries at a bound
+func boundaryHits(sequence []LogEntry, lo, hi int) (int, int) {
+	pairs, longest, stretch := 0, 0, 0
+	for _, entry := range sequence {
+		if entry.Value != lo && entry.Value != hi {
+			stretch = 0
+			continue
+		}
+		if stretch > 0 {
+			pairs++
+		}
+		stretch++
+		longest = max(longest, stretch)
+	}
+	return pairs, longest
+}
+
+func TestBoundaryModesOnVolatileRun(t *testing.T) {
+	// At full volatility clamping holds about a quarter of the values at a
+	// bound, often for ten or more steps at a time
+	for seed := int64(1); seed <= 3; seed++ {
+		hits := make(map[BoundaryMode][2]int)
+		for _, mode := range []BoundaryMode{BoundaryClamp, BoundaryReflect, BoundaryWrap, BoundarySoft} {
+			config := DefaultConfig()
+			config.Seed = &seed
+			config.Volatility = 1
+			config.BoundaryMode = mode
+			sequence, err := ChaoticTransactionSequence(20_000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, entry := range sequence {
+				if entry.Value < config.MinValue || entry.Value > config.MaxValue {
+					t.Fatalf("seed %d, %s: step %d has value %d, outside the range", seed, mode, entry.Step, entry.Value)
+				}
+			}
+			pairs, longest := boundaryHits(sequence, config.MinValue, config.MaxValue)
+			hits[mode] = [2]int{pairs, longest}
+		}
+
+		clamped := hits[BoundaryClamp]
+		if clamped[0] < 1000 {
+			t.Fatalf("seed %d: only %d consecutive boundary hits when clamping", seed, clamped[0])
+		}
+		for _, mode := range []BoundaryMode{BoundaryReflect, BoundaryWrap, BoundarySoft} {
+			if got := hits[mode]; got[0] > clamped[0]/5 || got[1] >= clamped[1] {
+				t.Errorf("seed %d, %s: %d consecutive boundary hits, at most %d in a row; clamping gave %d and %d",
+					seed, mode, got[0], got[1], clamped[0], clamped[1])
+			}
+		}
+	}
+}
+
+func TestBound(t *testing.T) {
+	tests := []struct {
+		mode          BoundaryMode
+		value, lo, hi int
+		want          int
+	}{
+		{"", 1005, 0, 1000, 1000},
+		{BoundaryClamp, -5, 0, 1000, 0},
+		{BoundaryReflect, 1010, 0, 1000, 990},
+		{BoundaryReflect, -10, 0, 1000, 10},
+		{BoundaryReflect, 2010, 0, 1000, 10},
+		{BoundaryReflect, 500, 0, 1000, 500},
+		{BoundaryReflect, 7, 5, 5, 5},
+		{BoundaryWrap, 1005, 0, 999, 5},
+		{BoundaryWrap, -1, 0, 999, 999},
+		{BoundaryWrap, 2500, 0, 999, 500},
+		// Within 100 of a bound values are squashed towards it:
+		// 900 + 100·tanh(0.5) ≈ 946.2
+		{BoundarySoft, 950, 0, 1000, 946},
+		{BoundarySoft, 50, 0, 1000, 54},
+		{BoundarySoft, 5000, 0, 1000, 1000},
+		{BoundarySoft, 500, 0, 1000, 500},
+		// An empty interval is clamped whatever the mode
+		{BoundaryReflect, 50, 10, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := bound(tt.value, tt.lo, tt.hi, tt.mode); got != tt.want {
+			t.Errorf("bound(%d, %d, %d, %q) = %d, want %d", tt.value, tt.lo, tt.hi, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestBoundaryModeInExtendedRunAndMetadata(t *testing.T) {
+	for _, mode := range []BoundaryMode{BoundaryReflect, BoundaryWrap, BoundarySoft} {
+		seed := int64(40)
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.Volatility = 1
+		config.BoundaryMode = mode
+		doc := seededDocument(t, 5000, config)
+
+		// The enhanced values are brought into [MinValue, 2·MaxValue] by
+		// the same mode
+		hi := 2 * config.MaxValue
+		outside := 0
+		for _, entry := range doc.Sequence {
+			raw := entry.Value + *entry.EnhancementDelta
+			if raw < config.MinValue || raw > hi {
+				outside++
+			}
+			if got := *entry.EnhancedValue; got != bound(raw, config.MinValue, hi, mode) || got < config.MinValue || got > hi {
+				t.Fatalf("%s: step %d enhanced %d to %d", mode, entry.Step, raw, got)
+			}
+		}
+		if outside == 0 {
+			t.Errorf("%s: no enhanced value left the range", mode)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteJSON(doc, &buf); err != nil {
+			t.Fatal(err)
+		}
+		_, meta, _, err := LoadSequenceFromJSON(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Config.BoundaryMode != mode {
+			t.Errorf("loaded boundary mode %q, want %q", meta.Config.BoundaryMode, mode)
+		}
+	}
+
+	config := DefaultConfig()
+	config.BoundaryMode = "bounce"
+	if err := config.Validate(); !errors.Is(err, ErrUnknownBoundaryMode) {
+		t.Errorf("Validate returned %v, want ErrUnknownBoundaryMode", err)
+	}
+}
+
+func TestSaturationWarning(t *testing.T) {
+	// Steps of up to 10 around values of at most 10 leave a range this
+	// narrow more than a third of the time
+	run := func(opts ...Option) ([]LogEntry, []error) {
+		g, err := NewGenerator(append([]Option{WithSeed(42), WithRange(1, 10), WithVolatility(1)}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sequence, err := g.Generate(5000)
+		if err != nil {
+			t.Fatalf("a saturated run failed: %v", err)
+		}
+		return sequence, g.Warnings()
+	}
+	sequence, warnings := run()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrSaturated) {
+		t.Fatalf("warnings %v, want one wrapping ErrSaturated", warnings)
+	}
+	if len(sequence) != 5000 {
+		t.Errorf("%d entries returned with the warning, want 5000", len(sequence))
+	}
+
+	low, high := 0, 0
+	for _, entry := range sequence {
+		switch entry.Clamped {
+		case ClampedLow:
+			low++
+			if entry.Value != 1 {
+				t.Fatalf("step %d clamped low to %d", entry.Step, entry.Value)
+			}
+		case ClampedHigh:
+			high++
+			if entry.Value != 10 {
+				t.Fatalf("step %d clamped high to %d", entry.Step, entry.Value)
+			}
+		case "":
+		default:
+			t.Fatalf("step %d has clamped %q", entry.Step, entry.Clamped)
+		}
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if low == 0 || high == 0 || stats.ClampedLow != low || stats.ClampedHigh != high {
+		t.Errorf("clamped_low %d, clamped_high %d; counted %d and %d", stats.ClampedLow, stats.ClampedHigh, low, high)
+	}
+	if want := float64(low+high) / 5000; stats.ClampRate != want || stats.ClampRate <= DefaultSaturationThreshold {
+		t.Errorf("clamp_rate %v, want %v above the threshold", stats.ClampRate, want)
+	}
+	var buf bytes.Buffer
+	if err := WriteJSON(stats, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"clamp_rate"`)) {
+		t.Errorf("no clamp_rate in %s", buf.Bytes())
+	}
+
+	// A threshold of 1 never warns, and the run is the same
+	quiet, warnings := run(WithSaturationWarning(1))
+	if len(warnings) != 0 {
+		t.Errorf("warnings %v with a threshold of 1", warnings)
+	}
+	if !reflect.DeepEqual(quiet, sequence) {
+		t.Error("the threshold changed the run")
+	}
+	if _, err := NewGenerator(WithSaturationWarning(1.5)); err == nil {
+		t.Error("a threshold above 1 was accepted")
+	}
+}
@@ -0,0 +1,36 @@
+package chaotic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// cborEncMode writes times, such as a timing's start, as RFC3339 text with
+// nanoseconds; the default whole Unix seconds would drop the fraction
+var cborEncMode, _ = cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+
+// SaveToCBOR writes data to w as CBOR. Struct fields are keyed by their JSON
+// names, integers stay CBOR integers, and the GeneratedAt timestamp of a run
+// is kept as its RFC3339 text string rather than a CBOR time tag.
+func SaveToCBOR(data interface{}, w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	if err := cborEncMode.NewEncoder(buffered).Encode(data); err != nil {
+		return fmt.Errorf("failed to encode CBOR: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write CBOR: %w", err)
+	}
+	return nil
+}
+
+// LoadCBOR reads a run document written by SaveToCBOR
+func LoadCBOR(r io.Reader) (RunDocument, error) {
+	var doc RunDocument
+	if err := cbor.NewDecoder(bufio.NewReader(r)).Decode(&doc); err != nil {
+		return doc, fmt.Errorf("failed to decode CBOR: %w", err)
+	}
+	return doc, nil
+}
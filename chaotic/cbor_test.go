@@ -0,0 +1,72 @@
+package chaotic
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+func TestCBORRoundTrip(t *testing.T) {
+	seed := int64(61)
+	config := DefaultConfig()
+	config.Seed = &seed
+	tests := []struct {
+		name     string
+		extended bool
+	}{
+		{"plain", false},
+		{"extended", true},
+	}
+	for _, tt := range tests {
+		generate := ChaoticTransactionSequence
+		if tt.extended {
+			generate = ChaoticTransactionSequenceExtended
+		}
+		sequence, err := generate(300, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := ComputeStatistics(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc := RunDocument{
+			Metadata:   RunMetadata{GeneratedAt: "2024-01-01T12:30:45Z", Config: config, SequenceLength: len(sequence), Seed: &seed},
+			Statistics: stats,
+			Sequence:   sequence,
+		}
+		var buf bytes.Buffer
+		if err := SaveToCBOR(doc, &buf); err != nil {
+			t.Fatal(err)
+		}
+		encoded := buf.Bytes()
+		decoded, err := LoadCBOR(bytes.NewReader(encoded))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, doc) {
+			t.Errorf("%s: decoded a different run", tt.name)
+		}
+
+		// Keyed by the JSON names, with integer values and a text timestamp
+		var generic struct {
+			Metadata map[string]any   `cbor:"metadata"`
+			Sequence []map[string]any `cbor:"sequence"`
+		}
+		if err := cbor.Unmarshal(encoded, &generic); err != nil {
+			t.Fatal(err)
+		}
+		if at, ok := generic.Metadata["generated_at"].(string); !ok || at != doc.Metadata.GeneratedAt {
+			t.Errorf("%s: generated_at encoded as %#v", tt.name, generic.Metadata["generated_at"])
+		}
+		for i, entry := range generic.Sequence {
+			switch entry["value"].(type) {
+			case uint64, int64:
+			default:
+				t.Fatalf("%s: step %d value encoded as %T", tt.name, i, entry["value"])
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// minChangepointSegment is the shortest segment DetectChangepoints will cut,
+// so a single extreme value is not mistaken for a shift in level
+const minChangepointSegment = 2
+
+// DetectChangepoints finds the indexes where the mean level of values
+// shifts, by binary segmentation: the segment is split where the split most
+// reduces the sum of squared deviations from the segment means, and each half
+// is searched again, for as long as a split reduces that cost by more than
+// penalty times the variance of the whole sequence. Measuring against the
+// overall variance rather than the local noise keeps the detector to the
+// large shifts: the generator's walk wanders enough that a noise-scaled test
+// would cut it into many short segments.
+//
+// A penalty of 0 uses the BIC-style 2*ln(len(values)); larger penalties find
+// fewer changepoints. Each changepoint is the index of the first value of a
+// new segment, and they are returned in increasing order, nil when there
+// are none.
+func DetectChangepoints(values []int, penalty float64) ([]int, error) {
+	if len(values) == 0 {
+		return nil, errors.New("empty sequence")
+	}
+	if penalty < 0 || math.IsNaN(penalty) {
+		return nil, fmt.Errorf("penalty must not be negative, got %v", penalty)
+	}
+	if penalty == 0 {
+		penalty = 2 * math.Log(float64(len(values)))
+	}
+	if len(values) < 2*minChangepointSegment {
+		return nil, nil
+	}
+	stdev := calculateStdev(values, calculateMean(values))
+	if stdev == 0 {
+		return nil, nil
+	}
+
+	// Prefix sums give any segment's cost in constant time
+	sums := make([]float64, len(values)+1)
+	squares := make([]float64, len(values)+1)
+	for i, v := range values {
+		x := float64(v)
+		sums[i+1] = sums[i] + x
+		squares[i+1] = squares[i] + x*x
+	}
+	// cost is the sum of squared deviations from the mean of values[from:to]
+	cost := func(from, to int) float64 {
+		sum := sums[to] - sums[from]
+		return squares[to] - squares[from] - sum*sum/float64(to-from)
+	}
+
+	var changepoints []int
+	threshold := penalty * stdev * stdev
+	segments := [][2]int{{0, len(values)}}
+	for len(segments) > 0 {
+		from, to := segments[0][0], segments[0][1]
+		segments = segments[1:]
+
+		best, bestGain := -1, threshold
+		whole := cost(from, to)
+		for split := from + minChangepointSegment; split <= to-minChangepointSegment; split++ {
+			if gain := whole - cost(from, split) - cost(split, to); gain > bestGain {
+				best, bestGain = split, gain
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		changepoints = append(changepoints, best)
+		segments = append(segments, [2]int{from, best}, [2]int{best, to})
+	}
+	sort.Ints(changepoints)
+	return changepoints, nil
+}
@@ -0,0 +1,64 @@
+package chaotic
+
+import (
+	"reflect"
+	"testing"
+)
+
+// rangeSequence generates n seeded values between lo and hi
+func rangeSequence(t *testing.T, n int, seed int64, lo, hi int) []int {
+	t.Helper()
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.MinValue, config.MaxValue = lo, hi
+	sequence, err := ChaoticTransactionSequence(n, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return valuesOf(sequence)
+}
+
+func TestDetectChangepointsFindsRangeBoundary(t *testing.T) {
+	const tolerance = 5
+	for seed := int64(1); seed <= 5; seed++ {
+		low := rangeSequence(t, 600, seed, 1, 100)
+		high := rangeSequence(t, 400, seed+100, 5000, 10000)
+		values := append(low, high...)
+
+		changepoints, err := DetectChangepoints(values, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, cp := range changepoints {
+			found = found || abs(cp-len(low)) <= tolerance
+		}
+		if !found {
+			t.Errorf("seed %d: changepoints %v, want one within %d of %d", seed, changepoints, tolerance, len(low))
+		}
+
+		stats, err := ComputeStatistics(entriesOf(values...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stats.Changepoints, changepoints) {
+			t.Errorf("seed %d: statistics list changepoints %v, want %v", seed, stats.Changepoints, changepoints)
+		}
+	}
+}
+
+func TestDetectChangepointsOfFlatSequence(t *testing.T) {
+	values := make([]int, 200)
+	for i := range values {
+		values[i] = 40
+	}
+	if changepoints, err := DetectChangepoints(values, 0); err != nil || changepoints != nil {
+		t.Errorf("constant sequence: %v, %v; want none", changepoints, err)
+	}
+	if _, err := DetectChangepoints(nil, 0); err == nil {
+		t.Error("empty sequence succeeded")
+	}
+	if _, err := DetectChangepoints(values, -1); err == nil {
+		t.Error("negative penalty succeeded")
+	}
+}
@@ -0,0 +1,3042 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: chaotic.proto
+
+package chaoticpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LogEntry struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Step                int64                  `protobuf:"varint,1,opt,name=step,proto3" json:"step,omitempty"`
+	Value               int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Type                string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	EnhancedValue       *int64                 `protobuf:"varint,4,opt,name=enhanced_value,json=enhancedValue,proto3,oneof" json:"enhanced_value,omitempty"`
+	EnhancementDelta    *int64                 `protobuf:"varint,5,opt,name=enhancement_delta,json=enhancementDelta,proto3,oneof" json:"enhancement_delta,omitempty"`
+	IsOutlier           bool                   `protobuf:"varint,6,opt,name=is_outlier,json=isOutlier,proto3" json:"is_outlier,omitempty"`
+	AmountMinor         *int64                 `protobuf:"varint,7,opt,name=amount_minor,json=amountMinor,proto3,oneof" json:"amount_minor,omitempty"`
+	Amount              string                 `protobuf:"bytes,8,opt,name=amount,proto3" json:"amount,omitempty"`
+	Timestamp           string                 `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TransactionId       string                 `protobuf:"bytes,10,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	AccountId           string                 `protobuf:"bytes,11,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Merchant            string                 `protobuf:"bytes,12,opt,name=merchant,proto3" json:"merchant,omitempty"`
+	Direction           string                 `protobuf:"bytes,13,opt,name=direction,proto3" json:"direction,omitempty"`
+	Posted              *int64                 `protobuf:"varint,14,opt,name=posted,proto3,oneof" json:"posted,omitempty"`
+	Balance             *int64                 `protobuf:"varint,15,opt,name=balance,proto3,oneof" json:"balance,omitempty"`
+	Rejected            bool                   `protobuf:"varint,16,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Regime              string                 `protobuf:"bytes,17,opt,name=regime,proto3" json:"regime,omitempty"`
+	Jump                bool                   `protobuf:"varint,18,opt,name=jump,proto3" json:"jump,omitempty"`
+	EffectiveVolatility float64                `protobuf:"fixed64,19,opt,name=effective_volatility,json=effectiveVolatility,proto3" json:"effective_volatility,omitempty"`
+	Clamped             string                 `protobuf:"bytes,20,opt,name=clamped,proto3" json:"clamped,omitempty"`
+	Anomaly             bool                   `protobuf:"varint,21,opt,name=anomaly,proto3" json:"anomaly,omitempty"`
+	AnomalyKind         string                 `protobuf:"bytes,22,opt,name=anomaly_kind,json=anomalyKind,proto3" json:"anomaly_kind,omitempty"`
+	Missing             bool                   `protobuf:"varint,23,opt,name=missing,proto3" json:"missing,omitempty"`
+	DuplicateOf         *int64                 `protobuf:"varint,24,opt,name=duplicate_of,json=duplicateOf,proto3,oneof" json:"duplicate_of,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_chaotic_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LogEntry) GetStep() int64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *LogEntry) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *LogEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LogEntry) GetEnhancedValue() int64 {
+	if x != nil && x.EnhancedValue != nil {
+		return *x.EnhancedValue
+	}
+	return 0
+}
+
+func (x *LogEntry) GetEnhancementDelta() int64 {
+	if x != nil && x.EnhancementDelta != nil {
+		return *x.EnhancementDelta
+	}
+	return 0
+}
+
+func (x *LogEntry) GetIsOutlier() bool {
+	if x != nil {
+		return x.IsOutlier
+	}
+	return false
+}
+
+func (x *LogEntry) GetAmountMinor() int64 {
+	if x != nil && x.AmountMinor != nil {
+		return *x.AmountMinor
+	}
+	return 0
+}
+
+func (x *LogEntry) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMerchant() string {
+	if x != nil {
+		return x.Merchant
+	}
+	return ""
+}
+
+func (x *LogEntry) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *LogEntry) GetPosted() int64 {
+	if x != nil && x.Posted != nil {
+		return *x.Posted
+	}
+	return 0
+}
+
+func (x *LogEntry) GetBalance() int64 {
+	if x != nil && x.Balance != nil {
+		return *x.Balance
+	}
+	return 0
+}
+
+func (x *LogEntry) GetRejected() bool {
+	if x != nil {
+		return x.Rejected
+	}
+	return false
+}
+
+func (x *LogEntry) GetRegime() string {
+	if x != nil {
+		return x.Regime
+	}
+	return ""
+}
+
+func (x *LogEntry) GetJump() bool {
+	if x != nil {
+		return x.Jump
+	}
+	return false
+}
+
+func (x *LogEntry) GetEffectiveVolatility() float64 {
+	if x != nil {
+		return x.EffectiveVolatility
+	}
+	return 0
+}
+
+func (x *LogEntry) GetClamped() string {
+	if x != nil {
+		return x.Clamped
+	}
+	return ""
+}
+
+func (x *LogEntry) GetAnomaly() bool {
+	if x != nil {
+		return x.Anomaly
+	}
+	return false
+}
+
+func (x *LogEntry) GetAnomalyKind() string {
+	if x != nil {
+		return x.AnomalyKind
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMissing() bool {
+	if x != nil {
+		return x.Missing
+	}
+	return false
+}
+
+func (x *LogEntry) GetDuplicateOf() int64 {
+	if x != nil && x.DuplicateOf != nil {
+		return *x.DuplicateOf
+	}
+	return 0
+}
+
+type Statistics struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Mean                      float64                `protobuf:"fixed64,1,opt,name=mean,proto3" json:"mean,omitempty"`
+	Stdev                     float64                `protobuf:"fixed64,3,opt,name=stdev,proto3" json:"stdev,omitempty"`
+	Variance                  float64                `protobuf:"fixed64,4,opt,name=variance,proto3" json:"variance,omitempty"`
+	Min                       int64                  `protobuf:"varint,5,opt,name=min,proto3" json:"min,omitempty"`
+	Max                       int64                  `protobuf:"varint,6,opt,name=max,proto3" json:"max,omitempty"`
+	Count                     int64                  `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	CoefficientOfVariation    *float64               `protobuf:"fixed64,11,opt,name=coefficient_of_variation,json=coefficientOfVariation,proto3,oneof" json:"coefficient_of_variation,omitempty"`
+	TrendStrength             float64                `protobuf:"fixed64,12,opt,name=trend_strength,json=trendStrength,proto3" json:"trend_strength,omitempty"`
+	Volatility                float64                `protobuf:"fixed64,13,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	Skewness                  float64                `protobuf:"fixed64,14,opt,name=skewness,proto3" json:"skewness,omitempty"`
+	Kurtosis                  float64                `protobuf:"fixed64,15,opt,name=kurtosis,proto3" json:"kurtosis,omitempty"`
+	Lag1Autocorrelation       float64                `protobuf:"fixed64,16,opt,name=lag1_autocorrelation,json=lag1Autocorrelation,proto3" json:"lag1_autocorrelation,omitempty"`
+	Mode                      int64                  `protobuf:"varint,17,opt,name=mode,proto3" json:"mode,omitempty"`
+	GeometricMean             float64                `protobuf:"fixed64,18,opt,name=geometric_mean,json=geometricMean,proto3" json:"geometric_mean,omitempty"`
+	HarmonicMean              float64                `protobuf:"fixed64,19,opt,name=harmonic_mean,json=harmonicMean,proto3" json:"harmonic_mean,omitempty"`
+	Mad                       float64                `protobuf:"fixed64,20,opt,name=mad,proto3" json:"mad,omitempty"`
+	MadNormalized             float64                `protobuf:"fixed64,21,opt,name=mad_normalized,json=madNormalized,proto3" json:"mad_normalized,omitempty"`
+	HurstExponent             float64                `protobuf:"fixed64,22,opt,name=hurst_exponent,json=hurstExponent,proto3" json:"hurst_exponent,omitempty"`
+	SampleEntropy             float64                `protobuf:"fixed64,23,opt,name=sample_entropy,json=sampleEntropy,proto3" json:"sample_entropy,omitempty"`
+	PermutationEntropy        float64                `protobuf:"fixed64,24,opt,name=permutation_entropy,json=permutationEntropy,proto3" json:"permutation_entropy,omitempty"`
+	DistributionEntropy       float64                `protobuf:"fixed64,25,opt,name=distribution_entropy,json=distributionEntropy,proto3" json:"distribution_entropy,omitempty"`
+	MaxDrawdown               int64                  `protobuf:"varint,26,opt,name=max_drawdown,json=maxDrawdown,proto3" json:"max_drawdown,omitempty"`
+	MaxDrawdownFraction       float64                `protobuf:"fixed64,27,opt,name=max_drawdown_fraction,json=maxDrawdownFraction,proto3" json:"max_drawdown_fraction,omitempty"`
+	MaxDrawdownPeakStep       int64                  `protobuf:"varint,28,opt,name=max_drawdown_peak_step,json=maxDrawdownPeakStep,proto3" json:"max_drawdown_peak_step,omitempty"`
+	MaxDrawdownTroughStep     int64                  `protobuf:"varint,29,opt,name=max_drawdown_trough_step,json=maxDrawdownTroughStep,proto3" json:"max_drawdown_trough_step,omitempty"`
+	MaxDrawup                 int64                  `protobuf:"varint,30,opt,name=max_drawup,json=maxDrawup,proto3" json:"max_drawup,omitempty"`
+	MaxDrawupFraction         float64                `protobuf:"fixed64,31,opt,name=max_drawup_fraction,json=maxDrawupFraction,proto3" json:"max_drawup_fraction,omitempty"`
+	MaxDrawupTroughStep       int64                  `protobuf:"varint,32,opt,name=max_drawup_trough_step,json=maxDrawupTroughStep,proto3" json:"max_drawup_trough_step,omitempty"`
+	MaxDrawupPeakStep         int64                  `protobuf:"varint,33,opt,name=max_drawup_peak_step,json=maxDrawupPeakStep,proto3" json:"max_drawup_peak_step,omitempty"`
+	LongestIncreasingRun      int64                  `protobuf:"varint,34,opt,name=longest_increasing_run,json=longestIncreasingRun,proto3" json:"longest_increasing_run,omitempty"`
+	LongestIncreasingRunStart int64                  `protobuf:"varint,35,opt,name=longest_increasing_run_start,json=longestIncreasingRunStart,proto3" json:"longest_increasing_run_start,omitempty"`
+	LongestDecreasingRun      int64                  `protobuf:"varint,36,opt,name=longest_decreasing_run,json=longestDecreasingRun,proto3" json:"longest_decreasing_run,omitempty"`
+	LongestDecreasingRunStart int64                  `protobuf:"varint,37,opt,name=longest_decreasing_run_start,json=longestDecreasingRunStart,proto3" json:"longest_decreasing_run_start,omitempty"`
+	LongestFlatRun            int64                  `protobuf:"varint,38,opt,name=longest_flat_run,json=longestFlatRun,proto3" json:"longest_flat_run,omitempty"`
+	LongestFlatRunStart       int64                  `protobuf:"varint,39,opt,name=longest_flat_run_start,json=longestFlatRunStart,proto3" json:"longest_flat_run_start,omitempty"`
+	TurningPoints             int64                  `protobuf:"varint,40,opt,name=turning_points,json=turningPoints,proto3" json:"turning_points,omitempty"`
+	MeanCrossings             int64                  `protobuf:"varint,41,opt,name=mean_crossings,json=meanCrossings,proto3" json:"mean_crossings,omitempty"`
+	ReturnMean                float64                `protobuf:"fixed64,42,opt,name=return_mean,json=returnMean,proto3" json:"return_mean,omitempty"`
+	ReturnVolatility          float64                `protobuf:"fixed64,43,opt,name=return_volatility,json=returnVolatility,proto3" json:"return_volatility,omitempty"`
+	Histogram                 *Histogram             `protobuf:"bytes,44,opt,name=histogram,proto3" json:"histogram,omitempty"`
+	OutlierCount              int64                  `protobuf:"varint,45,opt,name=outlier_count,json=outlierCount,proto3" json:"outlier_count,omitempty"`
+	Changepoints              []int64                `protobuf:"varint,46,rep,packed,name=changepoints,proto3" json:"changepoints,omitempty"`
+	ByType                    []*TypeStatistics      `protobuf:"bytes,47,rep,name=by_type,json=byType,proto3" json:"by_type,omitempty"`
+	Stationarity              *Stationarity          `protobuf:"bytes,48,opt,name=stationarity,proto3" json:"stationarity,omitempty"`
+	Q1                        float64                `protobuf:"fixed64,49,opt,name=q1,proto3" json:"q1,omitempty"`
+	Q3                        float64                `protobuf:"fixed64,50,opt,name=q3,proto3" json:"q3,omitempty"`
+	Iqr                       float64                `protobuf:"fixed64,51,opt,name=iqr,proto3" json:"iqr,omitempty"`
+	Median                    float64                `protobuf:"fixed64,52,opt,name=median,proto3" json:"median,omitempty"`
+	Money                     *Money                 `protobuf:"bytes,53,opt,name=money,proto3" json:"money,omitempty"`
+	Duration                  float64                `protobuf:"fixed64,54,opt,name=duration,proto3" json:"duration,omitempty"`
+	EventsPerSecond           float64                `protobuf:"fixed64,55,opt,name=events_per_second,json=eventsPerSecond,proto3" json:"events_per_second,omitempty"`
+	Arrivals                  *Arrivals              `protobuf:"bytes,56,opt,name=arrivals,proto3" json:"arrivals,omitempty"`
+	ByAccount                 []*AccountCount        `protobuf:"bytes,57,rep,name=by_account,json=byAccount,proto3" json:"by_account,omitempty"`
+	FinalBalance              int64                  `protobuf:"varint,58,opt,name=final_balance,json=finalBalance,proto3" json:"final_balance,omitempty"`
+	MinBalance                int64                  `protobuf:"varint,59,opt,name=min_balance,json=minBalance,proto3" json:"min_balance,omitempty"`
+	DebitRatio                float64                `protobuf:"fixed64,60,opt,name=debit_ratio,json=debitRatio,proto3" json:"debit_ratio,omitempty"`
+	ByRegime                  []*RegimeStatistics    `protobuf:"bytes,61,rep,name=by_regime,json=byRegime,proto3" json:"by_regime,omitempty"`
+	JumpCount                 int64                  `protobuf:"varint,62,opt,name=jump_count,json=jumpCount,proto3" json:"jump_count,omitempty"`
+	DiffusionVolatility       float64                `protobuf:"fixed64,63,opt,name=diffusion_volatility,json=diffusionVolatility,proto3" json:"diffusion_volatility,omitempty"`
+	TargetDistance            *float64               `protobuf:"fixed64,64,opt,name=target_distance,json=targetDistance,proto3,oneof" json:"target_distance,omitempty"`
+	ClampedLow                int64                  `protobuf:"varint,65,opt,name=clamped_low,json=clampedLow,proto3" json:"clamped_low,omitempty"`
+	ClampedHigh               int64                  `protobuf:"varint,66,opt,name=clamped_high,json=clampedHigh,proto3" json:"clamped_high,omitempty"`
+	ClampRate                 float64                `protobuf:"fixed64,67,opt,name=clamp_rate,json=clampRate,proto3" json:"clamp_rate,omitempty"`
+	Missing                   int64                  `protobuf:"varint,68,opt,name=missing,proto3" json:"missing,omitempty"`
+	Duplicates                int64                  `protobuf:"varint,69,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	mi := &file_chaotic_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Statistics) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Statistics) GetStdev() float64 {
+	if x != nil {
+		return x.Stdev
+	}
+	return 0
+}
+
+func (x *Statistics) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+func (x *Statistics) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Statistics) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Statistics) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Statistics) GetCoefficientOfVariation() float64 {
+	if x != nil && x.CoefficientOfVariation != nil {
+		return *x.CoefficientOfVariation
+	}
+	return 0
+}
+
+func (x *Statistics) GetTrendStrength() float64 {
+	if x != nil {
+		return x.TrendStrength
+	}
+	return 0
+}
+
+func (x *Statistics) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *Statistics) GetSkewness() float64 {
+	if x != nil {
+		return x.Skewness
+	}
+	return 0
+}
+
+func (x *Statistics) GetKurtosis() float64 {
+	if x != nil {
+		return x.Kurtosis
+	}
+	return 0
+}
+
+func (x *Statistics) GetLag1Autocorrelation() float64 {
+	if x != nil {
+		return x.Lag1Autocorrelation
+	}
+	return 0
+}
+
+func (x *Statistics) GetMode() int64 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *Statistics) GetGeometricMean() float64 {
+	if x != nil {
+		return x.GeometricMean
+	}
+	return 0
+}
+
+func (x *Statistics) GetHarmonicMean() float64 {
+	if x != nil {
+		return x.HarmonicMean
+	}
+	return 0
+}
+
+func (x *Statistics) GetMad() float64 {
+	if x != nil {
+		return x.Mad
+	}
+	return 0
+}
+
+func (x *Statistics) GetMadNormalized() float64 {
+	if x != nil {
+		return x.MadNormalized
+	}
+	return 0
+}
+
+func (x *Statistics) GetHurstExponent() float64 {
+	if x != nil {
+		return x.HurstExponent
+	}
+	return 0
+}
+
+func (x *Statistics) GetSampleEntropy() float64 {
+	if x != nil {
+		return x.SampleEntropy
+	}
+	return 0
+}
+
+func (x *Statistics) GetPermutationEntropy() float64 {
+	if x != nil {
+		return x.PermutationEntropy
+	}
+	return 0
+}
+
+func (x *Statistics) GetDistributionEntropy() float64 {
+	if x != nil {
+		return x.DistributionEntropy
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawdown() int64 {
+	if x != nil {
+		return x.MaxDrawdown
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawdownFraction() float64 {
+	if x != nil {
+		return x.MaxDrawdownFraction
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawdownPeakStep() int64 {
+	if x != nil {
+		return x.MaxDrawdownPeakStep
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawdownTroughStep() int64 {
+	if x != nil {
+		return x.MaxDrawdownTroughStep
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawup() int64 {
+	if x != nil {
+		return x.MaxDrawup
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawupFraction() float64 {
+	if x != nil {
+		return x.MaxDrawupFraction
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawupTroughStep() int64 {
+	if x != nil {
+		return x.MaxDrawupTroughStep
+	}
+	return 0
+}
+
+func (x *Statistics) GetMaxDrawupPeakStep() int64 {
+	if x != nil {
+		return x.MaxDrawupPeakStep
+	}
+	return 0
+}
+
+func (x *Statistics) GetLongestIncreasingRun() int64 {
+	if x != nil {
+		return x.LongestIncreasingRun
+	}
+	return 0
+}
+
+func (x *Statistics) GetLongestIncreasingRunStart() int64 {
+	if x != nil {
+		return x.LongestIncreasingRunStart
+	}
+	return 0
+}
+
+func (x *Statistics) GetLongestDecreasingRun() int64 {
+	if x != nil {
+		return x.LongestDecreasingRun
+	}
+	return 0
+}
+
+func (x *Statistics) GetLongestDecreasingRunStart() int64 {
+	if x != nil {
+		return x.LongestDecreasingRunStart
+	}
+	return 0
+}
+
+func (x *Statistics) GetLongestFlatRun() int64 {
+	if x != nil {
+		return x.LongestFlatRun
+	}
+	return 0
+}
+
+func (x *Statistics) GetLongestFlatRunStart() int64 {
+	if x != nil {
+		return x.LongestFlatRunStart
+	}
+	return 0
+}
+
+func (x *Statistics) GetTurningPoints() int64 {
+	if x != nil {
+		return x.TurningPoints
+	}
+	return 0
+}
+
+func (x *Statistics) GetMeanCrossings() int64 {
+	if x != nil {
+		return x.MeanCrossings
+	}
+	return 0
+}
+
+func (x *Statistics) GetReturnMean() float64 {
+	if x != nil {
+		return x.ReturnMean
+	}
+	return 0
+}
+
+func (x *Statistics) GetReturnVolatility() float64 {
+	if x != nil {
+		return x.ReturnVolatility
+	}
+	return 0
+}
+
+func (x *Statistics) GetHistogram() *Histogram {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+func (x *Statistics) GetOutlierCount() int64 {
+	if x != nil {
+		return x.OutlierCount
+	}
+	return 0
+}
+
+func (x *Statistics) GetChangepoints() []int64 {
+	if x != nil {
+		return x.Changepoints
+	}
+	return nil
+}
+
+func (x *Statistics) GetByType() []*TypeStatistics {
+	if x != nil {
+		return x.ByType
+	}
+	return nil
+}
+
+func (x *Statistics) GetStationarity() *Stationarity {
+	if x != nil {
+		return x.Stationarity
+	}
+	return nil
+}
+
+func (x *Statistics) GetQ1() float64 {
+	if x != nil {
+		return x.Q1
+	}
+	return 0
+}
+
+func (x *Statistics) GetQ3() float64 {
+	if x != nil {
+		return x.Q3
+	}
+	return 0
+}
+
+func (x *Statistics) GetIqr() float64 {
+	if x != nil {
+		return x.Iqr
+	}
+	return 0
+}
+
+func (x *Statistics) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *Statistics) GetMoney() *Money {
+	if x != nil {
+		return x.Money
+	}
+	return nil
+}
+
+func (x *Statistics) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *Statistics) GetEventsPerSecond() float64 {
+	if x != nil {
+		return x.EventsPerSecond
+	}
+	return 0
+}
+
+func (x *Statistics) GetArrivals() *Arrivals {
+	if x != nil {
+		return x.Arrivals
+	}
+	return nil
+}
+
+func (x *Statistics) GetByAccount() []*AccountCount {
+	if x != nil {
+		return x.ByAccount
+	}
+	return nil
+}
+
+func (x *Statistics) GetFinalBalance() int64 {
+	if x != nil {
+		return x.FinalBalance
+	}
+	return 0
+}
+
+func (x *Statistics) GetMinBalance() int64 {
+	if x != nil {
+		return x.MinBalance
+	}
+	return 0
+}
+
+func (x *Statistics) GetDebitRatio() float64 {
+	if x != nil {
+		return x.DebitRatio
+	}
+	return 0
+}
+
+func (x *Statistics) GetByRegime() []*RegimeStatistics {
+	if x != nil {
+		return x.ByRegime
+	}
+	return nil
+}
+
+func (x *Statistics) GetJumpCount() int64 {
+	if x != nil {
+		return x.JumpCount
+	}
+	return 0
+}
+
+func (x *Statistics) GetDiffusionVolatility() float64 {
+	if x != nil {
+		return x.DiffusionVolatility
+	}
+	return 0
+}
+
+func (x *Statistics) GetTargetDistance() float64 {
+	if x != nil && x.TargetDistance != nil {
+		return *x.TargetDistance
+	}
+	return 0
+}
+
+func (x *Statistics) GetClampedLow() int64 {
+	if x != nil {
+		return x.ClampedLow
+	}
+	return 0
+}
+
+func (x *Statistics) GetClampedHigh() int64 {
+	if x != nil {
+		return x.ClampedHigh
+	}
+	return 0
+}
+
+func (x *Statistics) GetClampRate() float64 {
+	if x != nil {
+		return x.ClampRate
+	}
+	return 0
+}
+
+func (x *Statistics) GetMissing() int64 {
+	if x != nil {
+		return x.Missing
+	}
+	return 0
+}
+
+func (x *Statistics) GetDuplicates() int64 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+type AccountCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       string                 `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountCount) Reset() {
+	*x = AccountCount{}
+	mi := &file_chaotic_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountCount) ProtoMessage() {}
+
+func (x *AccountCount) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountCount.ProtoReflect.Descriptor instead.
+func (*AccountCount) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AccountCount) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *AccountCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type TypeStatistics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	MeanValue     float64                `protobuf:"fixed64,3,opt,name=mean_value,json=meanValue,proto3" json:"mean_value,omitempty"`
+	MeanAbsChange float64                `protobuf:"fixed64,4,opt,name=mean_abs_change,json=meanAbsChange,proto3" json:"mean_abs_change,omitempty"`
+	Share         float64                `protobuf:"fixed64,5,opt,name=share,proto3" json:"share,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeStatistics) Reset() {
+	*x = TypeStatistics{}
+	mi := &file_chaotic_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeStatistics) ProtoMessage() {}
+
+func (x *TypeStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeStatistics.ProtoReflect.Descriptor instead.
+func (*TypeStatistics) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TypeStatistics) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TypeStatistics) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TypeStatistics) GetMeanValue() float64 {
+	if x != nil {
+		return x.MeanValue
+	}
+	return 0
+}
+
+func (x *TypeStatistics) GetMeanAbsChange() float64 {
+	if x != nil {
+		return x.MeanAbsChange
+	}
+	return 0
+}
+
+func (x *TypeStatistics) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+type RegimeStatistics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Regime        string                 `protobuf:"bytes,1,opt,name=regime,proto3" json:"regime,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Share         float64                `protobuf:"fixed64,3,opt,name=share,proto3" json:"share,omitempty"`
+	Volatility    float64                `protobuf:"fixed64,4,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegimeStatistics) Reset() {
+	*x = RegimeStatistics{}
+	mi := &file_chaotic_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegimeStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegimeStatistics) ProtoMessage() {}
+
+func (x *RegimeStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegimeStatistics.ProtoReflect.Descriptor instead.
+func (*RegimeStatistics) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RegimeStatistics) GetRegime() string {
+	if x != nil {
+		return x.Regime
+	}
+	return ""
+}
+
+func (x *RegimeStatistics) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RegimeStatistics) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *RegimeStatistics) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+type Stationarity struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MeanT          float64                `protobuf:"fixed64,1,opt,name=mean_t,json=meanT,proto3" json:"mean_t,omitempty"`
+	MeanPValue     float64                `protobuf:"fixed64,2,opt,name=mean_p_value,json=meanPValue,proto3" json:"mean_p_value,omitempty"`
+	VarianceF      float64                `protobuf:"fixed64,3,opt,name=variance_f,json=varianceF,proto3" json:"variance_f,omitempty"`
+	VariancePValue float64                `protobuf:"fixed64,4,opt,name=variance_p_value,json=variancePValue,proto3" json:"variance_p_value,omitempty"`
+	AdfStatistic   float64                `protobuf:"fixed64,5,opt,name=adf_statistic,json=adfStatistic,proto3" json:"adf_statistic,omitempty"`
+	Stationary     bool                   `protobuf:"varint,6,opt,name=stationary,proto3" json:"stationary,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Stationarity) Reset() {
+	*x = Stationarity{}
+	mi := &file_chaotic_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stationarity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stationarity) ProtoMessage() {}
+
+func (x *Stationarity) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stationarity.ProtoReflect.Descriptor instead.
+func (*Stationarity) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Stationarity) GetMeanT() float64 {
+	if x != nil {
+		return x.MeanT
+	}
+	return 0
+}
+
+func (x *Stationarity) GetMeanPValue() float64 {
+	if x != nil {
+		return x.MeanPValue
+	}
+	return 0
+}
+
+func (x *Stationarity) GetVarianceF() float64 {
+	if x != nil {
+		return x.VarianceF
+	}
+	return 0
+}
+
+func (x *Stationarity) GetVariancePValue() float64 {
+	if x != nil {
+		return x.VariancePValue
+	}
+	return 0
+}
+
+func (x *Stationarity) GetAdfStatistic() float64 {
+	if x != nil {
+		return x.AdfStatistic
+	}
+	return 0
+}
+
+func (x *Stationarity) GetStationary() bool {
+	if x != nil {
+		return x.Stationary
+	}
+	return false
+}
+
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Total         string                 `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Mean          string                 `protobuf:"bytes,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	Median        string                 `protobuf:"bytes,4,opt,name=median,proto3" json:"median,omitempty"`
+	Stdev         string                 `protobuf:"bytes,5,opt,name=stdev,proto3" json:"stdev,omitempty"`
+	Min           string                 `protobuf:"bytes,6,opt,name=min,proto3" json:"min,omitempty"`
+	Max           string                 `protobuf:"bytes,7,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_chaotic_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Money) GetTotal() string {
+	if x != nil {
+		return x.Total
+	}
+	return ""
+}
+
+func (x *Money) GetMean() string {
+	if x != nil {
+		return x.Mean
+	}
+	return ""
+}
+
+func (x *Money) GetMedian() string {
+	if x != nil {
+		return x.Median
+	}
+	return ""
+}
+
+func (x *Money) GetStdev() string {
+	if x != nil {
+		return x.Stdev
+	}
+	return ""
+}
+
+func (x *Money) GetMin() string {
+	if x != nil {
+		return x.Min
+	}
+	return ""
+}
+
+func (x *Money) GetMax() string {
+	if x != nil {
+		return x.Max
+	}
+	return ""
+}
+
+type Arrivals struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Model             string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	InterArrivalMean  float64                `protobuf:"fixed64,2,opt,name=inter_arrival_mean,json=interArrivalMean,proto3" json:"inter_arrival_mean,omitempty"`
+	InterArrivalStdev float64                `protobuf:"fixed64,3,opt,name=inter_arrival_stdev,json=interArrivalStdev,proto3" json:"inter_arrival_stdev,omitempty"`
+	ExpectedMean      float64                `protobuf:"fixed64,4,opt,name=expected_mean,json=expectedMean,proto3" json:"expected_mean,omitempty"`
+	RateDeviation     float64                `protobuf:"fixed64,5,opt,name=rate_deviation,json=rateDeviation,proto3" json:"rate_deviation,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Arrivals) Reset() {
+	*x = Arrivals{}
+	mi := &file_chaotic_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Arrivals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Arrivals) ProtoMessage() {}
+
+func (x *Arrivals) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Arrivals.ProtoReflect.Descriptor instead.
+func (*Arrivals) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Arrivals) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Arrivals) GetInterArrivalMean() float64 {
+	if x != nil {
+		return x.InterArrivalMean
+	}
+	return 0
+}
+
+func (x *Arrivals) GetInterArrivalStdev() float64 {
+	if x != nil {
+		return x.InterArrivalStdev
+	}
+	return 0
+}
+
+func (x *Arrivals) GetExpectedMean() float64 {
+	if x != nil {
+		return x.ExpectedMean
+	}
+	return 0
+}
+
+func (x *Arrivals) GetRateDeviation() float64 {
+	if x != nil {
+		return x.RateDeviation
+	}
+	return 0
+}
+
+type Histogram struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edges         []float64              `protobuf:"fixed64,1,rep,packed,name=edges,proto3" json:"edges,omitempty"`
+	Counts        []int64                `protobuf:"varint,2,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+	Frequencies   []float64              `protobuf:"fixed64,3,rep,packed,name=frequencies,proto3" json:"frequencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Histogram) Reset() {
+	*x = Histogram{}
+	mi := &file_chaotic_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Histogram) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Histogram) ProtoMessage() {}
+
+func (x *Histogram) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Histogram.ProtoReflect.Descriptor instead.
+func (*Histogram) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Histogram) GetEdges() []float64 {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+func (x *Histogram) GetCounts() []int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *Histogram) GetFrequencies() []float64 {
+	if x != nil {
+		return x.Frequencies
+	}
+	return nil
+}
+
+type Config struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Volatility            float64                `protobuf:"fixed64,1,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	TrendStrength         float64                `protobuf:"fixed64,2,opt,name=trend_strength,json=trendStrength,proto3" json:"trend_strength,omitempty"`
+	MeanReversion         float64                `protobuf:"fixed64,3,opt,name=mean_reversion,json=meanReversion,proto3" json:"mean_reversion,omitempty"`
+	MinValue              int64                  `protobuf:"varint,4,opt,name=min_value,json=minValue,proto3" json:"min_value,omitempty"`
+	MaxValue              int64                  `protobuf:"varint,5,opt,name=max_value,json=maxValue,proto3" json:"max_value,omitempty"`
+	Seed                  *int64                 `protobuf:"varint,6,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	RandomnessMode        string                 `protobuf:"bytes,7,opt,name=randomness_mode,json=randomnessMode,proto3" json:"randomness_mode,omitempty"`
+	RegimeWeights         *RegimeWeights         `protobuf:"bytes,8,opt,name=regime_weights,json=regimeWeights,proto3" json:"regime_weights,omitempty"`
+	MultiplicativeFactors []float64              `protobuf:"fixed64,9,rep,packed,name=multiplicative_factors,json=multiplicativeFactors,proto3" json:"multiplicative_factors,omitempty"`
+	NoiseAmplitude        int64                  `protobuf:"varint,10,opt,name=noise_amplitude,json=noiseAmplitude,proto3" json:"noise_amplitude,omitempty"`
+	Currency              *Currency              `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	Timing                *Timing                `protobuf:"bytes,12,opt,name=timing,proto3" json:"timing,omitempty"`
+	Entities              *Entities              `protobuf:"bytes,13,opt,name=entities,proto3" json:"entities,omitempty"`
+	Ledger                *Ledger                `protobuf:"bytes,14,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Regimes               *RegimeSwitching       `protobuf:"bytes,15,opt,name=regimes,proto3" json:"regimes,omitempty"`
+	Jumps                 *Jumps                 `protobuf:"bytes,16,opt,name=jumps,proto3" json:"jumps,omitempty"`
+	Garch                 *Garch                 `protobuf:"bytes,17,opt,name=garch,proto3" json:"garch,omitempty"`
+	MeanReversionTarget   *int64                 `protobuf:"varint,18,opt,name=mean_reversion_target,json=meanReversionTarget,proto3,oneof" json:"mean_reversion_target,omitempty"`
+	ReversionSpeed        float64                `protobuf:"fixed64,19,opt,name=reversion_speed,json=reversionSpeed,proto3" json:"reversion_speed,omitempty"`
+	Model                 string                 `protobuf:"bytes,20,opt,name=model,proto3" json:"model,omitempty"`
+	LogisticR             float64                `protobuf:"fixed64,21,opt,name=logistic_r,json=logisticR,proto3" json:"logistic_r,omitempty"`
+	MapX0                 *float64               `protobuf:"fixed64,22,opt,name=map_x0,json=mapX0,proto3,oneof" json:"map_x0,omitempty"`
+	TentMu                float64                `protobuf:"fixed64,23,opt,name=tent_mu,json=tentMu,proto3" json:"tent_mu,omitempty"`
+	HenonA                float64                `protobuf:"fixed64,24,opt,name=henon_a,json=henonA,proto3" json:"henon_a,omitempty"`
+	HenonB                float64                `protobuf:"fixed64,25,opt,name=henon_b,json=henonB,proto3" json:"henon_b,omitempty"`
+	MapY0                 float64                `protobuf:"fixed64,26,opt,name=map_y0,json=mapY0,proto3" json:"map_y0,omitempty"`
+	Lorenz                *Lorenz                `protobuf:"bytes,27,opt,name=lorenz,proto3" json:"lorenz,omitempty"`
+	BoundaryMode          string                 `protobuf:"bytes,28,opt,name=boundary_mode,json=boundaryMode,proto3" json:"boundary_mode,omitempty"`
+	Anomalies             *Anomalies             `protobuf:"bytes,29,opt,name=anomalies,proto3" json:"anomalies,omitempty"`
+	Faults                *DeliveryFaults        `protobuf:"bytes,30,opt,name=faults,proto3" json:"faults,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_chaotic_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Config) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *Config) GetTrendStrength() float64 {
+	if x != nil {
+		return x.TrendStrength
+	}
+	return 0
+}
+
+func (x *Config) GetMeanReversion() float64 {
+	if x != nil {
+		return x.MeanReversion
+	}
+	return 0
+}
+
+func (x *Config) GetMinValue() int64 {
+	if x != nil {
+		return x.MinValue
+	}
+	return 0
+}
+
+func (x *Config) GetMaxValue() int64 {
+	if x != nil {
+		return x.MaxValue
+	}
+	return 0
+}
+
+func (x *Config) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *Config) GetRandomnessMode() string {
+	if x != nil {
+		return x.RandomnessMode
+	}
+	return ""
+}
+
+func (x *Config) GetRegimeWeights() *RegimeWeights {
+	if x != nil {
+		return x.RegimeWeights
+	}
+	return nil
+}
+
+func (x *Config) GetMultiplicativeFactors() []float64 {
+	if x != nil {
+		return x.MultiplicativeFactors
+	}
+	return nil
+}
+
+func (x *Config) GetNoiseAmplitude() int64 {
+	if x != nil {
+		return x.NoiseAmplitude
+	}
+	return 0
+}
+
+func (x *Config) GetCurrency() *Currency {
+	if x != nil {
+		return x.Currency
+	}
+	return nil
+}
+
+func (x *Config) GetTiming() *Timing {
+	if x != nil {
+		return x.Timing
+	}
+	return nil
+}
+
+func (x *Config) GetEntities() *Entities {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *Config) GetLedger() *Ledger {
+	if x != nil {
+		return x.Ledger
+	}
+	return nil
+}
+
+func (x *Config) GetRegimes() *RegimeSwitching {
+	if x != nil {
+		return x.Regimes
+	}
+	return nil
+}
+
+func (x *Config) GetJumps() *Jumps {
+	if x != nil {
+		return x.Jumps
+	}
+	return nil
+}
+
+func (x *Config) GetGarch() *Garch {
+	if x != nil {
+		return x.Garch
+	}
+	return nil
+}
+
+func (x *Config) GetMeanReversionTarget() int64 {
+	if x != nil && x.MeanReversionTarget != nil {
+		return *x.MeanReversionTarget
+	}
+	return 0
+}
+
+func (x *Config) GetReversionSpeed() float64 {
+	if x != nil {
+		return x.ReversionSpeed
+	}
+	return 0
+}
+
+func (x *Config) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Config) GetLogisticR() float64 {
+	if x != nil {
+		return x.LogisticR
+	}
+	return 0
+}
+
+func (x *Config) GetMapX0() float64 {
+	if x != nil && x.MapX0 != nil {
+		return *x.MapX0
+	}
+	return 0
+}
+
+func (x *Config) GetTentMu() float64 {
+	if x != nil {
+		return x.TentMu
+	}
+	return 0
+}
+
+func (x *Config) GetHenonA() float64 {
+	if x != nil {
+		return x.HenonA
+	}
+	return 0
+}
+
+func (x *Config) GetHenonB() float64 {
+	if x != nil {
+		return x.HenonB
+	}
+	return 0
+}
+
+func (x *Config) GetMapY0() float64 {
+	if x != nil {
+		return x.MapY0
+	}
+	return 0
+}
+
+func (x *Config) GetLorenz() *Lorenz {
+	if x != nil {
+		return x.Lorenz
+	}
+	return nil
+}
+
+func (x *Config) GetBoundaryMode() string {
+	if x != nil {
+		return x.BoundaryMode
+	}
+	return ""
+}
+
+func (x *Config) GetAnomalies() *Anomalies {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+func (x *Config) GetFaults() *DeliveryFaults {
+	if x != nil {
+		return x.Faults
+	}
+	return nil
+}
+
+type Lorenz struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sigma         float64                `protobuf:"fixed64,1,opt,name=sigma,proto3" json:"sigma,omitempty"`
+	Rho           float64                `protobuf:"fixed64,2,opt,name=rho,proto3" json:"rho,omitempty"`
+	Beta          float64                `protobuf:"fixed64,3,opt,name=beta,proto3" json:"beta,omitempty"`
+	Dt            float64                `protobuf:"fixed64,4,opt,name=dt,proto3" json:"dt,omitempty"`
+	Stride        int64                  `protobuf:"varint,5,opt,name=stride,proto3" json:"stride,omitempty"`
+	Axis          string                 `protobuf:"bytes,6,opt,name=axis,proto3" json:"axis,omitempty"`
+	BurnIn        int64                  `protobuf:"varint,7,opt,name=burn_in,json=burnIn,proto3" json:"burn_in,omitempty"`
+	Start         []float64              `protobuf:"fixed64,8,rep,packed,name=start,proto3" json:"start,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lorenz) Reset() {
+	*x = Lorenz{}
+	mi := &file_chaotic_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lorenz) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lorenz) ProtoMessage() {}
+
+func (x *Lorenz) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lorenz.ProtoReflect.Descriptor instead.
+func (*Lorenz) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Lorenz) GetSigma() float64 {
+	if x != nil {
+		return x.Sigma
+	}
+	return 0
+}
+
+func (x *Lorenz) GetRho() float64 {
+	if x != nil {
+		return x.Rho
+	}
+	return 0
+}
+
+func (x *Lorenz) GetBeta() float64 {
+	if x != nil {
+		return x.Beta
+	}
+	return 0
+}
+
+func (x *Lorenz) GetDt() float64 {
+	if x != nil {
+		return x.Dt
+	}
+	return 0
+}
+
+func (x *Lorenz) GetStride() int64 {
+	if x != nil {
+		return x.Stride
+	}
+	return 0
+}
+
+func (x *Lorenz) GetAxis() string {
+	if x != nil {
+		return x.Axis
+	}
+	return ""
+}
+
+func (x *Lorenz) GetBurnIn() int64 {
+	if x != nil {
+		return x.BurnIn
+	}
+	return 0
+}
+
+func (x *Lorenz) GetStart() []float64 {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+type Garch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Omega         float64                `protobuf:"fixed64,1,opt,name=omega,proto3" json:"omega,omitempty"`
+	Alpha         float64                `protobuf:"fixed64,2,opt,name=alpha,proto3" json:"alpha,omitempty"`
+	Beta          float64                `protobuf:"fixed64,3,opt,name=beta,proto3" json:"beta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Garch) Reset() {
+	*x = Garch{}
+	mi := &file_chaotic_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Garch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Garch) ProtoMessage() {}
+
+func (x *Garch) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Garch.ProtoReflect.Descriptor instead.
+func (*Garch) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Garch) GetOmega() float64 {
+	if x != nil {
+		return x.Omega
+	}
+	return 0
+}
+
+func (x *Garch) GetAlpha() float64 {
+	if x != nil {
+		return x.Alpha
+	}
+	return 0
+}
+
+func (x *Garch) GetBeta() float64 {
+	if x != nil {
+		return x.Beta
+	}
+	return 0
+}
+
+type Jumps struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Probability   float64                `protobuf:"fixed64,1,opt,name=probability,proto3" json:"probability,omitempty"`
+	Mean          float64                `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	Stdev         float64                `protobuf:"fixed64,3,opt,name=stdev,proto3" json:"stdev,omitempty"`
+	Sizes         []float64              `protobuf:"fixed64,4,rep,packed,name=sizes,proto3" json:"sizes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Jumps) Reset() {
+	*x = Jumps{}
+	mi := &file_chaotic_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Jumps) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Jumps) ProtoMessage() {}
+
+func (x *Jumps) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Jumps.ProtoReflect.Descriptor instead.
+func (*Jumps) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Jumps) GetProbability() float64 {
+	if x != nil {
+		return x.Probability
+	}
+	return 0
+}
+
+func (x *Jumps) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Jumps) GetStdev() float64 {
+	if x != nil {
+		return x.Stdev
+	}
+	return 0
+}
+
+func (x *Jumps) GetSizes() []float64 {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+type DeliveryFaults struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DropRate      float64                `protobuf:"fixed64,1,opt,name=drop_rate,json=dropRate,proto3" json:"drop_rate,omitempty"`
+	DuplicateRate float64                `protobuf:"fixed64,2,opt,name=duplicate_rate,json=duplicateRate,proto3" json:"duplicate_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryFaults) Reset() {
+	*x = DeliveryFaults{}
+	mi := &file_chaotic_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryFaults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryFaults) ProtoMessage() {}
+
+func (x *DeliveryFaults) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryFaults.ProtoReflect.Descriptor instead.
+func (*DeliveryFaults) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeliveryFaults) GetDropRate() float64 {
+	if x != nil {
+		return x.DropRate
+	}
+	return 0
+}
+
+func (x *DeliveryFaults) GetDuplicateRate() float64 {
+	if x != nil {
+		return x.DuplicateRate
+	}
+	return 0
+}
+
+type Anomalies struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          float64                `protobuf:"fixed64,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	Multiplier    float64                `protobuf:"fixed64,2,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Duration      int64                  `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	LevelShifts   float64                `protobuf:"fixed64,5,opt,name=level_shifts,json=levelShifts,proto3" json:"level_shifts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Anomalies) Reset() {
+	*x = Anomalies{}
+	mi := &file_chaotic_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Anomalies) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Anomalies) ProtoMessage() {}
+
+func (x *Anomalies) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Anomalies.ProtoReflect.Descriptor instead.
+func (*Anomalies) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Anomalies) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *Anomalies) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *Anomalies) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *Anomalies) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *Anomalies) GetLevelShifts() float64 {
+	if x != nil {
+		return x.LevelShifts
+	}
+	return 0
+}
+
+type RegimeSwitching struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	States        []*VolatilityRegime    `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	Transitions   []*TransitionRow       `protobuf:"bytes,2,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegimeSwitching) Reset() {
+	*x = RegimeSwitching{}
+	mi := &file_chaotic_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegimeSwitching) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegimeSwitching) ProtoMessage() {}
+
+func (x *RegimeSwitching) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegimeSwitching.ProtoReflect.Descriptor instead.
+func (*RegimeSwitching) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RegimeSwitching) GetStates() []*VolatilityRegime {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *RegimeSwitching) GetTransitions() []*TransitionRow {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+type VolatilityRegime struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Volatility    float64                `protobuf:"fixed64,2,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	TrendStrength float64                `protobuf:"fixed64,3,opt,name=trend_strength,json=trendStrength,proto3" json:"trend_strength,omitempty"`
+	MeanReversion float64                `protobuf:"fixed64,4,opt,name=mean_reversion,json=meanReversion,proto3" json:"mean_reversion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolatilityRegime) Reset() {
+	*x = VolatilityRegime{}
+	mi := &file_chaotic_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolatilityRegime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolatilityRegime) ProtoMessage() {}
+
+func (x *VolatilityRegime) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolatilityRegime.ProtoReflect.Descriptor instead.
+func (*VolatilityRegime) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *VolatilityRegime) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VolatilityRegime) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *VolatilityRegime) GetTrendStrength() float64 {
+	if x != nil {
+		return x.TrendStrength
+	}
+	return 0
+}
+
+func (x *VolatilityRegime) GetMeanReversion() float64 {
+	if x != nil {
+		return x.MeanReversion
+	}
+	return 0
+}
+
+type TransitionRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Probabilities []float64              `protobuf:"fixed64,1,rep,packed,name=probabilities,proto3" json:"probabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionRow) Reset() {
+	*x = TransitionRow{}
+	mi := &file_chaotic_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionRow) ProtoMessage() {}
+
+func (x *TransitionRow) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionRow.ProtoReflect.Descriptor instead.
+func (*TransitionRow) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TransitionRow) GetProbabilities() []float64 {
+	if x != nil {
+		return x.Probabilities
+	}
+	return nil
+}
+
+type Ledger struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OpeningBalance   int64                  `protobuf:"varint,1,opt,name=opening_balance,json=openingBalance,proto3" json:"opening_balance,omitempty"`
+	DebitProbability float64                `protobuf:"fixed64,2,opt,name=debit_probability,json=debitProbability,proto3" json:"debit_probability,omitempty"`
+	Floor            *int64                 `protobuf:"varint,3,opt,name=floor,proto3,oneof" json:"floor,omitempty"`
+	FloorPolicy      string                 `protobuf:"bytes,4,opt,name=floor_policy,json=floorPolicy,proto3" json:"floor_policy,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Ledger) Reset() {
+	*x = Ledger{}
+	mi := &file_chaotic_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ledger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ledger) ProtoMessage() {}
+
+func (x *Ledger) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ledger.ProtoReflect.Descriptor instead.
+func (*Ledger) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Ledger) GetOpeningBalance() int64 {
+	if x != nil {
+		return x.OpeningBalance
+	}
+	return 0
+}
+
+func (x *Ledger) GetDebitProbability() float64 {
+	if x != nil {
+		return x.DebitProbability
+	}
+	return 0
+}
+
+func (x *Ledger) GetFloor() int64 {
+	if x != nil && x.Floor != nil {
+		return *x.Floor
+	}
+	return 0
+}
+
+func (x *Ledger) GetFloorPolicy() string {
+	if x != nil {
+		return x.FloorPolicy
+	}
+	return ""
+}
+
+type Entities struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      int64                  `protobuf:"varint,1,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	Merchants     []*Merchant            `protobuf:"bytes,2,rep,name=merchants,proto3" json:"merchants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Entities) Reset() {
+	*x = Entities{}
+	mi := &file_chaotic_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entities) ProtoMessage() {}
+
+func (x *Entities) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entities.ProtoReflect.Descriptor instead.
+func (*Entities) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Entities) GetAccounts() int64 {
+	if x != nil {
+		return x.Accounts
+	}
+	return 0
+}
+
+func (x *Entities) GetMerchants() []*Merchant {
+	if x != nil {
+		return x.Merchants
+	}
+	return nil
+}
+
+type Merchant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Weight        float64                `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Merchant) Reset() {
+	*x = Merchant{}
+	mi := &file_chaotic_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Merchant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Merchant) ProtoMessage() {}
+
+func (x *Merchant) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Merchant.ProtoReflect.Descriptor instead.
+func (*Merchant) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Merchant) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Merchant) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type Timing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	IntervalNanos int64                  `protobuf:"varint,2,opt,name=interval_nanos,json=intervalNanos,proto3" json:"interval_nanos,omitempty"`
+	Jitter        float64                `protobuf:"fixed64,3,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	Arrivals      string                 `protobuf:"bytes,4,opt,name=arrivals,proto3" json:"arrivals,omitempty"`
+	HourlyRates   []float64              `protobuf:"fixed64,5,rep,packed,name=hourly_rates,json=hourlyRates,proto3" json:"hourly_rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Timing) Reset() {
+	*x = Timing{}
+	mi := &file_chaotic_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Timing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timing) ProtoMessage() {}
+
+func (x *Timing) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timing.ProtoReflect.Descriptor instead.
+func (*Timing) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Timing) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Timing) GetIntervalNanos() int64 {
+	if x != nil {
+		return x.IntervalNanos
+	}
+	return 0
+}
+
+func (x *Timing) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *Timing) GetArrivals() string {
+	if x != nil {
+		return x.Arrivals
+	}
+	return ""
+}
+
+func (x *Timing) GetHourlyRates() []float64 {
+	if x != nil {
+		return x.HourlyRates
+	}
+	return nil
+}
+
+type Currency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Exponent      int64                  `protobuf:"varint,2,opt,name=exponent,proto3" json:"exponent,omitempty"`
+	Rounding      string                 `protobuf:"bytes,3,opt,name=rounding,proto3" json:"rounding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Currency) Reset() {
+	*x = Currency{}
+	mi := &file_chaotic_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Currency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Currency) ProtoMessage() {}
+
+func (x *Currency) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Currency.ProtoReflect.Descriptor instead.
+func (*Currency) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Currency) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Currency) GetExponent() int64 {
+	if x != nil {
+		return x.Exponent
+	}
+	return 0
+}
+
+func (x *Currency) GetRounding() string {
+	if x != nil {
+		return x.Rounding
+	}
+	return ""
+}
+
+type RegimeWeights struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TrendFollowing float64                `protobuf:"fixed64,1,opt,name=trend_following,json=trendFollowing,proto3" json:"trend_following,omitempty"`
+	MeanReversion  float64                `protobuf:"fixed64,2,opt,name=mean_reversion,json=meanReversion,proto3" json:"mean_reversion,omitempty"`
+	Multiplicative float64                `protobuf:"fixed64,3,opt,name=multiplicative,proto3" json:"multiplicative,omitempty"`
+	AdditiveNoise  float64                `protobuf:"fixed64,4,opt,name=additive_noise,json=additiveNoise,proto3" json:"additive_noise,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RegimeWeights) Reset() {
+	*x = RegimeWeights{}
+	mi := &file_chaotic_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegimeWeights) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegimeWeights) ProtoMessage() {}
+
+func (x *RegimeWeights) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegimeWeights.ProtoReflect.Descriptor instead.
+func (*RegimeWeights) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RegimeWeights) GetTrendFollowing() float64 {
+	if x != nil {
+		return x.TrendFollowing
+	}
+	return 0
+}
+
+func (x *RegimeWeights) GetMeanReversion() float64 {
+	if x != nil {
+		return x.MeanReversion
+	}
+	return 0
+}
+
+func (x *RegimeWeights) GetMultiplicative() float64 {
+	if x != nil {
+		return x.Multiplicative
+	}
+	return 0
+}
+
+func (x *RegimeWeights) GetAdditiveNoise() float64 {
+	if x != nil {
+		return x.AdditiveNoise
+	}
+	return 0
+}
+
+type RunMetadata struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	GeneratedAt      string                 `protobuf:"bytes,1,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	Config           *Config                `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	SequenceLength   int64                  `protobuf:"varint,3,opt,name=sequence_length,json=sequenceLength,proto3" json:"sequence_length,omitempty"`
+	Seed             *int64                 `protobuf:"varint,4,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	GeneratorVersion string                 `protobuf:"bytes,5,opt,name=generator_version,json=generatorVersion,proto3" json:"generator_version,omitempty"`
+	Extended         bool                   `protobuf:"varint,6,opt,name=extended,proto3" json:"extended,omitempty"`
+	FormatVersion    string                 `protobuf:"bytes,7,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	Anomalies        []int64                `protobuf:"varint,8,rep,packed,name=anomalies,proto3" json:"anomalies,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RunMetadata) Reset() {
+	*x = RunMetadata{}
+	mi := &file_chaotic_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunMetadata) ProtoMessage() {}
+
+func (x *RunMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunMetadata.ProtoReflect.Descriptor instead.
+func (*RunMetadata) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RunMetadata) GetGeneratedAt() string {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetSequenceLength() int64 {
+	if x != nil {
+		return x.SequenceLength
+	}
+	return 0
+}
+
+func (x *RunMetadata) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *RunMetadata) GetGeneratorVersion() string {
+	if x != nil {
+		return x.GeneratorVersion
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetExtended() bool {
+	if x != nil {
+		return x.Extended
+	}
+	return false
+}
+
+func (x *RunMetadata) GetFormatVersion() string {
+	if x != nil {
+		return x.FormatVersion
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetAnomalies() []int64 {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+type Sequence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *RunMetadata           `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Statistics    *Statistics            `protobuf:"bytes,2,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	Entries       []*LogEntry            `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sequence) Reset() {
+	*x = Sequence{}
+	mi := &file_chaotic_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sequence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sequence) ProtoMessage() {}
+
+func (x *Sequence) ProtoReflect() protoreflect.Message {
+	mi := &file_chaotic_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sequence.ProtoReflect.Descriptor instead.
+func (*Sequence) Descriptor() ([]byte, []int) {
+	return file_chaotic_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Sequence) GetMetadata() *RunMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Sequence) GetStatistics() *Statistics {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+func (x *Sequence) GetEntries() []*LogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+var File_chaotic_proto protoreflect.FileDescriptor
+
+const file_chaotic_proto_rawDesc = "" +
+	"\n" +
+	"\rchaotic.proto\x12\n" +
+	"chaotic.v1\"\xd5\x06\n" +
+	"\bLogEntry\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\x03R\x04step\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12*\n" +
+	"\x0eenhanced_value\x18\x04 \x01(\x03H\x00R\renhancedValue\x88\x01\x01\x120\n" +
+	"\x11enhancement_delta\x18\x05 \x01(\x03H\x01R\x10enhancementDelta\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"is_outlier\x18\x06 \x01(\bR\tisOutlier\x12&\n" +
+	"\famount_minor\x18\a \x01(\x03H\x02R\vamountMinor\x88\x01\x01\x12\x16\n" +
+	"\x06amount\x18\b \x01(\tR\x06amount\x12\x1c\n" +
+	"\ttimestamp\x18\t \x01(\tR\ttimestamp\x12%\n" +
+	"\x0etransaction_id\x18\n" +
+	" \x01(\tR\rtransactionId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\v \x01(\tR\taccountId\x12\x1a\n" +
+	"\bmerchant\x18\f \x01(\tR\bmerchant\x12\x1c\n" +
+	"\tdirection\x18\r \x01(\tR\tdirection\x12\x1b\n" +
+	"\x06posted\x18\x0e \x01(\x03H\x03R\x06posted\x88\x01\x01\x12\x1d\n" +
+	"\abalance\x18\x0f \x01(\x03H\x04R\abalance\x88\x01\x01\x12\x1a\n" +
+	"\brejected\x18\x10 \x01(\bR\brejected\x12\x16\n" +
+	"\x06regime\x18\x11 \x01(\tR\x06regime\x12\x12\n" +
+	"\x04jump\x18\x12 \x01(\bR\x04jump\x121\n" +
+	"\x14effective_volatility\x18\x13 \x01(\x01R\x13effectiveVolatility\x12\x18\n" +
+	"\aclamped\x18\x14 \x01(\tR\aclamped\x12\x18\n" +
+	"\aanomaly\x18\x15 \x01(\bR\aanomaly\x12!\n" +
+	"\fanomaly_kind\x18\x16 \x01(\tR\vanomalyKind\x12\x18\n" +
+	"\amissing\x18\x17 \x01(\bR\amissing\x12&\n" +
+	"\fduplicate_of\x18\x18 \x01(\x03H\x05R\vduplicateOf\x88\x01\x01B\x11\n" +
+	"\x0f_enhanced_valueB\x14\n" +
+	"\x12_enhancement_deltaB\x0f\n" +
+	"\r_amount_minorB\t\n" +
+	"\a_postedB\n" +
+	"\n" +
+	"\b_balanceB\x0f\n" +
+	"\r_duplicate_of\"\xca\x14\n" +
+	"\n" +
+	"Statistics\x12\x12\n" +
+	"\x04mean\x18\x01 \x01(\x01R\x04mean\x12\x14\n" +
+	"\x05stdev\x18\x03 \x01(\x01R\x05stdev\x12\x1a\n" +
+	"\bvariance\x18\x04 \x01(\x01R\bvariance\x12\x10\n" +
+	"\x03min\x18\x05 \x01(\x03R\x03min\x12\x10\n" +
+	"\x03max\x18\x06 \x01(\x03R\x03max\x12\x14\n" +
+	"\x05count\x18\a \x01(\x03R\x05count\x12=\n" +
+	"\x18coefficient_of_variation\x18\v \x01(\x01H\x00R\x16coefficientOfVariation\x88\x01\x01\x12%\n" +
+	"\x0etrend_strength\x18\f \x01(\x01R\rtrendStrength\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\r \x01(\x01R\n" +
+	"volatility\x12\x1a\n" +
+	"\bskewness\x18\x0e \x01(\x01R\bskewness\x12\x1a\n" +
+	"\bkurtosis\x18\x0f \x01(\x01R\bkurtosis\x121\n" +
+	"\x14lag1_autocorrelation\x18\x10 \x01(\x01R\x13lag1Autocorrelation\x12\x12\n" +
+	"\x04mode\x18\x11 \x01(\x03R\x04mode\x12%\n" +
+	"\x0egeometric_mean\x18\x12 \x01(\x01R\rgeometricMean\x12#\n" +
+	"\rharmonic_mean\x18\x13 \x01(\x01R\fharmonicMean\x12\x10\n" +
+	"\x03mad\x18\x14 \x01(\x01R\x03mad\x12%\n" +
+	"\x0emad_normalized\x18\x15 \x01(\x01R\rmadNormalized\x12%\n" +
+	"\x0ehurst_exponent\x18\x16 \x01(\x01R\rhurstExponent\x12%\n" +
+	"\x0esample_entropy\x18\x17 \x01(\x01R\rsampleEntropy\x12/\n" +
+	"\x13permutation_entropy\x18\x18 \x01(\x01R\x12permutationEntropy\x121\n" +
+	"\x14distribution_entropy\x18\x19 \x01(\x01R\x13distributionEntropy\x12!\n" +
+	"\fmax_drawdown\x18\x1a \x01(\x03R\vmaxDrawdown\x122\n" +
+	"\x15max_drawdown_fraction\x18\x1b \x01(\x01R\x13maxDrawdownFraction\x123\n" +
+	"\x16max_drawdown_peak_step\x18\x1c \x01(\x03R\x13maxDrawdownPeakStep\x127\n" +
+	"\x18max_drawdown_trough_step\x18\x1d \x01(\x03R\x15maxDrawdownTroughStep\x12\x1d\n" +
+	"\n" +
+	"max_drawup\x18\x1e \x01(\x03R\tmaxDrawup\x12.\n" +
+	"\x13max_drawup_fraction\x18\x1f \x01(\x01R\x11maxDrawupFraction\x123\n" +
+	"\x16max_drawup_trough_step\x18  \x01(\x03R\x13maxDrawupTroughStep\x12/\n" +
+	"\x14max_drawup_peak_step\x18! \x01(\x03R\x11maxDrawupPeakStep\x124\n" +
+	"\x16longest_increasing_run\x18\" \x01(\x03R\x14longestIncreasingRun\x12?\n" +
+	"\x1clongest_increasing_run_start\x18# \x01(\x03R\x19longestIncreasingRunStart\x124\n" +
+	"\x16longest_decreasing_run\x18$ \x01(\x03R\x14longestDecreasingRun\x12?\n" +
+	"\x1clongest_decreasing_run_start\x18% \x01(\x03R\x19longestDecreasingRunStart\x12(\n" +
+	"\x10longest_flat_run\x18& \x01(\x03R\x0elongestFlatRun\x123\n" +
+	"\x16longest_flat_run_start\x18' \x01(\x03R\x13longestFlatRunStart\x12%\n" +
+	"\x0eturning_points\x18( \x01(\x03R\rturningPoints\x12%\n" +
+	"\x0emean_crossings\x18) \x01(\x03R\rmeanCrossings\x12\x1f\n" +
+	"\vreturn_mean\x18* \x01(\x01R\n" +
+	"returnMean\x12+\n" +
+	"\x11return_volatility\x18+ \x01(\x01R\x10returnVolatility\x123\n" +
+	"\thistogram\x18, \x01(\v2\x15.chaotic.v1.HistogramR\thistogram\x12#\n" +
+	"\routlier_count\x18- \x01(\x03R\foutlierCount\x12\"\n" +
+	"\fchangepoints\x18. \x03(\x03R\fchangepoints\x123\n" +
+	"\aby_type\x18/ \x03(\v2\x1a.chaotic.v1.TypeStatisticsR\x06byType\x12<\n" +
+	"\fstationarity\x180 \x01(\v2\x18.chaotic.v1.StationarityR\fstationarity\x12\x0e\n" +
+	"\x02q1\x181 \x01(\x01R\x02q1\x12\x0e\n" +
+	"\x02q3\x182 \x01(\x01R\x02q3\x12\x10\n" +
+	"\x03iqr\x183 \x01(\x01R\x03iqr\x12\x16\n" +
+	"\x06median\x184 \x01(\x01R\x06median\x12'\n" +
+	"\x05money\x185 \x01(\v2\x11.chaotic.v1.MoneyR\x05money\x12\x1a\n" +
+	"\bduration\x186 \x01(\x01R\bduration\x12*\n" +
+	"\x11events_per_second\x187 \x01(\x01R\x0feventsPerSecond\x120\n" +
+	"\barrivals\x188 \x01(\v2\x14.chaotic.v1.ArrivalsR\barrivals\x127\n" +
+	"\n" +
+	"by_account\x189 \x03(\v2\x18.chaotic.v1.AccountCountR\tbyAccount\x12#\n" +
+	"\rfinal_balance\x18: \x01(\x03R\ffinalBalance\x12\x1f\n" +
+	"\vmin_balance\x18; \x01(\x03R\n" +
+	"minBalance\x12\x1f\n" +
+	"\vdebit_ratio\x18< \x01(\x01R\n" +
+	"debitRatio\x129\n" +
+	"\tby_regime\x18= \x03(\v2\x1c.chaotic.v1.RegimeStatisticsR\bbyRegime\x12\x1d\n" +
+	"\n" +
+	"jump_count\x18> \x01(\x03R\tjumpCount\x121\n" +
+	"\x14diffusion_volatility\x18? \x01(\x01R\x13diffusionVolatility\x12,\n" +
+	"\x0ftarget_distance\x18@ \x01(\x01H\x01R\x0etargetDistance\x88\x01\x01\x12\x1f\n" +
+	"\vclamped_low\x18A \x01(\x03R\n" +
+	"clampedLow\x12!\n" +
+	"\fclamped_high\x18B \x01(\x03R\vclampedHigh\x12\x1d\n" +
+	"\n" +
+	"clamp_rate\x18C \x01(\x01R\tclampRate\x12\x18\n" +
+	"\amissing\x18D \x01(\x03R\amissing\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18E \x01(\x03R\n" +
+	"duplicatesB\x1b\n" +
+	"\x19_coefficient_of_variationB\x12\n" +
+	"\x10_target_distanceJ\x04\b\x02\x10\x03J\x04\b\b\x10\tJ\x04\b\t\x10\n" +
+	"J\x04\b\n" +
+	"\x10\v\">\n" +
+	"\fAccountCount\x12\x18\n" +
+	"\aaccount\x18\x01 \x01(\tR\aaccount\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\x97\x01\n" +
+	"\x0eTypeStatistics\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1d\n" +
+	"\n" +
+	"mean_value\x18\x03 \x01(\x01R\tmeanValue\x12&\n" +
+	"\x0fmean_abs_change\x18\x04 \x01(\x01R\rmeanAbsChange\x12\x14\n" +
+	"\x05share\x18\x05 \x01(\x01R\x05share\"v\n" +
+	"\x10RegimeStatistics\x12\x16\n" +
+	"\x06regime\x18\x01 \x01(\tR\x06regime\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x14\n" +
+	"\x05share\x18\x03 \x01(\x01R\x05share\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\x04 \x01(\x01R\n" +
+	"volatility\"\xd5\x01\n" +
+	"\fStationarity\x12\x15\n" +
+	"\x06mean_t\x18\x01 \x01(\x01R\x05meanT\x12 \n" +
+	"\fmean_p_value\x18\x02 \x01(\x01R\n" +
+	"meanPValue\x12\x1d\n" +
+	"\n" +
+	"variance_f\x18\x03 \x01(\x01R\tvarianceF\x12(\n" +
+	"\x10variance_p_value\x18\x04 \x01(\x01R\x0evariancePValue\x12#\n" +
+	"\radf_statistic\x18\x05 \x01(\x01R\fadfStatistic\x12\x1e\n" +
+	"\n" +
+	"stationary\x18\x06 \x01(\bR\n" +
+	"stationary\"\x9f\x01\n" +
+	"\x05Money\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\tR\x05total\x12\x12\n" +
+	"\x04mean\x18\x03 \x01(\tR\x04mean\x12\x16\n" +
+	"\x06median\x18\x04 \x01(\tR\x06median\x12\x14\n" +
+	"\x05stdev\x18\x05 \x01(\tR\x05stdev\x12\x10\n" +
+	"\x03min\x18\x06 \x01(\tR\x03min\x12\x10\n" +
+	"\x03max\x18\a \x01(\tR\x03max\"\xca\x01\n" +
+	"\bArrivals\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12,\n" +
+	"\x12inter_arrival_mean\x18\x02 \x01(\x01R\x10interArrivalMean\x12.\n" +
+	"\x13inter_arrival_stdev\x18\x03 \x01(\x01R\x11interArrivalStdev\x12#\n" +
+	"\rexpected_mean\x18\x04 \x01(\x01R\fexpectedMean\x12%\n" +
+	"\x0erate_deviation\x18\x05 \x01(\x01R\rrateDeviation\"[\n" +
+	"\tHistogram\x12\x14\n" +
+	"\x05edges\x18\x01 \x03(\x01R\x05edges\x12\x16\n" +
+	"\x06counts\x18\x02 \x03(\x03R\x06counts\x12 \n" +
+	"\vfrequencies\x18\x03 \x03(\x01R\vfrequencies\"\xd6\t\n" +
+	"\x06Config\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\x01 \x01(\x01R\n" +
+	"volatility\x12%\n" +
+	"\x0etrend_strength\x18\x02 \x01(\x01R\rtrendStrength\x12%\n" +
+	"\x0emean_reversion\x18\x03 \x01(\x01R\rmeanReversion\x12\x1b\n" +
+	"\tmin_value\x18\x04 \x01(\x03R\bminValue\x12\x1b\n" +
+	"\tmax_value\x18\x05 \x01(\x03R\bmaxValue\x12\x17\n" +
+	"\x04seed\x18\x06 \x01(\x03H\x00R\x04seed\x88\x01\x01\x12'\n" +
+	"\x0frandomness_mode\x18\a \x01(\tR\x0erandomnessMode\x12@\n" +
+	"\x0eregime_weights\x18\b \x01(\v2\x19.chaotic.v1.RegimeWeightsR\rregimeWeights\x125\n" +
+	"\x16multiplicative_factors\x18\t \x03(\x01R\x15multiplicativeFactors\x12'\n" +
+	"\x0fnoise_amplitude\x18\n" +
+	" \x01(\x03R\x0enoiseAmplitude\x120\n" +
+	"\bcurrency\x18\v \x01(\v2\x14.chaotic.v1.CurrencyR\bcurrency\x12*\n" +
+	"\x06timing\x18\f \x01(\v2\x12.chaotic.v1.TimingR\x06timing\x120\n" +
+	"\bentities\x18\r \x01(\v2\x14.chaotic.v1.EntitiesR\bentities\x12*\n" +
+	"\x06ledger\x18\x0e \x01(\v2\x12.chaotic.v1.LedgerR\x06ledger\x125\n" +
+	"\aregimes\x18\x0f \x01(\v2\x1b.chaotic.v1.RegimeSwitchingR\aregimes\x12'\n" +
+	"\x05jumps\x18\x10 \x01(\v2\x11.chaotic.v1.JumpsR\x05jumps\x12'\n" +
+	"\x05garch\x18\x11 \x01(\v2\x11.chaotic.v1.GarchR\x05garch\x127\n" +
+	"\x15mean_reversion_target\x18\x12 \x01(\x03H\x01R\x13meanReversionTarget\x88\x01\x01\x12'\n" +
+	"\x0freversion_speed\x18\x13 \x01(\x01R\x0ereversionSpeed\x12\x14\n" +
+	"\x05model\x18\x14 \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"logistic_r\x18\x15 \x01(\x01R\tlogisticR\x12\x1a\n" +
+	"\x06map_x0\x18\x16 \x01(\x01H\x02R\x05mapX0\x88\x01\x01\x12\x17\n" +
+	"\atent_mu\x18\x17 \x01(\x01R\x06tentMu\x12\x17\n" +
+	"\ahenon_a\x18\x18 \x01(\x01R\x06henonA\x12\x17\n" +
+	"\ahenon_b\x18\x19 \x01(\x01R\x06henonB\x12\x15\n" +
+	"\x06map_y0\x18\x1a \x01(\x01R\x05mapY0\x12*\n" +
+	"\x06lorenz\x18\x1b \x01(\v2\x12.chaotic.v1.LorenzR\x06lorenz\x12#\n" +
+	"\rboundary_mode\x18\x1c \x01(\tR\fboundaryMode\x123\n" +
+	"\tanomalies\x18\x1d \x01(\v2\x15.chaotic.v1.AnomaliesR\tanomalies\x122\n" +
+	"\x06faults\x18\x1e \x01(\v2\x1a.chaotic.v1.DeliveryFaultsR\x06faultsB\a\n" +
+	"\x05_seedB\x18\n" +
+	"\x16_mean_reversion_targetB\t\n" +
+	"\a_map_x0\"\xaf\x01\n" +
+	"\x06Lorenz\x12\x14\n" +
+	"\x05sigma\x18\x01 \x01(\x01R\x05sigma\x12\x10\n" +
+	"\x03rho\x18\x02 \x01(\x01R\x03rho\x12\x12\n" +
+	"\x04beta\x18\x03 \x01(\x01R\x04beta\x12\x0e\n" +
+	"\x02dt\x18\x04 \x01(\x01R\x02dt\x12\x16\n" +
+	"\x06stride\x18\x05 \x01(\x03R\x06stride\x12\x12\n" +
+	"\x04axis\x18\x06 \x01(\tR\x04axis\x12\x17\n" +
+	"\aburn_in\x18\a \x01(\x03R\x06burnIn\x12\x14\n" +
+	"\x05start\x18\b \x03(\x01R\x05start\"G\n" +
+	"\x05Garch\x12\x14\n" +
+	"\x05omega\x18\x01 \x01(\x01R\x05omega\x12\x14\n" +
+	"\x05alpha\x18\x02 \x01(\x01R\x05alpha\x12\x12\n" +
+	"\x04beta\x18\x03 \x01(\x01R\x04beta\"i\n" +
+	"\x05Jumps\x12 \n" +
+	"\vprobability\x18\x01 \x01(\x01R\vprobability\x12\x12\n" +
+	"\x04mean\x18\x02 \x01(\x01R\x04mean\x12\x14\n" +
+	"\x05stdev\x18\x03 \x01(\x01R\x05stdev\x12\x14\n" +
+	"\x05sizes\x18\x04 \x03(\x01R\x05sizes\"T\n" +
+	"\x0eDeliveryFaults\x12\x1b\n" +
+	"\tdrop_rate\x18\x01 \x01(\x01R\bdropRate\x12%\n" +
+	"\x0eduplicate_rate\x18\x02 \x01(\x01R\rduplicateRate\"\x94\x01\n" +
+	"\tAnomalies\x12\x12\n" +
+	"\x04rate\x18\x01 \x01(\x01R\x04rate\x12\x1e\n" +
+	"\n" +
+	"multiplier\x18\x02 \x01(\x01R\n" +
+	"multiplier\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x12\x1a\n" +
+	"\bduration\x18\x04 \x01(\x03R\bduration\x12!\n" +
+	"\flevel_shifts\x18\x05 \x01(\x01R\vlevelShifts\"\x84\x01\n" +
+	"\x0fRegimeSwitching\x124\n" +
+	"\x06states\x18\x01 \x03(\v2\x1c.chaotic.v1.VolatilityRegimeR\x06states\x12;\n" +
+	"\vtransitions\x18\x02 \x03(\v2\x19.chaotic.v1.TransitionRowR\vtransitions\"\x94\x01\n" +
+	"\x10VolatilityRegime\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\x02 \x01(\x01R\n" +
+	"volatility\x12%\n" +
+	"\x0etrend_strength\x18\x03 \x01(\x01R\rtrendStrength\x12%\n" +
+	"\x0emean_reversion\x18\x04 \x01(\x01R\rmeanReversion\"5\n" +
+	"\rTransitionRow\x12$\n" +
+	"\rprobabilities\x18\x01 \x03(\x01R\rprobabilities\"\xa6\x01\n" +
+	"\x06Ledger\x12'\n" +
+	"\x0fopening_balance\x18\x01 \x01(\x03R\x0eopeningBalance\x12+\n" +
+	"\x11debit_probability\x18\x02 \x01(\x01R\x10debitProbability\x12\x19\n" +
+	"\x05floor\x18\x03 \x01(\x03H\x00R\x05floor\x88\x01\x01\x12!\n" +
+	"\ffloor_policy\x18\x04 \x01(\tR\vfloorPolicyB\b\n" +
+	"\x06_floor\"Z\n" +
+	"\bEntities\x12\x1a\n" +
+	"\baccounts\x18\x01 \x01(\x03R\baccounts\x122\n" +
+	"\tmerchants\x18\x02 \x03(\v2\x14.chaotic.v1.MerchantR\tmerchants\"8\n" +
+	"\bMerchant\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x01R\x06weight\"\x9c\x01\n" +
+	"\x06Timing\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12%\n" +
+	"\x0einterval_nanos\x18\x02 \x01(\x03R\rintervalNanos\x12\x16\n" +
+	"\x06jitter\x18\x03 \x01(\x01R\x06jitter\x12\x1a\n" +
+	"\barrivals\x18\x04 \x01(\tR\barrivals\x12!\n" +
+	"\fhourly_rates\x18\x05 \x03(\x01R\vhourlyRates\"V\n" +
+	"\bCurrency\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1a\n" +
+	"\bexponent\x18\x02 \x01(\x03R\bexponent\x12\x1a\n" +
+	"\brounding\x18\x03 \x01(\tR\brounding\"\xae\x01\n" +
+	"\rRegimeWeights\x12'\n" +
+	"\x0ftrend_following\x18\x01 \x01(\x01R\x0etrendFollowing\x12%\n" +
+	"\x0emean_reversion\x18\x02 \x01(\x01R\rmeanReversion\x12&\n" +
+	"\x0emultiplicative\x18\x03 \x01(\x01R\x0emultiplicative\x12%\n" +
+	"\x0eadditive_noise\x18\x04 \x01(\x01R\radditiveNoise\"\xb5\x02\n" +
+	"\vRunMetadata\x12!\n" +
+	"\fgenerated_at\x18\x01 \x01(\tR\vgeneratedAt\x12*\n" +
+	"\x06config\x18\x02 \x01(\v2\x12.chaotic.v1.ConfigR\x06config\x12'\n" +
+	"\x0fsequence_length\x18\x03 \x01(\x03R\x0esequenceLength\x12\x17\n" +
+	"\x04seed\x18\x04 \x01(\x03H\x00R\x04seed\x88\x01\x01\x12+\n" +
+	"\x11generator_version\x18\x05 \x01(\tR\x10generatorVersion\x12\x1a\n" +
+	"\bextended\x18\x06 \x01(\bR\bextended\x12%\n" +
+	"\x0eformat_version\x18\a \x01(\tR\rformatVersion\x12\x1c\n" +
+	"\tanomalies\x18\b \x03(\x03R\tanomaliesB\a\n" +
+	"\x05_seed\"\xa7\x01\n" +
+	"\bSequence\x123\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x17.chaotic.v1.RunMetadataR\bmetadata\x126\n" +
+	"\n" +
+	"statistics\x18\x02 \x01(\v2\x16.chaotic.v1.StatisticsR\n" +
+	"statistics\x12.\n" +
+	"\aentries\x18\x03 \x03(\v2\x14.chaotic.v1.LogEntryR\aentriesB7Z5github.com/AScotM/chaotic_sequencer/chaotic/chaoticpbb\x06proto3"
+
+var (
+	file_chaotic_proto_rawDescOnce sync.Once
+	file_chaotic_proto_rawDescData []byte
+)
+
+func file_chaotic_proto_rawDescGZIP() []byte {
+	file_chaotic_proto_rawDescOnce.Do(func() {
+		file_chaotic_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_chaotic_proto_rawDesc), len(file_chaotic_proto_rawDesc)))
+	})
+	return file_chaotic_proto_rawDescData
+}
+
+var file_chaotic_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_chaotic_proto_goTypes = []any{
+	(*LogEntry)(nil),         // 0: chaotic.v1.LogEntry
+	(*Statistics)(nil),       // 1: chaotic.v1.Statistics
+	(*AccountCount)(nil),     // 2: chaotic.v1.AccountCount
+	(*TypeStatistics)(nil),   // 3: chaotic.v1.TypeStatistics
+	(*RegimeStatistics)(nil), // 4: chaotic.v1.RegimeStatistics
+	(*Stationarity)(nil),     // 5: chaotic.v1.Stationarity
+	(*Money)(nil),            // 6: chaotic.v1.Money
+	(*Arrivals)(nil),         // 7: chaotic.v1.Arrivals
+	(*Histogram)(nil),        // 8: chaotic.v1.Histogram
+	(*Config)(nil),           // 9: chaotic.v1.Config
+	(*Lorenz)(nil),           // 10: chaotic.v1.Lorenz
+	(*Garch)(nil),            // 11: chaotic.v1.Garch
+	(*Jumps)(nil),            // 12: chaotic.v1.Jumps
+	(*DeliveryFaults)(nil),   // 13: chaotic.v1.DeliveryFaults
+	(*Anomalies)(nil),        // 14: chaotic.v1.Anomalies
+	(*RegimeSwitching)(nil),  // 15: chaotic.v1.RegimeSwitching
+	(*VolatilityRegime)(nil), // 16: chaotic.v1.VolatilityRegime
+	(*TransitionRow)(nil),    // 17: chaotic.v1.TransitionRow
+	(*Ledger)(nil),           // 18: chaotic.v1.Ledger
+	(*Entities)(nil),         // 19: chaotic.v1.Entities
+	(*Merchant)(nil),         // 20: chaotic.v1.Merchant
+	(*Timing)(nil),           // 21: chaotic.v1.Timing
+	(*Currency)(nil),         // 22: chaotic.v1.Currency
+	(*RegimeWeights)(nil),    // 23: chaotic.v1.RegimeWeights
+	(*RunMetadata)(nil),      // 24: chaotic.v1.RunMetadata
+	(*Sequence)(nil),         // 25: chaotic.v1.Sequence
+}
+var file_chaotic_proto_depIdxs = []int32{
+	8,  // 0: chaotic.v1.Statistics.histogram:type_name -> chaotic.v1.Histogram
+	3,  // 1: chaotic.v1.Statistics.by_type:type_name -> chaotic.v1.TypeStatistics
+	5,  // 2: chaotic.v1.Statistics.stationarity:type_name -> chaotic.v1.Stationarity
+	6,  // 3: chaotic.v1.Statistics.money:type_name -> chaotic.v1.Money
+	7,  // 4: chaotic.v1.Statistics.arrivals:type_name -> chaotic.v1.Arrivals
+	2,  // 5: chaotic.v1.Statistics.by_account:type_name -> chaotic.v1.AccountCount
+	4,  // 6: chaotic.v1.Statistics.by_regime:type_name -> chaotic.v1.RegimeStatistics
+	23, // 7: chaotic.v1.Config.regime_weights:type_name -> chaotic.v1.RegimeWeights
+	22, // 8: chaotic.v1.Config.currency:type_name -> chaotic.v1.Currency
+	21, // 9: chaotic.v1.Config.timing:type_name -> chaotic.v1.Timing
+	19, // 10: chaotic.v1.Config.entities:type_name -> chaotic.v1.Entities
+	18, // 11: chaotic.v1.Config.ledger:type_name -> chaotic.v1.Ledger
+	15, // 12: chaotic.v1.Config.regimes:type_name -> chaotic.v1.RegimeSwitching
+	12, // 13: chaotic.v1.Config.jumps:type_name -> chaotic.v1.Jumps
+	11, // 14: chaotic.v1.Config.garch:type_name -> chaotic.v1.Garch
+	10, // 15: chaotic.v1.Config.lorenz:type_name -> chaotic.v1.Lorenz
+	14, // 16: chaotic.v1.Config.anomalies:type_name -> chaotic.v1.Anomalies
+	13, // 17: chaotic.v1.Config.faults:type_name -> chaotic.v1.DeliveryFaults
+	16, // 18: chaotic.v1.RegimeSwitching.states:type_name -> chaotic.v1.VolatilityRegime
+	17, // 19: chaotic.v1.RegimeSwitching.transitions:type_name -> chaotic.v1.TransitionRow
+	20, // 20: chaotic.v1.Entities.merchants:type_name -> chaotic.v1.Merchant
+	9,  // 21: chaotic.v1.RunMetadata.config:type_name -> chaotic.v1.Config
+	24, // 22: chaotic.v1.Sequence.metadata:type_name -> chaotic.v1.RunMetadata
+	1,  // 23: chaotic.v1.Sequence.statistics:type_name -> chaotic.v1.Statistics
+	0,  // 24: chaotic.v1.Sequence.entries:type_name -> chaotic.v1.LogEntry
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_chaotic_proto_init() }
+func file_chaotic_proto_init() {
+	if File_chaotic_proto != nil {
+		return
+	}
+	file_chaotic_proto_msgTypes[0].OneofWrappers = []any{}
+	file_chaotic_proto_msgTypes[1].OneofWrappers = []any{}
+	file_chaotic_proto_msgTypes[9].OneofWrappers = []any{}
+	file_chaotic_proto_msgTypes[18].OneofWrappers = []any{}
+	file_chaotic_proto_msgTypes[24].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chaotic_proto_rawDesc), len(file_chaotic_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   26,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_chaotic_proto_goTypes,
+		DependencyIndexes: file_chaotic_proto_depIdxs,
+		MessageInfos:      file_chaotic_proto_msgTypes,
+	}.Build()
+	File_chaotic_proto = out.File
+	file_chaotic_proto_goTypes = nil
+	file_chaotic_proto_depIdxs = nil
+}
@@ -0,0 +1,6 @@
+// Package chaoticpb holds the protobuf wire schema for chaotic sequences,
+// generated from chaotic.proto. Convert to and from the chaotic package types
+// with their ToProto and FromProto methods.
+package chaoticpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative chaotic.proto
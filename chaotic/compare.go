@@ -0,0 +1,168 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// headlineStatKeys are the statistics CompareSequences reports differences in
+var headlineStatKeys = []string{"mean", "stdev", "volatility", "trend_strength"}
+
+// Comparison is the outcome of CompareSequences. Point-by-point measures
+// cover the first Compared entries of both sequences; the headline
+// differences are B's statistic minus A's, each over its whole sequence.
+type Comparison struct {
+	LengthA   int  `json:"length_a"`
+	LengthB   int  `json:"length_b"`
+	Compared  int  `json:"compared"`  // length of the common prefix
+	Truncated bool `json:"truncated"` // the lengths differ, so the longer tail was ignored
+
+	// Correlation is the Pearson correlation of the values, or 0 when either
+	// prefix is constant and it is undefined
+	Correlation float64 `json:"correlation"`
+	RMSE        float64 `json:"rmse"`
+	MAE         float64 `json:"mae"`
+
+	MeanDiff          float64 `json:"mean_diff"`
+	StdevDiff         float64 `json:"stdev_diff"`
+	VolatilityDiff    float64 `json:"volatility_diff"`
+	TrendStrengthDiff float64 `json:"trend_strength_diff"`
+}
+
+// CompareSequences measures how closely b follows a, typically two runs
+// generated with slightly different configurations. Sequences of different
+// lengths are compared over their common prefix and the result is marked
+// Truncated. Both sequences must be non-empty.
+func CompareSequences(a, b []LogEntry) (Comparison, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return Comparison{}, errors.New("cannot compare an empty sequence")
+	}
+
+	n := min(len(a), len(b))
+	result := Comparison{
+		LengthA:   len(a),
+		LengthB:   len(b),
+		Compared:  n,
+		Truncated: len(a) != len(b),
+	}
+
+	var meanA, meanB float64
+	for i := 0; i < n; i++ {
+		meanA += float64(a[i].Value)
+		meanB += float64(b[i].Value)
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+
+	var sumSquares, sumAbs, covariance, varianceA, varianceB float64
+	for i := 0; i < n; i++ {
+		x, y := float64(a[i].Value), float64(b[i].Value)
+		diff := y - x
+		sumSquares += diff * diff
+		sumAbs += math.Abs(diff)
+		covariance += (x - meanA) * (y - meanB)
+		varianceA += (x - meanA) * (x - meanA)
+		varianceB += (y - meanB) * (y - meanB)
+	}
+	result.RMSE = math.Sqrt(sumSquares / float64(n))
+	result.MAE = sumAbs / float64(n)
+	if varianceA > 0 && varianceB > 0 {
+		result.Correlation = covariance / math.Sqrt(varianceA*varianceB)
+	}
+
+	statsA, err := ComputeStatisticsFor(a, headlineStatKeys...)
+	if err != nil {
+		return Comparison{}, fmt.Errorf("first sequence: %w", err)
+	}
+	statsB, err := ComputeStatisticsFor(b, headlineStatKeys...)
+	if err != nil {
+		return Comparison{}, fmt.Errorf("second sequence: %w", err)
+	}
+	result.MeanDiff = statsB.Mean - statsA.Mean
+	result.StdevDiff = statsB.Stdev - statsA.Stdev
+	result.VolatilityDiff = statsB.Volatility - statsA.Volatility
+	result.TrendStrengthDiff = statsB.TrendStrength - statsA.TrendStrength
+	return result, nil
+}
+
+// Pair is one step of a warping path, matching a[I] with b[J]
+type Pair struct {
+	I int `json:"i"`
+	J int `json:"j"`
+}
+
+// DTWDistance returns the dynamic time warping distance between a and b,
+// the smallest total |a[i] - b[j]| over warping paths that run from the
+// first entries of both to their last, and that path. Unlike RMSE it
+// forgives a shift in time, so a delayed copy of a sequence is close to the
+// original.
+//
+// A positive window restricts the path to a Sakoe-Chiba band, |i - j| <=
+// window, which keeps long inputs tractable: only the band is stored,
+// taking memory proportional to min(len(a), len(b)) * window. The band is
+// widened to the difference in lengths when that is larger, since no path
+// fits otherwise. A window of 0 searches the full matrix.
+func DTWDistance(a, b []int, window int) (float64, []Pair, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return 0, nil, errors.New("cannot compare an empty sequence")
+	}
+	if window < 0 {
+		return 0, nil, fmt.Errorf("window must not be negative, got %d", window)
+	}
+
+	// Rows run along the shorter sequence so the band is as small as it can be
+	rowValues, colValues, swapped := a, b, false
+	if len(b) < len(a) {
+		rowValues, colValues, swapped = b, a, true
+	}
+	n, m := len(rowValues), len(colValues)
+	if window == 0 || window > m {
+		window = m
+	}
+	window = max(window, m-n)
+
+	// cost[i] holds the accumulated costs of row i for columns lo(i) to hi(i)
+	lo := func(i int) int { return max(0, i-window) }
+	hi := func(i int) int { return min(m-1, i+window) }
+	cost := make([][]float64, n)
+	at := func(i, j int) float64 {
+		if i < 0 || j < lo(i) || j > hi(i) {
+			return math.Inf(1)
+		}
+		return cost[i][j-lo(i)]
+	}
+	for i := range cost {
+		cost[i] = make([]float64, hi(i)-lo(i)+1)
+		for j := lo(i); j <= hi(i); j++ {
+			step := math.Abs(float64(rowValues[i]) - float64(colValues[j]))
+			if i > 0 || j > 0 {
+				step += min(at(i-1, j-1), at(i-1, j), at(i, j-1))
+			}
+			cost[i][j-lo(i)] = step
+		}
+	}
+
+	// Walk back from the end, preferring the diagonal on ties
+	path := []Pair{{n - 1, m - 1}}
+	for i, j := n-1, m-1; i > 0 || j > 0; {
+		switch diagonal, up, left := at(i-1, j-1), at(i-1, j), at(i, j-1); {
+		case diagonal <= up && diagonal <= left:
+			i, j = i-1, j-1
+		case up <= left:
+			i--
+		default:
+			j--
+		}
+		path = append(path, Pair{i, j})
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	if swapped {
+		for k := range path {
+			path[k].I, path[k].J = path[k].J, path[k].I
+		}
+	}
+	return at(n-1, m-1), path, nil
+}
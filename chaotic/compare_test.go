@@ -0,0 +1,129 @@
+package chaotic
+
+import "testing"
+
+// checkWarpingPath fails t unless path runs from the start of a and b to
+// their ends in unit steps
+func checkWarpingPath(t *testing.T, path []Pair, n, m int) {
+	t.Helper()
+	if len(path) == 0 || path[0] != (Pair{0, 0}) || path[len(path)-1] != (Pair{n - 1, m - 1}) {
+		t.Fatalf("path does not run from (0, 0) to (%d, %d)", n-1, m-1)
+	}
+	for k := 1; k < len(path); k++ {
+		di, dj := path[k].I-path[k-1].I, path[k].J-path[k-1].J
+		if di < 0 || dj < 0 || di > 1 || dj > 1 || di+dj == 0 {
+			t.Fatalf("path steps from %v to %v", path[k-1], path[k])
+		}
+	}
+}
+
+func TestDTWDistanceOfDelayedCopy(t *testing.T) {
+	seed := int64(12)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(500, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := valuesOf(sequence)
+	// The same values, five steps late
+	b := append([]int{a[0], a[0], a[0], a[0], a[0]}, a...)
+
+	for _, window := range []int{0, 10} {
+		distance, path, err := DTWDistance(a, b, window)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if distance != 0 {
+			t.Errorf("window %d: DTW distance %v of a delayed copy, want 0", window, distance)
+		}
+		checkWarpingPath(t, path, len(a), len(b))
+		for _, p := range path {
+			if a[p.I] != b[p.J] {
+				t.Fatalf("window %d: the path matches a[%d] = %d with b[%d] = %d", window, p.I, a[p.I], p.J, b[p.J])
+			}
+		}
+	}
+
+	comparison, err := CompareSequences(entriesOf(a...), entriesOf(b...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.RMSE < 100 {
+		t.Errorf("RMSE %v of the delayed copy, want it large", comparison.RMSE)
+	}
+}
+
+func TestDTWDistanceOfShiftedSinusoid(t *testing.T) {
+	// Equal lengths, so the five values shifted off the end cost a little
+	a := sinusoid(1000, 50, 300)
+	b := make([]int, len(a))
+	for i := range b {
+		b[i] = a[max(0, i-5)]
+	}
+	distance, path, err := DTWDistance(a, b, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWarpingPath(t, path, len(a), len(b))
+	comparison, err := CompareSequences(entriesOf(a...), entriesOf(b...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perStep := distance / float64(len(a)); perStep > comparison.RMSE/20 {
+		t.Errorf("DTW distance %.1f per step, RMSE %.1f; want DTW near zero", perStep, comparison.RMSE)
+	}
+
+	// A band narrower than the shift cannot line the copies up
+	narrow, _, err := DTWDistance(a, b, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if narrow <= distance {
+		t.Errorf("window 2 gives %v, no more than window 10's %v", narrow, distance)
+	}
+}
+
+func TestDTWDistanceByHand(t *testing.T) {
+	tests := []struct {
+		a, b []int
+		want float64
+	}{
+		{[]int{1, 2, 3}, []int{1, 1, 2, 3}, 0},
+		{[]int{0, 0}, []int{1}, 2},
+		{[]int{1, 5, 2}, []int{1, 2}, 3},
+		{[]int{7}, []int{7}, 0},
+	}
+	for _, tt := range tests {
+		for _, pair := range [][2][]int{{tt.a, tt.b}, {tt.b, tt.a}} {
+			distance, path, err := DTWDistance(pair[0], pair[1], 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if distance != tt.want {
+				t.Errorf("DTW(%v, %v) = %v, want %v", pair[0], pair[1], distance, tt.want)
+			}
+			checkWarpingPath(t, path, len(pair[0]), len(pair[1]))
+		}
+	}
+	if _, _, err := DTWDistance(nil, []int{1}, 0); err == nil {
+		t.Error("an empty sequence succeeded")
+	}
+	if _, _, err := DTWDistance([]int{1}, []int{1}, -1); err == nil {
+		t.Error("a negative window succeeded")
+	}
+}
+
+func TestDTWDistanceOfLongSequences(t *testing.T) {
+	// A narrow band over tens of thousands of steps stores only the band
+	a := sinusoid(30000, 200, 300)
+	b := append([]int{a[0], a[0], a[0]}, a...)
+	distance, path, err := DTWDistance(a, b, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance != 0 {
+		t.Errorf("DTW distance %v, want 0", distance)
+	}
+	checkWarpingPath(t, path, len(a), len(b))
+}
@@ -0,0 +1,338 @@
+// Package chaotic generates chaotic transaction sequences and computes
+// statistics over them.
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ChaoticConfig holds configuration for chaotic sequence generation
+type ChaoticConfig struct {
+	// Model selects the process behind the values; empty means
+	// ModelBranching. The map models iterate a chaotic map from MapX0 and
+	// scale each x onto the range, ignoring the step dynamics below, Garch,
+	// Jumps and the mean-reversion target; the layers from Currency to
+	// Ledger still apply. ModelLogistic iterates
+	// x(n+1) = LogisticR·x(n)·(1-x(n)), ModelTent x(n+1) = TentMu·min(x(n), 1-x(n)),
+	// both within [0, 1], and ModelHenon
+	// x(n+1) = 1 - HenonA·x(n)² + y(n), y(n+1) = HenonB·x(n), scaling x from
+	// [-1.5, 1.5]. ModelLorenz samples the flow set by Lorenz instead of a
+	// map and ignores the same settings.
+	Model     Model    `json:",omitempty"`
+	LogisticR float64  `json:",omitempty"` // (0, 4] - the growth rate of the logistic map; chaotic from about 3.57
+	TentMu    float64  `json:",omitempty"` // (0, 2] - the slope of the tent map; at exactly 2 rounding collapses the orbit to 0 within about 50 steps
+	HenonA    float64  `json:",omitempty"` // 0 for DefaultHenonA
+	HenonB    float64  `json:",omitempty"` // 0 for DefaultHenonB
+	MapX0     *float64 `json:",omitempty"` // the first x, in (0, 1) except for Hénon; nil draws it from [0, 1) with the source
+	MapY0     float64  `json:",omitempty"` // the first y of the Hénon map
+	Lorenz    *Lorenz  `json:",omitempty"` // the system of ModelLorenz; nil for DefaultLorenz()
+
+	Volatility     float64 // 0.0 to 1.0 - how chaotic the sequence is
+	TrendStrength  float64 // 0.0 to 1.0 - tendency to follow trends
+	MeanReversion  float64 // 0.0 to 1.0 - tendency to revert to mean
+	MinValue       int
+	MaxValue       int
+	Seed           *int64         `json:",omitempty"` // deterministic seed; nil means RandomnessMode applies
+	Source         RandSource     `json:"-"`          // explicit random source, takes precedence over Seed
+	RandomnessMode RandomnessMode `json:",omitempty"` // secure (default) or fast
+	BoundaryMode   BoundaryMode   `json:",omitempty"` // how values that leave the range are brought back; empty means clamp
+	RegimeWeights  *RegimeWeights `json:",omitempty"` // how often each step type is picked; nil means equally often
+	StepGenerators []WeightedStep `json:"-"`          // step rules picked alongside the built-in ones; like Source, not serialized
+	OnStep         StepHook       `json:"-"`          // observes or changes each entry before it is committed; like Source, not serialized
+
+	MultiplicativeFactors []float64 // the multiplicative step scales the value by one of these, picked uniformly
+	NoiseAmplitude        int       // additive noise and the second step's random walk are drawn from ±NoiseAmplitude
+
+	// MeanReversionTarget makes mean-reversion steps pull toward a fixed
+	// level, as in a discretized Ornstein–Uhlenbeck process, instead of
+	// toward the running mean; nil keeps the running mean. It must lie
+	// within the range.
+	MeanReversionTarget *int    `json:",omitempty"`
+	ReversionSpeed      float64 `json:",omitempty"` // 0.0 to 1.0 - with a target, the fraction of the distance to it a step closes, replacing MeanReversion
+
+	Currency *Currency `json:",omitempty"` // makes values amounts in minor units; nil for plain integers
+	Timing   *Timing   `json:",omitempty"` // gives entries timestamps; nil leaves them untimed
+	Entities *Entities `json:",omitempty"` // gives entries transaction IDs, accounts and merchants
+	Ledger   *Ledger   `json:",omitempty"` // posts entries as credits and debits to a running balance
+
+	Regimes *RegimeSwitching `json:",omitempty"` // switches the three dynamics above between regimes; nil keeps them fixed
+	Jumps   *Jumps           `json:",omitempty"` // adds rare shocks to the chaotic steps; nil for none
+	Garch   *Garch           `json:",omitempty"` // clusters the volatility, replacing Volatility; nil keeps it fixed
+
+	Anomalies *AnomalyConfig  `json:",omitempty"` // injects labelled spikes and level shifts for testing detectors; nil for none
+	Faults    *DeliveryFaults `json:",omitempty"` // drops and duplicates entries after generation; nil delivers each once
+}
+
+// RegimeWeights sets the relative frequency of the four step types. The
+// weights are normalized by their sum, so {3, 1, 1, 0} picks trend
+// following 60% of the time and never adds plain noise.
+type RegimeWeights struct {
+	TrendFollowing float64
+	MeanReversion  float64
+	Multiplicative float64
+	AdditiveNoise  float64
+}
+
+// The step types drawn by the generator after its first two steps, in the
+// order of their regime weights
+const (
+	trendFollowing = iota
+	meanReversion
+	multiplicative
+	additiveNoise
+)
+
+// regimeTypes names the step types, indexed by the constants above
+var regimeTypes = [...]string{
+	trendFollowing: "trend_following",
+	meanReversion:  "mean_reversion",
+	multiplicative: "multiplicative",
+	additiveNoise:  "additive_noise",
+}
+
+// values returns the weights in regimeTypes order
+func (w RegimeWeights) values() [len(regimeTypes)]float64 {
+	return [...]float64{w.TrendFollowing, w.MeanReversion, w.Multiplicative, w.AdditiveNoise}
+}
+
+// Proportions returns the normalized weight of each step type that can be
+// picked, keyed by the type names used in LogEntry.Type. The result can be
+// passed to BranchFrequencyTest to check a sequence against the weights.
+func (w RegimeWeights) Proportions() map[string]float64 {
+	weights := w.values()
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	proportions := make(map[string]float64, len(weights))
+	for k, weight := range weights {
+		if weight > 0 {
+			proportions[regimeTypes[k]] = weight / total
+		}
+	}
+	return proportions
+}
+
+// thresholds returns the cumulative proportions at which the step type
+// changes: a uniform draw below thresholds[k] and at or above the previous
+// threshold picks regimeTypes[k], and one at or above the last picks the last
+// type. A step type with no weight gets an empty interval.
+func (w RegimeWeights) thresholds() [len(regimeTypes) - 1]float64 {
+	weights := w.values()
+	return [len(regimeTypes) - 1]float64(cumulativeThresholds(weights[:]))
+}
+
+// cumulativeThresholds returns the thresholds at which a uniform draw moves
+// from one of weights to the next, as RegimeWeights.thresholds does for
+// any number of weights
+func cumulativeThresholds(weights []float64) []float64 {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	thresholds := make([]float64, len(weights)-1)
+	var cumulative float64
+	for k := range thresholds {
+		cumulative += weights[k]
+		thresholds[k] = cumulative / total
+		if cumulative == total {
+			// Only zero weights follow; rounding must not leave them a sliver
+			thresholds[k] = 1
+		}
+	}
+	return thresholds
+}
+
+// defaultThresholds are the step type thresholds when no weights are given
+var defaultThresholds = RegimeWeights{1, 1, 1, 1}.thresholds()
+
+// regimeThresholds returns the step type thresholds for weights, which may
+// be nil for the default equal split
+func regimeThresholds(weights *RegimeWeights) [len(regimeTypes) - 1]float64 {
+	if weights == nil {
+		return defaultThresholds
+	}
+	return weights.thresholds()
+}
+
+// DefaultConfig returns a sensible default configuration
+func DefaultConfig() ChaoticConfig {
+	return ChaoticConfig{
+		Volatility:    0.7,
+		TrendStrength: 0.3,
+		MeanReversion: 0.2,
+		MinValue:      1,
+		MaxValue:      1000,
+
+		MultiplicativeFactors: []float64{0.3, 0.7, 1.3, 1.7, 2.0, -0.5},
+		NoiseAmplitude:        10,
+	}
+}
+
+// Validation errors returned (wrapped) by ChaoticConfig.Validate and
+// ChaoticFloatConfig.Validate
+var (
+	ErrInvalidRange                 = errors.New("invalid value range")
+	ErrVolatilityOutOfBounds        = errors.New("volatility out of bounds")
+	ErrTrendStrengthOutOfBounds     = errors.New("trend strength out of bounds")
+	ErrMeanReversionOutOfBounds     = errors.New("mean reversion out of bounds")
+	ErrReversionSpeedOutOfBounds    = errors.New("reversion speed out of bounds")
+	ErrUnknownRandomnessMode        = errors.New("unknown randomness mode")
+	ErrUnknownBoundaryMode          = errors.New("unknown boundary mode")
+	ErrInvalidRegimeWeights         = errors.New("invalid regime weights")
+	ErrInvalidMultiplicativeFactors = errors.New("invalid multiplicative factors")
+	ErrNoiseAmplitudeOutOfBounds    = errors.New("noise amplitude out of bounds")
+	ErrInvalidCurrency              = errors.New("invalid currency")
+	ErrInvalidTiming                = errors.New("invalid timing")
+	ErrInvalidEntities              = errors.New("invalid entities")
+	ErrInvalidLedger                = errors.New("invalid ledger")
+	ErrInvalidRegimeSwitching       = errors.New("invalid regime switching")
+	ErrInvalidJumps                 = errors.New("invalid jumps")
+	ErrInvalidGarch                 = errors.New("invalid GARCH parameters")
+	ErrInvalidModel                 = errors.New("invalid generation model")
+	ErrInvalidLorenz                = errors.New("invalid Lorenz system")
+	ErrInvalidStepGenerators        = errors.New("invalid step generators")
+	ErrInvalidAnomalies             = errors.New("invalid anomalies")
+	ErrInvalidFaults                = errors.New("invalid delivery faults")
+)
+
+// Validate reports the first configuration field that would make generation
+// meaningless. The returned error wraps one of the Err* sentinels above.
+func (c ChaoticConfig) Validate() error {
+	if c.MinValue > c.MaxValue {
+		return fmt.Errorf("%w: MinValue %d must not exceed MaxValue %d", ErrInvalidRange, c.MinValue, c.MaxValue)
+	}
+	if c.MaxValue-c.MinValue+1 <= 0 {
+		return fmt.Errorf("%w: [%d, %d] is too wide to sample from", ErrInvalidRange, c.MinValue, c.MaxValue)
+	}
+
+	switch c.Model {
+	case "", ModelBranching:
+	case ModelLogistic, ModelHenon, ModelTent:
+		if err := c.validateMap(); err != nil {
+			return err
+		}
+	case ModelLorenz:
+		if err := c.lorenz().Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown model %q", ErrInvalidModel, c.Model)
+	}
+	if err := validateDynamics(c.Volatility, c.TrendStrength, c.MeanReversion, c.RandomnessMode, c.MultiplicativeFactors, c.RegimeWeights); err != nil {
+		return err
+	}
+	switch c.BoundaryMode {
+	case "", BoundaryClamp, BoundaryReflect, BoundaryWrap, BoundarySoft:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownBoundaryMode, c.BoundaryMode)
+	}
+	if err := validateStepGenerators(c.StepGenerators); err != nil {
+		return err
+	}
+	if c.NoiseAmplitude < 0 || c.NoiseAmplitude > (math.MaxInt-1)/2 {
+		return fmt.Errorf("%w: NoiseAmplitude %d must be between 0 and %d", ErrNoiseAmplitudeOutOfBounds, c.NoiseAmplitude, (math.MaxInt-1)/2)
+	}
+	if t := c.MeanReversionTarget; t != nil && (*t < c.MinValue || *t > c.MaxValue) {
+		return fmt.Errorf("%w: MeanReversionTarget %d is outside [%d, %d]", ErrInvalidRange, *t, c.MinValue, c.MaxValue)
+	}
+	if !(c.ReversionSpeed >= 0 && c.ReversionSpeed <= 1) {
+		return fmt.Errorf("%w: ReversionSpeed %v must be between 0.0 and 1.0", ErrReversionSpeedOutOfBounds, c.ReversionSpeed)
+	}
+	if c.Currency != nil {
+		if err := c.Currency.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Timing != nil {
+		if err := c.Timing.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Entities != nil {
+		if err := c.Entities.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Ledger != nil {
+		if err := c.Ledger.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Regimes != nil {
+		if err := c.Regimes.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Jumps != nil {
+		if err := c.Jumps.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Garch != nil {
+		if err := c.Garch.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Anomalies != nil {
+		if err := c.Anomalies.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Faults != nil {
+		if err := c.Faults.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDynamics checks the settings ChaoticConfig and ChaoticFloatConfig
+// share, which do not depend on the type of the values
+func validateDynamics(volatility, trendStrength, meanReversion float64, mode RandomnessMode, multiplicativeFactors []float64, weights *RegimeWeights) error {
+	factors := []struct {
+		name  string
+		value float64
+		err   error
+	}{
+		{"Volatility", volatility, ErrVolatilityOutOfBounds},
+		{"TrendStrength", trendStrength, ErrTrendStrengthOutOfBounds},
+		{"MeanReversion", meanReversion, ErrMeanReversionOutOfBounds},
+	}
+	for _, f := range factors {
+		if !(f.value >= 0 && f.value <= 1) {
+			return fmt.Errorf("%w: %s %v must be between 0.0 and 1.0", f.err, f.name, f.value)
+		}
+	}
+
+	switch mode {
+	case "", RandomnessSecure, RandomnessFast:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownRandomnessMode, mode)
+	}
+
+	if len(multiplicativeFactors) == 0 {
+		return fmt.Errorf("%w: MultiplicativeFactors must list at least one factor", ErrInvalidMultiplicativeFactors)
+	}
+	for _, factor := range multiplicativeFactors {
+		if math.IsNaN(factor) || math.IsInf(factor, 0) {
+			return fmt.Errorf("%w: factor %v is not a finite number", ErrInvalidMultiplicativeFactors, factor)
+		}
+	}
+
+	if weights != nil {
+		var total float64
+		for k, weight := range weights.values() {
+			if !(weight >= 0) || math.IsInf(weight, 1) {
+				return fmt.Errorf("%w: %s weight %v must be a non-negative number", ErrInvalidRegimeWeights, regimeTypes[k], weight)
+			}
+			total += weight
+		}
+		if !(total > 0) || math.IsInf(total, 1) {
+			return fmt.Errorf("%w: at least one weight must be positive and their sum finite", ErrInvalidRegimeWeights)
+		}
+	}
+	return nil
+}
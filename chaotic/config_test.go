@@ -0,0 +1,166 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateRejectsEachInvalidField(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  string
+		modify func(*ChaoticConfig)
+	}{
+		{"inverted range", "MinValue", func(c *ChaoticConfig) { c.MinValue, c.MaxValue = 10, 5 }},
+		{"range too wide", "too wide", func(c *ChaoticConfig) { c.MinValue, c.MaxValue = math.MinInt, math.MaxInt }},
+		{"volatility above 1", "Volatility", func(c *ChaoticConfig) { c.Volatility = 5 }},
+		{"volatility below 0", "Volatility", func(c *ChaoticConfig) { c.Volatility = -0.1 }},
+		{"volatility NaN", "Volatility", func(c *ChaoticConfig) { c.Volatility = math.NaN() }},
+		{"trend strength above 1", "TrendStrength", func(c *ChaoticConfig) { c.TrendStrength = 1.5 }},
+		{"trend strength below 0", "TrendStrength", func(c *ChaoticConfig) { c.TrendStrength = -1 }},
+		{"mean reversion above 1", "MeanReversion", func(c *ChaoticConfig) { c.MeanReversion = 2 }},
+		{"mean reversion below 0", "MeanReversion", func(c *ChaoticConfig) { c.MeanReversion = -0.5 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(&config)
+			err := config.Validate()
+			if err == nil {
+				t.Fatal("Validate accepted the config")
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("error %q does not name %s", err, tt.field)
+			}
+			if _, err := ChaoticTransactionSequence(10, config); err == nil {
+				t.Error("ChaoticTransactionSequence accepted the config")
+			}
+			if _, err := ChaoticTransactionSequenceExtended(10, config); err == nil {
+				t.Error("ChaoticTransactionSequenceExtended accepted the config")
+			}
+		})
+	}
+}
+
+func TestValidateAcceptsBounds(t *testing.T) {
+	config := DefaultConfig()
+	config.MinValue, config.MaxValue = 5, 5
+	config.Volatility, config.TrendStrength, config.MeanReversion = 0, 1, 1
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate rejected a single-value range and factors at the bounds: %v", err)
+	}
+	sequence, err := ChaoticTransactionSequence(20, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range sequence {
+		if entry.Value != 5 {
+			t.Fatalf("step %d has value %d outside [5, 5]", entry.Step, entry.Value)
+		}
+	}
+}
+
+func TestValidateSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   error
+		modify func(*ChaoticConfig)
+	}{
+		{"inverted range", ErrInvalidRange, func(c *ChaoticConfig) { c.MinValue, c.MaxValue = 1000, 1 }},
+		{"range too wide", ErrInvalidRange, func(c *ChaoticConfig) { c.MinValue, c.MaxValue = math.MinInt, 0 }},
+		{"volatility", ErrVolatilityOutOfBounds, func(c *ChaoticConfig) { c.Volatility = 5 }},
+		{"negative volatility", ErrVolatilityOutOfBounds, func(c *ChaoticConfig) { c.Volatility = -1 }},
+		{"trend strength", ErrTrendStrengthOutOfBounds, func(c *ChaoticConfig) { c.TrendStrength = 1.01 }},
+		{"negative trend strength", ErrTrendStrengthOutOfBounds, func(c *ChaoticConfig) { c.TrendStrength = -0.3 }},
+		{"mean reversion", ErrMeanReversionOutOfBounds, func(c *ChaoticConfig) { c.MeanReversion = 3 }},
+		{"negative mean reversion", ErrMeanReversionOutOfBounds, func(c *ChaoticConfig) { c.MeanReversion = -0.2 }},
+		{"no factors", ErrInvalidMultiplicativeFactors, func(c *ChaoticConfig) { c.MultiplicativeFactors = nil }},
+		{"empty factors", ErrInvalidMultiplicativeFactors, func(c *ChaoticConfig) { c.MultiplicativeFactors = []float64{} }},
+		{"NaN factor", ErrInvalidMultiplicativeFactors, func(c *ChaoticConfig) { c.MultiplicativeFactors = []float64{1, math.NaN()} }},
+		{"negative noise amplitude", ErrNoiseAmplitudeOutOfBounds, func(c *ChaoticConfig) { c.NoiseAmplitude = -1 }},
+		{"huge noise amplitude", ErrNoiseAmplitudeOutOfBounds, func(c *ChaoticConfig) { c.NoiseAmplitude = math.MaxInt/2 + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(&config)
+			if err := config.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate returned %v, want %v", err, tt.want)
+			}
+			if _, err := ChaoticTransactionSequence(10, config); !errors.Is(err, tt.want) {
+				t.Errorf("ChaoticTransactionSequence returned %v, want %v", err, tt.want)
+			}
+			if _, err := ChaoticTransactionSequenceExtended(10, config); !errors.Is(err, tt.want) {
+				t.Errorf("ChaoticTransactionSequenceExtended returned %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegimeWeightsSetBranchFrequencies(t *testing.T) {
+	weights := []*RegimeWeights{
+		nil,
+		{TrendFollowing: 3, MeanReversion: 1, Multiplicative: 1},
+		{TrendFollowing: 0.1, MeanReversion: 0.2, Multiplicative: 0.3, AdditiveNoise: 0.4},
+		{Multiplicative: 5},
+	}
+	for i, w := range weights {
+		seed := int64(100 + i)
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.RegimeWeights = w
+		sequence, err := ChaoticTransactionSequence(100_000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := RegimeWeights{1, 1, 1, 1}.Proportions()
+		if w != nil {
+			expected = w.Proportions()
+		}
+
+		// The first two steps are always initial and random_walk
+		counts := make(map[string]int)
+		for _, entry := range sequence[2:] {
+			counts[entry.Type]++
+		}
+		n := len(sequence) - 2
+		for _, name := range regimeTypes {
+			got := float64(counts[name]) / float64(n)
+			// Over 6 standard errors at 100k steps
+			if !closeTo(got, expected[name], 0.01) {
+				t.Errorf("weights %+v: %s picked %.4f of the time, want %.4f", w, name, got, expected[name])
+			}
+			if expected[name] == 0 && counts[name] != 0 {
+				t.Errorf("weights %+v: %s has no weight but was picked %d times", w, name, counts[name])
+			}
+		}
+
+		if len(expected) < 2 {
+			continue
+		}
+		result, err := BranchFrequencyTest(sequence, expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Pass(0.001) {
+			t.Errorf("weights %+v: branch frequencies rejected with p = %g", w, result.PValue)
+		}
+	}
+}
+
+func TestValidateRegimeWeights(t *testing.T) {
+	for _, w := range []RegimeWeights{
+		{},
+		{TrendFollowing: -1, MeanReversion: 2},
+		{AdditiveNoise: math.NaN(), Multiplicative: 1},
+		{TrendFollowing: math.Inf(1)},
+	} {
+		config := DefaultConfig()
+		config.RegimeWeights = &w
+		if err := config.Validate(); !errors.Is(err, ErrInvalidRegimeWeights) {
+			t.Errorf("weights %+v: Validate returned %v, want ErrInvalidRegimeWeights", w, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package chaotic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidCorrelation is returned (wrapped) by GenerateCorrelated when the
+// correlation matrix is not a valid correlation matrix
+var ErrInvalidCorrelation = errors.New("invalid correlation matrix")
+
+// correlationTolerance is how far a correlation matrix may stray from
+// symmetry, or below positive semi-definiteness, through rounding
+const correlationTolerance = 1e-9
+
+// GenerateCorrelated generates one sequence of n steps per config, with the
+// chaos factors driving their steps correlated across the sequences by corr.
+// corr must be a correlation matrix with a row and column per config: square,
+// symmetric, with ones on the diagonal and positive semi-definite. A matrix
+// that is not is rejected with an error wrapping ErrInvalidCorrelation.
+//
+// At each step, correlated standard normal draws are made from independent
+// ones with the Cholesky factor of corr, and each is mapped back through the
+// normal CDF to a chaos factor between -1 and 1. The chaos factors therefore
+// keep the uniform distribution they have in ChaoticTransactionSequence,
+// and an identity matrix gives independent sequences. The independent draw
+// behind sequence i is made from that sequence's source: with a Seed,
+// sequence i is seeded with PathSeed(*configs[i].Seed, i), so the same
+// config may be passed for every sequence.
+//
+// Only the chaos factors are correlated: step types and multiplicative
+// factors are still drawn independently, and values are clamped to each
+// range, so the returns of the sequences correlate more weakly than corr.
+// How much more depends on the step types; with the default regime weights
+// even a perfect correlation leaves their absolute returns correlated by
+// about 0.3, while mean reversion alone tracks corr closely.
+// ReturnCorrelations measures the realized correlation and CorrelationTest
+// checks it against a target. The sequences are compared step by step, so
+// the Faults of the configs are not applied to them.
+func GenerateCorrelated(n int, configs []ChaoticConfig, corr [][]float64) ([][]LogEntry, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("need at least one config")
+	}
+	for i, config := range configs {
+		if err := validateSequence(n, config); err != nil {
+			return nil, fmt.Errorf("config %d: %w", i, err)
+		}
+	}
+	factor, err := choleskyCorrelation(corr, len(configs))
+	if err != nil {
+		return nil, err
+	}
+
+	// draws[i][step] is the independent normal behind sequence i at step,
+	// kept for the later sequences that mix it in; chaos draws start at
+	// step 2, so step s is stored at index s-2
+	draws := make([][]float64, len(configs))
+	series := make([][]LogEntry, len(configs))
+	for i, config := range configs {
+		if config.Seed != nil {
+			seed := PathSeed(*config.Seed, i)
+			config.Seed = &seed
+		}
+		config.Faults = nil
+		src := config.randSource()
+		draws[i] = make([]float64, 0, max(n-2, 0))
+		series[i], err = generateSequence(context.Background(), n, config, src, func(step int) float64 {
+			draws[i] = append(draws[i], standardNormal(src))
+			var z float64
+			for j, weight := range factor[i][:i+1] {
+				z += weight * draws[j][step-2]
+			}
+			// The normal CDF maps z to [0, 1], and 2Φ(z)-1 = erf(z/√2)
+			return math.Erf(z / math.Sqrt2)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return series, nil
+}
+
+// standardNormal draws from the standard normal distribution by inverting
+// its CDF at a uniform draw from src
+func standardNormal(src RandSource) float64 {
+	u := src.Float64()
+	for u == 0 {
+		// The inverse CDF is -Inf at 0
+		u = src.Float64()
+	}
+	return math.Sqrt2 * math.Erfinv(2*u-1)
+}
+
+// choleskyCorrelation checks that corr is an m×m correlation matrix and
+// returns its lower-triangular Cholesky factor L, with corr = L·Lᵀ. A
+// positive semi-definite matrix that is singular, such as one with a
+// perfect correlation, gets a zero column in L where a positive definite
+// one would need a positive pivot.
+func choleskyCorrelation(corr [][]float64, m int) ([][]float64, error) {
+	if len(corr) != m {
+		return nil, fmt.Errorf("%w: %d rows for %d series", ErrInvalidCorrelation, len(corr), m)
+	}
+	for i, row := range corr {
+		if len(row) != m {
+			return nil, fmt.Errorf("%w: row %d has %d columns, not %d", ErrInvalidCorrelation, i, len(row), m)
+		}
+	}
+	for i := range corr {
+		if corr[i][i] != 1 {
+			return nil, fmt.Errorf("%w: diagonal entry %d is %v, not 1", ErrInvalidCorrelation, i, corr[i][i])
+		}
+		for j := 0; j < i; j++ {
+			if !(math.Abs(corr[i][j]) <= 1) {
+				return nil, fmt.Errorf("%w: entry (%d, %d) is %v, outside [-1, 1]", ErrInvalidCorrelation, i, j, corr[i][j])
+			}
+			if math.Abs(corr[i][j]-corr[j][i]) > correlationTolerance {
+				return nil, fmt.Errorf("%w: not symmetric at (%d, %d): %v and %v", ErrInvalidCorrelation, i, j, corr[i][j], corr[j][i])
+			}
+		}
+	}
+
+	factor := make([][]float64, m)
+	for i := range factor {
+		factor[i] = make([]float64, m)
+	}
+	for j := 0; j < m; j++ {
+		pivot := corr[j][j]
+		for k := 0; k < j; k++ {
+			pivot -= factor[j][k] * factor[j][k]
+		}
+		if pivot < -correlationTolerance {
+			return nil, fmt.Errorf("%w: not positive semi-definite (pivot %d is %v)", ErrInvalidCorrelation, j, pivot)
+		}
+		singular := pivot <= correlationTolerance
+		if !singular {
+			factor[j][j] = math.Sqrt(pivot)
+		}
+		for i := j + 1; i < m; i++ {
+			rest := corr[i][j]
+			for k := 0; k < j; k++ {
+				rest -= factor[i][k] * factor[j][k]
+			}
+			if singular {
+				if math.Abs(rest) > correlationTolerance {
+					return nil, fmt.Errorf("%w: not positive semi-definite (pivot %d is 0 but entry (%d, %d) remains %v)", ErrInvalidCorrelation, j, i, j, rest)
+				}
+				continue
+			}
+			factor[i][j] = rest / factor[j][j]
+		}
+	}
+	return factor, nil
+}
+
+// ReturnCorrelations returns the matrix of Pearson correlations between the
+// absolute step-to-step returns of the series, which must all have the same
+// length of at least 2 steps. A series whose value never changes has no
+// defined correlation and returns ErrConstantSequence.
+func ReturnCorrelations(series [][]LogEntry) ([][]float64, error) {
+	returns := make([][]float64, len(series))
+	for i, log := range series {
+		if len(log) != len(series[0]) {
+			return nil, fmt.Errorf("series %d has %d steps, not %d", i, len(log), len(series[0]))
+		}
+		values := make([]int, len(log))
+		for k, entry := range log {
+			values[k] = entry.Value
+		}
+		var err error
+		if returns[i], err = ComputeReturns(values, ReturnAbsolute); err != nil {
+			return nil, fmt.Errorf("series %d: %w", i, err)
+		}
+	}
+
+	matrix := make([][]float64, len(series))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(series))
+		matrix[i][i] = 1
+	}
+	for i := range returns {
+		for j := 0; j < i; j++ {
+			r, err := pearsonCorrelation(returns[i], returns[j])
+			if err != nil {
+				return nil, fmt.Errorf("series %d and %d: %w", j, i, err)
+			}
+			matrix[i][j], matrix[j][i] = r, r
+		}
+	}
+	return matrix, nil
+}
+
+// CorrelatedRun is a set of series generated together by GenerateCorrelated,
+// nested by name under "correlated" in a RunDocument
+type CorrelatedRun struct {
+	Names    []string              `json:"names"`    // the series in the order of the matrix rows
+	Target   [][]float64           `json:"target"`   // the correlation matrix they were generated with
+	Realized [][]float64           `json:"realized"` // as measured by ReturnCorrelations
+	Series   map[string][]LogEntry `json:"series"`   // keyed by name
+}
+
+// NewCorrelatedRun names the series generated by GenerateCorrelated with
+// target and measures their realized correlation. Names must be unique, one
+// per series.
+func NewCorrelatedRun(names []string, series [][]LogEntry, target [][]float64) (CorrelatedRun, error) {
+	if len(names) != len(series) {
+		return CorrelatedRun{}, fmt.Errorf("%d names for %d series", len(names), len(series))
+	}
+	run := CorrelatedRun{
+		Names:  append([]string(nil), names...),
+		Target: target,
+		Series: make(map[string][]LogEntry, len(series)),
+	}
+	for i, name := range names {
+		if _, ok := run.Series[name]; ok {
+			return CorrelatedRun{}, fmt.Errorf("series name %q is used twice", name)
+		}
+		run.Series[name] = series[i]
+	}
+	var err error
+	if run.Realized, err = ReturnCorrelations(series); err != nil {
+		return CorrelatedRun{}, err
+	}
+	return run, nil
+}
@@ -0,0 +1,127 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// meanRevertingConfig returns a seeded config whose steps are all mean
+// reversion over a wide range, the dynamics under which the returns of
+// correlated series track the target correlation most closely
+func meanRevertingConfig(seed int64) ChaoticConfig {
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.RegimeWeights = &RegimeWeights{MeanReversion: 1}
+	config.Volatility, config.MeanReversion = 0, 1
+	config.MinValue, config.MaxValue = 1, 1_000_000_000
+	return config
+}
+
+func TestGenerateCorrelatedRealizesTarget(t *testing.T) {
+	corr := [][]float64{
+		{1, 0.8, -0.5},
+		{0.8, 1, -0.3},
+		{-0.5, -0.3, 1},
+	}
+	config := meanRevertingConfig(28)
+	series, err := GenerateCorrelated(20_000, []ChaoticConfig{config, config, config}, corr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	realized, err := ReturnCorrelations(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clamping and the different levels of the series weaken the
+	// correlation of their returns a little
+	for i := range corr {
+		for j := range corr {
+			if !closeTo(realized[i][j], corr[i][j], 0.05) {
+				t.Errorf("series %d and %d: returns correlate by %.3f, want about %.3f", i, j, realized[i][j], corr[i][j])
+			}
+		}
+	}
+
+	// An identity matrix leaves the series independent
+	identity := [][]float64{{1, 0}, {0, 1}}
+	independent, err := GenerateCorrelated(20_000, []ChaoticConfig{config, config}, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err := ReturnCorrelations(independent); err != nil || math.Abs(r[0][1]) > 0.03 {
+		t.Errorf("independent series: returns correlate by %v, %v; want about 0", r, err)
+	}
+}
+
+func TestGenerateCorrelatedRejectsInvalidMatrices(t *testing.T) {
+	config := meanRevertingConfig(1)
+	configs := []ChaoticConfig{config, config, config}
+	tests := []struct {
+		name string
+		corr [][]float64
+	}{
+		{"too few rows", [][]float64{{1, 0, 0}, {0, 1, 0}}},
+		{"ragged", [][]float64{{1, 0, 0}, {0, 1}, {0, 0, 1}}},
+		{"asymmetric", [][]float64{{1, 0.5, 0}, {0.4, 1, 0}, {0, 0, 1}}},
+		{"diagonal", [][]float64{{2, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+		{"out of range", [][]float64{{1, 1.2, 0}, {1.2, 1, 0}, {0, 0, 1}}},
+		// Each pair is plausible, but no three series can all be this
+		// anti-correlated
+		{"not positive semi-definite", [][]float64{{1, -0.9, -0.9}, {-0.9, 1, -0.9}, {-0.9, -0.9, 1}}},
+	}
+	for _, tt := range tests {
+		if _, err := GenerateCorrelated(100, configs, tt.corr); !errors.Is(err, ErrInvalidCorrelation) {
+			t.Errorf("%s: returned %v, want ErrInvalidCorrelation", tt.name, err)
+		}
+	}
+
+	// Perfectly correlated series are semi-definite, and allowed
+	perfect := [][]float64{{1, 1}, {1, 1}}
+	if _, err := GenerateCorrelated(100, configs[:2], perfect); err != nil {
+		t.Errorf("perfect correlation: %v", err)
+	}
+}
+
+func TestCorrelatedRunNestsSeriesByName(t *testing.T) {
+	corr := [][]float64{{1, 0.6}, {0.6, 1}}
+	config := meanRevertingConfig(7)
+	series, err := GenerateCorrelated(500, []ChaoticConfig{config, config}, corr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := NewCorrelatedRun([]string{"equities", "bonds"}, series, corr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := seededDocument(t, 100, config)
+	doc.Correlated = &run
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Correlated struct {
+			Names  []string              `json:"names"`
+			Series map[string][]LogEntry `json:"series"`
+		} `json:"correlated"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Correlated.Names, []string{"equities", "bonds"}) {
+		t.Errorf("names %v", decoded.Correlated.Names)
+	}
+	for i, name := range []string{"equities", "bonds"} {
+		if !reflect.DeepEqual(valuesOf(decoded.Correlated.Series[name]), valuesOf(series[i])) {
+			t.Errorf("series %q does not hold series %d", name, i)
+		}
+	}
+
+	if _, err := NewCorrelatedRun([]string{"a", "a"}, series, corr); err == nil {
+		t.Error("a repeated name succeeded")
+	}
+}
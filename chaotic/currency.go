@@ -0,0 +1,147 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects how a fractional number of minor units is rounded
+type RoundingMode string
+
+// Rounding modes for Currency
+const (
+	RoundHalfEven RoundingMode = "half_even" // to the nearest, ties to the even neighbour (the default)
+	RoundHalfUp   RoundingMode = "half_up"   // to the nearest, ties away from zero
+	RoundDown     RoundingMode = "down"      // towards zero
+	RoundFloor    RoundingMode = "floor"     // towards negative infinity
+	RoundCeiling  RoundingMode = "ceiling"   // towards positive infinity
+)
+
+// maxCurrencyExponent is the largest minor-unit exponent a Currency may
+// have, enough for 18-decimal tokens while 10^exponent still fits an int64
+const maxCurrencyExponent = 18
+
+// Currency makes the values of a sequence money amounts in minor units, such
+// as cents. Entries then carry the amount both as an integer and as a
+// decimal string in major units, and the generator rounds the fractional
+// minor units its float arithmetic produces with Rounding instead of
+// truncating them.
+type Currency struct {
+	Code     string       // ISO 4217 style code of three capital letters, e.g. "USD"
+	Exponent int          // minor units per major unit as a power of ten: 2 for cents, 0 for yen
+	Rounding RoundingMode `json:",omitempty"` // half_even when empty
+}
+
+// Validate reports whether the currency is usable, returning an error
+// wrapping ErrInvalidCurrency if not
+func (c Currency) Validate() error {
+	if len(c.Code) != 3 || strings.IndexFunc(c.Code, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+		return fmt.Errorf("%w: code %q must be three capital letters", ErrInvalidCurrency, c.Code)
+	}
+	if c.Exponent < 0 || c.Exponent > maxCurrencyExponent {
+		return fmt.Errorf("%w: exponent %d must be between 0 and %d", ErrInvalidCurrency, c.Exponent, maxCurrencyExponent)
+	}
+	switch c.Rounding {
+	case "", RoundHalfEven, RoundHalfUp, RoundDown, RoundFloor, RoundCeiling:
+	default:
+		return fmt.Errorf("%w: unknown rounding mode %q", ErrInvalidCurrency, c.Rounding)
+	}
+	return nil
+}
+
+// Round rounds a fractional number of minor units to a whole one with the
+// currency's rounding mode
+func (c Currency) Round(minor float64) float64 {
+	switch c.Rounding {
+	case RoundHalfUp:
+		return math.Round(minor)
+	case RoundDown:
+		return math.Trunc(minor)
+	case RoundFloor:
+		return math.Floor(minor)
+	case RoundCeiling:
+		return math.Ceil(minor)
+	default:
+		return math.RoundToEven(minor)
+	}
+}
+
+// Format renders an amount in minor units as a decimal string in major
+// units, e.g. 1234 cents as "12.34" and -5 as "-0.05", with exactly Exponent
+// digits after the point. It uses integer arithmetic only, so the text is
+// the same on every platform.
+func (c Currency) Format(minor int64) string {
+	if minor < 0 {
+		// Negating math.MinInt64 overflows, so widen before taking the magnitude
+		return c.formatDigits(true, strconv.FormatUint(uint64(-(minor+1))+1, 10))
+	}
+	return c.formatDigits(false, strconv.FormatInt(minor, 10))
+}
+
+// formatDigits places the decimal point in the decimal digits of a
+// magnitude in minor units
+func (c Currency) formatDigits(negative bool, digits string) string {
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	if c.Exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= c.Exponent {
+		digits = strings.Repeat("0", c.Exponent-len(digits)+1) + digits
+	}
+	split := len(digits) - c.Exponent
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// formatFloat rounds a fractional amount in minor units with the currency's
+// rounding mode and formats it
+func (c Currency) formatFloat(minor float64) string {
+	return c.Format(int64(toIntSat(c.Round(minor))))
+}
+
+// MoneyStatistics summarizes a sequence of amounts in major units. Amounts
+// are decimal strings formatted by Currency.Format, so they print the same
+// everywhere; those that are not whole minor units, such as the mean, are
+// first rounded with the currency's rounding mode.
+type MoneyStatistics struct {
+	Currency string `json:"currency"`
+	Total    string `json:"total"`
+	Mean     string `json:"mean"`
+	Median   string `json:"median"`
+	Stdev    string `json:"stdev"`
+	Min      string `json:"min"`
+	Max      string `json:"max"`
+}
+
+// ComputeMoneyStatistics summarizes sequence, whose values are amounts in
+// the minor units of currency. The total is exact however large it grows.
+func ComputeMoneyStatistics(sequence []LogEntry, currency Currency) (MoneyStatistics, error) {
+	if err := currency.Validate(); err != nil {
+		return MoneyStatistics{}, err
+	}
+	stats, err := ComputeStatisticsFor(sequence, "mean", "median", "stdev", "min", "max")
+	if err != nil {
+		return MoneyStatistics{}, err
+	}
+
+	total := new(big.Int)
+	for _, entry := range sequence {
+		total.Add(total, big.NewInt(int64(entry.Value)))
+	}
+	magnitude := new(big.Int).Abs(total)
+
+	return MoneyStatistics{
+		Currency: currency.Code,
+		Total:    currency.formatDigits(total.Sign() < 0, magnitude.String()),
+		Mean:     currency.formatFloat(stats.Mean),
+		Median:   currency.formatFloat(stats.Median),
+		Stdev:    currency.formatFloat(stats.Stdev),
+		Min:      currency.Format(int64(stats.Min)),
+		Max:      currency.Format(int64(stats.Max)),
+	}, nil
+}
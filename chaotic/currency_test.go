@@ -0,0 +1,68 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCurrencyRounding(t *testing.T) {
+	modes := []RoundingMode{"", RoundHalfEven, RoundHalfUp, RoundDown, RoundFloor, RoundCeiling}
+	// Fractional cents, rounded by each of the modes above
+	tests := []struct {
+		minor float64
+		want  [6]string
+	}{
+		{12.5, [6]string{"0.12", "0.12", "0.13", "0.12", "0.12", "0.13"}},
+		{13.5, [6]string{"0.14", "0.14", "0.14", "0.13", "0.13", "0.14"}},
+		{-12.5, [6]string{"-0.12", "-0.12", "-0.13", "-0.12", "-0.13", "-0.12"}},
+		{-13.5, [6]string{"-0.14", "-0.14", "-0.14", "-0.13", "-0.14", "-0.13"}},
+		{12.4, [6]string{"0.12", "0.12", "0.12", "0.12", "0.12", "0.13"}},
+		{-12.6, [6]string{"-0.13", "-0.13", "-0.13", "-0.12", "-0.13", "-0.12"}},
+		{1234, [6]string{"12.34", "12.34", "12.34", "12.34", "12.34", "12.34"}},
+	}
+	for _, tt := range tests {
+		for i, mode := range modes {
+			currency := Currency{Code: "USD", Exponent: 2, Rounding: mode}
+			if got := currency.formatFloat(tt.minor); got != tt.want[i] {
+				t.Errorf("%v cents rounded %q: %s, want %s", tt.minor, mode, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestCurrencyFormat(t *testing.T) {
+	tests := []struct {
+		exponent int
+		minor    int64
+		want     string
+	}{
+		{2, 1234, "12.34"},
+		{2, -5, "-0.05"},
+		{2, 0, "0.00"},
+		{0, -1234, "-1234"},
+		{3, 1, "0.001"},
+		{2, math.MinInt64, "-92233720368547758.08"},
+		{18, math.MaxInt64, "9.223372036854775807"},
+	}
+	for _, tt := range tests {
+		currency := Currency{Code: "XTS", Exponent: tt.exponent}
+		if got := currency.Format(tt.minor); got != tt.want {
+			t.Errorf("%d with exponent %d: %s, want %s", tt.minor, tt.exponent, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCurrency(t *testing.T) {
+	for _, currency := range []Currency{
+		{Code: "usd", Exponent: 2},
+		{Code: "EURO", Exponent: 2},
+		{Code: "USD", Exponent: -1},
+		{Code: "USD", Exponent: 19},
+		{Code: "USD", Exponent: 2, Rounding: "bankers"},
+	} {
+		if err := currency.Validate(); !errors.Is(err, ErrInvalidCurrency) {
+			t.Errorf("%+v: Validate returned %v, want ErrInvalidCurrency", currency, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package chaotic
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+)
+
+// ensemblePercentiles are the per-step percentiles reported by GenerateEnsemble
+var ensemblePercentiles = [...]float64{0.05, 0.25, 0.5, 0.75, 0.95}
+
+// Ensemble is a set of independent paths generated from one configuration,
+// summarized step by step and path by path
+type Ensemble struct {
+	Paths int            `json:"paths"`
+	Steps []EnsembleStep `json:"steps"`
+
+	// Distributions of one statistic per path, across the paths
+	PathMeans        Distribution `json:"path_means"`
+	PathVolatilities Distribution `json:"path_volatilities"`
+	PathMaxDrawdowns Distribution `json:"path_max_drawdowns"`
+
+	// Sequences holds the paths themselves, nil when they were discarded
+	Sequences [][]LogEntry `json:"sequences,omitempty"`
+}
+
+// EnsembleStep is the distribution of the value at one step across the paths
+type EnsembleStep struct {
+	Step   int     `json:"step"`
+	Mean   float64 `json:"mean"`
+	P5     float64 `json:"p5"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"p50"`
+	P75    float64 `json:"p75"`
+	P95    float64 `json:"p95"`
+}
+
+// Distribution summarizes a sample of floats
+type Distribution struct {
+	Mean   float64 `json:"mean"`
+	Stdev  float64 `json:"stdev"` // sample standard deviation, 0 for a single value
+	Min    float64 `json:"min"`
+	P5     float64 `json:"p5"`
+	Median float64 `json:"p50"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+}
+
+// EnsembleOptions tunes GenerateEnsembleWithOptions
+type EnsembleOptions struct {
+	// DiscardPaths drops each path once it has been summarized, so memory
+	// grows with the number of steps but not the number of paths. Per-step
+	// percentiles are then estimated with the P² algorithm instead of being
+	// computed exactly; the per-step means and the per-path distributions
+	// stay exact.
+	DiscardPaths bool
+	// Concurrency is the number of workers generating paths, as for
+	// GenerateParallel; 0 means runtime.GOMAXPROCS(0)
+	Concurrency int
+}
+
+// GenerateEnsemble generates m independent paths of n steps with config and
+// summarizes them, keeping the paths. It is GenerateEnsembleWithOptions with
+// the zero options.
+func GenerateEnsemble(m, n int, config ChaoticConfig) (Ensemble, error) {
+	return GenerateEnsembleWithOptions(m, n, config, EnsembleOptions{})
+}
+
+// GenerateEnsembleWithOptions generates m independent paths of n steps with
+// config and summarizes them: each step gets the mean and the 5th, 25th,
+// 50th, 75th and 95th percentiles of its value across the paths, and the
+// paths' means, volatilities and maximum drawdowns are summarized as
+// distributions. The paths are generated on a worker pool as by
+// GenerateParallel and summarized in path order, so a seeded config
+// produces the same ensemble whatever the concurrency. The paths are
+// compared step by step, so the config's Faults are not applied to them.
+func GenerateEnsembleWithOptions(m, n int, config ChaoticConfig, opts EnsembleOptions) (Ensemble, error) {
+	if m <= 0 {
+		return Ensemble{}, errors.New("the number of paths must be a positive integer")
+	}
+	if err := validateSequence(n, config); err != nil {
+		return Ensemble{}, err
+	}
+
+	config.Faults = nil
+	ensemble := Ensemble{Paths: m}
+	means := make([]float64, m)
+	volatilities := make([]float64, m)
+	drawdowns := make([]float64, m)
+	sums := make([]float64, n)
+	var sketches [][len(ensemblePercentiles)]p2Quantile
+	if opts.DiscardPaths {
+		sketches = make([][len(ensemblePercentiles)]p2Quantile, n)
+		for i := range sketches {
+			for k, p := range ensemblePercentiles {
+				sketches[i][k].p = p
+			}
+		}
+	}
+
+	err := generatePaths(context.Background(), m, n, config, ParallelOptions{Concurrency: opts.Concurrency}, func(path int, log []LogEntry) error {
+		stats, err := ComputeStatisticsFor(log, "mean", "volatility", "max_drawdown")
+		if err != nil {
+			return err
+		}
+		means[path] = stats.Mean
+		volatilities[path] = stats.Volatility
+		drawdowns[path] = float64(stats.MaxDrawdown)
+
+		for i, entry := range log {
+			sums[i] += float64(entry.Value)
+		}
+		if opts.DiscardPaths {
+			for i, entry := range log {
+				for k := range sketches[i] {
+					sketches[i][k].add(float64(entry.Value))
+				}
+			}
+		} else {
+			ensemble.Sequences = append(ensemble.Sequences, log)
+		}
+		return nil
+	})
+	if err != nil {
+		return Ensemble{}, err
+	}
+
+	ensemble.Steps = make([]EnsembleStep, n)
+	column := make([]float64, m)
+	for i := range ensemble.Steps {
+		var percentiles [len(ensemblePercentiles)]float64
+		if opts.DiscardPaths {
+			for k := range sketches[i] {
+				percentiles[k] = sketches[i][k].quantile()
+			}
+		} else {
+			for path, log := range ensemble.Sequences {
+				column[path] = float64(log[i].Value)
+			}
+			sort.Float64s(column)
+			copy(percentiles[:], Quantiles(column, ensemblePercentiles[:]...))
+		}
+		ensemble.Steps[i] = EnsembleStep{
+			Step:   i,
+			Mean:   sums[i] / float64(m),
+			P5:     percentiles[0],
+			P25:    percentiles[1],
+			Median: percentiles[2],
+			P75:    percentiles[3],
+			P95:    percentiles[4],
+		}
+	}
+
+	ensemble.PathMeans = summarize(means)
+	ensemble.PathVolatilities = summarize(volatilities)
+	ensemble.PathMaxDrawdowns = summarize(drawdowns)
+	return ensemble, nil
+}
+
+// summarize describes a non-empty sample, sorting it in place
+func summarize(sample []float64) Distribution {
+	sort.Float64s(sample)
+	var sum float64
+	for _, x := range sample {
+		sum += x
+	}
+	mean := sum / float64(len(sample))
+	var squares float64
+	for _, x := range sample {
+		squares += (x - mean) * (x - mean)
+	}
+	var stdev float64
+	if len(sample) > 1 {
+		stdev = math.Sqrt(squares / float64(len(sample)-1))
+	}
+	q := Quantiles(sample, 0.05, 0.5, 0.95)
+	return Distribution{
+		Mean:   mean,
+		Stdev:  stdev,
+		Min:    sample[0],
+		P5:     q[0],
+		Median: q[1],
+		P95:    q[2],
+		Max:    sample[len(sample)-1],
+	}
+}
@@ -0,0 +1,116 @@
+package chaotic
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestEnsembleBands(t *testing.T) {
+	seed := int64(43)
+	config := DefaultConfig()
+	config.Seed = &seed
+	ensemble, err := GenerateEnsemble(5, 300, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ensemble.Paths != 5 || len(ensemble.Sequences) != 5 || len(ensemble.Steps) != 300 {
+		t.Fatalf("%d paths, %d sequences and %d steps", ensemble.Paths, len(ensemble.Sequences), len(ensemble.Steps))
+	}
+	for i, step := range ensemble.Steps {
+		column := make([]float64, 5)
+		sum := 0.0
+		for path, log := range ensemble.Sequences {
+			column[path] = float64(log[i].Value)
+			sum += column[path]
+		}
+		sort.Float64s(column)
+		// With five paths the quartiles and the median fall on values, and
+		// the 5th and 95th percentiles a fifth of the way past the ends
+		want := EnsembleStep{
+			Step:   i,
+			Mean:   sum / 5,
+			P5:     0.8*column[0] + 0.2*column[1],
+			P25:    column[1],
+			Median: column[2],
+			P75:    column[3],
+			P95:    0.2*column[3] + 0.8*column[4],
+		}
+		if !closeTo(step.Mean, want.Mean, 1e-9) || !closeTo(step.P5, want.P5, 1e-9) || !closeTo(step.P95, want.P95, 1e-9) ||
+			step.Step != i || step.P25 != want.P25 || step.Median != want.Median || step.P75 != want.P75 {
+			t.Fatalf("step %d: %+v, want %+v", i, step, want)
+		}
+	}
+
+	means := make([]float64, 5)
+	for path, log := range ensemble.Sequences {
+		stats, err := ComputeStatistics(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		means[path] = stats.Mean
+	}
+	sort.Float64s(means)
+	if d := ensemble.PathMeans; d.Min != means[0] || d.Median != means[2] || d.Max != means[4] {
+		t.Errorf("path means %+v, want min %v, median %v and max %v", d, means[0], means[2], means[4])
+	}
+
+	// The paths are drawn from their own seeds, not one after the other
+	// from a shared source, so the workers do not change the ensemble
+	single, err := GenerateEnsembleWithOptions(5, 300, config, EnsembleOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(single, ensemble) {
+		t.Error("one worker generated a different ensemble")
+	}
+}
+
+func TestEnsembleDiscardPaths(t *testing.T) {
+	seed := int64(44)
+	config := DefaultConfig()
+	config.Seed = &seed
+	kept, err := GenerateEnsemble(1000, 100, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discarded, err := GenerateEnsembleWithOptions(1000, 100, config, EnsembleOptions{DiscardPaths: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if discarded.Sequences != nil {
+		t.Errorf("%d sequences kept", len(discarded.Sequences))
+	}
+	if !reflect.DeepEqual(discarded.PathMeans, kept.PathMeans) ||
+		!reflect.DeepEqual(discarded.PathVolatilities, kept.PathVolatilities) ||
+		!reflect.DeepEqual(discarded.PathMaxDrawdowns, kept.PathMaxDrawdowns) {
+		t.Error("the per-path distributions changed when discarding the paths")
+	}
+	for i, step := range discarded.Steps {
+		exact := kept.Steps[i]
+		if !closeTo(step.Mean, exact.Mean, 1e-9) {
+			t.Fatalf("step %d: mean %v, want the exact %v", i, step.Mean, exact.Mean)
+		}
+		// The values pile up at the bounds, which the P² curve fits poorly,
+		// but over 1000 paths the estimates stay within a tenth of the range
+		// of the exact percentiles
+		for _, pair := range [][2]float64{
+			{step.P5, exact.P5}, {step.P25, exact.P25}, {step.Median, exact.Median},
+			{step.P75, exact.P75}, {step.P95, exact.P95},
+		} {
+			if math.Abs(pair[0]-pair[1]) > 0.1*float64(config.MaxValue-config.MinValue) {
+				t.Fatalf("step %d: estimated %+v, exact %+v", i, step, exact)
+			}
+		}
+	}
+}
+
+func TestEnsembleRejectsInvalidSizes(t *testing.T) {
+	config := DefaultConfig()
+	for _, size := range [][2]int{{0, 10}, {-1, 10}, {4, 0}, {4, -1}} {
+		if _, err := GenerateEnsemble(size[0], size[1], config); err == nil {
+			t.Errorf("%d paths of %d steps: no error", size[0], size[1])
+		}
+	}
+}
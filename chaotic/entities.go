@@ -0,0 +1,162 @@
+package chaotic
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Entities decorates each generated entry with the identifiers downstream
+// tooling expects of a transaction: a unique transaction ID, and optionally
+// the account it belongs to and the merchant it was paid to.
+//
+// Transaction IDs are random version 4 UUIDs drawn from crypto/rand, or,
+// with a Seed or an explicit Source, version 5 UUIDs named by the seed and
+// the step, so a replay reproduces them. Accounts and merchants follow
+// the same rule, drawing from a source of the configured RandomnessMode
+// when unseeded. None of these draws come from the value source, so adding
+// entities to a configuration leaves its values unchanged.
+type Entities struct {
+	Accounts  int        // size of the pool account IDs are drawn from uniformly; 0 leaves them out
+	Merchants []Merchant `json:",omitempty"` // merchants to pick from by weight; nil leaves them out
+}
+
+// Merchant is a merchant label and how often it is picked, relative to the others
+type Merchant struct {
+	Label  string
+	Weight float64
+}
+
+// Validate reports whether the entities are usable, returning an error
+// wrapping ErrInvalidEntities if not
+func (e Entities) Validate() error {
+	if e.Accounts < 0 {
+		return fmt.Errorf("%w: Accounts %d must not be negative", ErrInvalidEntities, e.Accounts)
+	}
+	if e.Merchants == nil {
+		return nil
+	}
+	var total float64
+	for i, m := range e.Merchants {
+		if m.Label == "" {
+			return fmt.Errorf("%w: merchant %d has no label", ErrInvalidEntities, i)
+		}
+		if !(m.Weight >= 0) || math.IsInf(m.Weight, 1) {
+			return fmt.Errorf("%w: merchant %q has weight %v, which must be finite and not negative", ErrInvalidEntities, m.Label, m.Weight)
+		}
+		total += m.Weight
+	}
+	if !(total > 0) || math.IsInf(total, 1) {
+		return fmt.Errorf("%w: merchant weights must have a positive, finite sum", ErrInvalidEntities)
+	}
+	return nil
+}
+
+// entityNamespace is the UUID namespace of the version 5 transaction IDs
+var entityNamespace = [16]byte{
+	0x6b, 0x1f, 0x3c, 0x52, 0x8e, 0x4d, 0x4a, 0x07, 0x9c, 0x35, 0x2e, 0xd1, 0x70, 0x64, 0xb8, 0x19,
+}
+
+// entityStream separates the entity draws from other uses of a seed
+const entityStream = 0x656e74697479 // "entity"
+
+// Streams forking the entity draws, one per field
+const (
+	accountStream  = 1
+	merchantStream = 2
+)
+
+// entityTagger decorates the entries of one run with entity fields
+type entityTagger struct {
+	entities   Entities
+	thresholds []float64 // cumulative merchant weights
+	draws      sideDraws // names the transaction IDs when derived
+	accounts   sideDraws
+	merchants  sideDraws
+	width      int // digits in an account number
+}
+
+// newEntityTagger prepares the tagger of a run with config, which must have
+// Entities
+func newEntityTagger(config ChaoticConfig) *entityTagger {
+	draws := newSideDraws(config, entityStream)
+	t := &entityTagger{
+		entities:  *config.Entities,
+		draws:     draws,
+		accounts:  draws.fork(accountStream),
+		merchants: draws.fork(merchantStream),
+	}
+	if accounts := t.entities.Accounts; accounts > 0 {
+		t.width = len(strconv.Itoa(accounts - 1))
+	}
+	var cumulative float64
+	for _, m := range t.entities.Merchants {
+		cumulative += m.Weight
+		t.thresholds = append(t.thresholds, cumulative)
+	}
+	return t
+}
+
+// tag sets the entity fields of entry
+func (t *entityTagger) tag(entry *LogEntry) {
+	entry.TransactionID = t.transactionID(entry.Step)
+	if accounts := t.entities.Accounts; accounts > 0 {
+		n := int(t.accounts.uniform(entry.Step) * float64(accounts))
+		entry.AccountID = fmt.Sprintf("acct-%0*d", t.width, min(n, accounts-1))
+	}
+	if len(t.thresholds) > 0 {
+		total := t.thresholds[len(t.thresholds)-1]
+		u := t.merchants.uniform(entry.Step) * total
+		k := sort.Search(len(t.thresholds), func(k int) bool { return u < t.thresholds[k] })
+		entry.Merchant = t.entities.Merchants[min(k, len(t.thresholds)-1)].Label
+	}
+}
+
+// transactionID returns a random version 4 UUID, or for derived draws the
+// version 5 UUID named by the key and the step
+func (t *entityTagger) transactionID(step int) string {
+	var uuid [16]byte
+	if !t.draws.derived() {
+		// crypto/rand.Read never fails
+		rand.Read(uuid[:])
+		uuid[6] = uuid[6]&0x0f | 0x40
+	} else {
+		var name [16]byte
+		binary.BigEndian.PutUint64(name[:8], t.draws.key)
+		binary.BigEndian.PutUint64(name[8:], uint64(step))
+		hash := sha1.New()
+		hash.Write(entityNamespace[:])
+		hash.Write(name[:])
+		copy(uuid[:], hash.Sum(nil))
+		uuid[6] = uuid[6]&0x0f | 0x50
+	}
+	uuid[8] = uuid[8]&0x3f | 0x80 // RFC 4122 variant
+	return formatUUID(uuid)
+}
+
+// formatUUID renders a UUID in its canonical 8-4-4-4-12 hex form
+func formatUUID(uuid [16]byte) string {
+	text := hex.EncodeToString(uuid[:])
+	return text[:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+}
+
+// CountByAccount counts the entries of each account, skipping entries
+// without an account ID. It returns nil when no entry has one.
+func CountByAccount(sequence []LogEntry) map[string]int {
+	var counts map[string]int
+	for _, entry := range sequence {
+		if entry.AccountID == "" {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[entry.AccountID]++
+	}
+	return counts
+}
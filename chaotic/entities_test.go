@@ -0,0 +1,90 @@
+package chaotic
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// uuidPattern matches a canonical RFC 4122 UUID, capturing its version
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// entityFields returns the transaction IDs, account IDs and merchants of
+// sequence, checking that each transaction ID is a UUID of version
+func entityFields(t *testing.T, sequence []LogEntry, version string) ([]string, []string, []string) {
+	t.Helper()
+	var ids, accounts, merchants []string
+	seen := make(map[string]bool, len(sequence))
+	for _, entry := range sequence {
+		match := uuidPattern.FindStringSubmatch(entry.TransactionID)
+		if match == nil || match[1] != version {
+			t.Fatalf("step %d: transaction ID %q is not a version %s UUID", entry.Step, entry.TransactionID, version)
+		}
+		if seen[entry.TransactionID] {
+			t.Fatalf("step %d: transaction ID %s repeats", entry.Step, entry.TransactionID)
+		}
+		seen[entry.TransactionID] = true
+		ids = append(ids, entry.TransactionID)
+		accounts = append(accounts, entry.AccountID)
+		merchants = append(merchants, entry.Merchant)
+	}
+	return ids, accounts, merchants
+}
+
+func TestSeededTransactionIDs(t *testing.T) {
+	run := func(seed int64) []LogEntry {
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.Entities = &Entities{Accounts: 50, Merchants: []Merchant{{Label: "grocer", Weight: 3}, {Label: "fuel", Weight: 1}}}
+		sequence, err := ChaoticTransactionSequence(5000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sequence
+	}
+	first := run(53)
+	ids, accounts, merchants := entityFields(t, first, "5")
+	againIDs, againAccounts, againMerchants := entityFields(t, run(53), "5")
+	if !reflect.DeepEqual(againIDs, ids) || !reflect.DeepEqual(againAccounts, accounts) || !reflect.DeepEqual(againMerchants, merchants) {
+		t.Error("the same seed gave different entity fields")
+	}
+	otherIDs, _, _ := entityFields(t, run(54), "5")
+	if otherIDs[0] == ids[0] {
+		t.Errorf("seeds 53 and 54 both name step 0 %s", ids[0])
+	}
+	// Replays regenerate the IDs of saved runs, so they must not change
+	if want := "981e55e9-1c36-529c-adf3-85d812cac42e"; ids[0] != want {
+		t.Errorf("seed 53 names step 0 %s, want %s", ids[0], want)
+	}
+
+	// The entities come from their own draws, leaving the values alone
+	seed := int64(53)
+	config := DefaultConfig()
+	config.Seed = &seed
+	plain, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(valuesOf(first), valuesOf(plain)) {
+		t.Error("adding entities changed the values")
+	}
+}
+
+func TestUnseededTransactionIDsAreRandom(t *testing.T) {
+	config := DefaultConfig()
+	config.Entities = &Entities{}
+	seen := make(map[string]bool)
+	for range 2 {
+		sequence, err := ChaoticTransactionSequence(5000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, _, _ := entityFields(t, sequence, "4")
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("transaction ID %s repeats across runs", id)
+			}
+			seen[id] = true
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package chaotic
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// SaveToJson saves data to a JSON file with proper error handling
+func SaveToJson(data interface{}, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := WriteJSON(data, file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes data to w as JSON indented with two spaces
+func WriteJSON(data interface{}, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// SaveToCSV writes the sequence to w as CSV: a header row followed by one row
+// per step. The enhanced_value and enhancement_delta columns are only written
+// when some entry carries them (entries without them get empty cells), the
+// is_outlier column only when some entry is flagged, the amount_minor and
+// amount columns only when some entry is a currency amount, the timestamp
+// column only when some entry is timed, the transaction_id, account_id and
+// merchant columns only when some entry has a transaction ID, the
+// direction, posted, balance and rejected columns only when some entry was
+// posted to a ledger, the regime column only when some entry has a regime,
+// the jump column only when some entry jumped, the effective_volatility
+// column only when some entry has one, the clamped column only when some
+// entry was clamped, the anomaly and anomaly_kind columns only when some
+// entry is an injected anomaly, and the missing and duplicate_of columns only
+// when some entry was dropped or repeated, so plain sequences stay at three
+// columns. Amounts are written as the formatted strings, so they read the
+// same on every platform. A missing value, like any other absent field, is
+// an empty cell.
+func SaveToCSV(sequence []LogEntry, w io.Writer) error {
+	columns := []string{"step", "value", "type"}
+	enhanced := hasEnhancement(sequence)
+	if enhanced {
+		columns = append(columns, "enhanced_value", "enhancement_delta")
+	}
+	flagged := hasOutlier(sequence)
+	if flagged {
+		columns = append(columns, "is_outlier")
+	}
+	priced := hasAmount(sequence)
+	if priced {
+		columns = append(columns, "amount_minor", "amount")
+	}
+	timed := hasTimestamp(sequence)
+	if timed {
+		columns = append(columns, "timestamp")
+	}
+	identified := hasTransactionID(sequence)
+	if identified {
+		columns = append(columns, "transaction_id", "account_id", "merchant")
+	}
+	posted := hasBalance(sequence)
+	if posted {
+		columns = append(columns, "direction", "posted", "balance", "rejected")
+	}
+	switched := hasRegime(sequence)
+	if switched {
+		columns = append(columns, "regime")
+	}
+	shocked := hasJump(sequence)
+	if shocked {
+		columns = append(columns, "jump")
+	}
+	clustered := hasEffectiveVolatility(sequence)
+	if clustered {
+		columns = append(columns, "effective_volatility")
+	}
+	clipped := hasClamped(sequence)
+	if clipped {
+		columns = append(columns, "clamped")
+	}
+	injected := hasAnomaly(sequence)
+	if injected {
+		columns = append(columns, "anomaly", "anomaly_kind")
+	}
+	faulty := hasFault(sequence)
+	if faulty {
+		columns = append(columns, "missing", "duplicate_of")
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, entry := range sequence {
+		value := strconv.Itoa(entry.Value)
+		if entry.Missing {
+			value = ""
+		}
+		row := []string{strconv.Itoa(entry.Step), value, entry.Type}
+		if enhanced {
+			row = append(row, formatOptionalInt(entry.EnhancedValue), formatOptionalInt(entry.EnhancementDelta))
+		}
+		if flagged {
+			row = append(row, strconv.FormatBool(entry.IsOutlier))
+		}
+		if priced {
+			minor := ""
+			if entry.AmountMinor != nil {
+				minor = strconv.FormatInt(*entry.AmountMinor, 10)
+			}
+			row = append(row, minor, entry.Amount)
+		}
+		if timed {
+			row = append(row, entry.Timestamp)
+		}
+		if identified {
+			row = append(row, entry.TransactionID, entry.AccountID, entry.Merchant)
+		}
+		if posted {
+			row = append(row, entry.Direction, formatOptionalInt(entry.Posted), formatOptionalInt(entry.Balance),
+				strconv.FormatBool(entry.Rejected))
+		}
+		if switched {
+			row = append(row, entry.Regime)
+		}
+		if shocked {
+			row = append(row, strconv.FormatBool(entry.Jump))
+		}
+		if clustered {
+			volatility := ""
+			if entry.EffectiveVolatility != 0 {
+				volatility = strconv.FormatFloat(entry.EffectiveVolatility, 'g', -1, 64)
+			}
+			row = append(row, volatility)
+		}
+		if clipped {
+			row = append(row, entry.Clamped)
+		}
+		if injected {
+			row = append(row, strconv.FormatBool(entry.Anomaly), entry.AnomalyKind)
+		}
+		if faulty {
+			row = append(row, strconv.FormatBool(entry.Missing), formatOptionalInt(entry.DuplicateOf))
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// hasEnhancement reports whether any entry carries enhanced fields
+func hasEnhancement(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.EnhancedValue != nil || entry.EnhancementDelta != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOutlier reports whether any entry is flagged as an outlier
+func hasOutlier(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.IsOutlier {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAmount reports whether any entry carries a currency amount
+func hasAmount(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.AmountMinor != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTimestamp reports whether any entry carries a timestamp
+func hasTimestamp(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Timestamp != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTransactionID reports whether any entry carries a transaction ID
+func hasTransactionID(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.TransactionID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBalance reports whether any entry was posted to a ledger
+func hasBalance(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Balance != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRegime reports whether any entry was generated in a volatility regime
+func hasRegime(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Regime != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasJump reports whether any entry was shocked by a jump
+func hasJump(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Jump {
+			return true
+		}
+	}
+	return false
+}
+
+// hasEffectiveVolatility reports whether any entry records a GARCH volatility
+func hasEffectiveVolatility(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.EffectiveVolatility != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasClamped reports whether any entry left the range
+func hasClamped(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Clamped != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFault reports whether any entry was dropped or is a duplicate
+func hasFault(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Missing || entry.DuplicateOf != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnomaly reports whether any entry is an injected anomaly
+func hasAnomaly(sequence []LogEntry) bool {
+	for _, entry := range sequence {
+		if entry.Anomaly {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOptionalInt formats an optional integer, using an empty string when unset
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
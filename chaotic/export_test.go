@@ -0,0 +1,58 @@
+package chaotic
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestSaveToCSV(t *testing.T) {
+	enhanced, delta := 20, 10
+	tests := []struct {
+		name     string
+		sequence []LogEntry
+		want     string
+	}{
+		{
+			"plain",
+			[]LogEntry{{Step: 0, Value: 10, Type: "initial"}, {Step: 1, Value: -3, Type: "random_walk"}},
+			"step,value,type\n0,10,initial\n1,-3,random_walk\n",
+		},
+		{
+			// Large values stay plain integers
+			"large value",
+			[]LogEntry{{Step: 0, Value: 12_345_678_901, Type: "initial"}},
+			"step,value,type\n0,12345678901,initial\n",
+		},
+		{
+			"enhanced",
+			[]LogEntry{
+				{Step: 0, Value: 10, Type: "initial", EnhancedValue: &enhanced, EnhancementDelta: &delta},
+				{Step: 1, Value: 12, Type: "random_walk"},
+			},
+			"step,value,type,enhanced_value,enhancement_delta\n0,10,initial,20,10\n1,12,random_walk,,\n",
+		},
+		{"empty", nil, "step,value,type\n"},
+	}
+	for _, tt := range tests {
+		var buf strings.Builder
+		if err := SaveToCSV(tt.sequence, &buf); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: wrote %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// The writer is flushed, so a failing destination is reported
+	if err := SaveToCSV([]LogEntry{{Step: 0, Value: 1, Type: "initial"}}, failingWriter{}); err == nil {
+		t.Error("writing to a failing writer succeeded")
+	}
+}
@@ -0,0 +1,120 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DeliveryFaults simulates an event pipeline that loses and repeats events,
+// for testing deduplication and imputation downstream. Each generated entry
+// is dropped with DropRate, leaving in its place an entry with only its Step
+// and the Missing mark, and an entry that is kept is delivered a second
+// time, straight after itself, with DuplicateRate; the copy is marked
+// DuplicateOf the step it repeats.
+//
+// The faults are applied to the entries after they are generated, so the
+// process, the balance of a Ledger and any other layer go on as though every
+// entry was delivered once. As with Jumps, they are not drawn from the value
+// source: with a Seed they are derived from the seed, so a seeded run drops
+// and repeats the same entries, and otherwise they come from a source of the
+// configured RandomnessMode.
+type DeliveryFaults struct {
+	DropRate      float64 // 0.0 to 1.0 - chance that an entry is lost
+	DuplicateRate float64 // 0.0 to 1.0 - chance that an entry is delivered twice
+}
+
+// Validate reports whether the faults are usable, returning an error
+// wrapping ErrInvalidFaults if not
+func (f DeliveryFaults) Validate() error {
+	if !(f.DropRate >= 0 && f.DropRate <= 1) {
+		return fmt.Errorf("%w: DropRate %v must be between 0.0 and 1.0", ErrInvalidFaults, f.DropRate)
+	}
+	if !(f.DuplicateRate >= 0 && f.DuplicateRate <= 1) {
+		return fmt.Errorf("%w: DuplicateRate %v must be between 0.0 and 1.0", ErrInvalidFaults, f.DuplicateRate)
+	}
+	return nil
+}
+
+// faultStream separates the fault draws from other uses of a seed
+const faultStream = 0x6661756c7473 // "faults"
+
+// faultDuplicateStream forks the duplication draws from the drop draws
+const faultDuplicateStream = 1
+
+// faultInjector decides the faults of one run
+type faultInjector struct {
+	faults     DeliveryFaults
+	drops      sideDraws // whether an entry is lost
+	duplicates sideDraws // whether it is delivered twice
+}
+
+// newFaultInjector prepares the faults of a run with config, which must have
+// Faults
+func newFaultInjector(config ChaoticConfig) *faultInjector {
+	draws := newSideDraws(config, faultStream)
+	return &faultInjector{faults: *config.Faults, drops: draws, duplicates: draws.fork(faultDuplicateStream)}
+}
+
+// deliver returns entry as it is delivered and, when it is delivered twice,
+// the copy that follows it
+func (f *faultInjector) deliver(entry LogEntry) (LogEntry, *LogEntry) {
+	// Both draws are made for every entry, so an unseeded run consumes its
+	// source the same way whatever happens to the entry
+	dropped := f.drops.uniform(entry.Step) < f.faults.DropRate
+	repeated := f.duplicates.uniform(entry.Step) < f.faults.DuplicateRate
+	if dropped {
+		return LogEntry{Step: entry.Step, Missing: true}, nil
+	}
+	if !repeated {
+		return entry, nil
+	}
+	original := entry.Step
+	duplicate := entry
+	duplicate.DuplicateOf = &original
+	return entry, &duplicate
+}
+
+// MarshalJSON encodes the entry with its JSON field names, writing the value
+// of a Missing entry as null
+func (e LogEntry) MarshalJSON() ([]byte, error) {
+	type plain LogEntry
+	if !e.Missing {
+		return json.Marshal(plain(e))
+	}
+	// The shallower Value hides the one of the embedded entry
+	return json.Marshal(struct {
+		plain
+		Value *int `json:"value"`
+	}{plain: plain(e)})
+}
+
+// countFaults returns the number of entries of sequence that are Missing and
+// that are duplicates
+func countFaults(sequence []LogEntry) (int, int) {
+	missing, duplicates := 0, 0
+	for _, entry := range sequence {
+		if entry.Missing {
+			missing++
+		}
+		if entry.DuplicateOf != nil {
+			duplicates++
+		}
+	}
+	return missing, duplicates
+}
+
+// ExcludeMissing returns the entries of sequence that are not Missing, the
+// ones with a value. sequence itself is returned when none are missing.
+func ExcludeMissing(sequence []LogEntry) []LogEntry {
+	missing, _ := countFaults(sequence)
+	if missing == 0 {
+		return sequence
+	}
+	present := make([]LogEntry, 0, len(sequence)-missing)
+	for _, entry := range sequence {
+		if !entry.Missing {
+			present = append(present, entry)
+		}
+	}
+	return present
+}
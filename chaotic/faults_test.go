@@ -0,0 +1,173 @@
+package chaotic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// faultyRun returns a seeded run of n steps with faults and the same run
+// without them
+func faultyRun(t *testing.T, n int, seed int64, faults DeliveryFaults) ([]LogEntry, []LogEntry) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Seed = &seed
+	plain, err := ChaoticTransactionSequence(n, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Faults = &faults
+	faulty, err := ChaoticTransactionSequence(n, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return faulty, plain
+}
+
+func TestFaultsLeaveDynamicsAndRepeatWithSeed(t *testing.T) {
+	const n = 20_000
+	faults := DeliveryFaults{DropRate: 0.1, DuplicateRate: 0.05}
+	faulty, plain := faultyRun(t, n, 47, faults)
+	again, _ := faultyRun(t, n, 47, faults)
+	if !reflect.DeepEqual(again, faulty) {
+		t.Error("the same seed dropped or repeated different entries")
+	}
+	if other, _ := faultyRun(t, n, 48, faults); reflect.DeepEqual(other, faulty) {
+		t.Error("different seeds dropped and repeated the same entries")
+	}
+
+	// Each step is delivered, dropped or delivered twice in order, and
+	// the entries delivered are those of the run without faults
+	missing, duplicates, i := 0, 0, 0
+	for step := range n {
+		entry := faulty[i]
+		i++
+		switch {
+		case entry.Missing:
+			missing++
+			if entry != (LogEntry{Step: step, Missing: true}) {
+				t.Fatalf("step %d: placeholder %+v", step, entry)
+			}
+			continue
+		case entry != plain[step]:
+			t.Fatalf("step %d: delivered %+v, generated %+v", step, entry, plain[step])
+		}
+		if i < len(faulty) && faulty[i].DuplicateOf != nil {
+			duplicates++
+			duplicate := faulty[i]
+			i++
+			if *duplicate.DuplicateOf != step {
+				t.Fatalf("step %d: duplicate of step %d", step, *duplicate.DuplicateOf)
+			}
+			duplicate.DuplicateOf = nil
+			if duplicate != entry {
+				t.Fatalf("step %d: duplicate %+v differs from the entry", step, duplicate)
+			}
+		}
+	}
+	if i != len(faulty) {
+		t.Errorf("%d entries left over after the last step", len(faulty)-i)
+	}
+	// The duplicates are drawn for the delivered entries only
+	if got := float64(missing) / n; !closeTo(got, faults.DropRate, 0.01) {
+		t.Errorf("%.4f of the entries dropped, want about %v", got, faults.DropRate)
+	}
+	if got := float64(duplicates) / float64(n-missing); !closeTo(got, faults.DuplicateRate, 0.01) {
+		t.Errorf("%.4f of the delivered entries repeated, want about %v", got, faults.DuplicateRate)
+	}
+}
+
+func TestStatisticsSkipMissing(t *testing.T) {
+	faulty, _ := faultyRun(t, 2000, 49, DeliveryFaults{DropRate: 0.3, DuplicateRate: 0.1})
+	stats, err := ComputeStatistics(faulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	present, err := ComputeStatistics(ExcludeMissing(faulty))
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, duplicates := countFaults(faulty)
+	if stats.Missing != missing || stats.Duplicates != duplicates || present.Missing != 0 {
+		t.Errorf("missing %d and duplicates %d, counted %d and %d", stats.Missing, stats.Duplicates, missing, duplicates)
+	}
+	// Apart from the count, the placeholders make no difference
+	stats.Missing = 0
+	if !reflect.DeepEqual(stats, present) {
+		t.Errorf("statistics with placeholders\n%+v\ndiffer from those without\n%+v", stats, present)
+	}
+
+	dropped := []LogEntry{{Step: 0, Missing: true}, {Step: 1, Missing: true}}
+	if _, err := ComputeStatistics(dropped); err == nil {
+		t.Error("statistics of placeholders alone gave no error")
+	}
+}
+
+func TestMissingValuesExport(t *testing.T) {
+	sequence := []LogEntry{
+		{Step: 0, Value: 10, Type: "initial"},
+		{Step: 1, Missing: true},
+		{Step: 2, Value: 12, Type: "random_walk"},
+		{Step: 2, Value: 12, Type: "random_walk", DuplicateOf: new(int)},
+	}
+	*sequence[3].DuplicateOf = 2
+
+	// JSON has a null value for a dropped entry
+	data, err := json.Marshal(sequence[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := fields["value"]; !ok || value != nil || fields["missing"] != true {
+		t.Errorf("placeholder marshaled as %s", data)
+	}
+
+	// CSV leaves its value empty
+	var buf bytes.Buffer
+	if err := SaveToCSV(sequence, &buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"step", "value", "type", "missing", "duplicate_of"},
+		{"0", "10", "initial", "false", ""},
+		{"1", "", "", "true", ""},
+		{"2", "12", "random_walk", "false", ""},
+		{"2", "12", "random_walk", "false", "2"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("CSV rows %q, want %q", rows, want)
+	}
+
+	// NDJSON reads the null back as a placeholder
+	buf.Reset()
+	if err := SaveRunToNDJSON(RunDocument{Sequence: sequence}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := LoadNDJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc.Sequence, sequence) {
+		t.Errorf("NDJSON read back %+v", doc.Sequence)
+	}
+}
+
+func TestValidateFaults(t *testing.T) {
+	for _, faults := range []DeliveryFaults{{DropRate: -0.1}, {DropRate: 1.1}, {DuplicateRate: 2}} {
+		config := DefaultConfig()
+		config.Faults = &faults
+		if err := config.Validate(); !errors.Is(err, ErrInvalidFaults) {
+			t.Errorf("%+v: Validate returned %v, want ErrInvalidFaults", faults, err)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package chaotic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FloatLogEntry is a single step of a sequence generated by ChaoticFloatSequence
+type FloatLogEntry struct {
+	Step  int     `json:"step"`
+	Value float64 `json:"value"`
+	Type  string  `json:"type"`
+}
+
+// ChaoticFloatConfig holds configuration for ChaoticFloatSequence. It mirrors
+// ChaoticConfig, with a float range and noise amplitude.
+type ChaoticFloatConfig struct {
+	Volatility     float64 // 0.0 to 1.0 - how chaotic the sequence is
+	TrendStrength  float64 // 0.0 to 1.0 - tendency to follow trends
+	MeanReversion  float64 // 0.0 to 1.0 - tendency to revert to mean
+	MinValue       float64
+	MaxValue       float64
+	Seed           *int64         `json:",omitempty"` // deterministic seed; nil means RandomnessMode applies
+	Source         RandSource     `json:"-"`          // explicit random source, takes precedence over Seed
+	RandomnessMode RandomnessMode `json:",omitempty"` // secure (default) or fast
+	RegimeWeights  *RegimeWeights `json:",omitempty"` // how often each step type is picked; nil means equally often
+
+	MultiplicativeFactors []float64 // the multiplicative step scales the value by one of these, picked uniformly
+	NoiseAmplitude        float64   // additive noise and the second step's random walk are drawn uniformly from ±NoiseAmplitude
+}
+
+// DefaultFloatConfig returns the float counterpart of DefaultConfig
+func DefaultFloatConfig() ChaoticFloatConfig {
+	return ChaoticFloatConfig{
+		Volatility:    0.7,
+		TrendStrength: 0.3,
+		MeanReversion: 0.2,
+		MinValue:      1,
+		MaxValue:      1000,
+
+		MultiplicativeFactors: []float64{0.3, 0.7, 1.3, 1.7, 2.0, -0.5},
+		NoiseAmplitude:        10,
+	}
+}
+
+// Validate reports the first configuration field that would make generation
+// meaningless. The returned error wraps one of the Err* sentinels returned by
+// ChaoticConfig.Validate.
+func (c ChaoticFloatConfig) Validate() error {
+	if !(c.MinValue <= c.MaxValue) {
+		return fmt.Errorf("%w: MinValue %v must not exceed MaxValue %v", ErrInvalidRange, c.MinValue, c.MaxValue)
+	}
+	if math.IsInf(c.MaxValue-c.MinValue, 0) {
+		return fmt.Errorf("%w: [%v, %v] is too wide to sample from", ErrInvalidRange, c.MinValue, c.MaxValue)
+	}
+	if err := validateDynamics(c.Volatility, c.TrendStrength, c.MeanReversion, c.RandomnessMode, c.MultiplicativeFactors, c.RegimeWeights); err != nil {
+		return err
+	}
+	if !(c.NoiseAmplitude >= 0) || math.IsInf(c.NoiseAmplitude, 1) {
+		return fmt.Errorf("%w: NoiseAmplitude %v must be a non-negative number", ErrNoiseAmplitudeOutOfBounds, c.NoiseAmplitude)
+	}
+	return nil
+}
+
+// ChaoticFloatSequence generates a chaotic sequence of n steps with float
+// values, for quantities such as exchange rates where fractions matter. It
+// follows the same process as ChaoticTransactionSequence without rounding
+// each step to an integer, so the two do not produce the same values for a
+// seed.
+func ChaoticFloatSequence(n int, config ChaoticFloatConfig) ([]FloatLogEntry, error) {
+	return ChaoticFloatSequenceCtx(context.Background(), n, config)
+}
+
+// ChaoticFloatSequenceCtx generates a chaotic float sequence of n steps,
+// returning the entries generated so far along with ctx.Err() if ctx is
+// cancelled
+func ChaoticFloatSequenceCtx(ctx context.Context, n int, config ChaoticFloatConfig) ([]FloatLogEntry, error) {
+	if n <= 0 {
+		return nil, errors.New("the number of steps must be a positive integer")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := floatSequenceState{
+		config:     config,
+		src:        selectRandSource(config.Source, config.Seed, config.RandomnessMode),
+		thresholds: regimeThresholds(config.RegimeWeights),
+	}
+	log := make([]FloatLogEntry, 0, n)
+	for i := 0; i < n; i++ {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return log, err
+			}
+		}
+		log = append(log, s.next())
+	}
+	return log, nil
+}
+
+// floatSequenceState carries the float chaotic process from one step to the next
+type floatSequenceState struct {
+	config       ChaoticFloatConfig
+	src          RandSource
+	thresholds   [len(regimeTypes) - 1]float64 // cumulative regime weights
+	step         int
+	prev1, prev2 float64
+	runningMean  float64
+}
+
+// next advances the process by one step, as sequenceState.next does for integers
+func (s *floatSequenceState) next() FloatLogEntry {
+	config := s.config
+	i := s.step
+	s.step++
+
+	switch i {
+	case 0:
+		s.prev1 = config.MinValue + s.src.Float64()*(config.MaxValue-config.MinValue)
+		return FloatLogEntry{Step: 0, Value: s.prev1, Type: "initial"}
+
+	case 1:
+		value := clamp(s.prev1+s.noise(), config.MinValue, config.MaxValue)
+		s.prev1, s.prev2 = value, s.prev1
+		s.runningMean = (s.prev1 + s.prev2) / 2.0
+		return FloatLogEntry{Step: 1, Value: value, Type: "random_walk"}
+	}
+
+	prev1, prev2 := s.prev1, s.prev2
+	var nextValue float64
+
+	stepType := pickStepType(s.src.Float64(), s.thresholds[:])
+	chaosFactor := s.src.Float64()*2 - 1
+
+	switch stepType {
+	case trendFollowing:
+		nextValue = prev1 + (prev1-prev2)*config.TrendStrength + chaosFactor*prev1*0.5
+
+	case meanReversion:
+		nextValue = prev1 - (prev1-s.runningMean)*config.MeanReversion + chaosFactor*prev1*0.3
+
+	case multiplicative:
+		factors := config.MultiplicativeFactors
+		factor := factors[s.src.Intn(len(factors))]
+		nextValue = prev1*factor + chaosFactor*10
+
+	default: // Additive noise with memory
+		nextValue = prev1 + (prev1-prev2)/2 + s.noise()
+	}
+
+	// A step can overflow to infinity near the ends of the float range;
+	// saturate it so the volatility effect cannot turn it into NaN
+	nextValue = clamp(nextValue, -math.MaxFloat64, math.MaxFloat64)
+	nextValue += chaosFactor * nextValue * config.Volatility
+	nextValue = clamp(nextValue, config.MinValue, config.MaxValue)
+
+	s.prev1, s.prev2 = nextValue, prev1
+	s.runningMean = (s.runningMean*float64(i) + nextValue) / float64(i+1)
+
+	return FloatLogEntry{Step: i, Value: nextValue, Type: regimeTypes[stepType]}
+}
+
+// noise draws a uniform float from -NoiseAmplitude to NoiseAmplitude
+func (s *floatSequenceState) noise() float64 {
+	return (s.src.Float64()*2 - 1) * s.config.NoiseAmplitude
+}
+
+// FloatStatistics summarizes a float sequence. Fields have the meaning and
+// JSON names of their Statistics counterparts.
+type FloatStatistics struct {
+	Count                  int      `json:"count"`
+	Mean                   float64  `json:"mean"`
+	Median                 float64  `json:"median"`
+	Stdev                  float64  `json:"stdev"`
+	Variance               float64  `json:"variance"`
+	Min                    float64  `json:"min"`
+	Max                    float64  `json:"max"`
+	Q1                     float64  `json:"q1"`
+	Q3                     float64  `json:"q3"`
+	IQR                    float64  `json:"iqr"`
+	CoefficientOfVariation *float64 `json:"coefficient_of_variation"` // null when the mean is 0
+	MAD                    float64  `json:"mad"`
+	TrendStrength          float64  `json:"trend_strength"`
+	Volatility             float64  `json:"volatility"`
+	Skewness               float64  `json:"skewness"`
+	Kurtosis               float64  `json:"kurtosis"`
+	Lag1Autocorrelation    float64  `json:"lag1_autocorrelation"`
+	MaxDrawdown            float64  `json:"max_drawdown"`
+	MaxDrawdownFraction    float64  `json:"max_drawdown_fraction"`
+	MaxDrawdownPeakStep    int      `json:"max_drawdown_peak_step"`
+	MaxDrawdownTroughStep  int      `json:"max_drawdown_trough_step"`
+	MaxDrawup              float64  `json:"max_drawup"`
+	MaxDrawupFraction      float64  `json:"max_drawup_fraction"`
+	MaxDrawupTroughStep    int      `json:"max_drawup_trough_step"`
+	MaxDrawupPeakStep      int      `json:"max_drawup_peak_step"`
+	TurningPoints          int      `json:"turning_points"`
+	MeanCrossings          int      `json:"mean_crossings"`
+}
+
+// ComputeFloatStatistics computes statistics for a float sequence with the
+// same helpers as ComputeStatistics. Every value must be finite.
+func ComputeFloatStatistics(sequence []FloatLogEntry) (FloatStatistics, error) {
+	var stats FloatStatistics
+	if len(sequence) == 0 {
+		return stats, errors.New("empty sequence")
+	}
+
+	values := make([]float64, len(sequence))
+	for i, entry := range sequence {
+		if math.IsNaN(entry.Value) || math.IsInf(entry.Value, 0) {
+			return stats, fmt.Errorf("value at step %d is not finite: %v", entry.Step, entry.Value)
+		}
+		values[i] = entry.Value
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	stats.Count = len(values)
+	stats.Min, stats.Max = sorted[0], sorted[len(sorted)-1]
+	quartiles := Quantiles(sorted, 0.25, 0.5, 0.75)
+	stats.Q1, stats.Median, stats.Q3 = quartiles[0], quartiles[1], quartiles[2]
+	stats.IQR = stats.Q3 - stats.Q1
+	stats.Mean = calculateMean(values)
+	stats.Stdev = calculateStdev(values, stats.Mean)
+	stats.Variance = stats.Stdev * stats.Stdev
+	stats.CoefficientOfVariation = coefficientOfVariation(stats.Stdev, stats.Mean)
+	stats.MAD = calculateMAD(sorted)
+
+	stats.TrendStrength = calculateTrendStrength(values)
+	stats.Volatility = calculateVolatility(values)
+	stats.Skewness, stats.Kurtosis = calculateShape(values, stats.Mean)
+	stats.Lag1Autocorrelation = calculateLag1Autocorrelation(values, stats.Mean)
+
+	down, up := calculateDrawdowns(values)
+	stats.MaxDrawdown, stats.MaxDrawdownFraction = down.amount, down.fraction
+	stats.MaxDrawdownPeakStep, stats.MaxDrawdownTroughStep = sequence[down.from].Step, sequence[down.to].Step
+	stats.MaxDrawup, stats.MaxDrawupFraction = up.amount, up.fraction
+	stats.MaxDrawupTroughStep, stats.MaxDrawupPeakStep = sequence[up.from].Step, sequence[up.to].Step
+	stats.TurningPoints = calculateTurningPoints(values)
+	stats.MeanCrossings = calculateMeanCrossings(values, stats.Mean)
+	return stats, nil
+}
+
+// FloatRunMetadata describes how a saved float run was produced
+type FloatRunMetadata struct {
+	GeneratedAt      string             `json:"generated_at"`
+	Config           ChaoticFloatConfig `json:"config"`
+	SequenceLength   int                `json:"sequence_length"`
+	Seed             *int64             `json:"seed,omitempty"`
+	GeneratorVersion string             `json:"generator_version,omitempty"`
+	FormatVersion    string             `json:"format_version,omitempty"`
+	Decimals         int                `json:"decimals"` // places values were rounded to, -1 for full precision
+}
+
+// FloatRunDocument is a saved float run, the float counterpart of RunDocument
+type FloatRunDocument struct {
+	Metadata   FloatRunMetadata `json:"metadata"`
+	Statistics FloatStatistics  `json:"statistics"`
+	Sequence   []FloatLogEntry  `json:"sequence"`
+}
+
+// Rounded returns a copy of the document with the sequence values and the
+// float statistics rounded to decimals places and Metadata.Decimals set, so
+// that its JSON prints at most that many digits after the point and files
+// from similar runs diff cleanly. A negative decimals leaves every value at
+// full precision. The configuration is never rounded.
+func (d FloatRunDocument) Rounded(decimals int) FloatRunDocument {
+	if decimals < 0 {
+		d.Metadata.Decimals = -1
+		return d
+	}
+	d.Metadata.Decimals = decimals
+	round := func(x float64) float64 { return roundTo(x, decimals) }
+
+	sequence := make([]FloatLogEntry, len(d.Sequence))
+	for i, entry := range d.Sequence {
+		entry.Value = round(entry.Value)
+		sequence[i] = entry
+	}
+	d.Sequence = sequence
+
+	s := &d.Statistics
+	for _, field := range []*float64{
+		&s.Mean, &s.Median, &s.Stdev, &s.Variance, &s.Min, &s.Max, &s.Q1, &s.Q3, &s.IQR, &s.MAD,
+		&s.TrendStrength, &s.Volatility, &s.Skewness, &s.Kurtosis, &s.Lag1Autocorrelation,
+		&s.MaxDrawdown, &s.MaxDrawdownFraction, &s.MaxDrawup, &s.MaxDrawupFraction,
+	} {
+		*field = round(*field)
+	}
+	if s.CoefficientOfVariation != nil {
+		cv := round(*s.CoefficientOfVariation)
+		s.CoefficientOfVariation = &cv
+	}
+	return d
+}
+
+// roundTo rounds x to decimals places, half away from zero. Values too
+// large to scale have no fractional digits left to round and are returned
+// unchanged.
+func roundTo(x float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	scaled := x * scale
+	if math.IsInf(scaled, 0) || math.IsNaN(scaled) {
+		return x
+	}
+	return math.Round(scaled) / scale
+}
@@ -0,0 +1,85 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// Garch makes the volatility of the chaotic steps cluster, as in a
+// GARCH(1,1) process: big moves are followed by big moves. The variance of
+// each chaotic step is
+//
+//	σ²(t) = Omega + Alpha·r²(t-1) + Beta·σ²(t-1)
+//
+// where r(t-1) is the change into the previous entry relative to the entry
+// before it, and σ(t) replaces ChaoticConfig.Volatility (or that of the
+// active regime) in the volatility term of step t. The variance starts from
+// its long-run level Omega/(1-Alpha-Beta) and is capped at 1, the largest
+// volatility a config allows. Each chaotic entry records its σ(t) as
+// EffectiveVolatility.
+//
+// The clustering shows most clearly in the absolute relative changes when
+// the volatility term drives the steps, as with mean reversion weighted up.
+// Multiplicative steps change the value by whole factors regardless of the
+// volatility, which dilutes it and often pins the variance at its cap.
+//
+// The recursion only reads the values, so it draws nothing: the same Seed
+// gives the same draws with or without Garch.
+type Garch struct {
+	Omega float64 // positive - the constant part of the variance
+	Alpha float64 // not negative - how much the last squared change adds
+	Beta  float64 // not negative - how much of the last variance persists
+}
+
+// Validate reports whether the parameters are usable, returning an error
+// wrapping ErrInvalidGarch if not. Alpha+Beta must be below 1, so the
+// variance has a finite long-run level to return to.
+func (g Garch) Validate() error {
+	if !(g.Omega > 0) || math.IsInf(g.Omega, 1) {
+		return fmt.Errorf("%w: Omega %v must be positive and finite", ErrInvalidGarch, g.Omega)
+	}
+	if !(g.Alpha >= 0) {
+		return fmt.Errorf("%w: Alpha %v must not be negative", ErrInvalidGarch, g.Alpha)
+	}
+	if !(g.Beta >= 0) {
+		return fmt.Errorf("%w: Beta %v must not be negative", ErrInvalidGarch, g.Beta)
+	}
+	if !(g.Alpha+g.Beta < 1) {
+		return fmt.Errorf("%w: Alpha+Beta is %v; it must be below 1 for the variance to be stationary", ErrInvalidGarch, g.Alpha+g.Beta)
+	}
+	return nil
+}
+
+// longRunVariance returns the unconditional variance Omega/(1-Alpha-Beta),
+// capped at 1
+func (g Garch) longRunVariance() float64 {
+	return math.Min(g.Omega/(1-g.Alpha-g.Beta), 1)
+}
+
+// garchFilter tracks the variance of one run
+type garchFilter struct {
+	garch    Garch
+	variance float64 // of the last chaotic step, or the long-run variance before the first
+}
+
+// newGarchFilter prepares the variance of a run with config, which must have
+// Garch
+func newGarchFilter(config ChaoticConfig) *garchFilter {
+	return &garchFilter{garch: *config.Garch, variance: config.Garch.longRunVariance()}
+}
+
+// resume continues the filter from the variance of the last chaotic step;
+// 0, as before the first one, keeps the long-run variance
+func (f *garchFilter) resume(variance float64) {
+	if variance > 0 {
+		f.variance = variance
+	}
+}
+
+// update moves the variance on from the change prev2 to prev1 and returns
+// the volatility of the next step
+func (f *garchFilter) update(prev1, prev2 int) float64 {
+	change := (float64(prev1) - float64(prev2)) / math.Max(math.Abs(float64(prev2)), 1)
+	f.variance = math.Min(f.garch.Omega+f.garch.Alpha*change*change+f.garch.Beta*f.variance, 1)
+	return math.Sqrt(f.variance)
+}
@@ -0,0 +1,86 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// absoluteReturnsLag1 returns the lag-1 autocorrelation of the absolute
+// percent returns of sequence
+func absoluteReturnsLag1(t *testing.T, sequence []LogEntry) float64 {
+	t.Helper()
+	returns, err := ComputeReturns(valuesOf(sequence), ReturnPercent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range returns {
+		returns[i] = math.Abs(r)
+	}
+	r, err := pearsonCorrelation(returns[1:], returns[:len(returns)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestGarchClustersVolatility(t *testing.T) {
+	// Mean reversion toward a target keeps the values away from the bounds,
+	// where the volatility term drives the steps and the plain mode's
+	// absolute returns are close to independent
+	target := 550_000_000
+	for seed := int64(1); seed <= 3; seed++ {
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.RegimeWeights = &RegimeWeights{MeanReversion: 1}
+		config.MinValue, config.MaxValue = 100_000_000, 1_000_000_000
+		config.MeanReversionTarget, config.ReversionSpeed = &target, 0.1
+		config.BoundaryMode = BoundaryReflect
+		plain, err := ChaoticTransactionSequence(20_000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.Garch = &Garch{Omega: 0.01, Alpha: 0.5, Beta: 0.45}
+		clustered, err := ChaoticTransactionSequence(20_000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The standard error of an autocorrelation over 20k returns is
+		// about 0.007
+		if r := absoluteReturnsLag1(t, plain); math.Abs(r) > 0.03 {
+			t.Errorf("seed %d: absolute returns of the plain mode autocorrelate by %.3f, want about 0", seed, r)
+		}
+		if r := absoluteReturnsLag1(t, clustered); r < 0.1 {
+			t.Errorf("seed %d: absolute returns with GARCH autocorrelate by %.3f, want clearly positive", seed, r)
+		}
+
+		for _, entry := range clustered {
+			v := entry.EffectiveVolatility
+			if entry.Step < 2 && v != 0 {
+				t.Fatalf("step %d has effective volatility %v before the chaotic steps", entry.Step, v)
+			}
+			if entry.Step >= 2 && !(v > 0 && v <= 1) {
+				t.Fatalf("step %d has effective volatility %v, want within (0, 1]", entry.Step, v)
+			}
+		}
+	}
+}
+
+func TestValidateGarch(t *testing.T) {
+	for _, g := range []Garch{
+		{Omega: 0, Alpha: 0.1, Beta: 0.8},
+		{Omega: math.Inf(1), Alpha: 0.1, Beta: 0.8},
+		{Omega: 0.01, Alpha: -0.1, Beta: 0.8},
+		{Omega: 0.01, Alpha: 0.1, Beta: -0.8},
+		{Omega: 0.01, Alpha: 0.2, Beta: 0.8},
+		{Omega: 0.01, Alpha: 0.5, Beta: 0.7},
+	} {
+		if err := g.Validate(); !errors.Is(err, ErrInvalidGarch) {
+			t.Errorf("%+v: Validate returned %v, want ErrInvalidGarch", g, err)
+		}
+	}
+	if err := (Garch{Omega: 0.01, Alpha: 0.2, Beta: 0.79}).Validate(); err != nil {
+		t.Errorf("a stationary process: %v", err)
+	}
+}
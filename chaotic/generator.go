@@ -0,0 +1,570 @@
+package chaotic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Generator produces chaotic sequences from a validated configuration. It
+// remembers where its last run ended so the run can be checkpointed with
+// State and continued later with ResumeFromState.
+type Generator struct {
+	config         ChaoticConfig
+	state          *sequenceState // state after the last run, or the state to resume from
+	resume         bool           // whether the next run continues from state
+	progress       func(done, total int)
+	progressStride int     // entries between progress reports; 0 for every 1% of a run
+	saturation     float64 // clamp rate above which a run warns
+	warnings       []error // of the last run
+}
+
+// Option customizes a Generator built by NewGenerator
+type Option func(*Generator)
+
+// WithVolatility sets how chaotic the sequence is (0.0 to 1.0)
+func WithVolatility(volatility float64) Option {
+	return func(g *Generator) {
+		g.config.Volatility = volatility
+	}
+}
+
+// WithTrendStrength sets the tendency to follow trends (0.0 to 1.0)
+func WithTrendStrength(strength float64) Option {
+	return func(g *Generator) {
+		g.config.TrendStrength = strength
+	}
+}
+
+// WithMeanReversion sets the tendency to revert to the mean (0.0 to 1.0)
+func WithMeanReversion(reversion float64) Option {
+	return func(g *Generator) {
+		g.config.MeanReversion = reversion
+	}
+}
+
+// WithLogisticMap generates the values by iterating the logistic map with
+// growth rate r, in (0, 4], from a first x drawn from the source
+func WithLogisticMap(r float64) Option {
+	return func(g *Generator) {
+		g.config.Model = ModelLogistic
+		g.config.LogisticR = r
+	}
+}
+
+// WithTentMap generates the values by iterating the tent map with slope mu,
+// in (0, 2], from a first x drawn from the source
+func WithTentMap(mu float64) Option {
+	return func(g *Generator) {
+		g.config.Model = ModelTent
+		g.config.TentMu = mu
+	}
+}
+
+// WithHenonMap generates the values by iterating the Hénon map with
+// parameters a and b, such as DefaultHenonA and DefaultHenonB, from a first
+// x drawn from the source and a first y of 0
+func WithHenonMap(a, b float64) Option {
+	return func(g *Generator) {
+		g.config.Model = ModelHenon
+		g.config.HenonA, g.config.HenonB = a, b
+	}
+}
+
+// WithLorenz generates the values by sampling the Lorenz system lorenz,
+// such as DefaultLorenz()
+func WithLorenz(lorenz Lorenz) Option {
+	return func(g *Generator) {
+		if lorenz.Start != nil {
+			lorenz.Start = append([]float64(nil), lorenz.Start...)
+		}
+		g.config.Model = ModelLorenz
+		g.config.Lorenz = &lorenz
+	}
+}
+
+// WithMeanReversionTarget makes mean-reversion steps close the fraction
+// speed (0.0 to 1.0) of the distance to a fixed target level, instead of
+// pulling toward the running mean
+func WithMeanReversionTarget(target int, speed float64) Option {
+	return func(g *Generator) {
+		g.config.MeanReversionTarget = &target
+		g.config.ReversionSpeed = speed
+	}
+}
+
+// WithSeed makes the generator deterministic: the same seed, options and
+// length always produce the same sequence
+func WithSeed(seed int64) Option {
+	return func(g *Generator) {
+		g.config.Seed = &seed
+	}
+}
+
+// WithRandSource makes the generator draw from src, e.g. a scripted fake in
+// tests. It takes precedence over WithSeed.
+func WithRandSource(src RandSource) Option {
+	return func(g *Generator) {
+		g.config.Source = src
+	}
+}
+
+// WithRandomnessMode selects secure or fast randomness when no seed or source is given
+func WithRandomnessMode(mode RandomnessMode) Option {
+	return func(g *Generator) {
+		g.config.RandomnessMode = mode
+	}
+}
+
+// WithBoundaryMode selects how values that leave the range are brought back
+// into it, instead of clamping them
+func WithBoundaryMode(mode BoundaryMode) Option {
+	return func(g *Generator) {
+		g.config.BoundaryMode = mode
+	}
+}
+
+// WithRegimeWeights sets how often each step type is picked, relative to
+// the others
+func WithRegimeWeights(weights RegimeWeights) Option {
+	return func(g *Generator) {
+		g.config.RegimeWeights = &weights
+	}
+}
+
+// WithStepGenerator adds a step rule picked with weight alongside the
+// built-in ones, relative to the regime weights
+func WithStepGenerator(generator StepGenerator, weight float64) Option {
+	return func(g *Generator) {
+		steps := append([]WeightedStep(nil), g.config.StepGenerators...)
+		g.config.StepGenerators = append(steps, WeightedStep{Generator: generator, Weight: weight})
+	}
+}
+
+// WithOnStep calls hook with each entry before it is committed; see StepHook
+func WithOnStep(hook StepHook) Option {
+	return func(g *Generator) {
+		g.config.OnStep = hook
+	}
+}
+
+// WithProgress calls report as a run goes on with the number of entries
+// generated so far and the number the run was asked for, every 1% of the run
+// unless WithProgressStride sets another stride, and once more when it
+// completes. Reports stop at an error, and an extended run reports the
+// generation of the base values, not their enhancement.
+func WithProgress(report func(done, total int)) Option {
+	return func(g *Generator) {
+		g.progress = report
+	}
+}
+
+// WithProgressStride makes WithProgress report every steps entries instead
+// of every 1% of a run
+func WithProgressStride(steps int) Option {
+	return func(g *Generator) {
+		g.progressStride = steps
+	}
+}
+
+// WithSaturationWarning makes a run warn, through Warnings, when more than
+// the fraction threshold (0.0 to 1.0) of its entries left the range,
+// instead of DefaultSaturationThreshold; 1 never warns
+func WithSaturationWarning(threshold float64) Option {
+	return func(g *Generator) {
+		g.saturation = threshold
+	}
+}
+
+// WithMultiplicativeFactors sets the factors the multiplicative step picks
+// from to scale the previous value
+func WithMultiplicativeFactors(factors ...float64) Option {
+	return func(g *Generator) {
+		g.config.MultiplicativeFactors = append([]float64(nil), factors...)
+	}
+}
+
+// WithNoiseAmplitude sets the largest step the additive noise and the
+// second step's random walk can take in either direction
+func WithNoiseAmplitude(amplitude int) Option {
+	return func(g *Generator) {
+		g.config.NoiseAmplitude = amplitude
+	}
+}
+
+// WithCurrency makes the generated values amounts of currency in its minor
+// units, such as cents
+func WithCurrency(currency Currency) Option {
+	return func(g *Generator) {
+		g.config.Currency = &currency
+	}
+}
+
+// WithTiming gives the generated entries timestamps
+func WithTiming(timing Timing) Option {
+	return func(g *Generator) {
+		if timing.HourlyRates != nil {
+			timing.HourlyRates = append([]float64(nil), timing.HourlyRates...)
+		}
+		g.config.Timing = &timing
+	}
+}
+
+// WithEntities gives the generated entries transaction IDs and, as
+// configured, accounts and merchants
+func WithEntities(entities Entities) Option {
+	return func(g *Generator) {
+		if entities.Merchants != nil {
+			entities.Merchants = append([]Merchant(nil), entities.Merchants...)
+		}
+		g.config.Entities = &entities
+	}
+}
+
+// WithLedger posts the generated entries as credits and debits to a
+// running balance
+func WithLedger(ledger Ledger) Option {
+	return func(g *Generator) {
+		if ledger.Floor != nil {
+			floor := *ledger.Floor
+			ledger.Floor = &floor
+		}
+		g.config.Ledger = &ledger
+	}
+}
+
+// WithRegimes switches the dynamics of the generated entries between
+// volatility regimes
+func WithRegimes(regimes RegimeSwitching) Option {
+	return func(g *Generator) {
+		regimes.States = append([]VolatilityRegime(nil), regimes.States...)
+		transitions := make([][]float64, len(regimes.Transitions))
+		for i, row := range regimes.Transitions {
+			transitions[i] = append([]float64(nil), row...)
+		}
+		regimes.Transitions = transitions
+		g.config.Regimes = &regimes
+	}
+}
+
+// WithJumps adds rare shocks to the generated values
+func WithJumps(jumps Jumps) Option {
+	return func(g *Generator) {
+		if jumps.Sizes != nil {
+			jumps.Sizes = append([]float64(nil), jumps.Sizes...)
+		}
+		g.config.Jumps = &jumps
+	}
+}
+
+// WithGarch makes the volatility of the generated entries cluster, updating
+// it each step from the last change
+func WithGarch(garch Garch) Option {
+	return func(g *Generator) {
+		g.config.Garch = &garch
+	}
+}
+
+// WithAnomalies injects labelled spikes and level shifts into the generated
+// values
+func WithAnomalies(anomalies AnomalyConfig) Option {
+	return func(g *Generator) {
+		g.config.Anomalies = &anomalies
+	}
+}
+
+// WithFaults drops and duplicates the generated entries as an unreliable
+// event pipeline would
+func WithFaults(faults DeliveryFaults) Option {
+	return func(g *Generator) {
+		g.config.Faults = &faults
+	}
+}
+
+// WithRange sets the inclusive bounds for generated values
+func WithRange(min, max int) Option {
+	return func(g *Generator) {
+		g.config.MinValue = min
+		g.config.MaxValue = max
+	}
+}
+
+// NewGenerator builds a Generator from DefaultConfig with opts applied,
+// returning an error if the resulting configuration is invalid
+func NewGenerator(opts ...Option) (*Generator, error) {
+	g := &Generator{config: DefaultConfig(), saturation: DefaultSaturationThreshold}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if err := g.config.Validate(); err != nil {
+		return nil, err
+	}
+	if !(g.saturation >= 0 && g.saturation <= 1) {
+		return nil, fmt.Errorf("saturation threshold %v must be between 0.0 and 1.0", g.saturation)
+	}
+	if g.progressStride < 0 {
+		return nil, fmt.Errorf("progress stride %d must not be negative", g.progressStride)
+	}
+	return g, nil
+}
+
+// Config returns the configuration the generator was built with
+func (g *Generator) Config() ChaoticConfig {
+	return g.config
+}
+
+// Generate produces a chaotic sequence of n steps, starting from step 0 or,
+// after ResumeFromState, continuing from the restored state
+func (g *Generator) Generate(n int) ([]LogEntry, error) {
+	return g.run(n, false)
+}
+
+// GenerateExtended produces a chaotic sequence of n steps with enhanced values.
+// Enhancement draws happen after the whole run, so a resumed extended run
+// reproduces the base values of an uninterrupted one but not the enhancements.
+func (g *Generator) GenerateExtended(n int) ([]LogEntry, error) {
+	return g.run(n, true)
+}
+
+// run generates n steps, either fresh or from the pending resume state
+func (g *Generator) run(n int, extended bool) ([]LogEntry, error) {
+	var it *SequenceIterator
+	if g.resume {
+		if n <= 0 {
+			return nil, errors.New("the number of steps must be a positive integer")
+		}
+		it = &SequenceIterator{state: g.state, n: g.state.step + n}
+		g.resume = false
+	} else {
+		src := g.config.randSource()
+		it = newSequenceIterator(n, g.config, src, uniformChaos(src))
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := context.Background()
+	var progress *progressReporter
+	if g.progress != nil {
+		progress = newProgressReporter(g.progress, n, g.progressStride)
+	}
+	log, err := collectSequence(ctx, it, n, progress)
+	g.state = it.state
+	g.warnings = nil
+	if err == nil {
+		if warning := saturationWarning(log, g.saturation); warning != nil {
+			g.warnings = append(g.warnings, warning)
+		}
+	}
+	if err != nil || !extended {
+		return log, err
+	}
+	return log, enhanceSequence(ctx, log, g.config, it.state.src)
+}
+
+// Warnings returns the problems the last run finished despite, such as an
+// error wrapping ErrSaturated when too many values left the range. They do
+// not make the run fail: its entries are returned as usual.
+func (g *Generator) Warnings() []error {
+	return g.warnings
+}
+
+// MapX0 returns the first x of the map in the last run, which is drawn from
+// the source unless the config sets MapX0. It returns false unless the
+// config uses a map model and a run has started from step 0.
+func (g *Generator) MapX0() (float64, bool) {
+	if g.state == nil || g.state.x0 == nil {
+		return 0, false
+	}
+	return *g.state.x0, true
+}
+
+// LorenzStart returns the x, y and z the Lorenz system was integrated from in
+// the last run, which are drawn from the source unless the config sets
+// Start. It returns false unless the config uses ModelLorenz and a run has
+// started from step 0.
+func (g *Generator) LorenzStart() ([]float64, bool) {
+	if g.state == nil || g.state.lorenz == nil || g.state.lorenz.start == nil {
+		return nil, false
+	}
+	return g.state.lorenz.start[:], true
+}
+
+// GeneratorState is a checkpoint of a generator's position in its run. RNG
+// holds the random source state and is only captured in seeded mode; in
+// secure or fast mode a resumed run continues from the same position with
+// fresh draws, so it cannot reproduce the uninterrupted values. Clock is
+// the timestamp of the last entry, zero unless the config has a Timing,
+// Balance the balance after it, zero unless the config has a Ledger,
+// Regime the name of its regime, empty unless the config has Regimes,
+// Variance its GARCH variance, zero unless the config has Garch and a
+// chaotic step has been generated, and MapX and MapY the orbit point behind
+// it, zero unless the config uses a map model. With ModelLorenz MapX, MapY
+// and MapZ are the point of the system, and MapLow and MapHigh the extent
+// the burn-in measured.
+type GeneratorState struct {
+	Step        int
+	Prev1       int
+	Prev2       int
+	RunningMean float64
+	RNG         []byte
+	Clock       time.Time
+	Balance     int
+	Regime      string
+	Variance    float64
+	MapX        float64
+	MapY        float64
+	MapZ        float64
+	MapLow      float64
+	MapHigh     float64
+}
+
+// generatorStateVersion is bumped whenever the serialized state layout changes
+const generatorStateVersion = 1
+
+// generatorStateJSON is the serialized form of GeneratorState
+type generatorStateJSON struct {
+	Version     int       `json:"version"`
+	Step        int       `json:"step"`
+	Prev1       int       `json:"prev1"`
+	Prev2       int       `json:"prev2"`
+	RunningMean float64   `json:"running_mean"`
+	RNG         []byte    `json:"rng,omitempty"`
+	Clock       time.Time `json:"clock,omitzero"`
+	Balance     int       `json:"balance,omitempty"`
+	Regime      string    `json:"regime,omitempty"`
+	Variance    float64   `json:"variance,omitempty"`
+	MapX        float64   `json:"map_x,omitempty"`
+	MapY        float64   `json:"map_y,omitempty"`
+	MapZ        float64   `json:"map_z,omitempty"`
+	MapLow      float64   `json:"map_low,omitempty"`
+	MapHigh     float64   `json:"map_high,omitempty"`
+}
+
+// MarshalJSON encodes the state with a format version
+func (s GeneratorState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(generatorStateJSON{
+		Version:     generatorStateVersion,
+		Step:        s.Step,
+		Prev1:       s.Prev1,
+		Prev2:       s.Prev2,
+		RunningMean: s.RunningMean,
+		RNG:         s.RNG,
+		Clock:       s.Clock,
+		Balance:     s.Balance,
+		Regime:      s.Regime,
+		Variance:    s.Variance,
+		MapX:        s.MapX,
+		MapY:        s.MapY,
+		MapZ:        s.MapZ,
+		MapLow:      s.MapLow,
+		MapHigh:     s.MapHigh,
+	})
+}
+
+// UnmarshalJSON decodes a state, rejecting unknown versions and negative steps
+func (s *GeneratorState) UnmarshalJSON(data []byte) error {
+	var raw generatorStateJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Version != generatorStateVersion {
+		return fmt.Errorf("unsupported generator state version %d", raw.Version)
+	}
+	if raw.Step < 0 {
+		return fmt.Errorf("invalid generator state step %d", raw.Step)
+	}
+	*s = GeneratorState{
+		Step:        raw.Step,
+		Prev1:       raw.Prev1,
+		Prev2:       raw.Prev2,
+		RunningMean: raw.RunningMean,
+		RNG:         raw.RNG,
+		Clock:       raw.Clock,
+		Balance:     raw.Balance,
+		Regime:      raw.Regime,
+		Variance:    raw.Variance,
+		MapX:        raw.MapX,
+		MapY:        raw.MapY,
+		MapZ:        raw.MapZ,
+		MapLow:      raw.MapLow,
+		MapHigh:     raw.MapHigh,
+	}
+	return nil
+}
+
+// State returns a checkpoint of where the last run ended (the zero state if
+// nothing has been generated yet)
+func (g *Generator) State() GeneratorState {
+	if g.state == nil {
+		return GeneratorState{}
+	}
+	return g.state.snapshot()
+}
+
+// ResumeFromState makes the next Generate or GenerateExtended call continue
+// from state instead of starting over. With a seeded generator and a state
+// captured in seeded mode, the continued values match an uninterrupted run.
+func (g *Generator) ResumeFromState(state GeneratorState) error {
+	if state.Step < 0 {
+		return fmt.Errorf("invalid generator state step %d", state.Step)
+	}
+	if g.config.Timing != nil && state.Step > 0 && state.Clock.IsZero() {
+		return errors.New("generator state carries no clock but the generator is timed")
+	}
+
+	src := g.config.randSource()
+	if state.RNG != nil {
+		seeded, ok := src.(*prngSource)
+		if !ok || seeded.pcg == nil {
+			return errors.New("generator state carries RNG state but the generator is not seeded")
+		}
+		if err := seeded.pcg.UnmarshalBinary(state.RNG); err != nil {
+			return fmt.Errorf("failed to restore RNG state: %w", err)
+		}
+	}
+
+	g.state = newSequenceState(g.config, src, uniformChaos(src))
+	g.state.step = state.Step
+	g.state.prev1, g.state.prev2 = state.Prev1, state.Prev2
+	g.state.runningMean = state.RunningMean
+	if g.state.clock != nil {
+		g.state.clock.now = state.Clock
+	}
+	if g.state.switcher != nil && state.Step > 0 {
+		regime, ok := g.config.Regimes.index(state.Regime)
+		if !ok {
+			return fmt.Errorf("generator state has unknown regime %q", state.Regime)
+		}
+		g.state.switcher.current = regime
+	}
+	if g.state.garch != nil {
+		g.state.garch.resume(state.Variance)
+	}
+	if isMapModel(g.config.Model) && state.Step > 0 {
+		if math.IsNaN(state.MapX) || math.IsInf(state.MapX, 0) || math.IsNaN(state.MapY) || math.IsInf(state.MapY, 0) {
+			return fmt.Errorf("generator state has map orbit point (%v, %v), which is not finite", state.MapX, state.MapY)
+		}
+		g.state.x, g.state.y = state.MapX, state.MapY
+	}
+	if g.state.lorenz != nil && state.Step > 0 {
+		for _, c := range []float64{state.MapX, state.MapY, state.MapZ, state.MapLow, state.MapHigh} {
+			if math.IsNaN(c) || math.IsInf(c, 0) {
+				return fmt.Errorf("generator state has Lorenz point (%v, %v, %v) and extent [%v, %v], which are not all finite",
+					state.MapX, state.MapY, state.MapZ, state.MapLow, state.MapHigh)
+			}
+		}
+		g.state.lorenz.point = [3]float64{state.MapX, state.MapY, state.MapZ}
+		g.state.lorenz.low, g.state.lorenz.high = state.MapLow, state.MapHigh
+	}
+	if g.state.book != nil && state.Step > 0 {
+		if err := g.state.book.resume(state.Balance); err != nil {
+			return err
+		}
+	}
+	g.resume = true
+	return nil
+}
@@ -0,0 +1,181 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// scriptedSource is a RandSource that returns set draws in order, recording
+// the bound of each Intn
+type scriptedSource struct {
+	ints   []int
+	floats []float64
+	bounds []int
+}
+
+func (s *scriptedSource) Intn(n int) int {
+	s.bounds = append(s.bounds, n)
+	v := s.ints[0]
+	s.ints = s.ints[1:]
+	return v
+}
+
+func (s *scriptedSource) Float64() float64 {
+	v := s.floats[0]
+	s.floats = s.floats[1:]
+	return v
+}
+
+func TestScriptedSourceForcesTrendFollowing(t *testing.T) {
+	// Steps 2 and 3 each draw the step type, under the first quarter for
+	// trend_following, then the chaos factor 2u-1
+	src := &scriptedSource{ints: []int{99, 15}, floats: []float64{0.1, 0.75, 0.2, 0.25}}
+	g, err := NewGenerator(WithRandSource(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sequence, err := g.Generate(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 100 + 5 noise = 105
+	// 105 + int(0.3*5) + int(0.5*105*0.5) = 132, then + int(0.5*132*0.7) = 178
+	// 178 + int(0.3*73) + int(-0.5*178*0.5) = 155, then + int(-0.5*155*0.7) = 101
+	want := []LogEntry{
+		{Step: 0, Value: 100, Type: "initial"},
+		{Step: 1, Value: 105, Type: "random_walk"},
+		{Step: 2, Value: 178, Type: "trend_following"},
+		{Step: 3, Value: 101, Type: "trend_following"},
+	}
+	if !reflect.DeepEqual(sequence, want) {
+		t.Errorf("scripted run\n%+v\nwant\n%+v", sequence, want)
+	}
+	if !reflect.DeepEqual(src.bounds, []int{1000, 21}) || len(src.ints)+len(src.floats) != 0 {
+		t.Errorf("drew Intn bounds %v, leaving %v and %v", src.bounds, src.ints, src.floats)
+	}
+}
+
+func TestScriptedSourceDrivesEnhancedLogic(t *testing.T) {
+	tests := []struct {
+		name        string
+		value, step int
+		chaos       float64
+		draw        int
+		bound, want int
+	}{
+		{"multiple of 11", 22, 1, 0.5, 30, 41, 22*3 + 10},
+		{"multiple of 7", 14, 1, 0.5, 0, 21, 14*2 - 10},
+		{"multiple of 5", 25, 1, 0.5, 10, 11, 12 + 5},
+		{"every 13th step", 17, 26, 0.5, 100, 101, 17 + 50},
+		{"major event", 17, 1, 0.05, 0, 201, 17 - 100},
+		{"normal", 17, 1, 0.5, 20, 21, 17 + 10},
+	}
+	for _, tt := range tests {
+		src := &scriptedSource{ints: []int{tt.draw}, floats: []float64{tt.chaos}}
+		if got := EnhancedChaoticLogic(tt.value, tt.step, src); got != tt.want {
+			t.Errorf("%s: %d, want %d", tt.name, got, tt.want)
+		}
+		if !reflect.DeepEqual(src.bounds, []int{tt.bound}) {
+			t.Errorf("%s: drew Intn bounds %v, want [%d]", tt.name, src.bounds, tt.bound)
+		}
+	}
+}
+
+func TestResumeFromJSONStateMatchesUninterruptedRun(t *testing.T) {
+	const n, stop = 3000, 1234
+	whole, err := NewGenerator(WithSeed(57))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uninterrupted, err := whole.Generate(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := NewGenerator(WithSeed(57))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := first.Generate(stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, err := json.Marshal(first.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new process restores the checkpoint into a fresh generator
+	var state GeneratorState
+	if err := json.Unmarshal(checkpoint, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Step != stop || state.RNG == nil {
+		t.Fatalf("checkpoint at step %d with RNG state %v", state.Step, state.RNG)
+	}
+	second, err := NewGenerator(WithSeed(57))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := second.ResumeFromState(state); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := second.Generate(n - stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed := append(head, tail...); !reflect.DeepEqual(resumed, uninterrupted) {
+		for i := range uninterrupted {
+			if resumed[i] != uninterrupted[i] {
+				t.Fatalf("step %d: resumed %+v, uninterrupted %+v", i, resumed[i], uninterrupted[i])
+			}
+		}
+	}
+}
+
+func TestResumeUnseededContinuesPosition(t *testing.T) {
+	g, err := NewGenerator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := g.Generate(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := g.State()
+	if state.RNG != nil {
+		t.Error("an unseeded generator captured RNG state")
+	}
+	if err := g.ResumeFromState(state); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := g.Generate(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tail[0].Step != 100 || head[99].Step != 99 {
+		t.Errorf("resumed at step %d after step %d", tail[0].Step, head[99].Step)
+	}
+
+	// Seeded draws cannot be restored into a secure source
+	seeded, err := NewGenerator(WithSeed(57))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := seeded.Generate(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ResumeFromState(seeded.State()); err == nil {
+		t.Error("an unseeded generator accepted RNG state")
+	}
+}
+
+func TestGeneratorStateRejectsUnknownVersion(t *testing.T) {
+	var state GeneratorState
+	for _, data := range []string{`{"version": 2, "step": 5}`, `{"version": 1, "step": -1}`} {
+		if err := json.Unmarshal([]byte(data), &state); err == nil {
+			t.Errorf("%s: no error", data)
+		}
+	}
+}
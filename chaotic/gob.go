@@ -0,0 +1,172 @@
+package chaotic
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// gobRun is the gob payload written by SaveToGob. gob flattens pointers
+// and drops zero values, so a coefficient of variation or target distance
+// of 0 would come back as nil, meaning undefined or not measured;
+// HasCoefficientOfVariation and HasTargetDistance tell them apart.
+type gobRun struct {
+	Sequence                  []gobEntry
+	Statistics                Statistics
+	HasCoefficientOfVariation bool
+	HasTargetDistance         bool
+}
+
+// gobEntry is the gob form of a LogEntry. gob flattens pointers and drops
+// zero values, so an enhancement delta of 0 would come back as nil; the
+// Enhanced, Priced and Ledgered flags record whether the optional fields are
+// present.
+type gobEntry struct {
+	Step             int
+	Value            int
+	Type             string
+	Enhanced         bool
+	EnhancedValue    int
+	EnhancementDelta int
+	IsOutlier        bool
+	Priced           bool
+	AmountMinor      int64
+	Amount           string
+	Timestamp        string
+	TransactionID    string
+	AccountID        string
+	Merchant         string
+	Direction        string
+	Ledgered         bool
+	Posted           int
+	Balance          int
+	Rejected         bool
+	Regime           string
+	Jump             bool
+	Volatility       float64
+	Clamped          string
+	Anomaly          bool
+	AnomalyKind      string
+	Missing          bool
+	Duplicate        bool
+	DuplicateOf      int
+}
+
+// SaveToGob writes the sequence and its statistics to w with encoding/gob,
+// the quickest way to hand a run between Go processes
+func SaveToGob(sequence []LogEntry, stats Statistics, w io.Writer) error {
+	run := gobRun{
+		Sequence:                  make([]gobEntry, len(sequence)),
+		Statistics:                stats,
+		HasCoefficientOfVariation: stats.CoefficientOfVariation != nil,
+		HasTargetDistance:         stats.TargetDistance != nil,
+	}
+	for i, entry := range sequence {
+		run.Sequence[i] = gobEntry{
+			Step:          entry.Step,
+			Value:         entry.Value,
+			Type:          entry.Type,
+			IsOutlier:     entry.IsOutlier,
+			Timestamp:     entry.Timestamp,
+			TransactionID: entry.TransactionID,
+			AccountID:     entry.AccountID,
+			Merchant:      entry.Merchant,
+			Direction:     entry.Direction,
+			Rejected:      entry.Rejected,
+			Regime:        entry.Regime,
+			Jump:          entry.Jump,
+			Volatility:    entry.EffectiveVolatility,
+			Clamped:       entry.Clamped,
+			Anomaly:       entry.Anomaly,
+			AnomalyKind:   entry.AnomalyKind,
+			Missing:       entry.Missing,
+		}
+		if entry.EnhancedValue != nil && entry.EnhancementDelta != nil {
+			run.Sequence[i].Enhanced = true
+			run.Sequence[i].EnhancedValue = *entry.EnhancedValue
+			run.Sequence[i].EnhancementDelta = *entry.EnhancementDelta
+		}
+		if entry.AmountMinor != nil {
+			run.Sequence[i].Priced = true
+			run.Sequence[i].AmountMinor = *entry.AmountMinor
+			run.Sequence[i].Amount = entry.Amount
+		}
+		if entry.Posted != nil && entry.Balance != nil {
+			run.Sequence[i].Ledgered = true
+			run.Sequence[i].Posted = *entry.Posted
+			run.Sequence[i].Balance = *entry.Balance
+		}
+		if entry.DuplicateOf != nil {
+			run.Sequence[i].Duplicate = true
+			run.Sequence[i].DuplicateOf = *entry.DuplicateOf
+		}
+	}
+
+	buffered := bufio.NewWriter(w)
+	if err := gob.NewEncoder(buffered).Encode(run); err != nil {
+		return fmt.Errorf("failed to encode gob: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write gob: %w", err)
+	}
+	return nil
+}
+
+// LoadGob reads a sequence and statistics written by SaveToGob. Truncated or
+// corrupted input returns an error rather than a partial run.
+func LoadGob(r io.Reader) ([]LogEntry, Statistics, error) {
+	var run gobRun
+	if err := gob.NewDecoder(bufio.NewReader(r)).Decode(&run); err != nil {
+		return nil, Statistics{}, fmt.Errorf("failed to decode gob: %w", err)
+	}
+
+	sequence := make([]LogEntry, len(run.Sequence))
+	for i, entry := range run.Sequence {
+		sequence[i] = LogEntry{
+			Step:                entry.Step,
+			Value:               entry.Value,
+			Type:                entry.Type,
+			IsOutlier:           entry.IsOutlier,
+			Timestamp:           entry.Timestamp,
+			TransactionID:       entry.TransactionID,
+			AccountID:           entry.AccountID,
+			Merchant:            entry.Merchant,
+			Direction:           entry.Direction,
+			Rejected:            entry.Rejected,
+			Regime:              entry.Regime,
+			Jump:                entry.Jump,
+			EffectiveVolatility: entry.Volatility,
+			Clamped:             entry.Clamped,
+			Anomaly:             entry.Anomaly,
+			AnomalyKind:         entry.AnomalyKind,
+			Missing:             entry.Missing,
+		}
+		if entry.Enhanced {
+			enhanced, delta := entry.EnhancedValue, entry.EnhancementDelta
+			sequence[i].EnhancedValue = &enhanced
+			sequence[i].EnhancementDelta = &delta
+		}
+		if entry.Priced {
+			minor := entry.AmountMinor
+			sequence[i].AmountMinor = &minor
+			sequence[i].Amount = entry.Amount
+		}
+		if entry.Ledgered {
+			posted, balance := entry.Posted, entry.Balance
+			sequence[i].Posted = &posted
+			sequence[i].Balance = &balance
+		}
+		if entry.Duplicate {
+			original := entry.DuplicateOf
+			sequence[i].DuplicateOf = &original
+		}
+	}
+	if run.HasCoefficientOfVariation && run.Statistics.CoefficientOfVariation == nil {
+		run.Statistics.CoefficientOfVariation = new(float64)
+	}
+	if run.HasTargetDistance && run.Statistics.TargetDistance == nil {
+		run.Statistics.TargetDistance = new(float64)
+	}
+	return sequence, run.Statistics, nil
+}
@@ -0,0 +1,162 @@
+package chaotic
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGobRoundTripThroughFile(t *testing.T) {
+	doc := seededDocument(t, 2000, layeredConfig(21))
+	path := filepath.Join(t.TempDir(), "run.gob")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveToGob(doc.Sequence, doc.Statistics, file); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	sequence, stats, err := LoadGob(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sequence, doc.Sequence) {
+		t.Error("the loaded sequence differs")
+	}
+	if !reflect.DeepEqual(stats, doc.Statistics) {
+		t.Errorf("the loaded statistics differ:\n got %+v\nwant %+v", stats, doc.Statistics)
+	}
+}
+
+func TestGobRejectsCorruptInput(t *testing.T) {
+	doc := seededDocument(t, 200, layeredConfig(22))
+	var buf bytes.Buffer
+	if err := SaveToGob(doc.Sequence, doc.Statistics, &buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	flipped := append([]byte(nil), data...)
+	for i := 10; i < len(flipped); i += 37 {
+		flipped[i] ^= 0xff
+	}
+	inputs := map[string][]byte{
+		"empty":     nil,
+		"garbage":   []byte("not a gob stream at all"),
+		"truncated": data[:len(data)/2],
+		"flipped":   flipped,
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			sequence, _, err := LoadGob(bytes.NewReader(input))
+			if err == nil {
+				t.Fatalf("loaded %d entries from corrupt input", len(sequence))
+			}
+			if !strings.Contains(err.Error(), "failed to decode gob") {
+				t.Errorf("error %q is not wrapped", err)
+			}
+		})
+	}
+}
+
+func TestGobKeepsZeroOptionalStatistics(t *testing.T) {
+	// A constant sequence sitting on its target has a coefficient of
+	// variation and a target distance of exactly 0, which must not come
+	// back as nil
+	sequence := entriesOf(50, 50, 50, 50)
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	distance := MeanTargetDistance(sequence, 50)
+	stats.TargetDistance = &distance
+	if *stats.CoefficientOfVariation != 0 || *stats.TargetDistance != 0 {
+		t.Fatalf("coefficient of variation %v, target distance %v; want both 0", *stats.CoefficientOfVariation, *stats.TargetDistance)
+	}
+	unset := stats
+	unset.CoefficientOfVariation, unset.TargetDistance = nil, nil
+
+	for name, want := range map[string]Statistics{"zero": stats, "nil": unset} {
+		var buf bytes.Buffer
+		if err := SaveToGob(sequence, want, &buf); err != nil {
+			t.Fatal(err)
+		}
+		_, got, err := LoadGob(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: loaded coefficient of variation %v and target distance %v, want %v and %v", name,
+				got.CoefficientOfVariation, got.TargetDistance, want.CoefficientOfVariation, want.TargetDistance)
+		}
+	}
+}
+
+// BenchmarkGob compares saving and loading a 1M-entry extended run with gob
+// and with JSON, reporting the size of each
+func BenchmarkGob(b *testing.B) {
+	seed := int64(1)
+	config := DefaultConfig()
+	config.Seed = &seed
+	doc := seededDocument(b, 1_000_000, config)
+
+	formats := []struct {
+		name string
+		save func(*bytes.Buffer) error
+		load func(*bytes.Buffer) error
+	}{
+		{
+			"json",
+			func(buf *bytes.Buffer) error { return WriteJSON(doc, buf) },
+			func(buf *bytes.Buffer) error {
+				_, _, _, err := LoadSequenceFromJSON(buf)
+				return err
+			},
+		},
+		{
+			"gob",
+			func(buf *bytes.Buffer) error { return SaveToGob(doc.Sequence, doc.Statistics, buf) },
+			func(buf *bytes.Buffer) error {
+				_, _, err := LoadGob(buf)
+				return err
+			},
+		},
+	}
+	for _, format := range formats {
+		var saved bytes.Buffer
+		if err := format.save(&saved); err != nil {
+			b.Fatal(err)
+		}
+		b.Run("save/"+format.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			b.ReportAllocs()
+			for b.Loop() {
+				buf.Reset()
+				if err := format.save(&buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(saved.Len()), "bytes")
+		})
+		b.Run("load/"+format.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := format.load(bytes.NewBuffer(saved.Bytes())); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
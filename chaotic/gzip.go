@@ -0,0 +1,63 @@
+package chaotic
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// gzipMagic is the two-byte header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SaveToJsonGz saves data to a gzip-compressed JSON file. The gzip writer is
+// closed before the file so its footer is written, and a failure from either
+// close is reported.
+func SaveToJsonGz(data interface{}, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	compressed := gzip.NewWriter(file)
+	if err := WriteJSON(data, compressed); err != nil {
+		compressed.Close()
+		file.Close()
+		return err
+	}
+	return closeGzip(compressed, file)
+}
+
+// closeGzip closes a gzip writer and then the file beneath it, returning the
+// errors from both
+func closeGzip(compressed *gzip.Writer, file io.Closer) error {
+	gzipErr := compressed.Close()
+	if gzipErr != nil {
+		gzipErr = fmt.Errorf("failed to compress: %w", gzipErr)
+	}
+	fileErr := file.Close()
+	if fileErr != nil {
+		fileErr = fmt.Errorf("failed to close file: %w", fileErr)
+	}
+	return errors.Join(gzipErr, fileErr)
+}
+
+// decompress returns a reader over r that transparently gunzips the stream
+// when it starts with the gzip magic bytes, whatever the file is called
+func decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !bytes.Equal(magic, gzipMagic) {
+		return buffered, nil
+	}
+	compressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	return compressed, nil
+}
@@ -0,0 +1,107 @@
+package chaotic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGzipRoundTrip(t *testing.T) {
+	seed := int64(63)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := RunDocument{
+		Metadata: RunMetadata{
+			Config:           config,
+			SequenceLength:   len(sequence),
+			Seed:             &seed,
+			GeneratorVersion: GeneratorVersion,
+			FormatVersion:    FormatVersion,
+		},
+		Sequence: sequence,
+	}
+	doc.Metadata.Config.Seed = nil
+
+	// The loader goes by the magic bytes, not the file name
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		save       func(interface{}, string) error
+		compressed bool
+	}{
+		{"run.json", SaveToJson, false},
+		{"run.json.gz", SaveToJsonGz, true},
+		{"plain.json.gz", SaveToJson, false},
+		{"compressed.json", SaveToJsonGz, true},
+	}
+	sizes := make(map[bool]int64)
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := tt.save(doc, path); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bytes.HasPrefix(data, gzipMagic); got != tt.compressed {
+			t.Errorf("%s: starts with the gzip magic %v, want %v", tt.name, got, tt.compressed)
+		}
+		sizes[tt.compressed] = int64(len(data))
+		replayed, err := ReplayFromFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(replayed, sequence) {
+			t.Errorf("%s: read back a different run", tt.name)
+		}
+	}
+	if sizes[true]*3 > sizes[false] {
+		t.Errorf("compressed to %d bytes from %d", sizes[true], sizes[false])
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	io.WriteString(writer, "{\"a\": 1}")
+	writer.Close()
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"plain", []byte("{\"a\": 1}"), "{\"a\": 1}"},
+		{"gzip", compressed.Bytes(), "{\"a\": 1}"},
+		{"one byte", []byte{0x1f}, "\x1f"},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		r, err := decompress(bytes.NewReader(tt.input))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("%s: read %q, %v; want %q", tt.name, data, err, tt.want)
+		}
+	}
+
+	// A stream cut short fails rather than passing as a shorter file
+	r, err := decompress(bytes.NewReader(compressed.Bytes()[:compressed.Len()-4]))
+	if err == nil {
+		_, err = io.ReadAll(r)
+	}
+	if err == nil {
+		t.Error("a truncated stream read without error")
+	}
+}
@@ -0,0 +1,70 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// HistogramResult is a histogram of a sequence's values
+type HistogramResult struct {
+	// Edges holds the len(Counts)+1 bin boundaries. Bin i covers
+	// [Edges[i], Edges[i+1]), except the last, which also includes its
+	// upper edge so the largest value is counted.
+	Edges       []float64 `json:"edges"`
+	Counts      []int     `json:"counts"`
+	Frequencies []float64 `json:"frequencies"` // Counts as a fraction of all values
+}
+
+// Histogram bins values into equal-width bins spanning their observed min to
+// max. bins of 0 uses Sturges' rule. A sequence whose values are all equal
+// has no width to divide and always gets a single bin holding every value.
+func Histogram(values []int, bins int) (HistogramResult, error) {
+	if len(values) == 0 {
+		return HistogramResult{}, errors.New("empty sequence")
+	}
+	if bins < 0 {
+		return HistogramResult{}, fmt.Errorf("bins must not be negative, got %d", bins)
+	}
+	if bins == 0 {
+		bins = sturgesBins(len(values))
+	}
+	lo, hi := calculateMinMax(values)
+	if lo == hi {
+		bins = 1
+	}
+
+	edges := make([]float64, bins+1)
+	span := float64(hi) - float64(lo)
+	for i := range edges {
+		edges[i] = float64(lo) + span*float64(i)/float64(bins)
+	}
+	edges[bins] = float64(hi) // exact, whatever the rounding above
+
+	counts := make([]int, bins)
+	for _, v := range values {
+		counts[histogramBin(float64(v), edges)]++
+	}
+
+	frequencies := make([]float64, bins)
+	for i, count := range counts {
+		frequencies[i] = float64(count) / float64(len(values))
+	}
+	return HistogramResult{Edges: edges, Counts: counts, Frequencies: frequencies}, nil
+}
+
+// histogramBin returns the bin of edges that holds x, which must lie within them
+func histogramBin(x float64, edges []float64) int {
+	bins := len(edges) - 1
+	if bins == 1 {
+		return 0
+	}
+	lo, hi := edges[0], edges[bins]
+	bin := min(int((x-lo)*float64(bins)/(hi-lo)), bins-1)
+	// Rounding can put a value that sits on an edge one bin off
+	if bin > 0 && x < edges[bin] {
+		bin--
+	} else if bin < bins-1 && x >= edges[bin+1] {
+		bin++
+	}
+	return bin
+}
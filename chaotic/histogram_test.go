@@ -0,0 +1,115 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHistogramEdgeInclusion(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		bins   int
+		edges  []float64
+		counts []int
+	}{
+		// Each value on an inner edge opens the next bin; the top one
+		// stays in the last
+		{"values on edges", []int{0, 10, 20, 30, 40}, 4, []float64{0, 10, 20, 30, 40}, []int{1, 1, 1, 2}},
+		// Edges at 10/3 and 20/3 fall between integers
+		{"fractional edges", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 3, []float64{0, 10.0 / 3, 20.0 / 3, 10}, []int{4, 3, 4}},
+		{"all at the top", []int{0, 9, 9, 9}, 3, []float64{0, 3, 6, 9}, []int{1, 0, 3}},
+		{"negative values", []int{-4, -2, 0, 0}, 2, []float64{-4, -2, 0}, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := Histogram(tt.values, tt.bins)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(h.Edges, tt.edges) {
+				t.Errorf("edges %v, want %v", h.Edges, tt.edges)
+			}
+			if !reflect.DeepEqual(h.Counts, tt.counts) {
+				t.Errorf("counts %v, want %v", h.Counts, tt.counts)
+			}
+			for i, count := range h.Counts {
+				if h.Frequencies[i] != float64(count)/float64(len(tt.values)) {
+					t.Errorf("frequency %d is %v for %d of %d values", i, h.Frequencies[i], count, len(tt.values))
+				}
+			}
+		})
+	}
+}
+
+func TestHistogramOfConstantSequence(t *testing.T) {
+	h, err := Histogram([]int{5, 5, 5, 5}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := HistogramResult{Edges: []float64{5, 5}, Counts: []int{4}, Frequencies: []float64{1}}
+	if !reflect.DeepEqual(h, want) {
+		t.Errorf("constant sequence: %+v, want %+v", h, want)
+	}
+}
+
+func TestHistogramDefaultBins(t *testing.T) {
+	// Sturges' rule gives ⌈log2 100⌉ + 1 = 8 bins
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+	h, err := Histogram(values, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Counts) != 8 || len(h.Edges) != 9 {
+		t.Errorf("%d bins and %d edges, want 8 and 9", len(h.Counts), len(h.Edges))
+	}
+	total := 0
+	for _, count := range h.Counts {
+		total += count
+	}
+	if total != len(values) {
+		t.Errorf("bins hold %d values, want %d", total, len(values))
+	}
+
+	if _, err := Histogram(nil, 0); err == nil {
+		t.Error("empty sequence succeeded")
+	}
+	if _, err := Histogram(values, -1); err == nil {
+		t.Error("negative bins succeeded")
+	}
+}
+
+func TestHistogramInStatisticsJSON(t *testing.T) {
+	stats, err := ComputeStatistics(entriesOf(1, 2, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"histogram"`) {
+		t.Error("statistics JSON has a histogram nobody asked for")
+	}
+
+	h, err := Histogram([]int{1, 2, 3}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats.Histogram = &h
+	if data, err = json.Marshal(stats); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Statistics
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Histogram, &h) {
+		t.Errorf("histogram round-tripped as %+v, want %+v", decoded.Histogram, h)
+	}
+}
@@ -0,0 +1,612 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// defaultBranchProportions are the rates at which the generator picks each
+// branch when the configuration has no RegimeWeights
+var defaultBranchProportions = RegimeWeights{1, 1, 1, 1}.Proportions()
+
+// specialStepTypes are the types of the generator's first two steps, which
+// are fixed rather than drawn from the branches
+var specialStepTypes = map[string]bool{"initial": true, "random_walk": true}
+
+// ChiSquareResult is the outcome of a chi-square goodness-of-fit test
+type ChiSquareResult struct {
+	Statistic        float64 `json:"statistic"`
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+}
+
+// Pass reports whether the test fails to reject the expected proportions at
+// significance level alpha, e.g. 0.05
+func (r ChiSquareResult) Pass(alpha float64) bool {
+	return r.PValue >= alpha
+}
+
+// BranchFrequencyTest checks with a chi-square goodness-of-fit test whether
+// the step types in log occur in the expected proportions, which must be
+// positive and sum to 1. A nil or empty expected map uses the generator's
+// default split, 0.25 for each of the four branches; for a configuration
+// with RegimeWeights, pass RegimeWeights.Proportions(). The generator's
+// initial and random_walk steps are not counted; any other type missing
+// from expected is an error. The p-value is from the chi-square
+// distribution, which is only a good approximation when every type is
+// expected at least 5 times.
+func BranchFrequencyTest(log []LogEntry, expected map[string]float64) (ChiSquareResult, error) {
+	if len(expected) == 0 {
+		expected = defaultBranchProportions
+	}
+	if len(expected) < 2 {
+		return ChiSquareResult{}, errors.New("need at least 2 expected types")
+	}
+	var total float64
+	for branch, p := range expected {
+		if !(p > 0) {
+			return ChiSquareResult{}, fmt.Errorf("expected proportion of %q must be positive, got %v", branch, p)
+		}
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		return ChiSquareResult{}, fmt.Errorf("expected proportions must sum to 1, got %v", total)
+	}
+
+	observed := make(map[string]int, len(expected))
+	n := 0
+	for _, entry := range log {
+		if specialStepTypes[entry.Type] {
+			continue
+		}
+		if _, ok := expected[entry.Type]; !ok {
+			return ChiSquareResult{}, fmt.Errorf("step %d: unexpected type %q", entry.Step, entry.Type)
+		}
+		observed[entry.Type]++
+		n++
+	}
+	if n == 0 {
+		return ChiSquareResult{}, errors.New("no branch steps to test")
+	}
+
+	var statistic float64
+	for branch, p := range expected {
+		want := p * float64(n)
+		diff := float64(observed[branch]) - want
+		statistic += diff * diff / want
+	}
+	df := len(expected) - 1
+	return ChiSquareResult{
+		Statistic:        statistic,
+		DegreesOfFreedom: df,
+		PValue:           chiSquareSurvival(statistic, df),
+	}, nil
+}
+
+// chiSquareSurvival is P(X >= x) for X chi-square distributed with df degrees of freedom
+func chiSquareSurvival(x float64, df int) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return upperGamma(float64(df)/2, x/2)
+}
+
+// upperGamma is the regularized upper incomplete gamma function Q(a, x),
+// from its series below a+1 and its continued fraction above
+func upperGamma(a, x float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lgamma)
+
+	if x < a+1 {
+		term := 1 / a
+		sum := term
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return math.Max(0, 1-prefix*sum)
+	}
+
+	// Lentz's method for the continued fraction
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return prefix * h
+}
+
+// KSResult is the outcome of a Kolmogorov-Smirnov test
+type KSResult struct {
+	Statistic float64 `json:"statistic"` // D, the largest gap between the two CDFs
+	PValue    float64 `json:"p_value"`
+}
+
+// KSTestUniform tests whether values are drawn uniformly from the integers
+// min through max, as the generator's range suggests. The CDFs are compared
+// exactly at every integer, but the p-value comes from the continuous
+// Kolmogorov distribution, which is conservative for discrete data.
+func KSTestUniform(values []int, min, max int) (KSResult, error) {
+	if len(values) == 0 {
+		return KSResult{}, errors.New("empty sequence")
+	}
+	if min > max {
+		return KSResult{}, fmt.Errorf("%w: min %d must not exceed max %d", ErrInvalidRange, min, max)
+	}
+	for _, v := range values {
+		if v < min || v > max {
+			return KSResult{}, fmt.Errorf("value %d is outside [%d, %d]", v, min, max)
+		}
+	}
+
+	width := float64(max) - float64(min) + 1
+	cdf := func(x int) float64 { return (float64(x) - float64(min) + 1) / width }
+	sorted := sortedCopy(values)
+	n := float64(len(sorted))
+	var d float64
+	for i := 0; i < len(sorted); {
+		x := sorted[i]
+		// Both CDFs are flat between consecutive observed values, so the
+		// gap is largest at one of them or just before
+		below := float64(i) / n
+		d = math.Max(d, math.Abs(below-cdf(x-1)))
+		for i < len(sorted) && sorted[i] == x {
+			i++
+		}
+		d = math.Max(d, math.Abs(float64(i)/n-cdf(x)))
+	}
+	return KSResult{Statistic: d, PValue: kolmogorovSurvival(d, n)}, nil
+}
+
+// KSTestNormal tests whether values look normally distributed, with the mean
+// and standard deviation estimated from values themselves. Estimating them
+// makes the test conservative (Lilliefors' correction would be stricter). A
+// constant sequence returns ErrConstantSequence.
+func KSTestNormal(values []int) (KSResult, error) {
+	if len(values) < 2 {
+		return KSResult{}, fmt.Errorf("need at least 2 values, got %d", len(values))
+	}
+	mean := calculateMean(values)
+	stdev := calculateStdev(values, mean)
+	if stdev == 0 {
+		return KSResult{}, ErrConstantSequence
+	}
+
+	sorted := sortedCopy(values)
+	n := float64(len(sorted))
+	var d float64
+	for i := 0; i < len(sorted); {
+		x := sorted[i]
+		f := 0.5 * math.Erfc(-(float64(x)-mean)/(stdev*math.Sqrt2))
+		below := float64(i) / n
+		for i < len(sorted) && sorted[i] == x {
+			i++
+		}
+		d = math.Max(d, math.Max(math.Abs(below-f), math.Abs(float64(i)/n-f)))
+	}
+	return KSResult{Statistic: d, PValue: kolmogorovSurvival(d, n)}, nil
+}
+
+// KSTestTwoSample tests whether a and b are drawn from the same distribution,
+// e.g. whether two generator configurations are distinguishable. Like
+// KSTestUniform it is conservative on integer data with many ties.
+func KSTestTwoSample(a, b []int) (KSResult, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return KSResult{}, errors.New("empty sequence")
+	}
+
+	sa, sb := sortedCopy(a), sortedCopy(b)
+	na, nb := float64(len(sa)), float64(len(sb))
+	var d float64
+	i, j := 0, 0
+	for i < len(sa) && j < len(sb) {
+		x := min(sa[i], sb[j])
+		for i < len(sa) && sa[i] == x {
+			i++
+		}
+		for j < len(sb) && sb[j] == x {
+			j++
+		}
+		d = math.Max(d, math.Abs(float64(i)/na-float64(j)/nb))
+	}
+	return KSResult{Statistic: d, PValue: kolmogorovSurvival(d, na*nb/(na+nb))}, nil
+}
+
+// kolmogorovSurvival approximates P(D >= d) for a KS statistic over an
+// effective sample size n with the asymptotic Kolmogorov series, using
+// Stephens' small-sample correction
+func kolmogorovSurvival(d, n float64) float64 {
+	sqrtN := math.Sqrt(n)
+	lambda := (sqrtN + 0.12 + 0.11/sqrtN) * d
+	if lambda < 0.2 {
+		// The series converges too slowly here, and the answer is 1 to
+		// well past float64 precision
+		return 1
+	}
+	var sum float64
+	sign := 1.0
+	for k := 1; k <= 100; k++ {
+		term := sign * math.Exp(-2*float64(k*k)*lambda*lambda)
+		sum += term
+		if math.Abs(term) < 1e-16 {
+			break
+		}
+		sign = -sign
+	}
+	return math.Min(1, math.Max(0, 2*sum))
+}
+
+// sortedCopy returns a sorted copy of values
+func sortedCopy(values []int) []int {
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// CorrelationTestResult is the outcome of CorrelationTest
+type CorrelationTestResult struct {
+	Correlation float64 `json:"correlation"` // Pearson correlation of the samples
+	Target      float64 `json:"target"`
+	Statistic   float64 `json:"statistic"` // Fisher z distance from the target, in standard errors
+	PValue      float64 `json:"p_value"`
+}
+
+// Pass reports whether the test fails to reject the target correlation at
+// significance level alpha, e.g. 0.05
+func (r CorrelationTestResult) Pass(alpha float64) bool {
+	return r.PValue >= alpha
+}
+
+// CorrelationTest tests whether the paired samples a and b, such as the
+// returns of two series from GenerateCorrelated, have the target Pearson
+// correlation. It compares Fisher's z-transform of the sample correlation,
+// which is close to normal with standard error 1/√(n-3), against that of
+// the target, so the target must lie strictly between -1 and 1 and there
+// must be at least 4 pairs. The p-value assumes independent pairs; for
+// autocorrelated series it is too small.
+func CorrelationTest(a, b []float64, target float64) (CorrelationTestResult, error) {
+	if len(a) != len(b) {
+		return CorrelationTestResult{}, fmt.Errorf("samples have different lengths, %d and %d", len(a), len(b))
+	}
+	if len(a) < 4 {
+		return CorrelationTestResult{}, fmt.Errorf("need at least 4 pairs, got %d", len(a))
+	}
+	if !(target > -1 && target < 1) {
+		return CorrelationTestResult{}, fmt.Errorf("target correlation %v must be strictly between -1 and 1", target)
+	}
+	r, err := pearsonCorrelation(a, b)
+	if err != nil {
+		return CorrelationTestResult{}, err
+	}
+
+	// atanh(±1) is infinite, and so is the statistic then, as it should be
+	z := (math.Atanh(r) - math.Atanh(target)) * math.Sqrt(float64(len(a)-3))
+	return CorrelationTestResult{
+		Correlation: r,
+		Target:      target,
+		Statistic:   z,
+		PValue:      math.Erfc(math.Abs(z) / math.Sqrt2),
+	}, nil
+}
+
+// pearsonCorrelation returns the Pearson correlation of the paired samples
+// a and b, which must have the same length. Either being constant returns
+// ErrConstantSequence.
+func pearsonCorrelation(a, b []float64) (float64, error) {
+	meanA, meanB := calculateMean(a), calculateMean(b)
+	var cross, sumA, sumB float64
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cross += da * db
+		sumA += da * da
+		sumB += db * db
+	}
+	if sumA == 0 || sumB == 0 {
+		return 0, ErrConstantSequence
+	}
+	// Rounding can carry the ratio just past ±1
+	return math.Max(-1, math.Min(1, cross/math.Sqrt(sumA*sumB))), nil
+}
+
+// RunsMode selects what RunsTest counts runs of
+type RunsMode string
+
+const (
+	// RunsAboveBelowMedian counts runs of values above and below the median,
+	// dropping values equal to it (the default)
+	RunsAboveBelowMedian RunsMode = "median"
+	// RunsChangeSign counts runs of rises and falls between successive
+	// values, dropping steps with no change
+	RunsChangeSign RunsMode = "changes"
+)
+
+// RunsTestResult is the outcome of a Wald-Wolfowitz runs test
+type RunsTestResult struct {
+	Runs     int     `json:"runs"`
+	Positive int     `json:"positive"` // values above the median, or rises
+	Negative int     `json:"negative"` // values below the median, or falls
+	Expected float64 `json:"expected"`
+	Variance float64 `json:"variance"`
+	ZScore   float64 `json:"z_score"`
+	PValue   float64 `json:"p_value"` // two-sided, from the normal approximation
+}
+
+// RunsTest is the Wald-Wolfowitz runs test for serial structure: too few
+// runs (negative z) means like follows like, as in a trend, and too many
+// (positive z) means the series alternates more than chance allows. mode
+// "" is RunsAboveBelowMedian. A constant sequence returns
+// ErrConstantSequence, and one where every counted value falls on the same
+// side, such as a steady ramp in RunsChangeSign mode, returns an error as
+// the test has nothing to compare.
+func RunsTest(values []int, mode RunsMode) (RunsTestResult, error) {
+	if len(values) < 2 {
+		return RunsTestResult{}, fmt.Errorf("need at least 2 values, got %d", len(values))
+	}
+
+	var signs []bool // true for above the median, or a rise
+	switch mode {
+	case "", RunsAboveBelowMedian:
+		// The exact median, which for an even count can fall between values
+		sorted := sortedCopy(values)
+		median := (float64(sorted[(len(sorted)-1)/2]) + float64(sorted[len(sorted)/2])) / 2
+		for _, v := range values {
+			if float64(v) != median {
+				signs = append(signs, float64(v) > median)
+			}
+		}
+	case RunsChangeSign:
+		for i := 1; i < len(values); i++ {
+			if values[i] != values[i-1] {
+				signs = append(signs, values[i] > values[i-1])
+			}
+		}
+	default:
+		return RunsTestResult{}, fmt.Errorf("unknown runs mode %q", mode)
+	}
+	if len(signs) == 0 {
+		return RunsTestResult{}, ErrConstantSequence
+	}
+
+	result := RunsTestResult{Runs: 1}
+	for i, positive := range signs {
+		if positive {
+			result.Positive++
+		} else {
+			result.Negative++
+		}
+		if i > 0 && positive != signs[i-1] {
+			result.Runs++
+		}
+	}
+	if result.Positive == 0 || result.Negative == 0 {
+		return RunsTestResult{}, errors.New("runs test needs values on both sides")
+	}
+
+	n1, n2 := float64(result.Positive), float64(result.Negative)
+	n := n1 + n2
+	result.Expected = 2*n1*n2/n + 1
+	result.Variance = 2 * n1 * n2 * (2*n1*n2 - n) / (n * n * (n - 1))
+	if result.Variance > 0 {
+		result.ZScore = (float64(result.Runs) - result.Expected) / math.Sqrt(result.Variance)
+	}
+	result.PValue = math.Erfc(math.Abs(result.ZScore) / math.Sqrt2)
+	return result, nil
+}
+
+// TestStationarity's limits and thresholds
+const (
+	// stationarityMinLength is the shortest sequence TestStationarity
+	// accepts, 10 values per half
+	stationarityMinLength = 20
+	// stationaritySignificance is the level the split-sample tests are run at
+	stationaritySignificance = 0.05
+	// adfCriticalValue is the 5% Dickey-Fuller critical value for a
+	// regression with a constant, in the large-sample limit
+	adfCriticalValue = -2.86
+)
+
+// StationarityResult is the outcome of TestStationarity
+type StationarityResult struct {
+	MeanT          float64 `json:"mean_t"`           // Welch's t for the difference in half means
+	MeanPValue     float64 `json:"mean_p_value"`     // two-sided
+	VarianceF      float64 `json:"variance_f"`       // first-half variance over second-half variance
+	VariancePValue float64 `json:"variance_p_value"` // two-sided
+	ADFStatistic   float64 `json:"adf_statistic"`    // t statistic of the unit-root coefficient
+	Stationary     bool    `json:"stationary"`
+}
+
+// TestStationarity is a heuristic check that values keep a stable level and
+// spread. It splits the sequence in half and compares the means with
+// Welch's t-test and the variances with an F-test, then runs a simplified
+// augmented Dickey-Fuller regression, Δy(t) = a + g*y(t-1), with no lagged
+// differences. The sequence is judged stationary when neither split test
+// rejects at the 5% level and the ADF statistic is below its 5% critical
+// value of -2.86. The split tests assume roughly independent normal values,
+// so on strongly autocorrelated or heavy-tailed sequences they reject more
+// often than their nominal level. Sequences shorter than 20 values return
+// an error, and constant ones ErrConstantSequence.
+func TestStationarity(values []int) (StationarityResult, error) {
+	if len(values) < stationarityMinLength {
+		return StationarityResult{}, fmt.Errorf("need at least %d values to test stationarity, got %d", stationarityMinLength, len(values))
+	}
+	if calculateStdev(values, calculateMean(values)) == 0 {
+		return StationarityResult{}, ErrConstantSequence
+	}
+
+	first, second := values[:len(values)/2], values[len(values)/2:]
+	n1, n2 := float64(len(first)), float64(len(second))
+	mean1, mean2 := calculateMean(first), calculateMean(second)
+	sd1, sd2 := calculateStdev(first, mean1), calculateStdev(second, mean2)
+	v1, v2 := sd1*sd1, sd2*sd2
+
+	var result StationarityResult
+	if se := math.Sqrt(v1/n1 + v2/n2); se > 0 {
+		result.MeanT = (mean1 - mean2) / se
+		// Welch-Satterthwaite degrees of freedom
+		df := (v1/n1 + v2/n2) * (v1/n1 + v2/n2) / (v1*v1/(n1*n1*(n1-1)) + v2*v2/(n2*n2*(n2-1)))
+		result.MeanPValue = studentTTwoSided(result.MeanT, df)
+	} else {
+		// Both halves are flat, at different levels since the whole is not
+		result.MeanT = math.Copysign(math.MaxFloat64, mean1-mean2)
+	}
+	if v2 > 0 {
+		result.VarianceF = v1 / v2
+		cdf := fCDF(result.VarianceF, n1-1, n2-1)
+		result.VariancePValue = math.Min(1, 2*math.Min(cdf, 1-cdf))
+	} else {
+		result.VarianceF = math.MaxFloat64
+	}
+	result.ADFStatistic = dickeyFuller(values)
+
+	result.Stationary = result.MeanPValue >= stationaritySignificance &&
+		result.VariancePValue >= stationaritySignificance &&
+		result.ADFStatistic < adfCriticalValue
+	return result, nil
+}
+
+// dickeyFuller returns the t statistic of g in the least-squares fit
+// Δy(t) = a + g*y(t-1), or 0 when y(t-1) is constant and g is undefined
+func dickeyFuller(values []int) float64 {
+	n := len(values) - 1
+	x := make([]float64, n) // y(t-1)
+	y := make([]float64, n) // Δy(t)
+	var meanX, meanY float64
+	for t := 1; t <= n; t++ {
+		x[t-1] = float64(values[t-1])
+		y[t-1] = float64(values[t]) - float64(values[t-1])
+		meanX += x[t-1]
+		meanY += y[t-1]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var sxx, sxy float64
+	for i := range x {
+		sxx += (x[i] - meanX) * (x[i] - meanX)
+		sxy += (x[i] - meanX) * (y[i] - meanY)
+	}
+	if sxx == 0 {
+		return 0
+	}
+	g := sxy / sxx
+	a := meanY - g*meanX
+	var rss float64
+	for i := range x {
+		residual := y[i] - a - g*x[i]
+		rss += residual * residual
+	}
+	se := math.Sqrt(rss / float64(n-2) / sxx)
+	if se == 0 {
+		return math.Copysign(math.MaxFloat64, g)
+	}
+	return g / se
+}
+
+// studentTTwoSided is P(|T| >= |t|) for T Student-t distributed with df degrees of freedom
+func studentTTwoSided(t, df float64) float64 {
+	return incompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// fCDF is P(F <= f) for F distributed with d1 and d2 degrees of freedom
+func fCDF(f, d1, d2 float64) float64 {
+	if f <= 0 {
+		return 0
+	}
+	return incompleteBeta(d1/2, d2/2, d1*f/(d1*f+d2))
+}
+
+// incompleteBeta is the regularized incomplete beta function I_x(a, b), from
+// its continued fraction, evaluated on whichever side converges quickly
+func incompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction for incompleteBeta with
+// Lentz's method
+func betaFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m < maxIterations; m++ {
+		fm := float64(m)
+		// Even step
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		// Odd step
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,250 @@
+package chaotic
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// uniformInts returns n independent draws from [lo, hi]
+func uniformInts(n int, seed int64, lo, hi int) []int {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]int, n)
+	for i := range values {
+		values[i] = lo + rng.Intn(hi-lo+1)
+	}
+	return values
+}
+
+// normalInts returns n independent normal draws with the given mean and
+// stdev, rounded
+func normalInts(n int, seed int64, mean, stdev float64) []int {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]int, n)
+	for i := range values {
+		values[i] = int(mean + stdev*rng.NormFloat64() + 0.5)
+	}
+	return values
+}
+
+func TestKSTestUniform(t *testing.T) {
+	uniform, err := KSTestUniform(uniformInts(5000, 1, 1, 1000), 1, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uniform.PValue < 0.01 {
+		t.Errorf("uniform draws: D %.4f, p %.3g; want no rejection", uniform.Statistic, uniform.PValue)
+	}
+	normal, err := KSTestUniform(normalInts(5000, 2, 500, 100), -1000, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normal.PValue > 1e-6 {
+		t.Errorf("normal draws: D %.4f, p %.3g; want rejection", normal.Statistic, normal.PValue)
+	}
+
+	// All four values at the bottom of [1, 4], where the uniform CDF is 0.25
+	piled, err := KSTestUniform([]int{1, 1, 1, 1}, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(piled.Statistic, 0.75, 1e-12) {
+		t.Errorf("D %v, want 0.75", piled.Statistic)
+	}
+
+	if _, err := KSTestUniform([]int{5}, 10, 1); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("min above max returned %v, want ErrInvalidRange", err)
+	}
+	if _, err := KSTestUniform([]int{0, 5}, 1, 10); err == nil {
+		t.Error("a value outside the range succeeded")
+	}
+}
+
+func TestKSTestNormal(t *testing.T) {
+	normal, err := KSTestNormal(normalInts(5000, 3, 0, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normal.PValue < 0.01 {
+		t.Errorf("normal draws: D %.4f, p %.3g; want no rejection", normal.Statistic, normal.PValue)
+	}
+	uniform, err := KSTestNormal(uniformInts(5000, 4, 1, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uniform.PValue > 1e-6 {
+		t.Errorf("uniform draws: D %.4f, p %.3g; want rejection", uniform.Statistic, uniform.PValue)
+	}
+	if _, err := KSTestNormal([]int{3, 3, 3}); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence returned %v, want ErrConstantSequence", err)
+	}
+}
+
+func TestKSTestTwoSampleDistinguishesConfigs(t *testing.T) {
+	run := func(seed int64, volatility float64) []int {
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.Volatility = volatility
+		sequence, err := ChaoticTransactionSequence(2000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return valuesOf(sequence)
+	}
+	calm, wild := run(1, 0.1), run(2, 0.9)
+	result, err := KSTestTwoSample(calm, wild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue > 1e-6 {
+		t.Errorf("volatility 0.1 vs 0.9: D %.4f, p %.3g; want the configs told apart", result.Statistic, result.PValue)
+	}
+
+	// Independent draws from one distribution are not
+	same, err := KSTestTwoSample(uniformInts(3000, 5, 1, 1000), uniformInts(2000, 6, 1, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.PValue < 0.01 {
+		t.Errorf("two uniform samples: D %.4f, p %.3g; want no rejection", same.Statistic, same.PValue)
+	}
+
+	disjoint, err := KSTestTwoSample([]int{1, 2, 3}, []int{4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disjoint.Statistic != 1 {
+		t.Errorf("disjoint samples: D %v, want 1", disjoint.Statistic)
+	}
+	if _, err := KSTestTwoSample(nil, []int{1}); err == nil {
+		t.Error("an empty sample succeeded")
+	}
+}
+
+func TestRunsTestExtremes(t *testing.T) {
+	alternating := make([]int, 40)
+	for i := range alternating {
+		alternating[i] = 1 + 8*(i%2)
+	}
+	ramp := make([]int, 40)
+	for i := range ramp {
+		ramp[i] = i
+	}
+	peak := make([]int, 41)
+	for i := range peak {
+		peak[i] = 20 - abs(i-20)
+	}
+
+	tests := []struct {
+		name     string
+		values   []int
+		mode     RunsMode
+		runs     int
+		positive bool // sign of the z-score
+	}{
+		// 20 values each side of the median of 5, switching every step
+		{"alternating about the median", alternating, RunsAboveBelowMedian, 40, true},
+		{"alternating changes", alternating, RunsChangeSign, 39, true},
+		// The lower half, then the upper
+		{"ramp about the median", ramp, RunsAboveBelowMedian, 2, false},
+		// 20 rises, then 20 falls
+		{"rise then fall", peak, RunsChangeSign, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RunsTest(tt.values, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Runs != tt.runs {
+				t.Errorf("%d runs, want %d", result.Runs, tt.runs)
+			}
+			if result.ZScore > 0 != tt.positive || result.PValue > 1e-4 {
+				t.Errorf("z %.3f, p %.3g; want an extreme z of sign %v", result.ZScore, result.PValue, tt.positive)
+			}
+		})
+	}
+
+	// With 20 on each side: expected 2·20·20/40 + 1 = 21 runs and variance
+	// 2·400·(800 - 40)/(40²·39)
+	result, err := RunsTest(alternating, RunsAboveBelowMedian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Positive != 20 || result.Negative != 20 || result.Expected != 21 ||
+		!closeTo(result.Variance, 800.0*760/(1600*39), 1e-12) {
+		t.Errorf("result %+v", result)
+	}
+}
+
+func TestRunsTestOfIndependentDraws(t *testing.T) {
+	// Only the median mode has independent draws as its null: their changes
+	// alternate more often than chance
+	result, err := RunsTest(uniformInts(2000, 7, 1, 1000), RunsAboveBelowMedian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.01 {
+		t.Errorf("z %.3f, p %.3g; want no rejection", result.ZScore, result.PValue)
+	}
+}
+
+func TestRunsTestErrors(t *testing.T) {
+	if _, err := RunsTest([]int{4, 4, 4, 4}, RunsAboveBelowMedian); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence returned %v, want ErrConstantSequence", err)
+	}
+	if _, err := RunsTest([]int{4, 4, 4, 4}, RunsChangeSign); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence by change returned %v, want ErrConstantSequence", err)
+	}
+	if _, err := RunsTest([]int{1, 2, 3, 4}, RunsChangeSign); err == nil {
+		t.Error("a steady ramp by change succeeded")
+	}
+	if _, err := RunsTest([]int{1, 2}, "sideways"); err == nil {
+		t.Error("an unknown mode succeeded")
+	}
+}
+
+func TestStationarityVerdicts(t *testing.T) {
+	noise := normalInts(2000, 8, 500, 50)
+	shifted := append(normalInts(1000, 9, 500, 50), normalInts(1000, 10, 600, 50)...)
+	widened := append(normalInts(1000, 11, 500, 50), normalInts(1000, 12, 500, 150)...)
+	walk := make([]int, 2000)
+	for i, step := range normalInts(2000, 13, 0, 10) {
+		walk[i] = step
+		if i > 0 {
+			walk[i] += walk[i-1]
+		}
+	}
+	tests := []struct {
+		name   string
+		values []int
+		want   bool
+	}{
+		{"independent noise", noise, true},
+		{"level shift", shifted, false},
+		{"wider second half", widened, false},
+		{"random walk", walk, false},
+	}
+	for _, tt := range tests {
+		result, err := TestStationarity(tt.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Stationary != tt.want {
+			t.Errorf("%s: stationary %v, want %v (%+v)", tt.name, result.Stationary, tt.want, result)
+		}
+	}
+}
+
+func TestStationarityErrors(t *testing.T) {
+	values := uniformInts(20, 14, 1, 1000)
+	if _, err := TestStationarity(values[:19]); err == nil {
+		t.Error("19 values succeeded")
+	}
+	if _, err := TestStationarity(values); err != nil {
+		t.Errorf("20 values: %v", err)
+	}
+	if _, err := TestStationarity(make([]int, 20)); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence returned %v, want ErrConstantSequence", err)
+	}
+}
@@ -0,0 +1,169 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EMA returns the exponential moving average of values with smoothing factor
+// alpha, which must be in (0, 1]; larger alphas follow the series more
+// closely and alpha 1 returns it unchanged. The average is seeded with the
+// first value, so ema[0] == values[0] and each later point is
+// alpha*values[i] + (1-alpha)*ema[i-1].
+func EMA(values []int, alpha float64) ([]float64, error) {
+	if len(values) == 0 {
+		return nil, errors.New("empty sequence")
+	}
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, fmt.Errorf("alpha must be in (0, 1], got %v", alpha)
+	}
+
+	series := make([]float64, len(values))
+	for i, v := range values {
+		series[i] = float64(v)
+	}
+	return smooth(series, alpha), nil
+}
+
+// smooth is EMA over an already converted, non-empty series
+func smooth(series []float64, alpha float64) []float64 {
+	ema := make([]float64, len(series))
+	ema[0] = series[0]
+	for i := 1; i < len(series); i++ {
+		ema[i] = alpha*series[i] + (1-alpha)*ema[i-1]
+	}
+	return ema
+}
+
+// spanAlpha is the EMA smoothing factor conventionally used for an n-step span
+func spanAlpha(n int) float64 {
+	return 2 / float64(n+1)
+}
+
+// MACDPoint holds the MACD values at Step
+type MACDPoint struct {
+	Step      int     `json:"step"`
+	MACD      float64 `json:"macd"`      // fast EMA minus slow EMA
+	Signal    float64 `json:"signal"`    // EMA of the MACD line
+	Histogram float64 `json:"histogram"` // MACD minus Signal
+}
+
+// MACD returns the moving average convergence/divergence of values for every
+// step, from EMAs over spans of fast, slow and signal steps (12, 26 and 9 are
+// the usual choice), each with alpha 2/(span+1). Like EMA, every average is
+// seeded with its first input, so the early points are defined but still
+// settling. fast must be below slow, and both below len(values).
+func MACD(values []int, fast, slow, signal int) ([]MACDPoint, error) {
+	if fast < 1 || signal < 1 {
+		return nil, fmt.Errorf("fast and signal spans must be positive, got %d and %d", fast, signal)
+	}
+	if fast >= slow || slow >= len(values) {
+		return nil, fmt.Errorf("need fast < slow < len(values), got %d, %d and %d values", fast, slow, len(values))
+	}
+
+	fastEMA, err := EMA(values, spanAlpha(fast))
+	if err != nil {
+		return nil, err
+	}
+	slowEMA, err := EMA(values, spanAlpha(slow))
+	if err != nil {
+		return nil, err
+	}
+	line := make([]float64, len(values))
+	for i := range line {
+		line[i] = fastEMA[i] - slowEMA[i]
+	}
+	signalLine := smooth(line, spanAlpha(signal))
+
+	points := make([]MACDPoint, len(values))
+	for i := range points {
+		points[i] = MACDPoint{
+			Step:      i,
+			MACD:      line[i],
+			Signal:    signalLine[i],
+			Histogram: line[i] - signalLine[i],
+		}
+	}
+	return points, nil
+}
+
+// BollingerPoint holds the Bollinger bands around the window ending at Step
+type BollingerPoint struct {
+	Step        int     `json:"step"`
+	Middle      float64 `json:"middle"` // rolling mean
+	Upper       float64 `json:"upper"`
+	Lower       float64 `json:"lower"`
+	OutsideBand bool    `json:"outside_band"` // the value at Step is strictly above Upper or below Lower
+}
+
+// BollingerBands returns the bands k rolling standard deviations above and
+// below the rolling mean of each full trailing window, built on RollingStats.
+// As there, partial windows are omitted, so the first point has Step
+// window-1. A flat window has zero width and never flags its last value.
+func BollingerBands(values []int, window int, k float64) ([]BollingerPoint, error) {
+	if !(k > 0) || math.IsInf(k, 1) {
+		return nil, fmt.Errorf("k must be positive and finite, got %v", k)
+	}
+	rolling, err := RollingStats(values, window)
+	if err != nil {
+		return nil, err
+	}
+
+	bands := make([]BollingerPoint, len(rolling))
+	for i, p := range rolling {
+		v := float64(values[p.Step])
+		upper, lower := p.Mean+k*p.Stdev, p.Mean-k*p.Stdev
+		bands[i] = BollingerPoint{
+			Step:        p.Step,
+			Middle:      p.Mean,
+			Upper:       upper,
+			Lower:       lower,
+			OutsideBand: v > upper || v < lower,
+		}
+	}
+	return bands, nil
+}
+
+// RSI returns the relative strength index of values over period steps with
+// Wilder's smoothing. The first average gain and loss are the plain means of
+// the first period changes, and each later one is (prev*(period-1) +
+// current) / period. rsi[i] is NaN for i < period, where there are not yet
+// period changes to average. A window with gains but no losses scores 100,
+// one with losses but no gains 0, and a flat window 50.
+func RSI(values []int, period int) ([]float64, error) {
+	if period < 1 || period >= len(values) {
+		return nil, fmt.Errorf("period must be between 1 and len(values)-1, got %d for %d values", period, len(values))
+	}
+
+	rsi := make([]float64, len(values))
+	var avgGain, avgLoss float64
+	for i := 1; i < len(values); i++ {
+		change := float64(values[i]) - float64(values[i-1])
+		gain, loss := math.Max(change, 0), math.Max(-change, 0)
+		switch {
+		case i < period:
+			avgGain += gain
+			avgLoss += loss
+			rsi[i] = math.NaN()
+			continue
+		case i == period:
+			avgGain = (avgGain + gain) / float64(period)
+			avgLoss = (avgLoss + loss) / float64(period)
+		default:
+			avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+			avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		}
+
+		switch {
+		case avgLoss == 0 && avgGain == 0:
+			rsi[i] = 50
+		case avgLoss == 0:
+			rsi[i] = 100
+		default:
+			rsi[i] = 100 - 100/(1+avgGain/avgLoss)
+		}
+	}
+	rsi[0] = math.NaN()
+	return rsi, nil
+}
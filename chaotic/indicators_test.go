@@ -0,0 +1,211 @@
+package chaotic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEMA(t *testing.T) {
+	values := []int{10, 20, 30, 30, -6}
+	same, err := EMA(values, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range values {
+		if same[i] != float64(v) {
+			t.Errorf("alpha 1: ema[%d] = %v, want %d", i, same[i], v)
+		}
+	}
+
+	// Seeded with the first value, then halfway to each new one
+	half, err := EMA(values, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{10, 15, 22.5, 26.25, 10.125} {
+		if !closeTo(half[i], want, 1e-12) {
+			t.Errorf("alpha 0.5: ema[%d] = %v, want %v", i, half[i], want)
+		}
+	}
+
+	if got, err := EMA([]int{7}, 0.1); err != nil || len(got) != 1 || got[0] != 7 {
+		t.Errorf("one value: %v, %v; want [7]", got, err)
+	}
+}
+
+func TestEMAErrors(t *testing.T) {
+	for _, alpha := range []float64{0, -0.5, 1.5, math.NaN(), math.Inf(1)} {
+		if _, err := EMA([]int{1, 2}, alpha); err == nil {
+			t.Errorf("alpha %v succeeded", alpha)
+		}
+	}
+	if _, err := EMA(nil, 0.5); err == nil {
+		t.Error("empty sequence succeeded")
+	}
+}
+
+func TestBollingerBandsOnGeneratorOutput(t *testing.T) {
+	seed := int64(10)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := valuesOf(sequence)
+	bands, err := BollingerBands(values, 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rolling, err := RollingStats(values, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bands) != len(rolling) {
+		t.Fatalf("%d bands for %d windows", len(bands), len(rolling))
+	}
+	outside := 0
+	for i, b := range bands {
+		r := rolling[i]
+		if b.Step != r.Step || b.Middle != r.Mean || !closeTo(b.Upper-b.Middle, 2*r.Stdev, 1e-9) ||
+			!closeTo(b.Middle-b.Lower, 2*r.Stdev, 1e-9) {
+			t.Fatalf("band %+v does not match window %+v", b, r)
+		}
+		if b.OutsideBand {
+			outside++
+		}
+	}
+	// Normal noise would leave about 5% of values two stdevs out; the
+	// generator's sudden jumps off a flat stretch add more, but far from all
+	if share := float64(outside) / float64(len(bands)); share < 0.01 || share > 0.25 {
+		t.Errorf("%.2f%% of steps outside the bands", 100*share)
+	}
+}
+
+func TestBollingerBandsOfConstantSeries(t *testing.T) {
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = 250
+	}
+	bands, err := BollingerBands(values, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bands {
+		if b.OutsideBand || b.Upper != 250 || b.Lower != 250 {
+			t.Fatalf("constant series: band %+v", b)
+		}
+	}
+	for _, k := range []float64{0, -1, math.Inf(1), math.NaN()} {
+		if _, err := BollingerBands(values, 10, k); err == nil {
+			t.Errorf("k %v succeeded", k)
+		}
+	}
+}
+
+func TestRSI(t *testing.T) {
+	// Changes +2, -1, +2, -1 over period 2: the first averages are gain 1
+	// and loss 0.5, then Wilder's smoothing gives 1.5 and 0.25, then 0.75
+	// and 0.625
+	rsi, err := RSI([]int{10, 12, 11, 13, 12}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if !math.IsNaN(rsi[i]) {
+			t.Errorf("rsi[%d] = %v before a full period, want NaN", i, rsi[i])
+		}
+	}
+	for i, want := range []float64{100 - 100/3.0, 100 - 100/7.0, 100 - 100/2.2} {
+		if !closeTo(rsi[i+2], want, 1e-9) {
+			t.Errorf("rsi[%d] = %v, want %v", i+2, rsi[i+2], want)
+		}
+	}
+}
+
+func TestRSIOneSidedWindows(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   float64
+	}{
+		{"all gains", []int{1, 2, 4, 7, 11, 16}, 100},
+		{"all losses", []int{16, 11, 7, 4, 2, 1}, 0},
+		{"flat", []int{5, 5, 5, 5, 5, 5}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsi, err := RSI(tt.values, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 3; i < len(rsi); i++ {
+				if rsi[i] != tt.want {
+					t.Errorf("rsi[%d] = %v, want %v", i, rsi[i], tt.want)
+				}
+			}
+		})
+	}
+	for _, period := range []int{0, 6} {
+		if _, err := RSI([]int{1, 2, 3, 4, 5, 6}, period); err == nil {
+			t.Errorf("period %d of 6 values succeeded", period)
+		}
+	}
+}
+
+func TestMACDByHand(t *testing.T) {
+	// Spans 1, 3 and 3 smooth with alphas 1, 0.5 and 0.5: the fast EMA is
+	// the series itself and the slow one 10, 15, 12.5, 16.25
+	points, err := MACD([]int{10, 20, 10, 20, 20}, 1, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MACDPoint{
+		{0, 0, 0, 0},
+		{1, 5, 2.5, 2.5},
+		{2, -2.5, 0, -2.5},
+		{3, 3.75, 1.875, 1.875},
+		{4, 1.875, 1.875, 0},
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Step != w.Step || !closeTo(p.MACD, w.MACD, 1e-12) || !closeTo(p.Signal, w.Signal, 1e-12) ||
+			!closeTo(p.Histogram, w.Histogram, 1e-12) {
+			t.Errorf("point %d is %+v, want %+v", i, p, w)
+		}
+	}
+}
+
+func TestMACDHistogramIsMACDMinusSignal(t *testing.T) {
+	seed := int64(11)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := valuesOf(sequence)
+	points, err := MACD(values, 12, 26, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, _ := EMA(values, 2.0/13)
+	slow, _ := EMA(values, 2.0/27)
+	for i, p := range points {
+		if p.Histogram != p.MACD-p.Signal {
+			t.Fatalf("step %d: histogram %v is not %v - %v", i, p.Histogram, p.MACD, p.Signal)
+		}
+		if p.MACD != fast[i]-slow[i] {
+			t.Fatalf("step %d: MACD %v is not the difference of the EMAs", i, p.MACD)
+		}
+	}
+}
+
+func TestMACDErrors(t *testing.T) {
+	values := make([]int, 30)
+	for _, spans := range [][3]int{{26, 12, 9}, {12, 12, 9}, {12, 30, 9}, {0, 26, 9}, {12, 26, 0}} {
+		if _, err := MACD(values, spans[0], spans[1], spans[2]); err == nil {
+			t.Errorf("spans %v over 30 values succeeded", spans)
+		}
+	}
+}
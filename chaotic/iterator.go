@@ -0,0 +1,56 @@
+package chaotic
+
+// SequenceIterator yields a chaotic sequence one entry at a time, carrying the
+// same state as ChaoticTransactionSequence so consumers can stop early
+type SequenceIterator struct {
+	state     *sequenceState
+	n         int
+	err       error
+	duplicate *LogEntry // the copy of the last entry still to deliver; nil for none
+}
+
+// NewSequenceIterator prepares an iterator over n steps. An invalid length or
+// configuration is reported by Err, and Next returns false straight away.
+func NewSequenceIterator(n int, config ChaoticConfig) *SequenceIterator {
+	src := config.randSource()
+	return newSequenceIterator(n, config, src, uniformChaos(src))
+}
+
+// newSequenceIterator prepares an iterator drawing chaos factors from chaos
+func newSequenceIterator(n int, config ChaoticConfig, src RandSource, chaos func(step int) float64) *SequenceIterator {
+	it := &SequenceIterator{n: n}
+	if err := validateSequence(n, config); err != nil {
+		it.err = err
+		return it
+	}
+	it.state = newSequenceState(config, src, chaos)
+	return it
+}
+
+// Next returns the next entry, or false once all n entries have been produced
+// or the iterator failed. With DeliveryFaults an entry may come back as a
+// Missing placeholder or be followed by its duplicate.
+func (it *SequenceIterator) Next() (LogEntry, bool) {
+	if it.duplicate != nil {
+		entry := *it.duplicate
+		it.duplicate = nil
+		return entry, true
+	}
+	if it.err != nil || it.state.step >= it.n {
+		return LogEntry{}, false
+	}
+	entry, err := it.state.next()
+	if err != nil {
+		it.err = err
+		return LogEntry{}, false
+	}
+	if it.state.faults != nil {
+		entry, it.duplicate = it.state.faults.deliver(entry)
+	}
+	return entry, true
+}
+
+// Err returns the error that stopped the iterator, if any
+func (it *SequenceIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,107 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// Jumps adds rare shocks to the chaotic steps, as in a jump-diffusion
+// process: each step from the third on jumps with Probability, scaling its
+// value by 1+size before it is clamped to the range, where size is drawn
+// from a normal distribution with mean Mean and standard deviation Stdev,
+// or uniformly from Sizes when that is set. A size of -0.3 is a 30% drop.
+// Entries that jumped are marked Jump.
+//
+// As with Timing, the jumps are not drawn from the value source: with a
+// Seed they are derived from the seed, so a seeded run reproduces them, and
+// otherwise they come from a source of the configured RandomnessMode. Steps
+// without a jump are generated exactly as they would be without Jumps.
+type Jumps struct {
+	Probability float64   // 0.0 to 1.0 - chance that a step jumps, e.g. 0.005
+	Mean        float64   // mean jump size, as a fraction of the value
+	Stdev       float64   // standard deviation of the jump size, not negative
+	Sizes       []float64 `json:",omitempty"` // jump sizes to pick from uniformly instead; nil draws them from Mean and Stdev
+}
+
+// Validate reports whether the jumps are usable, returning an error
+// wrapping ErrInvalidJumps if not
+func (j Jumps) Validate() error {
+	if !(j.Probability >= 0 && j.Probability <= 1) {
+		return fmt.Errorf("%w: Probability %v must be between 0.0 and 1.0", ErrInvalidJumps, j.Probability)
+	}
+	if math.IsNaN(j.Mean) || math.IsInf(j.Mean, 0) {
+		return fmt.Errorf("%w: Mean %v must be finite", ErrInvalidJumps, j.Mean)
+	}
+	if !(j.Stdev >= 0) || math.IsInf(j.Stdev, 1) {
+		return fmt.Errorf("%w: Stdev %v must be finite and not negative", ErrInvalidJumps, j.Stdev)
+	}
+	if j.Sizes == nil {
+		return nil
+	}
+	if len(j.Sizes) == 0 {
+		return fmt.Errorf("%w: Sizes must not be empty; leave it nil to draw the sizes", ErrInvalidJumps)
+	}
+	for i, size := range j.Sizes {
+		if math.IsNaN(size) || math.IsInf(size, 0) {
+			return fmt.Errorf("%w: Sizes[%d] %v must be finite", ErrInvalidJumps, i, size)
+		}
+	}
+	return nil
+}
+
+// jumpStream separates the jump draws from other uses of a seed
+const jumpStream = 0x6a756d7073 // "jumps"
+
+// jumpSizeStream forks the jump size draws from the jump occurrence draws
+const jumpSizeStream = 1
+
+// jumpDrawer decides the jumps of one run
+type jumpDrawer struct {
+	jumps Jumps
+	draws sideDraws // whether a step jumps
+	sizes sideDraws // how far
+}
+
+// newJumpDrawer prepares the jumps of a run with config, which must have
+// Jumps
+func newJumpDrawer(config ChaoticConfig) *jumpDrawer {
+	draws := newSideDraws(config, jumpStream)
+	return &jumpDrawer{jumps: *config.Jumps, draws: draws, sizes: draws.fork(jumpSizeStream)}
+}
+
+// jump returns the size of the jump at step, and false if it does not jump
+func (d *jumpDrawer) jump(step int) (float64, bool) {
+	if !(d.draws.uniform(step) < d.jumps.Probability) {
+		return 0, false
+	}
+	u := d.sizes.uniform(step)
+	if sizes := d.jumps.Sizes; sizes != nil {
+		return sizes[min(int(u*float64(len(sizes))), len(sizes)-1)], true
+	}
+	// Shifting the draw half a step keeps it inside (0, 1), where the
+	// normal quantile is finite
+	z := math.Sqrt2 * math.Erfinv(2*(u+0x1p-54)-1)
+	return d.jumps.Mean + d.jumps.Stdev*z, true
+}
+
+// calculateJumps returns the number of entries that jumped and the mean
+// absolute change into the entries that did not, the volatility of the
+// diffusion alone. Without jumps the latter is the plain volatility.
+func calculateJumps(sequence []LogEntry) (int, float64) {
+	jumps, changes := 0, 0
+	var absChange float64
+	for i, entry := range sequence {
+		if entry.Jump {
+			jumps++
+			continue
+		}
+		if i > 0 {
+			changes++
+			absChange += math.Abs(float64(entry.Value) - float64(sequence[i-1].Value))
+		}
+	}
+	if changes == 0 {
+		return jumps, 0
+	}
+	return jumps, absChange / float64(changes)
+}
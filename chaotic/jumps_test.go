@@ -0,0 +1,119 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestJumpFrequency(t *testing.T) {
+	for _, jumps := range []Jumps{
+		{Probability: 0.005, Mean: -0.2, Stdev: 0.1},
+		{Probability: 0.02, Sizes: []float64{-0.5, 0.5}},
+	} {
+		seed := int64(30)
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.MinValue, config.MaxValue = 1, 1_000_000
+		config.Jumps = &jumps
+		const n = 100_000
+		sequence, err := ChaoticTransactionSequence(n, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, entry := range sequence {
+			if entry.Jump {
+				if entry.Step < 2 {
+					t.Fatalf("step %d jumped before the chaotic steps", entry.Step)
+				}
+				count++
+			}
+		}
+		// About 4.5 standard errors at the lower probability
+		if got := float64(count) / (n - 2); !closeTo(got, jumps.Probability, 0.001) {
+			t.Errorf("probability %v: %.4f of steps jumped", jumps.Probability, got)
+		}
+
+		stats, err := ComputeStatistics(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.JumpCount != count {
+			t.Errorf("jump_count %d, counted %d", stats.JumpCount, count)
+		}
+		// The default dynamics move by as much as a jump, so leaving the
+		// jumps out barely changes the volatility
+		if !closeTo(stats.DiffusionVolatility, stats.Volatility, 0.05*stats.Volatility) {
+			t.Errorf("diffusion volatility %.2f, want near the volatility %.2f",
+				stats.DiffusionVolatility, stats.Volatility)
+		}
+	}
+}
+
+func TestDisabledJumpsReproduceRun(t *testing.T) {
+	seed := int64(31)
+	config := DefaultConfig()
+	config.Seed = &seed
+	plain, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainStats, err := ComputeStatistics(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plainStats.JumpCount != 0 || plainStats.DiffusionVolatility != plainStats.Volatility {
+		t.Errorf("without jumps: jump_count %d, diffusion volatility %v, volatility %v",
+			plainStats.JumpCount, plainStats.DiffusionVolatility, plainStats.Volatility)
+	}
+
+	config.Jumps = &Jumps{Probability: 0, Mean: 1, Stdev: 1}
+	disabled, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(disabled, plain) {
+		t.Error("jumps with probability 0 changed the run")
+	}
+
+	// Steps without a jump are generated as without Jumps, so the runs
+	// agree up to the first jump
+	config.Jumps = &Jumps{Probability: 0.01, Mean: 0.5}
+	jumpy, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := -1
+	for i, entry := range jumpy {
+		if entry.Jump {
+			first = i
+			break
+		}
+		if entry != plain[i] {
+			t.Fatalf("step %d differs before any jump", i)
+		}
+	}
+	if first < 0 {
+		t.Fatal("no step jumped")
+	}
+	if jumpy[first].Value == plain[first].Value && jumpy[first].Clamped == "" {
+		t.Errorf("step %d jumped but kept its value %d", first, plain[first].Value)
+	}
+}
+
+func TestValidateJumps(t *testing.T) {
+	for _, jumps := range []Jumps{
+		{Probability: 1.5},
+		{Probability: -0.1},
+		{Probability: 0.1, Mean: math.NaN()},
+		{Probability: 0.1, Stdev: -1},
+		{Probability: 0.1, Sizes: []float64{}},
+		{Probability: 0.1, Sizes: []float64{0.2, math.Inf(1)}},
+	} {
+		if err := jumps.Validate(); !errors.Is(err, ErrInvalidJumps) {
+			t.Errorf("%+v: Validate returned %v, want ErrInvalidJumps", jumps, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// FloorPolicy selects what happens to a debit that would take the balance
+// below the floor
+type FloorPolicy string
+
+// Floor policies for Ledger
+const (
+	FloorResize FloorPolicy = "resize" // post only as much as leaves the balance on the floor (the default)
+	FloorReject FloorPolicy = "reject" // post nothing and mark the entry rejected
+)
+
+// Entry directions set by a Ledger
+const (
+	DirectionCredit = "credit"
+	DirectionDebit  = "debit"
+)
+
+// Ledger makes each entry a credit or a debit of its value to a running
+// balance. It sits on top of the values, which keep their chaotic dynamics:
+// only the posting to the balance is signed, resized or rejected. As with
+// Timing, the directions are not drawn from the value source, so adding a
+// ledger to a configuration leaves its values unchanged.
+type Ledger struct {
+	OpeningBalance   int     // balance before step 0
+	DebitProbability float64 // 0.0 to 1.0 - chance that a step is a debit rather than a credit
+	// Floor is the lowest balance allowed; nil lets it go without bound.
+	// Any posting that would cross it is handled by FloorPolicy.
+	Floor       *int        `json:",omitempty"`
+	FloorPolicy FloorPolicy `json:",omitempty"` // resize when empty
+}
+
+// Validate reports whether the ledger is usable, returning an error wrapping
+// ErrInvalidLedger if not
+func (l Ledger) Validate() error {
+	if !(l.DebitProbability >= 0 && l.DebitProbability <= 1) {
+		return fmt.Errorf("%w: DebitProbability %v must be between 0.0 and 1.0", ErrInvalidLedger, l.DebitProbability)
+	}
+	if l.Floor != nil && l.OpeningBalance < *l.Floor {
+		return fmt.Errorf("%w: OpeningBalance %d is below Floor %d", ErrInvalidLedger, l.OpeningBalance, *l.Floor)
+	}
+	switch l.FloorPolicy {
+	case "", FloorResize, FloorReject:
+	default:
+		return fmt.Errorf("%w: unknown floor policy %q", ErrInvalidLedger, l.FloorPolicy)
+	}
+	return nil
+}
+
+// ledgerStream separates the ledger draws from other uses of a seed
+const ledgerStream = 0x6c6564676572 // "ledger"
+
+// ledgerBook posts the entries of one run to the balance
+type ledgerBook struct {
+	ledger  Ledger
+	draws   sideDraws
+	balance int // balance after the last entry
+}
+
+// newLedgerBook prepares the book of a run with config, which must have a
+// Ledger
+func newLedgerBook(config ChaoticConfig) *ledgerBook {
+	return &ledgerBook{
+		ledger:  *config.Ledger,
+		draws:   newSideDraws(config, ledgerStream),
+		balance: config.Ledger.OpeningBalance,
+	}
+}
+
+// resume continues the book from a balance, which must not be below the floor
+func (b *ledgerBook) resume(balance int) error {
+	if floor := b.ledger.Floor; floor != nil && balance < *floor {
+		return fmt.Errorf("%w: balance %d is below Floor %d", ErrInvalidLedger, balance, *floor)
+	}
+	b.balance = balance
+	return nil
+}
+
+// post draws the direction of entry and posts its value to the balance
+func (b *ledgerBook) post(entry *LogEntry) {
+	posted := entry.Value
+	entry.Direction = DirectionCredit
+	if b.draws.uniform(entry.Step) < b.ledger.DebitProbability {
+		posted = subSat(0, posted)
+		entry.Direction = DirectionDebit
+	}
+
+	balance := addSat(b.balance, posted)
+	if floor := b.ledger.Floor; floor != nil && balance < *floor {
+		if b.ledger.FloorPolicy == FloorReject {
+			posted, balance = 0, b.balance
+			entry.Rejected = true
+		} else {
+			posted, balance = subSat(*floor, b.balance), *floor
+		}
+	}
+	b.balance = balance
+	entry.Posted, entry.Balance = &posted, &balance
+}
+
+// calculateLedger returns the balance after the last entry, the lowest
+// balance of any entry and the share of entries with a direction that are
+// debits, over the entries a Ledger posted. All are 0 when there are none.
+func calculateLedger(sequence []LogEntry) (int, int, float64) {
+	final, lowest := 0, math.MaxInt
+	posted, debits := 0, 0
+	for _, entry := range sequence {
+		if entry.Balance == nil {
+			continue
+		}
+		final, lowest = *entry.Balance, min(lowest, *entry.Balance)
+		posted++
+		if entry.Direction == DirectionDebit {
+			debits++
+		}
+	}
+	if posted == 0 {
+		return 0, 0, 0
+	}
+	return final, lowest, float64(debits) / float64(posted)
+}
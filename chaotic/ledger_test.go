@@ -0,0 +1,82 @@
+package chaotic
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLedgerFloorHeldUnderRepeatedHits(t *testing.T) {
+	// Debits outnumber credits, so the balance keeps running into the floor
+	for _, policy := range []FloorPolicy{FloorResize, FloorReject} {
+		t.Run(string(policy), func(t *testing.T) {
+			seed := int64(26)
+			floor := -200
+			config := DefaultConfig()
+			config.Seed = &seed
+			plain, err := ChaoticTransactionSequence(5000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config.Ledger = &Ledger{OpeningBalance: 1000, DebitProbability: 0.7, Floor: &floor, FloorPolicy: policy}
+			sequence, err := ChaoticTransactionSequence(5000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(valuesOf(sequence), valuesOf(plain)) {
+				t.Fatal("the ledger changed the values")
+			}
+
+			balance, hits, debits, lowest := config.Ledger.OpeningBalance, 0, 0, math.MaxInt
+			for _, entry := range sequence {
+				if entry.Posted == nil || entry.Balance == nil {
+					t.Fatalf("step %d was not posted", entry.Step)
+				}
+				posted := *entry.Posted
+				if *entry.Balance != balance+posted {
+					t.Fatalf("step %d: balance %d after posting %d to %d", entry.Step, *entry.Balance, posted, balance)
+				}
+				balance = *entry.Balance
+				lowest = min(lowest, balance)
+				if balance < floor {
+					t.Fatalf("step %d: balance %d is below the floor %d", entry.Step, balance, floor)
+				}
+
+				switch {
+				case entry.Direction == DirectionCredit:
+					if posted != entry.Value || entry.Rejected {
+						t.Fatalf("step %d: credit of %d posted %d", entry.Step, entry.Value, posted)
+					}
+				case entry.Rejected:
+					if policy != FloorReject || posted != 0 {
+						t.Fatalf("step %d: rejected debit posted %d under %s", entry.Step, posted, policy)
+					}
+					hits++
+				case posted != -entry.Value:
+					// A resized debit stops exactly on the floor
+					if policy != FloorResize || balance != floor {
+						t.Fatalf("step %d: debit of %d posted %d, leaving %d", entry.Step, entry.Value, posted, balance)
+					}
+					hits++
+				}
+				if entry.Direction == DirectionDebit {
+					debits++
+				}
+			}
+			if hits < 100 {
+				t.Errorf("the floor was hit %d times, want it hit repeatedly", hits)
+			}
+
+			stats, err := ComputeStatistics(sequence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.FinalBalance != balance || stats.MinBalance != lowest || stats.MinBalance < floor {
+				t.Errorf("final balance %d, min balance %d; want %d and %d", stats.FinalBalance, stats.MinBalance, balance, lowest)
+			}
+			if want := float64(debits) / float64(len(sequence)); stats.DebitRatio != want || !closeTo(want, 0.7, 0.03) {
+				t.Errorf("debit ratio %.4f, counted %.4f, want about 0.7", stats.DebitRatio, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// rawRunDocument is a RunDocument whose entries have not been type-checked yet
+type rawRunDocument struct {
+	Metadata   RunMetadata              `json:"metadata"`
+	Statistics Statistics               `json:"statistics"`
+	Sequence   []map[string]interface{} `json:"sequence"`
+}
+
+// LoadSequenceFromJSON reads a run document as written by the CLI, gzipped or
+// not. Numbers in the sequence are normalized back to ints, so integral
+// values written as floats (12.0, 1e3) are accepted; a fractional or
+// non-numeric value is rejected with an error naming its step.
+func LoadSequenceFromJSON(r io.Reader) ([]LogEntry, RunMetadata, Statistics, error) {
+	r, err := decompress(r)
+	if err != nil {
+		return nil, RunMetadata{}, Statistics{}, err
+	}
+
+	var raw rawRunDocument
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, RunMetadata{}, Statistics{}, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	sequence, err := LogEntriesFromMaps(raw.Sequence)
+	if err != nil {
+		return nil, raw.Metadata, raw.Statistics, err
+	}
+	return sequence, raw.Metadata, raw.Statistics, nil
+}
+
+// LogEntriesFromMaps converts entries decoded into generic maps, e.g. by
+// json.Unmarshal into []map[string]interface{}, into a sequence ready for
+// ComputeStatistics. Numeric fields may be int, int64, json.Number or
+// float64; floats and json.Numbers must be integral. Anything else is
+// rejected with an error naming the step and the offending type.
+func LogEntriesFromMaps(entries []map[string]interface{}) ([]LogEntry, error) {
+	sequence := make([]LogEntry, len(entries))
+	for i, fields := range entries {
+		entry, err := entryFromMap(fields, i)
+		if err != nil {
+			return nil, err
+		}
+		sequence[i] = entry
+	}
+	return sequence, nil
+}
+
+// entryFromMap converts a decoded JSON object into a LogEntry. index is the
+// entry's position, used to name the step in errors until the step field
+// itself has been read.
+func entryFromMap(fields map[string]interface{}, index int) (LogEntry, error) {
+	var entry LogEntry
+	step, err := intField(fields, "step")
+	if err != nil {
+		return entry, fmt.Errorf("step %d: %w", index, err)
+	}
+	entry.Step = step
+
+	// A missing entry has a null value, left at 0
+	if missing, _ := fields["missing"].(bool); !missing || fields["value"] != nil {
+		if entry.Value, err = intField(fields, "value"); err != nil {
+			return entry, fmt.Errorf("step %d: %w", step, err)
+		}
+	}
+	if v, ok := fields["type"]; ok {
+		if entry.Type, ok = v.(string); !ok {
+			return entry, fmt.Errorf("step %d: type is %T, not a string", step, v)
+		}
+	}
+	for _, flag := range []struct {
+		key string
+		dst *bool
+	}{
+		{"is_outlier", &entry.IsOutlier},
+		{"rejected", &entry.Rejected},
+		{"jump", &entry.Jump},
+		{"anomaly", &entry.Anomaly},
+		{"missing", &entry.Missing},
+	} {
+		if v, ok := fields[flag.key]; ok {
+			if *flag.dst, ok = v.(bool); !ok {
+				return entry, fmt.Errorf("step %d: %s is %T, not a bool", step, flag.key, v)
+			}
+		}
+	}
+	for _, optional := range []struct {
+		key string
+		dst **int
+	}{
+		{"enhanced_value", &entry.EnhancedValue},
+		{"enhancement_delta", &entry.EnhancementDelta},
+		{"posted", &entry.Posted},
+		{"balance", &entry.Balance},
+		{"duplicate_of", &entry.DuplicateOf},
+	} {
+		if v, ok := fields[optional.key]; ok && v != nil {
+			n, err := toInt(v)
+			if err != nil {
+				return entry, fmt.Errorf("step %d: %s: %w", step, optional.key, err)
+			}
+			*optional.dst = &n
+		}
+	}
+	if v, ok := fields["amount_minor"]; ok && v != nil {
+		n, err := toInt(v)
+		if err != nil {
+			return entry, fmt.Errorf("step %d: amount_minor: %w", step, err)
+		}
+		minor := int64(n)
+		entry.AmountMinor = &minor
+	}
+	if v, ok := fields["effective_volatility"]; ok && v != nil {
+		if entry.EffectiveVolatility, err = toFloat(v); err != nil {
+			return entry, fmt.Errorf("step %d: effective_volatility: %w", step, err)
+		}
+	}
+	for _, text := range []struct {
+		key string
+		dst *string
+	}{
+		{"amount", &entry.Amount},
+		{"timestamp", &entry.Timestamp},
+		{"transaction_id", &entry.TransactionID},
+		{"account_id", &entry.AccountID},
+		{"merchant", &entry.Merchant},
+		{"direction", &entry.Direction},
+		{"regime", &entry.Regime},
+		{"clamped", &entry.Clamped},
+		{"anomaly_kind", &entry.AnomalyKind},
+	} {
+		if v, ok := fields[text.key]; ok && v != nil {
+			if *text.dst, ok = v.(string); !ok {
+				return entry, fmt.Errorf("step %d: %s is %T, not a string", step, text.key, v)
+			}
+		}
+	}
+	return entry, nil
+}
+
+// intField reads a required integer field
+func intField(fields map[string]interface{}, key string) (int, error) {
+	v, ok := fields[key]
+	if !ok {
+		return 0, fmt.Errorf("missing %s", key)
+	}
+	n, err := toInt(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return n, nil
+}
+
+// toInt converts a numeric value to an int. It accepts int, int64, json.Number
+// and float64, the last two only when they hold an integral value.
+func toInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		if isIntegral(n) {
+			return int(n), nil
+		}
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return int(i), nil
+		}
+		if f, err := n.Float64(); err == nil && isIntegral(f) {
+			return int(f), nil
+		}
+	}
+	return 0, fmt.Errorf("%v (%T) is not an integer", v, v)
+}
+
+// toFloat converts a numeric value to a float64. It accepts float64,
+// json.Number, int and int64.
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case json.Number:
+		if f, err := n.Float64(); err == nil {
+			return f, nil
+		}
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("%v (%T) is not a number", v, v)
+}
+
+// isIntegral reports whether f is a whole number that fits in an int64
+func isIntegral(f float64) bool {
+	return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+}
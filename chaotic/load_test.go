@@ -0,0 +1,153 @@
+package chaotic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSequenceFromJSONMatchesSavedStatistics(t *testing.T) {
+	doc := seededDocument(t, 2000, layeredConfig(41))
+	var buf bytes.Buffer
+	if err := WriteJSON(doc, &buf); err != nil {
+		t.Fatal(err)
+	}
+	gzipped := filepath.Join(t.TempDir(), "run.json.gz")
+	if err := SaveToJsonGz(doc, gzipped); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(gzipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	for name, r := range map[string]io.Reader{"plain": &buf, "gzip": file} {
+		t.Run(name, func(t *testing.T) {
+			sequence, meta, saved, err := LoadSequenceFromJSON(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sequence, doc.Sequence) {
+				t.Error("the loaded sequence differs from the saved one")
+			}
+			if meta.SequenceLength != doc.Metadata.SequenceLength || *meta.Seed != *doc.Metadata.Seed {
+				t.Errorf("loaded metadata %+v", meta)
+			}
+			stats, err := ComputeStatistics(sequence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(stats, saved) {
+				t.Errorf("statistics of the loaded log\n%+v\ndiffer from the saved ones\n%+v", stats, saved)
+			}
+		})
+	}
+}
+
+func TestLoadSequenceFromJSONRejectsFractionalValue(t *testing.T) {
+	input := `{"metadata": {}, "statistics": {}, "sequence": [
+		{"step": 0, "value": 10, "type": "initial"},
+		{"step": 1, "value": 1e3, "type": "random_walk"},
+		{"step": 2, "value": 12.5, "type": "mean_reversion"}
+	]}`
+	_, _, _, err := LoadSequenceFromJSON(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), "step 2") {
+		t.Errorf("loading a fractional value returned %v, want an error naming step 2", err)
+	}
+
+	valid := strings.Replace(input, "12.5", "12.0", 1)
+	sequence, _, _, err := LoadSequenceFromJSON(strings.NewReader(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOf(sequence); !reflect.DeepEqual(got, []int{10, 1000, 12}) {
+		t.Errorf("loaded values %v, want [10 1000 12]", got)
+	}
+}
+
+func TestLogEntriesFromMapsAfterJSON(t *testing.T) {
+	seed := int64(42)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequenceExtended(500, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Plain Unmarshal decodes numbers as float64, UseNumber as json.Number
+	var floats []map[string]interface{}
+	if err := json.Unmarshal(data, &floats); err != nil {
+		t.Fatal(err)
+	}
+	var numbers []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&numbers); err != nil {
+		t.Fatal(err)
+	}
+	for name, maps := range map[string][]map[string]interface{}{"float64": floats, "json.Number": numbers} {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := LogEntriesFromMaps(maps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, sequence) {
+				t.Error("the decoded sequence differs")
+			}
+			stats, err := ComputeStatistics(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(stats, want) {
+				t.Error("statistics of the decoded sequence differ")
+			}
+		})
+	}
+}
+
+func TestLogEntriesFromMapsValueTypes(t *testing.T) {
+	entries := []map[string]interface{}{
+		{"step": 0, "value": 5, "type": "initial"},
+		{"step": int64(1), "value": int64(6), "type": "random_walk"},
+		{"step": 2.0, "value": 7.0, "type": "trend_following"},
+		{"step": json.Number("3"), "value": json.Number("8"), "type": "mean_reversion"},
+	}
+	sequence, err := LogEntriesFromMaps(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOf(sequence); !reflect.DeepEqual(got, []int{5, 6, 7, 8}) {
+		t.Errorf("values %v, want [5 6 7 8]", got)
+	}
+
+	for _, bad := range []struct {
+		value interface{}
+		want  string
+	}{
+		{7.5, "float64"},
+		{json.Number("7.5"), "json.Number"},
+		{"seven", "string"},
+		{true, "bool"},
+	} {
+		invalid := append([]map[string]interface{}(nil), entries...)
+		invalid[3] = map[string]interface{}{"step": 3, "value": bad.value}
+		_, err := LogEntriesFromMaps(invalid)
+		if err == nil || !strings.Contains(err.Error(), "step 3") || !strings.Contains(err.Error(), bad.want) {
+			t.Errorf("value %v returned %v, want an error naming step 3 and %s", bad.value, err, bad.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// Lorenz sets the system that ModelLorenz samples, the Lorenz equations
+//
+//	dx/dt = Sigma·(y - x)
+//	dy/dt = x·(Rho - z) - y
+//	dz/dt = x·y - Beta·z
+//
+// integrated with the classic fourth-order Runge–Kutta method in steps of
+// Dt. Every Stride integration steps the coordinate Axis is sampled and
+// scaled onto the range. Before the first sample the system is integrated
+// for BurnIn steps from Start: the first half lets the orbit settle onto the
+// attractor, and the extent of Axis over the second half is the interval
+// scaled onto the range, so the values spread over all of it. Later samples
+// beyond that extent are clamped to the nearest end.
+//
+// The integration draws nothing, so the orbit is fixed by Start. Without a
+// Start one is drawn from the source at step 0, and the generator reports
+// it (Generator.LorenzStart) so the run can be reproduced or extended.
+type Lorenz struct {
+	Sigma  float64   // positive - the Prandtl number, 10 in the classic system
+	Rho    float64   // positive - the Rayleigh number, 28 in the classic system
+	Beta   float64   // positive - the geometric factor, 8/3 in the classic system
+	Dt     float64   // positive - the integration step; RK4 loses the attractor above about 0.05 with the classic system
+	Stride int       // positive - integration steps from one sample to the next
+	Axis   string    `json:",omitempty"` // x, y or z - the coordinate sampled; empty means x
+	BurnIn int       // at least 2 - integration steps before the first sample
+	Start  []float64 `json:",omitempty"` // the x, y and z to integrate from; nil draws them
+}
+
+// DefaultLorenz returns the classic system of Lorenz's 1963 paper, sampling
+// x every 0.05 time units after a burn-in of 100
+func DefaultLorenz() Lorenz {
+	return Lorenz{Sigma: 10, Rho: 28, Beta: 8.0 / 3, Dt: 0.01, Stride: 5, BurnIn: 10000}
+}
+
+// Validate reports whether the system is usable, returning an error
+// wrapping ErrInvalidLorenz if not
+func (l Lorenz) Validate() error {
+	for _, p := range []struct {
+		name  string
+		value float64
+	}{
+		{"Sigma", l.Sigma},
+		{"Rho", l.Rho},
+		{"Beta", l.Beta},
+		{"Dt", l.Dt},
+	} {
+		if !(p.value > 0) || math.IsInf(p.value, 1) {
+			return fmt.Errorf("%w: %s %v must be positive and finite", ErrInvalidLorenz, p.name, p.value)
+		}
+	}
+	if l.Stride < 1 {
+		return fmt.Errorf("%w: Stride %d must be positive", ErrInvalidLorenz, l.Stride)
+	}
+	if _, ok := lorenzAxis(l.Axis); !ok {
+		return fmt.Errorf("%w: unknown Axis %q; use x, y or z", ErrInvalidLorenz, l.Axis)
+	}
+	if l.BurnIn < 2 {
+		return fmt.Errorf("%w: BurnIn %d must be at least 2", ErrInvalidLorenz, l.BurnIn)
+	}
+	if l.Start == nil {
+		return nil
+	}
+	if len(l.Start) != 3 {
+		return fmt.Errorf("%w: Start has %d coordinates; it needs x, y and z", ErrInvalidLorenz, len(l.Start))
+	}
+	for i, c := range l.Start {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return fmt.Errorf("%w: Start[%d] %v must be finite", ErrInvalidLorenz, i, c)
+		}
+	}
+	return nil
+}
+
+// lorenzAxis returns the index of the coordinate named axis
+func lorenzAxis(axis string) (int, bool) {
+	switch axis {
+	case "", "x":
+		return 0, true
+	case "y":
+		return 1, true
+	case "z":
+		return 2, true
+	}
+	return 0, false
+}
+
+// lorenz returns the system of c, the default one when c has none
+func (c ChaoticConfig) lorenz() Lorenz {
+	if c.Lorenz == nil {
+		return DefaultLorenz()
+	}
+	return *c.Lorenz
+}
+
+// lorenzEscape is how far from the origin a Lorenz orbit may go before it is
+// taken to have escaped; the classic attractor stays within about 50
+const lorenzEscape = 1e6
+
+// derivative returns the rate of change of the system at p
+func (l Lorenz) derivative(p [3]float64) [3]float64 {
+	return [3]float64{
+		l.Sigma * (p[1] - p[0]),
+		p[0]*(l.Rho-p[2]) - p[1],
+		p[0]*p[1] - l.Beta*p[2],
+	}
+}
+
+// step integrates the system one Dt on from p with RK4
+func (l Lorenz) step(p [3]float64) [3]float64 {
+	along := func(k [3]float64, h float64) [3]float64 {
+		return [3]float64{p[0] + h*k[0], p[1] + h*k[1], p[2] + h*k[2]}
+	}
+	k1 := l.derivative(p)
+	k2 := l.derivative(along(k1, l.Dt/2))
+	k3 := l.derivative(along(k2, l.Dt/2))
+	k4 := l.derivative(along(k3, l.Dt))
+	var next [3]float64
+	for i := range next {
+		next[i] = p[i] + l.Dt/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+	}
+	return next
+}
+
+// lorenzOrbit integrates the Lorenz system of one run
+type lorenzOrbit struct {
+	lorenz    Lorenz
+	axis      int
+	point     [3]float64
+	low, high float64     // extent of the axis over the second half of the burn-in
+	start     *[3]float64 // where the integration began, nil until step 0 is generated
+}
+
+// newLorenzOrbit prepares the orbit of a run with config, which must use
+// ModelLorenz
+func newLorenzOrbit(config ChaoticConfig) *lorenzOrbit {
+	lorenz := config.lorenz()
+	axis, _ := lorenzAxis(lorenz.Axis)
+	return &lorenzOrbit{lorenz: lorenz, axis: axis}
+}
+
+// begin starts the orbit from start and runs the burn-in, measuring the
+// extent of the axis. It returns false if the orbit escapes.
+func (o *lorenzOrbit) begin(start [3]float64) bool {
+	o.start, o.point = &start, start
+	settle := o.lorenz.BurnIn / 2
+	if !o.integrate(settle) {
+		return false
+	}
+	o.low, o.high = math.Inf(1), math.Inf(-1)
+	for range o.lorenz.BurnIn - settle {
+		if !o.integrate(1) {
+			return false
+		}
+		o.low, o.high = min(o.low, o.point[o.axis]), max(o.high, o.point[o.axis])
+	}
+	return true
+}
+
+// integrate moves the orbit steps integration steps on. It returns false if
+// the orbit escapes.
+func (o *lorenzOrbit) integrate(steps int) bool {
+	for range steps {
+		o.point = o.lorenz.step(o.point)
+		for _, c := range o.point {
+			if !(math.Abs(c) <= lorenzEscape) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// replay integrates the orbit from the configured Start to the sample of
+// step n-1, for continuing a sequence of n entries
+func (o *lorenzOrbit) replay(n int) error {
+	if o.lorenz.Start == nil {
+		return fmt.Errorf("%w: continuing a sequence needs the Start it was integrated from", ErrInvalidLorenz)
+	}
+	if !o.begin([3]float64(o.lorenz.Start)) || !o.integrate((n-1)*o.lorenz.Stride) {
+		return fmt.Errorf("%w: the Lorenz orbit escaped before step %d (at %v)", ErrOrbitDiverged, n-1, o.point)
+	}
+	return nil
+}
+
+// value scales the sampled coordinate from the extent of the burn-in onto
+// the range of config. An orbit at rest on a fixed point has no extent and
+// maps to the middle of the range.
+func (o *lorenzOrbit) value(config ChaoticConfig) int {
+	position := 0.5
+	if o.high > o.low {
+		position = (o.point[o.axis] - o.low) / (o.high - o.low)
+	}
+	return mapValue(position, config)
+}
+
+// integrateLorenz computes entry i of ModelLorenz. Step 0 draws a start from
+// the source, even when the config sets Start, so the source is left in the
+// same state either way, and runs the burn-in; later steps draw nothing. An
+// orbit that escapes returns an error wrapping ErrOrbitDiverged.
+func (s *sequenceState) integrateLorenz(i int) (LogEntry, error) {
+	o := s.lorenz
+	if i == 0 {
+		// A box around the classic attractor
+		start := [3]float64{s.src.Float64()*40 - 20, s.src.Float64()*40 - 20, s.src.Float64() * 50}
+		if o.lorenz.Start != nil {
+			start = [3]float64(o.lorenz.Start)
+		}
+		if !o.begin(start) {
+			return LogEntry{}, fmt.Errorf("%w: the Lorenz orbit escaped during the burn-in (at %v)", ErrOrbitDiverged, o.point)
+		}
+	} else if !o.integrate(o.lorenz.Stride) {
+		return LogEntry{}, fmt.Errorf("%w: the Lorenz orbit escaped before step %d (at %v)", ErrOrbitDiverged, i, o.point)
+	}
+
+	return LogEntry{Step: i, Value: o.value(s.config), Type: string(ModelLorenz)}, nil
+}
@@ -0,0 +1,119 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLorenzBoundedOverLongRun(t *testing.T) {
+	const n = 100_000
+	seed := int64(35)
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.Model = ModelLorenz
+	config.MinValue, config.MaxValue = 0, 9999
+	sequence, err := ChaoticTransactionSequence(n, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Min < config.MinValue || stats.Max > config.MaxValue {
+		t.Fatalf("values span [%d, %d], outside the range", stats.Min, stats.Max)
+	}
+	// The burn-in sees nearly all of the attractor, so the values cover the
+	// range and samples beyond its extent, pinned to an end, are rare
+	if stats.Min > 500 || stats.Max < 9500 {
+		t.Errorf("values span only [%d, %d]", stats.Min, stats.Max)
+	}
+	pinned := 0
+	for _, entry := range sequence {
+		if entry.Type != string(ModelLorenz) {
+			t.Fatalf("step %d has type %q", entry.Step, entry.Type)
+		}
+		if entry.Value == config.MinValue || entry.Value == config.MaxValue {
+			pinned++
+		}
+	}
+	if pinned > n/100 {
+		t.Errorf("%d of %d samples are pinned to an end of the range", pinned, n)
+	}
+
+	// The orbit itself stays on the classic attractor, within about
+	// |x| < 20, |y| < 28 and 0 < z < 50
+	orbit := newLorenzOrbit(config)
+	if !orbit.begin([3]float64{1, 1, 1}) {
+		t.Fatal("the orbit escaped during the burn-in")
+	}
+	for i := range n {
+		if !orbit.integrate(orbit.lorenz.Stride) {
+			t.Fatalf("sample %d: the orbit escaped", i)
+		}
+		if p := orbit.point; math.Abs(p[0]) > 25 || math.Abs(p[1]) > 35 || p[2] < 0 || p[2] > 60 {
+			t.Fatalf("sample %d: the orbit left the attractor at %v", i, p)
+		}
+	}
+}
+
+func TestLorenzSeededRunsReproduce(t *testing.T) {
+	run := func(opts ...Option) ([]LogEntry, []float64) {
+		g, err := NewGenerator(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sequence, err := g.Generate(2000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, ok := g.LorenzStart()
+		if !ok {
+			t.Fatal("no Lorenz start reported")
+		}
+		return sequence, start
+	}
+
+	lorenz := DefaultLorenz()
+	lorenz.Axis, lorenz.Dt = "z", 0.005
+	first, start := run(WithLorenz(lorenz), WithSeed(3))
+	second, again := run(WithLorenz(lorenz), WithSeed(3))
+	if !reflect.DeepEqual(first, second) || !reflect.DeepEqual(start, again) {
+		t.Error("the same seed integrated a different orbit")
+	}
+	if other, _ := run(WithLorenz(lorenz), WithSeed(4)); reflect.DeepEqual(other, first) {
+		t.Error("different seeds integrated the same orbit")
+	}
+
+	// The reported start pins the orbit without the seed
+	lorenz.Start = start
+	if replayed, _ := run(WithLorenz(lorenz)); !reflect.DeepEqual(replayed, first) {
+		t.Error("integrating from the reported start gave a different run")
+	}
+}
+
+func TestValidateLorenz(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Lorenz)
+	}{
+		{"sigma", func(l *Lorenz) { l.Sigma = 0 }},
+		{"rho", func(l *Lorenz) { l.Rho = -28 }},
+		{"beta", func(l *Lorenz) { l.Beta = math.NaN() }},
+		{"dt", func(l *Lorenz) { l.Dt = math.Inf(1) }},
+		{"stride", func(l *Lorenz) { l.Stride = 0 }},
+		{"axis", func(l *Lorenz) { l.Axis = "w" }},
+		{"burn-in", func(l *Lorenz) { l.BurnIn = 1 }},
+		{"start", func(l *Lorenz) { l.Start = []float64{1, 2} }},
+		{"infinite start", func(l *Lorenz) { l.Start = []float64{1, math.Inf(1), 2} }},
+	}
+	for _, tt := range tests {
+		lorenz := DefaultLorenz()
+		tt.modify(&lorenz)
+		if err := lorenz.Validate(); !errors.Is(err, ErrInvalidLorenz) {
+			t.Errorf("%s: Validate returned %v, want ErrInvalidLorenz", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Model selects the process that generates the values
+type Model string
+
+// Generation models
+const (
+	ModelBranching Model = "branching" // random branch selection, the default
+	ModelLogistic  Model = "logistic"  // iterates the logistic map
+	ModelHenon     Model = "henon"     // iterates the Hénon map
+	ModelTent      Model = "tent"      // iterates the tent map
+	ModelLorenz    Model = "lorenz"    // samples the integrated Lorenz system
+)
+
+// Defaults of the Hénon map, the classic parameters of its strange attractor
+const (
+	DefaultHenonA = 1.4
+	DefaultHenonB = 0.3
+)
+
+// ErrOrbitDiverged is returned (wrapped) when the orbit of a map model
+// escapes to infinity, naming the step where it did
+var ErrOrbitDiverged = errors.New("map orbit diverged")
+
+// henonWindow is the half-width of the x interval of the Hénon map that is
+// scaled onto the range; the classic attractor lies within it
+const henonWindow = 1.5
+
+// henonEscape is how far from the origin a Hénon orbit may go before it is
+// taken to have escaped
+const henonEscape = 1e6
+
+// isMapModel reports whether model iterates a map rather than drawing branches
+func isMapModel(model Model) bool {
+	return model == ModelLogistic || model == ModelHenon || model == ModelTent
+}
+
+// henonParameters returns a and b of the Hénon map of c, with zero taken as
+// the default
+func (c ChaoticConfig) henonParameters() (float64, float64) {
+	a, b := c.HenonA, c.HenonB
+	if a == 0 {
+		a = DefaultHenonA
+	}
+	if b == 0 {
+		b = DefaultHenonB
+	}
+	return a, b
+}
+
+// validateMap checks the map settings of c, which uses a map model
+func (c ChaoticConfig) validateMap() error {
+	switch c.Model {
+	case ModelLogistic:
+		if !(c.LogisticR > 0 && c.LogisticR <= 4) {
+			return fmt.Errorf("%w: LogisticR %v must be in (0, 4]", ErrInvalidModel, c.LogisticR)
+		}
+	case ModelTent:
+		if !(c.TentMu > 0 && c.TentMu <= 2) {
+			return fmt.Errorf("%w: TentMu %v must be in (0, 2]", ErrInvalidModel, c.TentMu)
+		}
+	case ModelHenon:
+		for _, p := range []struct {
+			name  string
+			value float64
+		}{
+			{"HenonA", c.HenonA},
+			{"HenonB", c.HenonB},
+			{"MapY0", c.MapY0},
+		} {
+			if math.IsNaN(p.value) || math.IsInf(p.value, 0) {
+				return fmt.Errorf("%w: %s %v must be finite", ErrInvalidModel, p.name, p.value)
+			}
+		}
+		if x0 := c.MapX0; x0 != nil && (math.IsNaN(*x0) || math.IsInf(*x0, 0)) {
+			return fmt.Errorf("%w: MapX0 %v must be finite", ErrInvalidModel, *x0)
+		}
+		return nil
+	}
+	if x0 := c.MapX0; x0 != nil && !(*x0 > 0 && *x0 < 1) {
+		return fmt.Errorf("%w: MapX0 %v must be in (0, 1)", ErrInvalidModel, *x0)
+	}
+	return nil
+}
+
+// mapValue maps x onto the range of config, splitting the interval the
+// model's orbits live in into equal parts, one per value. A Hénon x outside
+// its window is clamped to the nearest end. For ModelLorenz x is the
+// position within the extent of the burn-in.
+func mapValue(x float64, config ChaoticConfig) int {
+	if config.Model == ModelHenon {
+		x = (x + henonWindow) / (2 * henonWindow)
+	}
+	span := float64(config.MaxValue) - float64(config.MinValue) + 1
+	return min(config.MinValue+int(min(max(x, 0), 1)*span), config.MaxValue)
+}
+
+// mapState returns the x that value maps back to, the middle of its part,
+// for continuing a sequence whose exact x is not known
+func mapState(value int, config ChaoticConfig) float64 {
+	span := float64(config.MaxValue) - float64(config.MinValue) + 1
+	x := (float64(value) - float64(config.MinValue) + 0.5) / span
+	if config.Model == ModelHenon {
+		x = x*2*henonWindow - henonWindow
+	}
+	return x
+}
+
+// iterateMap computes entry i of a map model. Step 0 draws x0 from the
+// source, even when the config sets MapX0, so the source is left in the same
+// state either way; later steps draw nothing. An orbit that escapes returns
+// an error wrapping ErrOrbitDiverged.
+func (s *sequenceState) iterateMap(i int) (LogEntry, error) {
+	config := s.config
+	if i == 0 {
+		x0 := s.src.Float64()
+		for x0 == 0 {
+			// 0 is a fixed point of the logistic and tent maps
+			x0 = s.src.Float64()
+		}
+		if config.MapX0 != nil {
+			x0 = *config.MapX0
+		}
+		s.x0, s.x, s.y = &x0, x0, config.MapY0
+	} else {
+		switch config.Model {
+		case ModelLogistic:
+			s.x = config.LogisticR * s.x * (1 - s.x)
+		case ModelTent:
+			s.x = config.TentMu * min(s.x, 1-s.x)
+		case ModelHenon:
+			a, b := config.henonParameters()
+			s.x, s.y = 1-a*s.x*s.x+s.y, b*s.x
+			if !(math.Abs(s.x) <= henonEscape && math.Abs(s.y) <= henonEscape) {
+				return LogEntry{}, fmt.Errorf("%w: the Hénon orbit escaped at step %d (x = %v, y = %v)", ErrOrbitDiverged, i, s.x, s.y)
+			}
+		}
+	}
+
+	return LogEntry{Step: i, Value: mapValue(s.x, config), Type: string(config.Model)}, nil
+}
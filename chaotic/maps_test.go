@@ -0,0 +1,214 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// mapConfig returns a seeded config of model over [0, 9999]
+func mapConfig(model Model, seed int64) ChaoticConfig {
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.Model = model
+	config.MinValue, config.MaxValue = 0, 9999
+	return config
+}
+
+func TestLogisticMapPeriodTwo(t *testing.T) {
+	// At r = 3.2 every orbit settles on the 2-cycle
+	// x = (r + 1 ± √((r-3)(r+1))) / 2r
+	const r = 3.2
+	root := math.Sqrt((r - 3) * (r + 1))
+	low, high := (r+1-root)/(2*r), (r+1+root)/(2*r)
+
+	for seed := int64(1); seed <= 5; seed++ {
+		config := mapConfig(ModelLogistic, seed)
+		config.LogisticR = r
+		sequence, err := ChaoticTransactionSequence(400, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range sequence {
+			if entry.Type != string(ModelLogistic) {
+				t.Fatalf("step %d has type %q, want logistic", entry.Step, entry.Type)
+			}
+		}
+		// 200 steps of burn-in bring the orbit to within one value of
+		// the cycle
+		for i := 200; i < len(sequence)-1; i++ {
+			a, b := sequence[i].Value, sequence[i+1].Value
+			lo, hi := min(a, b), max(a, b)
+			if math.Abs(float64(lo)-low*10000) > 1 || math.Abs(float64(hi)-high*10000) > 1 {
+				t.Fatalf("seed %d, steps %d and %d: %d and %d, want the cycle %.1f and %.1f",
+					seed, i, i+1, a, b, low*10000, high*10000)
+			}
+			if a == b {
+				t.Fatalf("seed %d: step %d repeats %d instead of alternating", seed, i+1, a)
+			}
+		}
+	}
+}
+
+func TestLogisticMapAtFourIsIrregular(t *testing.T) {
+	config := mapConfig(ModelLogistic, 2)
+	config.LogisticR = 4
+	sequence, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.SampleEntropy <= 0.5 {
+		t.Errorf("sample entropy %.3f at r = 4, want clearly positive", stats.SampleEntropy)
+	}
+}
+
+func TestLogisticMapX0(t *testing.T) {
+	run := func(opts ...Option) float64 {
+		g, err := NewGenerator(append([]Option{WithLogisticMap(3.9)}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := g.Generate(10); err != nil {
+			t.Fatal(err)
+		}
+		x0, ok := g.MapX0()
+		if !ok || !(x0 > 0 && x0 < 1) {
+			t.Fatalf("x0 %v, %v; want one in (0, 1)", x0, ok)
+		}
+		return x0
+	}
+	if run(WithSeed(1)) != run(WithSeed(1)) {
+		t.Error("the same seed drew different x0")
+	}
+	if run(WithSeed(1)) == run(WithSeed(2)) {
+		t.Error("different seeds drew the same x0")
+	}
+
+	config := mapConfig(ModelLogistic, 1)
+	config.LogisticR = 3.9
+	x0 := 0.25
+	config.MapX0 = &x0
+	sequence, err := ChaoticTransactionSequence(3, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0.25 → 0.73125 → 0.76644140625
+	if got := valuesOf(sequence); got[0] != 2500 || got[1] != 7312 || got[2] != 7664 {
+		t.Errorf("values %v from x0 = 0.25, want [2500 7312 7664]", got)
+	}
+}
+
+func TestValidateLogisticMap(t *testing.T) {
+	for _, r := range []float64{0, -1, 4.01, math.NaN()} {
+		config := mapConfig(ModelLogistic, 1)
+		config.LogisticR = r
+		if err := config.Validate(); !errors.Is(err, ErrInvalidModel) {
+			t.Errorf("r = %v: Validate returned %v, want ErrInvalidModel", r, err)
+		}
+	}
+	config := mapConfig(ModelLogistic, 1)
+	config.LogisticR = 3.5
+	for _, x0 := range []float64{0, 1, -0.5} {
+		config.MapX0 = &x0
+		if err := config.Validate(); !errors.Is(err, ErrInvalidModel) {
+			t.Errorf("x0 = %v: Validate returned %v, want ErrInvalidModel", x0, err)
+		}
+	}
+}
+
+func TestMapOrbitsFromFixedStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		model  Model
+		modify func(*ChaoticConfig)
+		orbit  []float64 // x worked out by hand
+	}{
+		{
+			// x' = 1 - 1.4x² + y, y' = 0.3x from (0, 0)
+			"henon", ModelHenon, func(c *ChaoticConfig) {},
+			[]float64{0, 1, -0.4, 1.076, -0.7408864},
+		},
+		{
+			// a = 1, b = 0.5: (0, 0.2) → (1.2, 0) → (-0.44, 0.6) → (1.4064, -0.22)
+			"henon parameters", ModelHenon, func(c *ChaoticConfig) { c.HenonA, c.HenonB, c.MapY0 = 1, 0.5, 0.2 },
+			[]float64{0, 1.2, -0.44, 1.4064},
+		},
+		{
+			// x' = 1.5·min(x, 1-x) from 0.2
+			"tent", ModelTent, func(c *ChaoticConfig) { c.TentMu = 1.5 },
+			[]float64{0.2, 0.3, 0.45, 0.675, 0.4875, 0.73125},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := mapConfig(tt.model, 1)
+			x0 := 0.0
+			if tt.model == ModelTent {
+				x0 = 0.2
+			}
+			config.MapX0 = &x0
+			tt.modify(&config)
+			sequence, err := ChaoticTransactionSequence(len(tt.orbit), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, x := range tt.orbit {
+				// The Hénon window [-1.5, 1.5] and the tent's [0, 1] are
+				// split into 10000 values; rounding in the orbit may move a
+				// value by one
+				want := x * 10000
+				if tt.model == ModelHenon {
+					want = (x + 1.5) / 3 * 10000
+				}
+				if got := sequence[i].Value; math.Abs(float64(got)-math.Floor(want)) > 1 {
+					t.Errorf("step %d: value %d, want %d for x = %v", i, got, int(want), x)
+				}
+				if sequence[i].Type != string(tt.model) {
+					t.Errorf("step %d has type %q", i, sequence[i].Type)
+				}
+			}
+		})
+	}
+}
+
+func TestHenonDivergenceNamesStep(t *testing.T) {
+	// From (2, 0) x runs -4.6, -28.024, about -1100 and then past -1e6
+	config := mapConfig(ModelHenon, 1)
+	x0 := 2.0
+	config.MapX0 = &x0
+	_, err := ChaoticTransactionSequence(10, config)
+	if !errors.Is(err, ErrOrbitDiverged) || !strings.Contains(err.Error(), "step 4") {
+		t.Errorf("returned %v, want ErrOrbitDiverged at step 4", err)
+	}
+	if _, err := ChaoticTransactionSequence(4, config); err != nil {
+		t.Errorf("the 4 steps before the escape: %v", err)
+	}
+}
+
+func TestValidateHenonAndTentMaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		model  Model
+		modify func(*ChaoticConfig)
+	}{
+		{"tent slope 0", ModelTent, func(c *ChaoticConfig) { c.TentMu = 0 }},
+		{"tent slope above 2", ModelTent, func(c *ChaoticConfig) { c.TentMu = 2.5 }},
+		{"tent x0", ModelTent, func(c *ChaoticConfig) { c.TentMu, c.MapX0 = 1.5, new(float64) }},
+		{"henon a", ModelHenon, func(c *ChaoticConfig) { c.HenonA = math.NaN() }},
+		{"henon b", ModelHenon, func(c *ChaoticConfig) { c.HenonB = math.Inf(-1) }},
+		{"henon y0", ModelHenon, func(c *ChaoticConfig) { c.MapY0 = math.Inf(1) }},
+		{"unknown model", "baker", func(c *ChaoticConfig) {}},
+	}
+	for _, tt := range tests {
+		config := mapConfig(tt.model, 1)
+		tt.modify(&config)
+		if err := config.Validate(); !errors.Is(err, ErrInvalidModel) {
+			t.Errorf("%s: Validate returned %v, want ErrInvalidModel", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package chaotic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// EncodeMsgpack writes a run to w as MessagePack. Maps are keyed by the same
+// field names as the JSON output, so consumers in other languages can decode
+// by key, and integers are packed in their smallest form.
+func EncodeMsgpack(doc RunDocument, w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	encoder := msgpack.NewEncoder(buffered)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode MessagePack: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write MessagePack: %w", err)
+	}
+	return nil
+}
+
+// DecodeMsgpack reads a run written by EncodeMsgpack. Values decode straight
+// into the typed fields, so the sequence is ready for ComputeStatistics.
+func DecodeMsgpack(r io.Reader) (RunDocument, error) {
+	var doc RunDocument
+	decoder := msgpack.NewDecoder(bufio.NewReader(r))
+	decoder.SetCustomStructTag("json")
+	if err := decoder.Decode(&doc); err != nil {
+		return doc, fmt.Errorf("failed to decode MessagePack: %w", err)
+	}
+	if t := doc.Metadata.Config.Timing; t != nil {
+		// MessagePack times carry no location and decode as local time
+		t.Start = t.Start.UTC()
+	}
+	return doc, nil
+}
@@ -0,0 +1,133 @@
+package chaotic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// seededDocument generates an extended run of n steps from config, which
+// must have a Seed, with its statistics
+func seededDocument(tb testing.TB, n int, config ChaoticConfig) RunDocument {
+	tb.Helper()
+	sequence, err := ChaoticTransactionSequenceExtended(n, config)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return RunDocument{
+		Metadata: RunMetadata{
+			Config:           config,
+			SequenceLength:   n,
+			Seed:             config.Seed,
+			GeneratorVersion: GeneratorVersion,
+			FormatVersion:    FormatVersion,
+			Extended:         true,
+		},
+		Statistics: stats,
+		Sequence:   sequence,
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	doc := seededDocument(t, 2000, layeredConfig(12))
+	var buf bytes.Buffer
+	if err := EncodeMsgpack(doc, &buf); err != nil {
+		t.Fatal(err)
+	}
+	size := buf.Len()
+	decoded, err := DecodeMsgpack(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Sequence, doc.Sequence) {
+		t.Error("the decoded sequence differs")
+	}
+	if !reflect.DeepEqual(decoded.Metadata, doc.Metadata) {
+		t.Errorf("the decoded metadata differs:\n got %+v\nwant %+v", decoded.Metadata, doc.Metadata)
+	}
+	if !reflect.DeepEqual(decoded.Statistics, doc.Statistics) {
+		t.Error("the decoded statistics differ")
+	}
+
+	// The values decode as ints, ready for ComputeStatistics
+	stats, err := ComputeStatistics(decoded.Sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stats, doc.Statistics) {
+		t.Error("statistics of the decoded sequence differ from the saved ones")
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size >= len(encoded) {
+		t.Errorf("MessagePack takes %d bytes, compact JSON %d", size, len(encoded))
+	}
+}
+
+// BenchmarkMsgpack compares encoding and decoding a 100k-step extended run
+// as MessagePack and as the indented JSON the CLI writes, reporting the size
+// of each
+func BenchmarkMsgpack(b *testing.B) {
+	seed := int64(1)
+	config := DefaultConfig()
+	config.Seed = &seed
+	doc := seededDocument(b, 100_000, config)
+
+	formats := []struct {
+		name   string
+		encode func(RunDocument, io.Writer) error
+		decode func(*bytes.Buffer) error
+	}{
+		{
+			"json",
+			func(doc RunDocument, w io.Writer) error { return WriteJSON(doc, w) },
+			func(buf *bytes.Buffer) error {
+				var doc RunDocument
+				return json.Unmarshal(buf.Bytes(), &doc)
+			},
+		},
+		{
+			"msgpack",
+			EncodeMsgpack,
+			func(buf *bytes.Buffer) error {
+				_, err := DecodeMsgpack(buf)
+				return err
+			},
+		},
+	}
+	for _, format := range formats {
+		var encoded bytes.Buffer
+		if err := format.encode(doc, &encoded); err != nil {
+			b.Fatal(err)
+		}
+		b.Run("encode/"+format.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			b.ReportAllocs()
+			for b.Loop() {
+				buf.Reset()
+				if err := format.encode(doc, &buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(encoded.Len()), "bytes")
+		})
+		b.Run("decode/"+format.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				buf := bytes.NewBuffer(encoded.Bytes())
+				if err := format.decode(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package chaotic
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Kinds of the self-describing header lines in an NDJSON run. Lines without a
+// kind are sequence entries.
+const (
+	NDJSONKindMetadata   = "metadata"
+	NDJSONKindStatistics = "statistics"
+)
+
+// ndjsonMetadata is the metadata header line of an NDJSON run
+type ndjsonMetadata struct {
+	Kind string `json:"kind"`
+	RunMetadata
+}
+
+// ndjsonStatistics is the statistics header line of an NDJSON run
+type ndjsonStatistics struct {
+	Kind string `json:"kind"`
+	Statistics
+}
+
+// SaveToNDJSON writes the sequence to w as JSON Lines, one compact entry per
+// line. Entries are encoded as they go, so memory use does not grow with the
+// sequence length.
+func SaveToNDJSON(sequence []LogEntry, w io.Writer) error {
+	return writeNDJSON(w, func(encoder *json.Encoder) error {
+		return encodeEntries(encoder, sequence)
+	})
+}
+
+// SaveRunToNDJSON writes a whole run as JSON Lines: a metadata line and a
+// statistics line, each tagged with a "kind" field, followed by the entries
+func SaveRunToNDJSON(doc RunDocument, w io.Writer) error {
+	return writeNDJSON(w, func(encoder *json.Encoder) error {
+		if err := encoder.Encode(ndjsonMetadata{NDJSONKindMetadata, doc.Metadata}); err != nil {
+			return err
+		}
+		if err := encoder.Encode(ndjsonStatistics{NDJSONKindStatistics, doc.Statistics}); err != nil {
+			return err
+		}
+		return encodeEntries(encoder, doc.Sequence)
+	})
+}
+
+// writeNDJSON runs encode against a buffered encoder on w and flushes it
+func writeNDJSON(w io.Writer, encode func(*json.Encoder) error) error {
+	buffered := bufio.NewWriter(w)
+	if err := encode(json.NewEncoder(buffered)); err != nil {
+		return fmt.Errorf("failed to encode NDJSON: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write NDJSON: %w", err)
+	}
+	return nil
+}
+
+// encodeEntries encodes each entry on its own line
+func encodeEntries(encoder *json.Encoder, sequence []LogEntry) error {
+	for _, entry := range sequence {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadNDJSON reads a JSON Lines stream written by SaveToNDJSON or
+// SaveRunToNDJSON. Metadata and Statistics are left zero when the stream has
+// no header lines of that kind.
+func LoadNDJSON(r io.Reader) (RunDocument, error) {
+	var doc RunDocument
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for line := 1; ; line++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return doc, nil
+			}
+			return doc, fmt.Errorf("failed to decode NDJSON record %d: %w", line, err)
+		}
+
+		var header struct {
+			Kind string `json:"kind"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return doc, fmt.Errorf("failed to decode NDJSON record %d: %w", line, err)
+		}
+
+		var err error
+		switch header.Kind {
+		case "":
+			var entry LogEntry
+			err = json.Unmarshal(raw, &entry)
+			doc.Sequence = append(doc.Sequence, entry)
+		case NDJSONKindMetadata:
+			err = json.Unmarshal(raw, &doc.Metadata)
+		case NDJSONKindStatistics:
+			err = json.Unmarshal(raw, &doc.Statistics)
+		default:
+			err = fmt.Errorf("unknown kind %q", header.Kind)
+		}
+		if err != nil {
+			return doc, fmt.Errorf("failed to decode NDJSON record %d: %w", line, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package chaotic
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	seed := int64(60)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequenceExtended(300, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := RunDocument{
+		Metadata:   RunMetadata{GeneratedAt: "2024-01-01T00:00:00Z", Config: config, SequenceLength: len(sequence), Seed: &seed},
+		Statistics: stats,
+		Sequence:   sequence,
+	}
+
+	tests := []struct {
+		name  string
+		save  func(*bytes.Buffer) error
+		kinds []string // of the header lines
+		want  RunDocument
+	}{
+		{
+			"entries",
+			func(buf *bytes.Buffer) error { return SaveToNDJSON(sequence, buf) },
+			nil,
+			RunDocument{Sequence: sequence},
+		},
+		{
+			"run",
+			func(buf *bytes.Buffer) error { return SaveRunToNDJSON(run, buf) },
+			[]string{NDJSONKindMetadata, NDJSONKindStatistics},
+			run,
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		// One compact object per line, headers first
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != len(tt.kinds)+len(sequence) {
+			t.Fatalf("%s: %d lines for %d entries", tt.name, len(lines), len(sequence))
+		}
+		for i, line := range lines {
+			var fields map[string]any
+			if err := json.Unmarshal([]byte(line), &fields); err != nil {
+				t.Fatalf("%s: line %d: %v", tt.name, i+1, err)
+			}
+			want := ""
+			if i < len(tt.kinds) {
+				want = tt.kinds[i]
+			}
+			if kind, _ := fields["kind"].(string); kind != want {
+				t.Errorf("%s: line %d has kind %q, want %q", tt.name, i+1, kind, want)
+			}
+		}
+
+		doc, err := LoadNDJSON(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(doc, tt.want) {
+			t.Errorf("%s: read back a different run", tt.name)
+		}
+	}
+}
+
+func TestLoadNDJSONErrors(t *testing.T) {
+	for _, stream := range []string{
+		"{\"step\": 0, \"value\": 1, \"type\": \"initial\"}\n{\"step\": 1,\n",
+		"{\"kind\": \"checksum\"}\n",
+		"{\"step\": \"zero\"}\n",
+	} {
+		if _, err := LoadNDJSON(strings.NewReader(stream)); err == nil {
+			t.Errorf("%q: no error", stream)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package chaotic
+
+import (
+	"math"
+	"sort"
+)
+
+// OutlierKind selects the rule FlagOutliers uses
+type OutlierKind string
+
+const (
+	// OutlierIQR flags values outside Tukey's fences, more than Threshold
+	// interquartile ranges below Q1 or above Q3 (the default)
+	OutlierIQR OutlierKind = "iqr"
+	// OutlierZScore flags values more than Threshold sample standard
+	// deviations from the mean
+	OutlierZScore OutlierKind = "zscore"
+)
+
+// Default thresholds used when OutlierMethod.Threshold is not positive
+const (
+	DefaultIQRFence        = 1.5
+	DefaultZScoreThreshold = 3.0
+)
+
+// OutlierMethod configures FlagOutliers. The zero value is Tukey's fences
+// at 1.5 IQR.
+type OutlierMethod struct {
+	Kind      OutlierKind
+	Threshold float64
+}
+
+// withDefaults fills in the kind and threshold left unset
+func (m OutlierMethod) withDefaults() OutlierMethod {
+	if m.Kind == "" {
+		m.Kind = OutlierIQR
+	}
+	if m.Threshold <= 0 {
+		switch m.Kind {
+		case OutlierIQR:
+			m.Threshold = DefaultIQRFence
+		case OutlierZScore:
+			m.Threshold = DefaultZScoreThreshold
+		}
+	}
+	return m
+}
+
+// FlagOutliers sets IsOutlier on every entry of log according to method,
+// clearing it on the rest, and returns how many were flagged. Quartiles,
+// mean and standard deviation are taken over the values of log itself. A
+// constant sequence has no outliers under either rule, and an unknown Kind
+// flags nothing.
+func FlagOutliers(log []LogEntry, method OutlierMethod) int {
+	if len(log) == 0 {
+		return 0
+	}
+	values := make([]int, len(log))
+	for i, entry := range log {
+		values[i] = entry.Value
+	}
+
+	var lo, hi float64
+	method = method.withDefaults()
+	switch method.Kind {
+	case OutlierIQR:
+		sorted := make([]int, len(values))
+		copy(sorted, values)
+		sort.Ints(sorted)
+		quartiles := Quantiles(sorted, 0.25, 0.75)
+		lo, hi = iqrFences(quartiles[0], quartiles[1], method.Threshold)
+	case OutlierZScore:
+		lo, hi = math.Inf(-1), math.Inf(1)
+		if len(values) > 1 {
+			mean := calculateMean(values)
+			if stdev := calculateStdev(values, mean); stdev > 0 {
+				lo, hi = mean-method.Threshold*stdev, mean+method.Threshold*stdev
+			}
+		}
+	default:
+		lo, hi = math.Inf(-1), math.Inf(1)
+	}
+
+	count := 0
+	for i, v := range values {
+		log[i].IsOutlier = float64(v) < lo || float64(v) > hi
+		if log[i].IsOutlier {
+			count++
+		}
+	}
+	return count
+}
+
+// iqrFences returns Tukey's fences k interquartile ranges outside q1 and q3
+func iqrFences(q1, q3, k float64) (lo, hi float64) {
+	iqr := q3 - q1
+	return q1 - k*iqr, q3 + k*iqr
+}
+
+// countOutside counts the values strictly below lo or above hi
+func countOutside(values []int, lo, hi float64) int {
+	count := 0
+	for _, v := range values {
+		if float64(v) < lo || float64(v) > hi {
+			count++
+		}
+	}
+	return count
+}
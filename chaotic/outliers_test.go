@@ -0,0 +1,76 @@
+package chaotic
+
+import "testing"
+
+// withOutliers returns 50 values cycling through 100..119 with the given
+// values placed at the given indexes
+func withOutliers(outliers map[int]int) []LogEntry {
+	values := make([]int, 50)
+	for i := range values {
+		values[i] = 100 + i%20
+	}
+	for i, v := range outliers {
+		values[i] = v
+	}
+	return entriesOf(values...)
+}
+
+func TestFlagOutliersFindsKnownOutliers(t *testing.T) {
+	outliers := map[int]int{5: 4000, 31: -3000}
+	methods := map[string]OutlierMethod{
+		"iqr default":    {},
+		"iqr fence 3":    {Kind: OutlierIQR, Threshold: 3},
+		"zscore default": {Kind: OutlierZScore},
+	}
+	for name, method := range methods {
+		t.Run(name, func(t *testing.T) {
+			log := withOutliers(outliers)
+			if count := FlagOutliers(log, method); count != len(outliers) {
+				t.Errorf("flagged %d entries, want %d", count, len(outliers))
+			}
+			for i, entry := range log {
+				if _, want := outliers[i]; entry.IsOutlier != want {
+					t.Errorf("entry %d (value %d): is_outlier %v, want %v", i, entry.Value, entry.IsOutlier, want)
+				}
+			}
+		})
+	}
+
+	stats, err := ComputeStatistics(withOutliers(outliers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.OutlierCount != len(outliers) {
+		t.Errorf("outlier_count %d, want %d", stats.OutlierCount, len(outliers))
+	}
+}
+
+func TestFlagOutliersWithoutOutliers(t *testing.T) {
+	for _, method := range []OutlierMethod{{}, {Kind: OutlierZScore}} {
+		log := withOutliers(nil)
+		// Flags left over from an earlier pass are cleared
+		for i := range log {
+			log[i].IsOutlier = true
+		}
+		if count := FlagOutliers(log, method); count != 0 {
+			t.Errorf("%s: flagged %d entries of a regular sequence", method.Kind, count)
+		}
+		for i, entry := range log {
+			if entry.IsOutlier {
+				t.Fatalf("%s: entry %d still flagged", method.Kind, i)
+			}
+		}
+	}
+
+	constant := entriesOf(7, 7, 7, 7, 7)
+	if count := FlagOutliers(constant, OutlierMethod{Kind: OutlierZScore}); count != 0 {
+		t.Errorf("flagged %d entries of a constant sequence", count)
+	}
+	stats, err := ComputeStatistics(withOutliers(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.OutlierCount != 0 {
+		t.Errorf("outlier_count %d of a regular sequence, want 0", stats.OutlierCount)
+	}
+}
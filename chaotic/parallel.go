@@ -0,0 +1,180 @@
+package chaotic
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ParallelOptions tunes GenerateParallel
+type ParallelOptions struct {
+	Concurrency int // number of workers; 0 means runtime.GOMAXPROCS(0)
+}
+
+// withDefaults fills in the zero fields of o
+func (o ParallelOptions) withDefaults() ParallelOptions {
+	if o.Concurrency == 0 {
+		o.Concurrency = runtime.GOMAXPROCS(0)
+	}
+	return o
+}
+
+// PathSeed derives the seed of path number path of a parallel run seeded
+// with master, so a single path can be regenerated on its own with
+// WithSeed(PathSeed(master, path)). Nearby paths get unrelated seeds.
+func PathSeed(master int64, path int) int64 {
+	return int64(mix64(uint64(master) + (uint64(path)+1)*golden64))
+}
+
+// golden64 is 2^64 divided by the golden ratio, the splitmix64 increment
+const golden64 = 0x9e3779b97f4a7c15
+
+// mix64 is the splitmix64 finalizer: it scrambles z so that nearby inputs
+// give unrelated outputs
+func mix64(z uint64) uint64 {
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// GenerateParallel generates m independent paths of n steps with config,
+// spreading them over a pool of workers. It is GenerateParallelCtx with a
+// background context.
+func GenerateParallel(m, n int, config ChaoticConfig, opts ParallelOptions) ([][]LogEntry, error) {
+	return GenerateParallelCtx(context.Background(), m, n, config, opts)
+}
+
+// GenerateParallelCtx generates m independent paths of n steps with config
+// on opts.Concurrency workers and returns them indexed by path number,
+// whatever order they finish in. Each worker owns its random source: with a
+// Seed, path i is generated from PathSeed(*config.Seed, i), so the result
+// does not depend on the number of workers; without one, each worker draws
+// from its own generator of the configured RandomnessMode rather than
+// contending on a shared one. An explicit Source cannot be shared between
+// goroutines, so the paths are then generated one after another from it.
+// Generation stops at the first error, or when ctx is cancelled.
+func GenerateParallelCtx(ctx context.Context, m, n int, config ChaoticConfig, opts ParallelOptions) ([][]LogEntry, error) {
+	paths := make([][]LogEntry, m)
+	err := generatePaths(ctx, m, n, config, opts, func(path int, log []LogEntry) error {
+		paths[path] = log
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// pathResult is a generated path on its way back from a worker
+type pathResult struct {
+	path int
+	log  []LogEntry
+	err  error
+}
+
+// generatePaths generates m paths of n steps on a worker pool and hands
+// each to consume in path order, on the calling goroutine. At most twice
+// as many paths as there are workers are in flight or waiting for an
+// earlier one, which bounds memory when consume discards them.
+func generatePaths(ctx context.Context, m, n int, config ChaoticConfig, opts ParallelOptions, consume func(path int, log []LogEntry) error) error {
+	if m <= 0 {
+		return errors.New("the number of paths must be a positive integer")
+	}
+	if opts.Concurrency < 0 {
+		return errors.New("concurrency must not be negative")
+	}
+	if err := validateSequence(n, config); err != nil {
+		return err
+	}
+	opts = opts.withDefaults()
+
+	if config.Source != nil {
+		for path := 0; path < m; path++ {
+			log, err := generateSequence(ctx, n, config, config.Source, uniformChaos(config.Source))
+			if err != nil {
+				return err
+			}
+			if err := consume(path, log); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := min(opts.Concurrency, m)
+	jobs := make(chan int)
+	results := make(chan pathResult, workers)
+	tokens := make(chan struct{}, 2*workers)
+
+	go func() {
+		defer close(jobs)
+		for path := 0; path < m; path++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var src RandSource
+			if config.Seed == nil {
+				src = config.workerSource()
+			}
+			for path := range jobs {
+				pathConfig, pathSrc := config, src
+				if config.Seed != nil {
+					// The path seed also keys the path's timestamp jitter
+					seed := PathSeed(*config.Seed, path)
+					pathConfig.Seed = &seed
+					pathSrc = NewSeededSource(seed)
+				}
+				log, err := generateSequence(ctx, n, pathConfig, pathSrc, uniformChaos(pathSrc))
+				select {
+				case results <- pathResult{path, log, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Results arrive in completion order and are released in path order
+	pending := make(map[int][]LogEntry)
+	next := 0
+	for result := range results {
+		if result.err != nil {
+			return result.err
+		}
+		pending[result.path] = result.log
+		for log, ok := pending[next]; ok; log, ok = pending[next] {
+			delete(pending, next)
+			if err := consume(next, log); err != nil {
+				return err
+			}
+			next++
+			<-tokens
+		}
+	}
+	if next < m {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package chaotic
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestGenerateParallelIsDeterministic(t *testing.T) {
+	seed := int64(13)
+	config := DefaultConfig()
+	config.Seed = &seed
+
+	want, err := GenerateParallel(16, 500, config, ParallelOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, concurrency := range []int{2, 5, 16} {
+		got, err := GenerateParallel(16, 500, config, ParallelOptions{Concurrency: concurrency})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d workers produced different paths than 1", concurrency)
+		}
+	}
+
+	// Each path regenerates on its own from its derived seed
+	for _, path := range []int{0, 7, 15} {
+		pathSeed := PathSeed(seed, path)
+		pathConfig := config
+		pathConfig.Seed = &pathSeed
+		alone, err := ChaoticTransactionSequence(500, pathConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(alone, want[path]) {
+			t.Errorf("path %d differs from a run seeded with PathSeed", path)
+		}
+	}
+	if reflect.DeepEqual(want[0], want[1]) {
+		t.Error("paths 0 and 1 are identical")
+	}
+}
+
+// BenchmarkGenerateParallel generates 64 paths of 100k steps on 1, 2 and 4
+// workers, and on GOMAXPROCS when that is more. The paths are independent, so time per run
+// should fall close to linearly with workers up to the core count.
+func BenchmarkGenerateParallel(b *testing.B) {
+	seed := int64(1)
+	config := DefaultConfig()
+	config.Seed = &seed
+	counts := []int{1, 2, 4}
+	if procs := runtime.GOMAXPROCS(0); procs > 4 {
+		counts = append(counts, procs)
+	}
+	for _, workers := range counts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := GenerateParallel(64, 100_000, config, ParallelOptions{Concurrency: workers}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// DefaultParquetRowGroupSize is the number of rows per Parquet row group,
+// sized so million-row sequences split into a handful of groups
+const DefaultParquetRowGroupSize = 256 * 1024
+
+// parquetBatchSize is how many rows are converted and written at a time
+const parquetBatchSize = 4096
+
+// parquetRow is the Parquet schema of a LogEntry
+type parquetRow struct {
+	Step                int64    `parquet:"step"`
+	Value               int64    `parquet:"value"`
+	Type                string   `parquet:"type,dict"`
+	EnhancedValue       *int64   `parquet:"enhanced_value,optional"`
+	EnhancementDelta    *int64   `parquet:"enhancement_delta,optional"`
+	IsOutlier           bool     `parquet:"is_outlier"`
+	AmountMinor         *int64   `parquet:"amount_minor,optional"`
+	Amount              *string  `parquet:"amount,optional"`
+	Timestamp           *string  `parquet:"timestamp,optional"`
+	TransactionID       *string  `parquet:"transaction_id,optional"`
+	AccountID           *string  `parquet:"account_id,optional,dict"`
+	Merchant            *string  `parquet:"merchant,optional,dict"`
+	Direction           *string  `parquet:"direction,optional,dict"`
+	Posted              *int64   `parquet:"posted,optional"`
+	Balance             *int64   `parquet:"balance,optional"`
+	Rejected            bool     `parquet:"rejected"`
+	Regime              *string  `parquet:"regime,optional,dict"`
+	Jump                bool     `parquet:"jump"`
+	EffectiveVolatility *float64 `parquet:"effective_volatility,optional"`
+	Clamped             *string  `parquet:"clamped,optional,dict"`
+	Anomaly             bool     `parquet:"anomaly"`
+	AnomalyKind         *string  `parquet:"anomaly_kind,optional,dict"`
+	Missing             bool     `parquet:"missing"`
+	DuplicateOf         *int64   `parquet:"duplicate_of,optional"`
+}
+
+// ParquetOption customizes SaveToParquet
+type ParquetOption func(*parquetOptions)
+
+// parquetOptions holds the settings applied by ParquetOption
+type parquetOptions struct {
+	rowGroupSize int
+}
+
+// WithRowGroupSize sets the number of rows per Parquet row group
+func WithRowGroupSize(rows int) ParquetOption {
+	return func(o *parquetOptions) {
+		o.rowGroupSize = rows
+	}
+}
+
+// SaveToParquet writes the sequence to w as a Parquet file with columns step,
+// value, type, is_outlier, rejected, jump, anomaly and missing plus nullable
+// enhanced_value, enhancement_delta, amount_minor, amount, timestamp,
+// transaction_id, account_id, merchant, direction, posted, balance, regime,
+// effective_volatility, clamped, anomaly_kind and duplicate_of. The value of
+// a missing entry is written as 0.
+func SaveToParquet(sequence []LogEntry, w io.Writer, opts ...ParquetOption) error {
+	options := parquetOptions{rowGroupSize: DefaultParquetRowGroupSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.rowGroupSize <= 0 {
+		return errors.New("parquet row group size must be positive")
+	}
+
+	writer := parquet.NewGenericWriter[parquetRow](w, parquet.MaxRowsPerRowGroup(int64(options.rowGroupSize)))
+	batch := make([]parquetRow, 0, parquetBatchSize)
+	for start := 0; start < len(sequence); start += parquetBatchSize {
+		end := min(start+parquetBatchSize, len(sequence))
+		batch = batch[:0]
+		for _, entry := range sequence[start:end] {
+			batch = append(batch, parquetRow{
+				Step:                int64(entry.Step),
+				Value:               int64(entry.Value),
+				Type:                entry.Type,
+				EnhancedValue:       toInt64Ptr(entry.EnhancedValue),
+				EnhancementDelta:    toInt64Ptr(entry.EnhancementDelta),
+				IsOutlier:           entry.IsOutlier,
+				AmountMinor:         entry.AmountMinor,
+				Amount:              optionalString(entry.Amount),
+				Timestamp:           optionalString(entry.Timestamp),
+				TransactionID:       optionalString(entry.TransactionID),
+				AccountID:           optionalString(entry.AccountID),
+				Merchant:            optionalString(entry.Merchant),
+				Direction:           optionalString(entry.Direction),
+				Posted:              toInt64Ptr(entry.Posted),
+				Balance:             toInt64Ptr(entry.Balance),
+				Rejected:            entry.Rejected,
+				Regime:              optionalString(entry.Regime),
+				Jump:                entry.Jump,
+				EffectiveVolatility: optionalFloat(entry.EffectiveVolatility),
+				Clamped:             optionalString(entry.Clamped),
+				Anomaly:             entry.Anomaly,
+				AnomalyKind:         optionalString(entry.AnomalyKind),
+				Missing:             entry.Missing,
+				DuplicateOf:         toInt64Ptr(entry.DuplicateOf),
+			})
+		}
+		if _, err := writer.Write(batch); err != nil {
+			writer.Close()
+			return fmt.Errorf("failed to write Parquet: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write Parquet: %w", err)
+	}
+	return nil
+}
+
+// LoadParquet reads a sequence written by SaveToParquet
+func LoadParquet(r io.ReaderAt, size int64) ([]LogEntry, error) {
+	rows, err := parquet.Read[parquetRow](r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet: %w", err)
+	}
+
+	sequence := make([]LogEntry, len(rows))
+	for i, row := range rows {
+		sequence[i] = LogEntry{
+			Step:             int(row.Step),
+			Value:            int(row.Value),
+			Type:             row.Type,
+			EnhancedValue:    toIntPtr(row.EnhancedValue),
+			EnhancementDelta: toIntPtr(row.EnhancementDelta),
+			IsOutlier:        row.IsOutlier,
+			AmountMinor:      row.AmountMinor,
+			Posted:           toIntPtr(row.Posted),
+			Balance:          toIntPtr(row.Balance),
+			Rejected:         row.Rejected,
+			Jump:             row.Jump,
+			Anomaly:          row.Anomaly,
+			Missing:          row.Missing,
+			DuplicateOf:      toIntPtr(row.DuplicateOf),
+		}
+		if row.EffectiveVolatility != nil {
+			sequence[i].EffectiveVolatility = *row.EffectiveVolatility
+		}
+		for _, text := range []struct {
+			src *string
+			dst *string
+		}{
+			{row.Amount, &sequence[i].Amount},
+			{row.Timestamp, &sequence[i].Timestamp},
+			{row.TransactionID, &sequence[i].TransactionID},
+			{row.AccountID, &sequence[i].AccountID},
+			{row.Merchant, &sequence[i].Merchant},
+			{row.Direction, &sequence[i].Direction},
+			{row.Regime, &sequence[i].Regime},
+			{row.Clamped, &sequence[i].Clamped},
+			{row.AnomalyKind, &sequence[i].AnomalyKind},
+		} {
+			if text.src != nil {
+				*text.dst = *text.src
+			}
+		}
+	}
+	return sequence, nil
+}
+
+// toInt64Ptr widens an optional int
+func toInt64Ptr(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	wide := int64(*v)
+	return &wide
+}
+
+// toIntPtr narrows an optional int64
+func toIntPtr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	narrow := int(*v)
+	return &narrow
+}
+
+// optionalString maps an empty string to a null one
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalFloat returns nil for 0, the zero value of an unset float field
+func optionalFloat(f float64) *float64 {
+	if f == 0 {
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,74 @@
+package chaotic
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	seed := int64(8)
+	config := DefaultConfig()
+	config.Seed = &seed
+	extended, err := ChaoticTransactionSequenceExtended(10_000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := ChaoticTransactionSequence(100, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layered, err := ChaoticTransactionSequenceExtended(2000, layeredConfig(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, sequence := range map[string][]LogEntry{"extended": extended, "plain": plain, "layered": layered} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := SaveToParquet(sequence, &buf, WithRowGroupSize(1000)); err != nil {
+				t.Fatal(err)
+			}
+			data := bytes.NewReader(buf.Bytes())
+
+			// The columns as the library reads them
+			rows, err := parquet.Read[parquetRow](data, data.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != len(sequence) {
+				t.Fatalf("read %d rows, wrote %d", len(rows), len(sequence))
+			}
+			for i, row := range rows {
+				entry := sequence[i]
+				if row.Step != int64(entry.Step) || row.Value != int64(entry.Value) || row.Type != entry.Type ||
+					!reflect.DeepEqual(row.EnhancedValue, toInt64Ptr(entry.EnhancedValue)) ||
+					!reflect.DeepEqual(row.EnhancementDelta, toInt64Ptr(entry.EnhancementDelta)) {
+					t.Fatalf("row %d is %+v, entry %+v", i, row, entry)
+				}
+			}
+
+			loaded, err := LoadParquet(data, data.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(loaded, sequence) {
+				t.Error("LoadParquet does not return the saved sequence")
+			}
+
+			file, err := parquet.OpenFile(data, data.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if groups, want := len(file.RowGroups()), (len(sequence)+999)/1000; groups != want {
+				t.Errorf("%d row groups, want %d of 1000 rows", groups, want)
+			}
+		})
+	}
+
+	if err := SaveToParquet(plain, &bytes.Buffer{}, WithRowGroupSize(0)); err == nil {
+		t.Error("accepted a row group size of 0")
+	}
+}
@@ -0,0 +1,715 @@
+package chaotic
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AScotM/chaotic_sequencer/chaotic/chaoticpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// ToProto converts the entry to its protobuf message
+func (e LogEntry) ToProto() *chaoticpb.LogEntry {
+	return &chaoticpb.LogEntry{
+		Step:                int64(e.Step),
+		Value:               int64(e.Value),
+		Type:                e.Type,
+		EnhancedValue:       toInt64Ptr(e.EnhancedValue),
+		EnhancementDelta:    toInt64Ptr(e.EnhancementDelta),
+		IsOutlier:           e.IsOutlier,
+		AmountMinor:         e.AmountMinor,
+		Amount:              e.Amount,
+		Timestamp:           e.Timestamp,
+		TransactionId:       e.TransactionID,
+		AccountId:           e.AccountID,
+		Merchant:            e.Merchant,
+		Direction:           e.Direction,
+		Posted:              toInt64Ptr(e.Posted),
+		Balance:             toInt64Ptr(e.Balance),
+		Rejected:            e.Rejected,
+		Regime:              e.Regime,
+		Jump:                e.Jump,
+		EffectiveVolatility: e.EffectiveVolatility,
+		Clamped:             e.Clamped,
+		Anomaly:             e.Anomaly,
+		AnomalyKind:         e.AnomalyKind,
+		Missing:             e.Missing,
+		DuplicateOf:         toInt64Ptr(e.DuplicateOf),
+	}
+}
+
+// FromProto replaces the entry with the contents of p
+func (e *LogEntry) FromProto(p *chaoticpb.LogEntry) {
+	*e = LogEntry{
+		Step:                int(p.GetStep()),
+		Value:               int(p.GetValue()),
+		Type:                p.GetType(),
+		EnhancedValue:       toIntPtr(p.EnhancedValue),
+		EnhancementDelta:    toIntPtr(p.EnhancementDelta),
+		IsOutlier:           p.GetIsOutlier(),
+		AmountMinor:         p.AmountMinor,
+		Amount:              p.GetAmount(),
+		Timestamp:           p.GetTimestamp(),
+		TransactionID:       p.GetTransactionId(),
+		AccountID:           p.GetAccountId(),
+		Merchant:            p.GetMerchant(),
+		Direction:           p.GetDirection(),
+		Posted:              toIntPtr(p.Posted),
+		Balance:             toIntPtr(p.Balance),
+		Rejected:            p.GetRejected(),
+		Regime:              p.GetRegime(),
+		Jump:                p.GetJump(),
+		EffectiveVolatility: p.GetEffectiveVolatility(),
+		Clamped:             p.GetClamped(),
+		Anomaly:             p.GetAnomaly(),
+		AnomalyKind:         p.GetAnomalyKind(),
+		Missing:             p.GetMissing(),
+		DuplicateOf:         toIntPtr(p.DuplicateOf),
+	}
+}
+
+// ToProto converts the statistics to their protobuf message
+func (s Statistics) ToProto() *chaoticpb.Statistics {
+	p := &chaoticpb.Statistics{
+		Mean:                      s.Mean,
+		Median:                    s.Median,
+		Stdev:                     s.Stdev,
+		Variance:                  s.Variance,
+		Min:                       int64(s.Min),
+		Max:                       int64(s.Max),
+		Count:                     int64(s.Count),
+		Q1:                        s.Q1,
+		Q3:                        s.Q3,
+		Iqr:                       s.IQR,
+		CoefficientOfVariation:    s.CoefficientOfVariation,
+		TrendStrength:             s.TrendStrength,
+		Volatility:                s.Volatility,
+		Skewness:                  s.Skewness,
+		Kurtosis:                  s.Kurtosis,
+		Lag1Autocorrelation:       s.Lag1Autocorrelation,
+		Mode:                      int64(s.Mode),
+		GeometricMean:             s.GeometricMean,
+		HarmonicMean:              s.HarmonicMean,
+		Mad:                       s.MAD,
+		MadNormalized:             s.MADNormalized,
+		HurstExponent:             s.HurstExponent,
+		SampleEntropy:             s.SampleEntropy,
+		PermutationEntropy:        s.PermutationEntropy,
+		DistributionEntropy:       s.DistributionEntropy,
+		MaxDrawdown:               int64(s.MaxDrawdown),
+		MaxDrawdownFraction:       s.MaxDrawdownFraction,
+		MaxDrawdownPeakStep:       int64(s.MaxDrawdownPeakStep),
+		MaxDrawdownTroughStep:     int64(s.MaxDrawdownTroughStep),
+		MaxDrawup:                 int64(s.MaxDrawup),
+		MaxDrawupFraction:         s.MaxDrawupFraction,
+		MaxDrawupTroughStep:       int64(s.MaxDrawupTroughStep),
+		MaxDrawupPeakStep:         int64(s.MaxDrawupPeakStep),
+		LongestIncreasingRun:      int64(s.LongestIncreasingRun),
+		LongestIncreasingRunStart: int64(s.LongestIncreasingRunStart),
+		LongestDecreasingRun:      int64(s.LongestDecreasingRun),
+		LongestDecreasingRunStart: int64(s.LongestDecreasingRunStart),
+		LongestFlatRun:            int64(s.LongestFlatRun),
+		LongestFlatRunStart:       int64(s.LongestFlatRunStart),
+		TurningPoints:             int64(s.TurningPoints),
+		MeanCrossings:             int64(s.MeanCrossings),
+		ReturnMean:                s.ReturnMean,
+		ReturnVolatility:          s.ReturnVolatility,
+		OutlierCount:              int64(s.OutlierCount),
+		Changepoints:              toInt64s(s.Changepoints),
+		Duration:                  s.Duration,
+		EventsPerSecond:           s.EventsPerSecond,
+		FinalBalance:              int64(s.FinalBalance),
+		MinBalance:                int64(s.MinBalance),
+		DebitRatio:                s.DebitRatio,
+		JumpCount:                 int64(s.JumpCount),
+		DiffusionVolatility:       s.DiffusionVolatility,
+		ClampedLow:                int64(s.ClampedLow),
+		ClampedHigh:               int64(s.ClampedHigh),
+		ClampRate:                 s.ClampRate,
+		Missing:                   int64(s.Missing),
+		Duplicates:                int64(s.Duplicates),
+	}
+	// Maps have no order, so types are written sorted for stable output
+	types := make([]string, 0, len(s.ByType))
+	for key := range s.ByType {
+		types = append(types, key)
+	}
+	sort.Strings(types)
+	for _, key := range types {
+		p.ByType = append(p.ByType, s.ByType[key].toProto(key))
+	}
+	regimes := make([]string, 0, len(s.ByRegime))
+	for name := range s.ByRegime {
+		regimes = append(regimes, name)
+	}
+	sort.Strings(regimes)
+	for _, name := range regimes {
+		p.ByRegime = append(p.ByRegime, s.ByRegime[name].toProto(name))
+	}
+	if s.Histogram != nil {
+		p.Histogram = s.Histogram.ToProto()
+	}
+	if s.Stationarity != nil {
+		p.Stationarity = s.Stationarity.ToProto()
+	}
+	if s.Money != nil {
+		p.Money = s.Money.ToProto()
+	}
+	if s.Arrivals != nil {
+		p.Arrivals = s.Arrivals.ToProto()
+	}
+	accounts := make([]string, 0, len(s.ByAccount))
+	for account := range s.ByAccount {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	for _, account := range accounts {
+		p.ByAccount = append(p.ByAccount, &chaoticpb.AccountCount{Account: account, Count: int64(s.ByAccount[account])})
+	}
+	p.TargetDistance = s.TargetDistance
+	return p
+}
+
+// FromProto replaces the statistics with the contents of p
+func (s *Statistics) FromProto(p *chaoticpb.Statistics) {
+	*s = Statistics{
+		Mean:                      p.GetMean(),
+		Median:                    p.GetMedian(),
+		Stdev:                     p.GetStdev(),
+		Variance:                  p.GetVariance(),
+		Min:                       int(p.GetMin()),
+		Max:                       int(p.GetMax()),
+		Count:                     int(p.GetCount()),
+		Q1:                        p.GetQ1(),
+		Q3:                        p.GetQ3(),
+		IQR:                       p.GetIqr(),
+		CoefficientOfVariation:    p.CoefficientOfVariation,
+		TrendStrength:             p.GetTrendStrength(),
+		Volatility:                p.GetVolatility(),
+		Skewness:                  p.GetSkewness(),
+		Kurtosis:                  p.GetKurtosis(),
+		Lag1Autocorrelation:       p.GetLag1Autocorrelation(),
+		Mode:                      int(p.GetMode()),
+		GeometricMean:             p.GetGeometricMean(),
+		HarmonicMean:              p.GetHarmonicMean(),
+		MAD:                       p.GetMad(),
+		MADNormalized:             p.GetMadNormalized(),
+		HurstExponent:             p.GetHurstExponent(),
+		SampleEntropy:             p.GetSampleEntropy(),
+		PermutationEntropy:        p.GetPermutationEntropy(),
+		DistributionEntropy:       p.GetDistributionEntropy(),
+		MaxDrawdown:               int(p.GetMaxDrawdown()),
+		MaxDrawdownFraction:       p.GetMaxDrawdownFraction(),
+		MaxDrawdownPeakStep:       int(p.GetMaxDrawdownPeakStep()),
+		MaxDrawdownTroughStep:     int(p.GetMaxDrawdownTroughStep()),
+		MaxDrawup:                 int(p.GetMaxDrawup()),
+		MaxDrawupFraction:         p.GetMaxDrawupFraction(),
+		MaxDrawupTroughStep:       int(p.GetMaxDrawupTroughStep()),
+		MaxDrawupPeakStep:         int(p.GetMaxDrawupPeakStep()),
+		LongestIncreasingRun:      int(p.GetLongestIncreasingRun()),
+		LongestIncreasingRunStart: int(p.GetLongestIncreasingRunStart()),
+		LongestDecreasingRun:      int(p.GetLongestDecreasingRun()),
+		LongestDecreasingRunStart: int(p.GetLongestDecreasingRunStart()),
+		LongestFlatRun:            int(p.GetLongestFlatRun()),
+		LongestFlatRunStart:       int(p.GetLongestFlatRunStart()),
+		TurningPoints:             int(p.GetTurningPoints()),
+		MeanCrossings:             int(p.GetMeanCrossings()),
+		ReturnMean:                p.GetReturnMean(),
+		ReturnVolatility:          p.GetReturnVolatility(),
+		OutlierCount:              int(p.GetOutlierCount()),
+		Changepoints:              toInts(p.GetChangepoints()),
+		Duration:                  p.GetDuration(),
+		EventsPerSecond:           p.GetEventsPerSecond(),
+		FinalBalance:              int(p.GetFinalBalance()),
+		MinBalance:                int(p.GetMinBalance()),
+		DebitRatio:                p.GetDebitRatio(),
+		JumpCount:                 int(p.GetJumpCount()),
+		DiffusionVolatility:       p.GetDiffusionVolatility(),
+		ClampedLow:                int(p.GetClampedLow()),
+		ClampedHigh:               int(p.GetClampedHigh()),
+		ClampRate:                 p.GetClampRate(),
+		Missing:                   int(p.GetMissing()),
+		Duplicates:                int(p.GetDuplicates()),
+	}
+	if len(p.GetByType()) > 0 {
+		s.ByType = make(map[string]TypeStatistics, len(p.GetByType()))
+		for _, group := range p.GetByType() {
+			s.ByType[group.GetType()] = TypeStatistics{
+				Count:         int(group.GetCount()),
+				MeanValue:     group.GetMeanValue(),
+				MeanAbsChange: group.GetMeanAbsChange(),
+				Share:         group.GetShare(),
+			}
+		}
+	}
+	if len(p.GetByRegime()) > 0 {
+		s.ByRegime = make(map[string]RegimeStatistics, len(p.GetByRegime()))
+		for _, group := range p.GetByRegime() {
+			s.ByRegime[group.GetRegime()] = RegimeStatistics{
+				Count:      int(group.GetCount()),
+				Share:      group.GetShare(),
+				Volatility: group.GetVolatility(),
+			}
+		}
+	}
+	if p.GetHistogram() != nil {
+		s.Histogram = new(HistogramResult)
+		s.Histogram.FromProto(p.GetHistogram())
+	}
+	if p.GetStationarity() != nil {
+		s.Stationarity = new(StationarityResult)
+		s.Stationarity.FromProto(p.GetStationarity())
+	}
+	if p.GetMoney() != nil {
+		s.Money = new(MoneyStatistics)
+		s.Money.FromProto(p.GetMoney())
+	}
+	if p.GetArrivals() != nil {
+		s.Arrivals = new(ArrivalStatistics)
+		s.Arrivals.FromProto(p.GetArrivals())
+	}
+	if len(p.GetByAccount()) > 0 {
+		s.ByAccount = make(map[string]int, len(p.GetByAccount()))
+		for _, group := range p.GetByAccount() {
+			s.ByAccount[group.GetAccount()] = int(group.GetCount())
+		}
+	}
+	s.TargetDistance = p.TargetDistance
+}
+
+// toProto converts the statistics of entries of type stepType to their protobuf message
+func (t TypeStatistics) toProto(stepType string) *chaoticpb.TypeStatistics {
+	return &chaoticpb.TypeStatistics{
+		Type:          stepType,
+		Count:         int64(t.Count),
+		MeanValue:     t.MeanValue,
+		MeanAbsChange: t.MeanAbsChange,
+		Share:         t.Share,
+	}
+}
+
+// toProto converts the statistics of entries in the regime called name to
+// their protobuf message
+func (r RegimeStatistics) toProto(name string) *chaoticpb.RegimeStatistics {
+	return &chaoticpb.RegimeStatistics{
+		Regime:     name,
+		Count:      int64(r.Count),
+		Share:      r.Share,
+		Volatility: r.Volatility,
+	}
+}
+
+// ToProto converts the histogram to its protobuf message
+func (h HistogramResult) ToProto() *chaoticpb.Histogram {
+	return &chaoticpb.Histogram{
+		Edges:       h.Edges,
+		Counts:      toInt64s(h.Counts),
+		Frequencies: h.Frequencies,
+	}
+}
+
+// FromProto replaces the histogram with the contents of p
+func (h *HistogramResult) FromProto(p *chaoticpb.Histogram) {
+	*h = HistogramResult{
+		Edges:       p.GetEdges(),
+		Counts:      toInts(p.GetCounts()),
+		Frequencies: p.GetFrequencies(),
+	}
+}
+
+// ToProto converts the stationarity test result to its protobuf message
+func (r StationarityResult) ToProto() *chaoticpb.Stationarity {
+	return &chaoticpb.Stationarity{
+		MeanT:          r.MeanT,
+		MeanPValue:     r.MeanPValue,
+		VarianceF:      r.VarianceF,
+		VariancePValue: r.VariancePValue,
+		AdfStatistic:   r.ADFStatistic,
+		Stationary:     r.Stationary,
+	}
+}
+
+// FromProto replaces the stationarity test result with the contents of p
+func (r *StationarityResult) FromProto(p *chaoticpb.Stationarity) {
+	*r = StationarityResult{
+		MeanT:          p.GetMeanT(),
+		MeanPValue:     p.GetMeanPValue(),
+		VarianceF:      p.GetVarianceF(),
+		VariancePValue: p.GetVariancePValue(),
+		ADFStatistic:   p.GetAdfStatistic(),
+		Stationary:     p.GetStationary(),
+	}
+}
+
+// ToProto converts the money statistics to their protobuf message
+func (m MoneyStatistics) ToProto() *chaoticpb.Money {
+	return &chaoticpb.Money{
+		Currency: m.Currency,
+		Total:    m.Total,
+		Mean:     m.Mean,
+		Median:   m.Median,
+		Stdev:    m.Stdev,
+		Min:      m.Min,
+		Max:      m.Max,
+	}
+}
+
+// FromProto replaces the money statistics with the contents of p
+func (m *MoneyStatistics) FromProto(p *chaoticpb.Money) {
+	*m = MoneyStatistics{
+		Currency: p.GetCurrency(),
+		Total:    p.GetTotal(),
+		Mean:     p.GetMean(),
+		Median:   p.GetMedian(),
+		Stdev:    p.GetStdev(),
+		Min:      p.GetMin(),
+		Max:      p.GetMax(),
+	}
+}
+
+// ToProto converts the arrival statistics to their protobuf message
+func (a ArrivalStatistics) ToProto() *chaoticpb.Arrivals {
+	return &chaoticpb.Arrivals{
+		Model:             string(a.Model),
+		InterArrivalMean:  a.InterArrivalMean,
+		InterArrivalStdev: a.InterArrivalStdev,
+		ExpectedMean:      a.ExpectedMean,
+		RateDeviation:     a.RateDeviation,
+	}
+}
+
+// FromProto replaces the arrival statistics with the contents of p
+func (a *ArrivalStatistics) FromProto(p *chaoticpb.Arrivals) {
+	*a = ArrivalStatistics{
+		Model:             ArrivalModel(p.GetModel()),
+		InterArrivalMean:  p.GetInterArrivalMean(),
+		InterArrivalStdev: p.GetInterArrivalStdev(),
+		ExpectedMean:      p.GetExpectedMean(),
+		RateDeviation:     p.GetRateDeviation(),
+	}
+}
+
+// ToProto converts the configuration to its protobuf message. Source is not
+// serializable and is left out, as it is from the JSON output.
+func (c ChaoticConfig) ToProto() *chaoticpb.Config {
+	p := &chaoticpb.Config{
+		Volatility:     c.Volatility,
+		TrendStrength:  c.TrendStrength,
+		MeanReversion:  c.MeanReversion,
+		MinValue:       int64(c.MinValue),
+		MaxValue:       int64(c.MaxValue),
+		Seed:           c.Seed,
+		RandomnessMode: string(c.RandomnessMode),
+		BoundaryMode:   string(c.BoundaryMode),
+
+		MultiplicativeFactors: c.MultiplicativeFactors,
+		NoiseAmplitude:        int64(c.NoiseAmplitude),
+
+		MeanReversionTarget: toInt64Ptr(c.MeanReversionTarget),
+		ReversionSpeed:      c.ReversionSpeed,
+
+		Model:     string(c.Model),
+		LogisticR: c.LogisticR,
+		TentMu:    c.TentMu,
+		HenonA:    c.HenonA,
+		HenonB:    c.HenonB,
+		MapX0:     c.MapX0,
+		MapY0:     c.MapY0,
+	}
+	if w := c.RegimeWeights; w != nil {
+		p.RegimeWeights = &chaoticpb.RegimeWeights{
+			TrendFollowing: w.TrendFollowing,
+			MeanReversion:  w.MeanReversion,
+			Multiplicative: w.Multiplicative,
+			AdditiveNoise:  w.AdditiveNoise,
+		}
+	}
+	if cur := c.Currency; cur != nil {
+		p.Currency = &chaoticpb.Currency{
+			Code:     cur.Code,
+			Exponent: int64(cur.Exponent),
+			Rounding: string(cur.Rounding),
+		}
+	}
+	if t := c.Timing; t != nil {
+		p.Timing = &chaoticpb.Timing{
+			Start:         formatTimestamp(t.Start),
+			IntervalNanos: int64(t.Interval),
+			Jitter:        t.Jitter,
+			Arrivals:      string(t.Arrivals),
+			HourlyRates:   t.HourlyRates,
+		}
+	}
+	if e := c.Entities; e != nil {
+		p.Entities = &chaoticpb.Entities{Accounts: int64(e.Accounts)}
+		for _, m := range e.Merchants {
+			p.Entities.Merchants = append(p.Entities.Merchants, &chaoticpb.Merchant{Label: m.Label, Weight: m.Weight})
+		}
+	}
+	if l := c.Ledger; l != nil {
+		p.Ledger = &chaoticpb.Ledger{
+			OpeningBalance:   int64(l.OpeningBalance),
+			DebitProbability: l.DebitProbability,
+			Floor:            toInt64Ptr(l.Floor),
+			FloorPolicy:      string(l.FloorPolicy),
+		}
+	}
+	if r := c.Regimes; r != nil {
+		p.Regimes = &chaoticpb.RegimeSwitching{}
+		for _, state := range r.States {
+			p.Regimes.States = append(p.Regimes.States, &chaoticpb.VolatilityRegime{
+				Name:          state.Name,
+				Volatility:    state.Volatility,
+				TrendStrength: state.TrendStrength,
+				MeanReversion: state.MeanReversion,
+			})
+		}
+		for _, row := range r.Transitions {
+			p.Regimes.Transitions = append(p.Regimes.Transitions, &chaoticpb.TransitionRow{Probabilities: row})
+		}
+	}
+	if j := c.Jumps; j != nil {
+		p.Jumps = &chaoticpb.Jumps{
+			Probability: j.Probability,
+			Mean:        j.Mean,
+			Stdev:       j.Stdev,
+			Sizes:       j.Sizes,
+		}
+	}
+	if g := c.Garch; g != nil {
+		p.Garch = &chaoticpb.Garch{Omega: g.Omega, Alpha: g.Alpha, Beta: g.Beta}
+	}
+	if a := c.Anomalies; a != nil {
+		p.Anomalies = &chaoticpb.Anomalies{
+			Rate:        a.Rate,
+			Multiplier:  a.Multiplier,
+			Delta:       int64(a.Delta),
+			Duration:    int64(a.Duration),
+			LevelShifts: a.LevelShifts,
+		}
+	}
+	if f := c.Faults; f != nil {
+		p.Faults = &chaoticpb.DeliveryFaults{DropRate: f.DropRate, DuplicateRate: f.DuplicateRate}
+	}
+	if l := c.Lorenz; l != nil {
+		p.Lorenz = &chaoticpb.Lorenz{
+			Sigma:  l.Sigma,
+			Rho:    l.Rho,
+			Beta:   l.Beta,
+			Dt:     l.Dt,
+			Stride: int64(l.Stride),
+			Axis:   l.Axis,
+			BurnIn: int64(l.BurnIn),
+			Start:  l.Start,
+		}
+	}
+	return p
+}
+
+// FromProto replaces the configuration with the contents of p
+func (c *ChaoticConfig) FromProto(p *chaoticpb.Config) {
+	*c = ChaoticConfig{
+		Volatility:     p.GetVolatility(),
+		TrendStrength:  p.GetTrendStrength(),
+		MeanReversion:  p.GetMeanReversion(),
+		MinValue:       int(p.GetMinValue()),
+		MaxValue:       int(p.GetMaxValue()),
+		Seed:           p.Seed,
+		RandomnessMode: RandomnessMode(p.GetRandomnessMode()),
+		BoundaryMode:   BoundaryMode(p.GetBoundaryMode()),
+
+		MultiplicativeFactors: p.GetMultiplicativeFactors(),
+		NoiseAmplitude:        int(p.GetNoiseAmplitude()),
+
+		MeanReversionTarget: toIntPtr(p.MeanReversionTarget),
+		ReversionSpeed:      p.GetReversionSpeed(),
+
+		Model:     Model(p.GetModel()),
+		LogisticR: p.GetLogisticR(),
+		TentMu:    p.GetTentMu(),
+		HenonA:    p.GetHenonA(),
+		HenonB:    p.GetHenonB(),
+		MapX0:     p.MapX0,
+		MapY0:     p.GetMapY0(),
+	}
+	if w := p.GetRegimeWeights(); w != nil {
+		c.RegimeWeights = &RegimeWeights{
+			TrendFollowing: w.GetTrendFollowing(),
+			MeanReversion:  w.GetMeanReversion(),
+			Multiplicative: w.GetMultiplicative(),
+			AdditiveNoise:  w.GetAdditiveNoise(),
+		}
+	}
+	if cur := p.GetCurrency(); cur != nil {
+		c.Currency = &Currency{
+			Code:     cur.GetCode(),
+			Exponent: int(cur.GetExponent()),
+			Rounding: RoundingMode(cur.GetRounding()),
+		}
+	}
+	if t := p.GetTiming(); t != nil {
+		// A malformed start is left zero
+		start, _ := time.Parse(time.RFC3339Nano, t.GetStart())
+		c.Timing = &Timing{
+			Start:       start,
+			Interval:    time.Duration(t.GetIntervalNanos()),
+			Jitter:      t.GetJitter(),
+			Arrivals:    ArrivalModel(t.GetArrivals()),
+			HourlyRates: t.GetHourlyRates(),
+		}
+	}
+	if e := p.GetEntities(); e != nil {
+		c.Entities = &Entities{Accounts: int(e.GetAccounts())}
+		for _, m := range e.GetMerchants() {
+			c.Entities.Merchants = append(c.Entities.Merchants, Merchant{Label: m.GetLabel(), Weight: m.GetWeight()})
+		}
+	}
+	if l := p.GetLedger(); l != nil {
+		c.Ledger = &Ledger{
+			OpeningBalance:   int(l.GetOpeningBalance()),
+			DebitProbability: l.GetDebitProbability(),
+			Floor:            toIntPtr(l.Floor),
+			FloorPolicy:      FloorPolicy(l.GetFloorPolicy()),
+		}
+	}
+	if r := p.GetRegimes(); r != nil {
+		c.Regimes = &RegimeSwitching{}
+		for _, state := range r.GetStates() {
+			c.Regimes.States = append(c.Regimes.States, VolatilityRegime{
+				Name:          state.GetName(),
+				Volatility:    state.GetVolatility(),
+				TrendStrength: state.GetTrendStrength(),
+				MeanReversion: state.GetMeanReversion(),
+			})
+		}
+		for _, row := range r.GetTransitions() {
+			c.Regimes.Transitions = append(c.Regimes.Transitions, row.GetProbabilities())
+		}
+	}
+	if j := p.GetJumps(); j != nil {
+		c.Jumps = &Jumps{
+			Probability: j.GetProbability(),
+			Mean:        j.GetMean(),
+			Stdev:       j.GetStdev(),
+			Sizes:       j.GetSizes(),
+		}
+	}
+	if g := p.GetGarch(); g != nil {
+		c.Garch = &Garch{Omega: g.GetOmega(), Alpha: g.GetAlpha(), Beta: g.GetBeta()}
+	}
+	if a := p.GetAnomalies(); a != nil {
+		c.Anomalies = &AnomalyConfig{
+			Rate:        a.GetRate(),
+			Multiplier:  a.GetMultiplier(),
+			Delta:       int(a.GetDelta()),
+			Duration:    int(a.GetDuration()),
+			LevelShifts: a.GetLevelShifts(),
+		}
+	}
+	if f := p.GetFaults(); f != nil {
+		c.Faults = &DeliveryFaults{DropRate: f.GetDropRate(), DuplicateRate: f.GetDuplicateRate()}
+	}
+	if l := p.GetLorenz(); l != nil {
+		c.Lorenz = &Lorenz{
+			Sigma:  l.GetSigma(),
+			Rho:    l.GetRho(),
+			Beta:   l.GetBeta(),
+			Dt:     l.GetDt(),
+			Stride: int(l.GetStride()),
+			Axis:   l.GetAxis(),
+			BurnIn: int(l.GetBurnIn()),
+			Start:  l.GetStart(),
+		}
+	}
+}
+
+// ToProto converts the metadata to its protobuf message
+func (m RunMetadata) ToProto() *chaoticpb.RunMetadata {
+	return &chaoticpb.RunMetadata{
+		GeneratedAt:      m.GeneratedAt,
+		Config:           m.Config.ToProto(),
+		SequenceLength:   int64(m.SequenceLength),
+		Seed:             m.Seed,
+		GeneratorVersion: m.GeneratorVersion,
+		FormatVersion:    m.FormatVersion,
+		Extended:         m.Extended,
+		Anomalies:        toInt64s(m.Anomalies),
+	}
+}
+
+// FromProto replaces the metadata with the contents of p
+func (m *RunMetadata) FromProto(p *chaoticpb.RunMetadata) {
+	*m = RunMetadata{
+		GeneratedAt:      p.GetGeneratedAt(),
+		SequenceLength:   int(p.GetSequenceLength()),
+		Seed:             p.Seed,
+		GeneratorVersion: p.GetGeneratorVersion(),
+		FormatVersion:    p.GetFormatVersion(),
+		Extended:         p.GetExtended(),
+		Anomalies:        toInts(p.GetAnomalies()),
+	}
+	m.Config.FromProto(p.GetConfig())
+}
+
+// ToProto converts the run to a Sequence envelope
+func (d RunDocument) ToProto() *chaoticpb.Sequence {
+	entries := make([]*chaoticpb.LogEntry, len(d.Sequence))
+	for i, entry := range d.Sequence {
+		entries[i] = entry.ToProto()
+	}
+	return &chaoticpb.Sequence{
+		Metadata:   d.Metadata.ToProto(),
+		Statistics: d.Statistics.ToProto(),
+		Entries:    entries,
+	}
+}
+
+// FromProto replaces the run with the contents of a Sequence envelope
+func (d *RunDocument) FromProto(p *chaoticpb.Sequence) {
+	d.Metadata.FromProto(p.GetMetadata())
+	d.Statistics.FromProto(p.GetStatistics())
+	d.Sequence = make([]LogEntry, len(p.GetEntries()))
+	for i, entry := range p.GetEntries() {
+		d.Sequence[i].FromProto(entry)
+	}
+}
+
+// MarshalProto encodes the run as protobuf wire bytes
+func (d RunDocument) MarshalProto() ([]byte, error) {
+	return proto.Marshal(d.ToProto())
+}
+
+// UnmarshalProto decodes a run from protobuf wire bytes
+func (d *RunDocument) UnmarshalProto(data []byte) error {
+	var p chaoticpb.Sequence
+	if err := proto.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	d.FromProto(&p)
+	return nil
+}
+
+// toInt64s widens a slice of ints, keeping nil as nil
+func toInt64s(values []int) []int64 {
+	if values == nil {
+		return nil
+	}
+	wide := make([]int64, len(values))
+	for i, v := range values {
+		wide[i] = int64(v)
+	}
+	return wide
+}
+
+// toInts narrows a slice of int64s, returning nil for an empty one since the
+// wire format cannot tell empty from absent
+func toInts(values []int64) []int {
+	if len(values) == 0 {
+		return nil
+	}
+	narrow := make([]int, len(values))
+	for i, v := range values {
+		narrow[i] = int(v)
+	}
+	return narrow
+}
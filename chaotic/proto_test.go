@@ -0,0 +1,55 @@
+package chaotic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	seed := int64(31)
+	plainConfig := DefaultConfig()
+	plainConfig.Seed = &seed
+	plain, err := ChaoticTransactionSequence(500, plainConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainStats, err := ComputeStatistics(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := map[string]RunDocument{
+		"layered": seededDocument(t, 2000, layeredConfig(seed)),
+		"plain": {
+			Metadata:   RunMetadata{Config: plainConfig, SequenceLength: 500, Seed: &seed, GeneratorVersion: GeneratorVersion},
+			Statistics: plainStats,
+			Sequence:   plain,
+		},
+	}
+	for name, doc := range docs {
+		t.Run(name, func(t *testing.T) {
+			data, err := doc.MarshalProto()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded RunDocument
+			if err := decoded.UnmarshalProto(data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded.Sequence, doc.Sequence) {
+				for i := range doc.Sequence {
+					if !reflect.DeepEqual(decoded.Sequence[i], doc.Sequence[i]) {
+						t.Fatalf("entry %d is %+v, want %+v", i, decoded.Sequence[i], doc.Sequence[i])
+					}
+				}
+				t.Fatalf("decoded %d entries, want %d", len(decoded.Sequence), len(doc.Sequence))
+			}
+			if !reflect.DeepEqual(decoded.Metadata, doc.Metadata) {
+				t.Errorf("metadata is\n%+v, want\n%+v", decoded.Metadata, doc.Metadata)
+			}
+			if !reflect.DeepEqual(decoded.Statistics, doc.Statistics) {
+				t.Errorf("statistics are\n%+v, want\n%+v", decoded.Statistics, doc.Statistics)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package chaotic
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math/big"
+	mrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// entropyBufferSize is how many bytes of crypto/rand entropy are fetched per read
+const entropyBufferSize = 4096
+
+// entropyBuffer serves crypto/rand entropy from a buffer refilled in bulk,
+// so each draw costs a copy instead of a kernel-backed read
+type entropyBuffer struct {
+	mu  sync.Mutex
+	buf [entropyBufferSize]byte
+	pos int
+}
+
+// secureEntropy is the shared buffer behind secureRandIntn and secureRandFloat64
+var secureEntropy = &entropyBuffer{pos: entropyBufferSize}
+
+// uint64 returns the next 8 random bytes, refilling the buffer when exhausted
+func (b *entropyBuffer) uint64() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pos+8 > len(b.buf) {
+		if _, err := io.ReadFull(rand.Reader, b.buf[:]); err != nil {
+			return 0, err
+		}
+		b.pos = 0
+	}
+	v := binary.LittleEndian.Uint64(b.buf[b.pos:])
+	b.pos += 8
+	return v, nil
+}
+
+// intn returns a uniform value in [0, bound), rejecting the top partial
+// block of the 64-bit range so the modulo is unbiased
+func (b *entropyBuffer) intn(bound uint64) (uint64, error) {
+	limit := ^uint64(0) - ^uint64(0)%bound
+	for {
+		v, err := b.uint64()
+		if err != nil {
+			return 0, err
+		}
+		if v < limit {
+			return v % bound, nil
+		}
+	}
+}
+
+// secureRandIntn generates cryptographically secure random numbers
+func secureRandIntn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if v, err := secureEntropy.intn(uint64(n)); err == nil {
+		return int(v)
+	}
+	num, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// Fallback to time-based seeding if crypto fails
+		var fallback int64
+		if err := binary.Read(rand.Reader, binary.BigEndian, &fallback); err != nil {
+			return int(time.Now().UnixNano() % int64(n))
+		}
+		if fallback < 0 {
+			fallback = -fallback
+		}
+		return int(fallback % int64(n))
+	}
+	return int(num.Int64())
+}
+
+// secureRandFloat64 generates cryptographically secure random float between 0 and 1
+func secureRandFloat64() float64 {
+	if v, err := secureEntropy.uint64(); err == nil {
+		return float64(v>>11) / (1 << 53)
+	}
+	var buf [8]byte
+	_, err := rand.Read(buf[:])
+	if err != nil {
+		return float64(secureRandIntn(1<<53)) / (1 << 53)
+	}
+	return float64(binary.LittleEndian.Uint64(buf[:])&((1<<53)-1)) / (1 << 53)
+}
+
+// RandSource abstracts the random number generator used by the sequencer.
+// *math/rand.Rand satisfies it, so a seeded rand.New(rand.NewSource(seed))
+// yields reproducible sequences.
+type RandSource interface {
+	Intn(n int) int
+	Float64() float64
+}
+
+// cryptoSource is the default RandSource backed by crypto/rand
+type cryptoSource struct{}
+
+func (cryptoSource) Intn(n int) int   { return secureRandIntn(n) }
+func (cryptoSource) Float64() float64 { return secureRandFloat64() }
+
+// privateCryptoSource is a RandSource backed by crypto/rand through an
+// entropy buffer of its own, for goroutines that would otherwise contend on
+// secureEntropy. Read errors fall back to the shared functions.
+type privateCryptoSource struct {
+	entropy *entropyBuffer
+}
+
+func (s privateCryptoSource) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if v, err := s.entropy.intn(uint64(n)); err == nil {
+		return int(v)
+	}
+	return secureRandIntn(n)
+}
+
+func (s privateCryptoSource) Float64() float64 {
+	if v, err := s.entropy.uint64(); err == nil {
+		return float64(v>>11) / (1 << 53)
+	}
+	return secureRandFloat64()
+}
+
+// RandomnessMode selects the default random source when no Seed or Source is set
+type RandomnessMode string
+
+const (
+	// RandomnessSecure draws from crypto/rand (the default)
+	RandomnessSecure RandomnessMode = "secure"
+	// RandomnessFast draws from a ChaCha8 generator seeded once from crypto/rand,
+	// trading cryptographic guarantees for speed on long simulations
+	RandomnessFast RandomnessMode = "fast"
+)
+
+// prngSource is a RandSource backed by a math/rand/v2 generator
+type prngSource struct {
+	rng *mrand.Rand
+	pcg *mrand.PCG // set for seeded sources so their state can be snapshotted
+}
+
+// seedStream is the fixed PCG stream selector used for seeded sources
+const seedStream = 0x9e3779b97f4a7c15
+
+// NewSeededSource returns a deterministic RandSource; the same seed always
+// produces the same draws
+func NewSeededSource(seed int64) RandSource {
+	pcg := mrand.NewPCG(uint64(seed), seedStream)
+	return &prngSource{rng: mrand.New(pcg), pcg: pcg}
+}
+
+// newFastSource returns a ChaCha8-backed RandSource seeded from crypto/rand
+func newFastSource() RandSource {
+	var seed [32]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		binary.LittleEndian.PutUint64(seed[:], uint64(time.Now().UnixNano()))
+	}
+	return &prngSource{rng: mrand.New(mrand.NewChaCha8(seed))}
+}
+
+func (s *prngSource) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return s.rng.IntN(n)
+}
+
+func (s *prngSource) Float64() float64 { return s.rng.Float64() }
+
+// NewSeed draws a fresh seed from crypto/rand, suitable for recording so a
+// seeded run can be replayed later
+func NewSeed() int64 {
+	var seed int64
+	if err := binary.Read(rand.Reader, binary.BigEndian, &seed); err != nil {
+		return time.Now().UnixNano()
+	}
+	return seed
+}
+
+// randSource returns the random source for one generation run: the explicit
+// Source if set, otherwise a fresh seeded source when Seed is set, otherwise
+// the source selected by RandomnessMode
+func (c ChaoticConfig) randSource() RandSource {
+	return selectRandSource(c.Source, c.Seed, c.RandomnessMode)
+}
+
+// selectRandSource picks the random source for a run from the Source, Seed
+// and RandomnessMode settings of a configuration
+func selectRandSource(source RandSource, seed *int64, mode RandomnessMode) RandSource {
+	switch {
+	case source != nil:
+		return source
+	case seed != nil:
+		return NewSeededSource(*seed)
+	case mode == RandomnessFast:
+		return newFastSource()
+	default:
+		return cryptoSource{}
+	}
+}
+
+// sideDraws supplies the draws of a layer that decorates the entries, such
+// as their timestamps, without touching the value source, so adding the
+// layer to a configuration leaves its values unchanged. With a Seed the
+// draws are derived from the seed, and with an explicit Source from a fixed
+// key, as a pure function of the step, so a resumed run can carry on from
+// any step; otherwise they come from a source of the configured
+// RandomnessMode.
+type sideDraws struct {
+	key uint64     // keys the derived draws
+	src RandSource // draws of an unseeded run; nil when they are derived from key
+}
+
+// newSideDraws prepares the draws of the layer whose seeds are separated
+// from other uses by stream
+func newSideDraws(config ChaoticConfig, stream uint64) sideDraws {
+	var d sideDraws
+	switch {
+	case config.Seed != nil:
+		d.key = mix64(uint64(*config.Seed) ^ stream)
+	case config.Source != nil:
+		// The zero key keeps runs from a scripted source reproducible
+	default:
+		d.src = selectRandSource(nil, nil, config.RandomnessMode)
+	}
+	return d
+}
+
+// fork returns draws with the same source whose derived draws are
+// independent of d's, for a second field of the same layer
+func (d sideDraws) fork(stream uint64) sideDraws {
+	return sideDraws{key: mix64(d.key + stream*golden64), src: d.src}
+}
+
+// derived reports whether the draws are a function of the key and the step
+func (d sideDraws) derived() bool {
+	return d.src == nil
+}
+
+// uniform returns the draw from [0, 1) for step
+func (d sideDraws) uniform(step int) float64 {
+	if d.src != nil {
+		return d.src.Float64()
+	}
+	return float64(mix64(d.key+uint64(step)*golden64)>>11) / (1 << 53)
+}
+
+// workerSource returns an unshared random source of the configured
+// RandomnessMode for one worker of a parallel run; Seed and Source are
+// handled by the caller
+func (c ChaoticConfig) workerSource() RandSource {
+	if c.RandomnessMode == RandomnessFast {
+		return newFastSource()
+	}
+	return privateCryptoSource{entropy: &entropyBuffer{pos: entropyBufferSize}}
+}
+
+// Shuffle returns a random permutation of values using Fisher-Yates over src.
+// The input slice is left untouched; a nil src falls back to crypto/rand.
+func Shuffle(values []int, src RandSource) []int {
+	if src == nil {
+		src = cryptoSource{}
+	}
+	shuffled := make([]int, len(values))
+	copy(shuffled, values)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := src.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
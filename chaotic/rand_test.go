@@ -0,0 +1,100 @@
+package chaotic
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"testing"
+)
+
+// BenchmarkRandomnessModes compares a 1M-step generation with the secure
+// default and with RandomnessFast
+func BenchmarkRandomnessModes(b *testing.B) {
+	for _, mode := range []RandomnessMode{RandomnessSecure, RandomnessFast} {
+		b.Run(string(mode), func(b *testing.B) {
+			config := DefaultConfig()
+			config.RandomnessMode = mode
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := ChaoticTransactionSequence(1_000_000, config); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSourceDraws compares the draws alone: one Intn and one Float64,
+// about what a step takes
+func BenchmarkSourceDraws(b *testing.B) {
+	for _, mode := range []RandomnessMode{RandomnessSecure, RandomnessFast} {
+		b.Run(string(mode), func(b *testing.B) {
+			src := selectRandSource(nil, nil, mode)
+			for b.Loop() {
+				src.Intn(1000)
+				src.Float64()
+			}
+		})
+	}
+}
+
+// countingReader counts the reads made from r
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.r.Read(p)
+}
+
+// unbufferedSource draws from crypto/rand as the secure source did before
+// it was buffered: a big.Int per Intn and an 8-byte read per Float64
+type unbufferedSource struct{}
+
+func (unbufferedSource) Intn(n int) int {
+	num, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(err)
+	}
+	return int(num.Int64())
+}
+
+func (unbufferedSource) Float64() float64 {
+	var buf [8]byte
+	if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+		panic(err)
+	}
+	return float64(binary.LittleEndian.Uint64(buf[:])&((1<<53)-1)) / (1 << 53)
+}
+
+// BenchmarkSecureEntropy compares a 100k-step generation drawing through the
+// entropy buffer with one reading crypto/rand for every draw, reporting the
+// reads from crypto/rand.Reader
+func BenchmarkSecureEntropy(b *testing.B) {
+	for _, bench := range []struct {
+		name   string
+		source RandSource
+	}{
+		{"buffered", privateCryptoSource{entropy: &entropyBuffer{pos: entropyBufferSize}}},
+		{"unbuffered", unbufferedSource{}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			reader := &countingReader{r: rand.Reader}
+			rand.Reader = reader
+			defer func() { rand.Reader = reader.r }()
+
+			config := DefaultConfig()
+			config.Source = bench.source
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := ChaoticTransactionSequence(100_000, config); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(reader.reads)/float64(b.N), "reads/op")
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// transitionTolerance is how far a row of a transition matrix may stray
+// from summing to 1 through rounding
+const transitionTolerance = 1e-9
+
+// VolatilityRegime is one state of a RegimeSwitching layer, with the
+// dynamics the generator uses while it is active
+type VolatilityRegime struct {
+	Name          string  // recorded on each entry generated in the regime
+	Volatility    float64 // 0.0 to 1.0, replacing ChaoticConfig.Volatility
+	TrendStrength float64 // 0.0 to 1.0, replacing ChaoticConfig.TrendStrength
+	MeanReversion float64 // 0.0 to 1.0, replacing ChaoticConfig.MeanReversion
+}
+
+// RegimeSwitching alternates the dynamics of a sequence between regimes,
+// such as calm and volatile periods, as a Markov chain. Step 0 is in the
+// first regime, and every later step moves from the regime of the step
+// before to regime j with probability Transitions[i][j], which may be i
+// itself. While a regime is active its Volatility, TrendStrength and
+// MeanReversion replace those of the ChaoticConfig.
+//
+// As with Timing, the transitions are not drawn from the value source: with
+// a Seed they are derived from the seed, so a seeded run reproduces its
+// regime path, and otherwise they come from a source of the configured
+// RandomnessMode.
+type RegimeSwitching struct {
+	States      []VolatilityRegime // at least two, with unique names
+	Transitions [][]float64        // a row per state, each a probability per state summing to 1
+}
+
+// Validate reports whether the regimes are usable, returning an error
+// wrapping ErrInvalidRegimeSwitching if not
+func (r RegimeSwitching) Validate() error {
+	if len(r.States) < 2 {
+		return fmt.Errorf("%w: need at least 2 regimes, have %d", ErrInvalidRegimeSwitching, len(r.States))
+	}
+	seen := make(map[string]bool, len(r.States))
+	for i, state := range r.States {
+		if state.Name == "" {
+			return fmt.Errorf("%w: regime %d has no name", ErrInvalidRegimeSwitching, i)
+		}
+		if seen[state.Name] {
+			return fmt.Errorf("%w: regime name %q is used twice", ErrInvalidRegimeSwitching, state.Name)
+		}
+		seen[state.Name] = true
+		for _, f := range []struct {
+			name  string
+			value float64
+		}{
+			{"Volatility", state.Volatility},
+			{"TrendStrength", state.TrendStrength},
+			{"MeanReversion", state.MeanReversion},
+		} {
+			if !(f.value >= 0 && f.value <= 1) {
+				return fmt.Errorf("%w: regime %q has %s %v, which must be between 0.0 and 1.0", ErrInvalidRegimeSwitching, state.Name, f.name, f.value)
+			}
+		}
+	}
+
+	if len(r.Transitions) != len(r.States) {
+		return fmt.Errorf("%w: %d transition rows for %d regimes", ErrInvalidRegimeSwitching, len(r.Transitions), len(r.States))
+	}
+	for i, row := range r.Transitions {
+		name := r.States[i].Name
+		if len(row) != len(r.States) {
+			return fmt.Errorf("%w: the transition row of %q has %d entries, not %d", ErrInvalidRegimeSwitching, name, len(row), len(r.States))
+		}
+		var total float64
+		for j, p := range row {
+			if !(p >= 0 && p <= 1) {
+				return fmt.Errorf("%w: transition from %q to %q has probability %v", ErrInvalidRegimeSwitching, name, r.States[j].Name, p)
+			}
+			total += p
+		}
+		if math.Abs(total-1) > transitionTolerance {
+			return fmt.Errorf("%w: the transition row of %q sums to %v, not 1", ErrInvalidRegimeSwitching, name, total)
+		}
+	}
+	return nil
+}
+
+// index returns the position of the regime called name, and false if there
+// is none
+func (r RegimeSwitching) index(name string) (int, bool) {
+	for i, state := range r.States {
+		if state.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// regimeStream separates the regime draws from other uses of a seed
+const regimeStream = 0x726567696d65 // "regime"
+
+// regimeSwitcher walks the regime path of one run
+type regimeSwitcher struct {
+	switching  RegimeSwitching
+	thresholds [][]float64 // cumulative transition rows
+	draws      sideDraws
+	current    int // regime of the last step entered
+}
+
+// newRegimeSwitcher prepares the switcher of a run with config, which must
+// have Regimes
+func newRegimeSwitcher(config ChaoticConfig) *regimeSwitcher {
+	r := &regimeSwitcher{
+		switching: *config.Regimes,
+		draws:     newSideDraws(config, regimeStream),
+	}
+	for _, row := range r.switching.Transitions {
+		cumulative := make([]float64, len(row))
+		var total float64
+		for j, p := range row {
+			total += p
+			cumulative[j] = total
+		}
+		r.thresholds = append(r.thresholds, cumulative)
+	}
+	return r
+}
+
+// enter moves to the regime of step, which must follow the last step entered
+func (r *regimeSwitcher) enter(step int) {
+	if step == 0 {
+		r.current = 0
+		return
+	}
+	row := r.thresholds[r.current]
+	// Scaling by the row total absorbs the rounding Validate tolerates
+	u := r.draws.uniform(step) * row[len(row)-1]
+	k := sort.Search(len(row), func(k int) bool { return u < row[k] })
+	r.current = min(k, len(row)-1)
+}
+
+// regime returns the active regime
+func (r *regimeSwitcher) regime() VolatilityRegime {
+	return r.switching.States[r.current]
+}
+
+// RegimeStatistics summarizes the entries generated in one volatility regime
+type RegimeStatistics struct {
+	Count      int     `json:"count"`
+	Share      float64 `json:"share"`      // fraction of all entries, the share of time spent in the regime
+	Volatility float64 `json:"volatility"` // mean absolute change from the previous value, over entries that have one
+}
+
+// calculateByRegime groups the entries by regime and summarizes each group.
+// It returns nil when no entry has a regime.
+func calculateByRegime(sequence []LogEntry) map[string]RegimeStatistics {
+	type totals struct {
+		count, changes int
+		absChange      float64
+	}
+	groups := make(map[string]*totals)
+	for i, entry := range sequence {
+		if entry.Regime == "" {
+			continue
+		}
+		group := groups[entry.Regime]
+		if group == nil {
+			group = &totals{}
+			groups[entry.Regime] = group
+		}
+		group.count++
+		if i > 0 {
+			group.changes++
+			group.absChange += math.Abs(float64(entry.Value) - float64(sequence[i-1].Value))
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	byRegime := make(map[string]RegimeStatistics, len(groups))
+	for name, group := range groups {
+		stats := RegimeStatistics{
+			Count: group.count,
+			Share: float64(group.count) / float64(len(sequence)),
+		}
+		if group.changes > 0 {
+			stats.Volatility = group.absChange / float64(group.changes)
+		}
+		byRegime[name] = stats
+	}
+	return byRegime
+}
@@ -0,0 +1,95 @@
+package chaotic
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// calmAndStorm returns two regimes that stay calm two thirds of the time
+func calmAndStorm() RegimeSwitching {
+	return RegimeSwitching{
+		States: []VolatilityRegime{
+			{Name: "calm", Volatility: 0.1, TrendStrength: 0.3, MeanReversion: 0.2},
+			{Name: "storm", Volatility: 0.9, TrendStrength: 0.3, MeanReversion: 0.2},
+		},
+		Transitions: [][]float64{{0.95, 0.05}, {0.1, 0.9}},
+	}
+}
+
+func TestSeededRegimePathRepeats(t *testing.T) {
+	path := func(seed int64) ([]string, Statistics) {
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.MinValue, config.MaxValue = 1, 1_000_000
+		regimes := calmAndStorm()
+		config.Regimes = &regimes
+		sequence, err := ChaoticTransactionSequence(50_000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := ComputeStatistics(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, len(sequence))
+		for i, entry := range sequence {
+			names[i] = entry.Regime
+		}
+		return names, stats
+	}
+
+	first, stats := path(51)
+	if again, _ := path(51); !reflect.DeepEqual(again, first) {
+		t.Error("the same seed took a different regime path")
+	}
+	if other, _ := path(52); reflect.DeepEqual(other, first) {
+		t.Error("different seeds took the same regime path")
+	}
+	if first[0] != "calm" {
+		t.Errorf("step 0 is in %q, want the first regime", first[0])
+	}
+
+	// The chain spends 0.1 / (0.05 + 0.1) of its time calm
+	calm, storm := stats.ByRegime["calm"], stats.ByRegime["storm"]
+	if !closeTo(calm.Share, 2.0/3, 0.03) || calm.Count+storm.Count != len(first) {
+		t.Errorf("calm for %d steps (%.3f) and stormy for %d, want about two thirds calm", calm.Count, calm.Share, storm.Count)
+	}
+	if storm.Volatility <= calm.Volatility {
+		t.Errorf("volatility %.1f in the storm, %.1f in the calm", storm.Volatility, calm.Volatility)
+	}
+}
+
+func TestValidateRegimeSwitching(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*RegimeSwitching)
+	}{
+		{"one regime", func(r *RegimeSwitching) { r.States, r.Transitions = r.States[:1], [][]float64{{1}} }},
+		{"no name", func(r *RegimeSwitching) { r.States[1].Name = "" }},
+		{"same name", func(r *RegimeSwitching) { r.States[1].Name = "calm" }},
+		{"volatility", func(r *RegimeSwitching) { r.States[0].Volatility = 1.5 }},
+		{"missing row", func(r *RegimeSwitching) { r.Transitions = r.Transitions[:1] }},
+		{"short row", func(r *RegimeSwitching) { r.Transitions[0] = []float64{1} }},
+		{"negative probability", func(r *RegimeSwitching) { r.Transitions[1] = []float64{-0.1, 1.1} }},
+		{"row under 1", func(r *RegimeSwitching) { r.Transitions[0] = []float64{0.5, 0.4} }},
+		{"row over 1", func(r *RegimeSwitching) { r.Transitions[1] = []float64{0.6, 0.6} }},
+		{"row a little over 1", func(r *RegimeSwitching) { r.Transitions[1] = []float64{0.1, 0.9 + 1e-6} }},
+	}
+	for _, tt := range tests {
+		regimes := calmAndStorm()
+		tt.modify(&regimes)
+		if err := regimes.Validate(); !errors.Is(err, ErrInvalidRegimeSwitching) {
+			t.Errorf("%s: Validate returned %v, want ErrInvalidRegimeSwitching", tt.name, err)
+		}
+	}
+
+	// A row that misses 1 only by rounding is accepted: 0.6 + 0.3 + 0.1
+	// comes to just under 1 in floating point
+	regimes := calmAndStorm()
+	regimes.States = append(regimes.States, VolatilityRegime{Name: "gale", Volatility: 1})
+	regimes.Transitions = [][]float64{{0.6, 0.3, 0.1}, {0.1, 0.8, 0.1}, {0.3, 0.3, 0.4}}
+	if err := regimes.Validate(); err != nil {
+		t.Errorf("rows summing to 1 up to rounding: %v", err)
+	}
+}
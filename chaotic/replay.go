@@ -0,0 +1,127 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// GeneratorVersion identifies the generation algorithm. It is bumped whenever
+// a change alters the values produced for a given seed, so saved runs can
+// tell whether they are still replayable.
+const GeneratorVersion = "1"
+
+// FormatVersion identifies the layout of saved run documents. It is bumped
+// whenever a saved field changes type or meaning, so readers can tell which
+// convention a file follows. Version 2 reports the median and quartiles as
+// floats, so an even-length sequence's median is the exact average of its
+// two middle values; files from before it (with no format_version) truncated
+// them to ints. Version 3 records the configured multiplicative factors and
+// noise amplitude, which earlier versions fixed at today's defaults.
+const FormatVersion = "3"
+
+// Replay errors
+var (
+	ErrNotReplayable  = errors.New("run is not replayable")
+	ErrReplayMismatch = errors.New("replayed sequence does not match saved sequence")
+)
+
+// RunMetadata describes how a saved run was produced
+type RunMetadata struct {
+	GeneratedAt      string        `json:"generated_at"`
+	Config           ChaoticConfig `json:"config"`
+	SequenceLength   int           `json:"sequence_length"` // steps generated; the duplicates of DeliveryFaults come besides
+	Seed             *int64        `json:"seed,omitempty"`
+	GeneratorVersion string        `json:"generator_version,omitempty"`
+	FormatVersion    string        `json:"format_version,omitempty"`
+	Extended         bool          `json:"extended,omitempty"`
+	// Anomalies lists the steps displaced by the injected anomalies, as
+	// AnomalySteps returns them, so consumers have the ground truth
+	// without scanning the sequence
+	Anomalies []int `json:"anomalies,omitempty"`
+}
+
+// RunDocument is the full output document written by the CLI
+type RunDocument struct {
+	Metadata   RunMetadata `json:"metadata"`
+	Statistics Statistics  `json:"statistics"`
+	Sequence   []LogEntry  `json:"sequence"`
+
+	// Ensemble optionally summarizes further paths generated alongside the
+	// run, and Correlated holds further series correlated with each other.
+	// They are carried by the JSON, CBOR and msgpack encodings but not by
+	// protobuf.
+	Ensemble   *Ensemble      `json:"ensemble,omitempty"`
+	Correlated *CorrelatedRun `json:"correlated,omitempty"`
+}
+
+// ReplayFromFile regenerates the run saved at path from its metadata and
+// checks it against the saved sequence. Gzip-compressed files are detected by
+// their magic bytes and decompressed transparently. Files without a seed, or written by
+// a different generator version, return an error wrapping ErrNotReplayable;
+// a regenerated value that differs returns one wrapping ErrReplayMismatch.
+func ReplayFromFile(path string) ([]LogEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	r, err := decompress(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc RunDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return Replay(doc)
+}
+
+// Replay regenerates a run from its metadata and checks it against doc.Sequence
+func Replay(doc RunDocument) ([]LogEntry, error) {
+	meta := doc.Metadata
+	if meta.Seed == nil {
+		return nil, fmt.Errorf("%w: no seed recorded", ErrNotReplayable)
+	}
+	if meta.GeneratorVersion != GeneratorVersion {
+		return nil, fmt.Errorf("%w: generated by version %q, this is version %q",
+			ErrNotReplayable, meta.GeneratorVersion, GeneratorVersion)
+	}
+
+	config := meta.Config
+	config.Seed = meta.Seed
+	config.Source = nil
+	switch meta.FormatVersion {
+	case "", "2":
+		// Written before the factors and noise amplitude were configurable
+		defaults := DefaultConfig()
+		config.MultiplicativeFactors = defaults.MultiplicativeFactors
+		config.NoiseAmplitude = defaults.NoiseAmplitude
+	}
+
+	generate := ChaoticTransactionSequence
+	if meta.Extended {
+		generate = ChaoticTransactionSequenceExtended
+	}
+	replayed, err := generate(meta.SequenceLength, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(replayed) != len(doc.Sequence) {
+		return replayed, fmt.Errorf("%w: replayed %d entries, saved %d",
+			ErrReplayMismatch, len(replayed), len(doc.Sequence))
+	}
+	for i := range replayed {
+		// Outlier flags are added after generation, so they do not count
+		saved := doc.Sequence[i]
+		saved.IsOutlier = false
+		if !reflect.DeepEqual(replayed[i], saved) {
+			return replayed, fmt.Errorf("%w: first difference at step %d", ErrReplayMismatch, i)
+		}
+	}
+	return replayed, nil
+}
@@ -0,0 +1,105 @@
+package chaotic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReplayRegeneratesSeededRun(t *testing.T) {
+	seed := int64(99)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequenceExtended(300, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := RunDocument{
+		Metadata: RunMetadata{
+			Config:           config,
+			SequenceLength:   len(sequence),
+			Seed:             &seed,
+			GeneratorVersion: GeneratorVersion,
+			FormatVersion:    FormatVersion,
+			Extended:         true,
+		},
+		Sequence: sequence,
+	}
+
+	// Through JSON, as the CLI saves it
+	var buf bytes.Buffer
+	if err := WriteJSON(doc, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var saved RunDocument
+	if err := json.Unmarshal(buf.Bytes(), &saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Replay(saved); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	saved.Sequence[150].Value++
+	if _, err := Replay(saved); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("Replay of an altered run returned %v, want ErrReplayMismatch", err)
+	}
+	saved.Metadata.Seed = nil
+	if _, err := Replay(saved); !errors.Is(err, ErrNotReplayable) {
+		t.Errorf("Replay without a seed returned %v, want ErrNotReplayable", err)
+	}
+}
+
+func TestReplayFromFile(t *testing.T) {
+	seed := int64(58)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(500, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		modify func(*RunDocument)
+		want   error
+	}{
+		{"intact", func(*RunDocument) {}, nil},
+		{"changed value", func(doc *RunDocument) { doc.Sequence[300].Value-- }, ErrReplayMismatch},
+		{"missing entry", func(doc *RunDocument) { doc.Sequence = doc.Sequence[:499] }, ErrReplayMismatch},
+		// Files from before seeds were recorded
+		{"no seed", func(doc *RunDocument) { doc.Metadata.Seed, doc.Metadata.GeneratorVersion = nil, "" }, ErrNotReplayable},
+		{"other version", func(doc *RunDocument) { doc.Metadata.GeneratorVersion = "0.0.1" }, ErrNotReplayable},
+	}
+	for _, tt := range tests {
+		doc := RunDocument{
+			Metadata: RunMetadata{
+				Config:           config,
+				SequenceLength:   len(sequence),
+				Seed:             &seed,
+				GeneratorVersion: GeneratorVersion,
+				FormatVersion:    FormatVersion,
+			},
+			Sequence: append([]LogEntry(nil), sequence...),
+		}
+		doc.Metadata.Config.Seed = nil
+		tt.modify(&doc)
+		path := filepath.Join(t.TempDir(), "run.json")
+		if err := SaveToJson(doc, path); err != nil {
+			t.Fatal(err)
+		}
+		replayed, err := ReplayFromFile(path)
+		if !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+			t.Errorf("%s: returned %v, want %v", tt.name, err, tt.want)
+		}
+		if tt.want == nil && !reflect.DeepEqual(replayed, sequence) {
+			t.Errorf("%s: replayed a different run", tt.name)
+		}
+	}
+
+	if _, err := ReplayFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil ||
+		errors.Is(err, ErrNotReplayable) || errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("a missing file returned %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ReturnKind selects how ComputeReturns measures the change between steps
+type ReturnKind string
+
+const (
+	// ReturnAbsolute is the plain difference v[i] - v[i-1]
+	ReturnAbsolute ReturnKind = "absolute"
+	// ReturnPercent is the change relative to the previous value,
+	// (v[i] - v[i-1]) / v[i-1], as a fraction (0.05 for a 5% rise)
+	ReturnPercent ReturnKind = "percent"
+	// ReturnLog is the log return ln(v[i] / v[i-1])
+	ReturnLog ReturnKind = "log"
+)
+
+// ErrNonPositiveValue is returned by ComputeReturns when a percent or log
+// return would divide by, or take the log of, a value that is not positive
+var ErrNonPositiveValue = errors.New("value is not positive")
+
+// ComputeReturns returns the len(values)-1 step-to-step returns of values;
+// element i holds the return from values[i] to values[i+1]. Percent and log
+// returns are only defined over positive values, so a zero or negative value
+// anywhere they would need one fails the whole call with an error wrapping
+// ErrNonPositiveValue and naming its index, rather than quietly dropping or
+// poisoning the affected returns. Absolute returns accept any values.
+func ComputeReturns(values []int, kind ReturnKind) ([]float64, error) {
+	if len(values) < 2 {
+		return nil, fmt.Errorf("need at least 2 values, got %d", len(values))
+	}
+
+	var ret func(prev, cur float64) float64
+	switch kind {
+	case ReturnAbsolute:
+		ret = func(prev, cur float64) float64 { return cur - prev }
+	case ReturnPercent:
+		ret = func(prev, cur float64) float64 { return (cur - prev) / prev }
+	case ReturnLog:
+		ret = func(prev, cur float64) float64 { return math.Log(cur / prev) }
+	default:
+		return nil, fmt.Errorf("unknown return kind %q", kind)
+	}
+
+	returns := make([]float64, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		if kind != ReturnAbsolute {
+			// Percent returns only need a positive base, log returns both ends
+			if values[i-1] <= 0 {
+				return nil, fmt.Errorf("%w: index %d is %d", ErrNonPositiveValue, i-1, values[i-1])
+			}
+			if kind == ReturnLog && values[i] <= 0 {
+				return nil, fmt.Errorf("%w: index %d is %d", ErrNonPositiveValue, i, values[i])
+			}
+		}
+		returns[i-1] = ret(float64(values[i-1]), float64(values[i]))
+	}
+	return returns, nil
+}
@@ -0,0 +1,97 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestComputeReturns(t *testing.T) {
+	values := []int{100, 110, 99, 198}
+	tests := []struct {
+		kind ReturnKind
+		want []float64
+	}{
+		{ReturnAbsolute, []float64{10, -11, 99}},
+		{ReturnPercent, []float64{0.1, -0.1, 1}},
+		{ReturnLog, []float64{math.Log(1.1), math.Log(0.9), math.Ln2}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			got, err := ComputeReturns(values, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d returns, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !closeTo(got[i], tt.want[i], 1e-12) {
+					t.Errorf("return %d is %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComputeReturnsOfPathTouchingZero(t *testing.T) {
+	// A fall to zero is a -100% return, but nothing can be measured from zero
+	// and no log return reaches it
+	touching := []int{100, 110, 0, 50}
+	absolute, err := ComputeReturns(touching, ReturnAbsolute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{10, -110, 50} {
+		if absolute[i] != want {
+			t.Errorf("absolute return %d is %v, want %v", i, absolute[i], want)
+		}
+	}
+	percent, err := ComputeReturns(touching[:3], ReturnPercent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(percent[1], -1, 1e-12) {
+		t.Errorf("percent return to zero is %v, want -1", percent[1])
+	}
+
+	for _, kind := range []ReturnKind{ReturnPercent, ReturnLog} {
+		_, err := ComputeReturns(touching, kind)
+		if !errors.Is(err, ErrNonPositiveValue) || !strings.Contains(err.Error(), "index 2") {
+			t.Errorf("%s returns returned %v, want ErrNonPositiveValue at index 2", kind, err)
+		}
+	}
+	if _, err := ComputeReturns(touching[:3], ReturnLog); !errors.Is(err, ErrNonPositiveValue) {
+		t.Errorf("log return to zero returned %v, want ErrNonPositiveValue", err)
+	}
+}
+
+func TestComputeReturnsErrors(t *testing.T) {
+	if _, err := ComputeReturns([]int{5}, ReturnAbsolute); err == nil {
+		t.Error("one value succeeded")
+	}
+	if _, err := ComputeReturns([]int{5, 6}, "simple"); err == nil {
+		t.Error("an unknown kind succeeded")
+	}
+}
+
+func TestReturnStatistics(t *testing.T) {
+	// Percent returns of +10% and -10%: mean 0, sample stdev √0.02
+	stats, err := ComputeStatistics(entriesOf(100, 110, 99))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(stats.ReturnMean, 0, 1e-12) || !closeTo(stats.ReturnVolatility, math.Sqrt(0.02), 1e-12) {
+		t.Errorf("return_mean %v, return_volatility %v; want 0 and %v", stats.ReturnMean, stats.ReturnVolatility, math.Sqrt(0.02))
+	}
+
+	touching, err := ComputeStatistics(entriesOf(100, 110, 0, 50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if touching.ReturnMean != 0 || touching.ReturnVolatility != 0 {
+		t.Errorf("a path touching zero has return_mean %v, return_volatility %v; want 0",
+			touching.ReturnMean, touching.ReturnVolatility)
+	}
+}
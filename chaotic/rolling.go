@@ -0,0 +1,85 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// RollingPoint summarizes the trailing window that ends at Step
+type RollingPoint struct {
+	Step  int     `json:"step"` // index of the last value in the window
+	Mean  float64 `json:"mean"`
+	Stdev float64 `json:"stdev"` // sample standard deviation, 0 for a window of 1
+	Min   int     `json:"min"`
+	Max   int     `json:"max"`
+}
+
+// RollingStats returns the mean, standard deviation, min and max of every
+// full trailing window of the given size. Partial windows are omitted, so the
+// result holds len(values)-window+1 points and the first one has Step
+// window-1. Each step updates the previous window in O(1) amortized time:
+// mean and variance with Welford's update for a replaced value, min and max
+// with monotonic queues of indexes.
+func RollingStats(values []int, window int) ([]RollingPoint, error) {
+	if window < 1 || window > len(values) {
+		return nil, fmt.Errorf("window must be between 1 and len(values), got %d for %d values", window, len(values))
+	}
+
+	points := make([]RollingPoint, 0, len(values)-window+1)
+	var mean, m2 float64 // m2 is the sum of squared deviations from mean
+	// Indexes in the window whose values are increasing (mins) or
+	// decreasing (maxes); the front is the window's min or max
+	var mins, maxes []int
+
+	for i, v := range values {
+		x := float64(v)
+		if i < window {
+			delta := x - mean
+			mean += delta / float64(i+1)
+			m2 += delta * (x - mean)
+		} else {
+			old := float64(values[i-window])
+			oldMean := mean
+			mean += (x - old) / float64(window)
+			m2 += (x - old) * (x - mean + old - oldMean)
+		}
+
+		for len(mins) > 0 && values[mins[len(mins)-1]] >= v {
+			mins = mins[:len(mins)-1]
+		}
+		mins = append(mins, i)
+		for len(maxes) > 0 && values[maxes[len(maxes)-1]] <= v {
+			maxes = maxes[:len(maxes)-1]
+		}
+		maxes = append(maxes, i)
+		if mins[0] <= i-window {
+			mins = mins[1:]
+		}
+		if maxes[0] <= i-window {
+			maxes = maxes[1:]
+		}
+
+		if i < window-1 {
+			continue
+		}
+		if values[mins[0]] == values[maxes[0]] {
+			// A flat window is known exactly; resetting drops the rounding
+			// the updates have accumulated, which would otherwise show as a
+			// small stdev here
+			mean, m2 = x, 0
+		}
+		var stdev float64
+		if window > 1 {
+			// Rounding can leave m2 slightly negative on a flat window
+			stdev = math.Sqrt(math.Max(m2, 0) / float64(window-1))
+		}
+		points = append(points, RollingPoint{
+			Step:  i,
+			Mean:  mean,
+			Stdev: stdev,
+			Min:   values[mins[0]],
+			Max:   values[maxes[0]],
+		})
+	}
+	return points, nil
+}
@@ -0,0 +1,82 @@
+package chaotic
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceRolling recomputes every trailing window from scratch
+func bruteForceRolling(values []int, window int) []RollingPoint {
+	var points []RollingPoint
+	for end := window - 1; end < len(values); end++ {
+		w := values[end-window+1 : end+1]
+		point := RollingPoint{Step: end, Min: w[0], Max: w[0]}
+		var sum float64
+		for _, v := range w {
+			sum += float64(v)
+			point.Min = min(point.Min, v)
+			point.Max = max(point.Max, v)
+		}
+		point.Mean = sum / float64(window)
+		if window > 1 {
+			var ss float64
+			for _, v := range w {
+				ss += (float64(v) - point.Mean) * (float64(v) - point.Mean)
+			}
+			point.Stdev = math.Sqrt(ss / float64(window-1))
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+func TestRollingStatsMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	values := make([]int, 3000)
+	for i := range values {
+		// Long flat stretches exercise the monotonic queues on ties
+		if i > 0 && rng.Intn(4) == 0 {
+			values[i] = values[i-1]
+		} else {
+			values[i] = rng.Intn(2_000_001) - 1_000_000
+		}
+	}
+	for _, window := range []int{1, 2, 7, 50, 1000, len(values)} {
+		got, err := RollingStats(values, window)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bruteForceRolling(values, window)
+		if len(got) != len(want) {
+			t.Fatalf("window %d: %d points, want %d", window, len(got), len(want))
+		}
+		for i := range got {
+			g, w := got[i], want[i]
+			if g.Step != w.Step || g.Min != w.Min || g.Max != w.Max ||
+				!closeTo(g.Mean, w.Mean, 1e-6) || !closeTo(g.Stdev, w.Stdev, 1e-6*math.Max(1, w.Stdev)) {
+				t.Fatalf("window %d, point %d: %+v, want %+v", window, i, g, w)
+			}
+		}
+	}
+}
+
+func TestRollingStatsOmitsPartialWindows(t *testing.T) {
+	points, err := RollingStats([]int{4, 4, 4, 4, 4}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || points[0].Step != 2 {
+		t.Fatalf("points %+v, want three starting at step 2", points)
+	}
+	for _, p := range points {
+		if p.Stdev != 0 || p.Mean != 4 {
+			t.Errorf("flat window %+v", p)
+		}
+	}
+	for _, window := range []int{0, 6} {
+		if _, err := RollingStats([]int{1, 2, 3, 4, 5}, window); err == nil {
+			t.Errorf("window %d of 5 values succeeded", window)
+		}
+	}
+}
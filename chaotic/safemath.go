@@ -0,0 +1,62 @@
+package chaotic
+
+import "math"
+
+// Saturating arithmetic for the generator. Values near the ends of the int
+// range would otherwise wrap around before clamp sees them, turning a large
+// positive step into one pinned at MinValue. Within range every helper
+// gives exactly the result of the plain operator.
+
+// addSat returns a + b, saturating at the int range instead of wrapping
+func addSat(a, b int) int {
+	sum := a + b
+	switch {
+	case b > 0 && sum < a:
+		return math.MaxInt
+	case b < 0 && sum > a:
+		return math.MinInt
+	}
+	return sum
+}
+
+// subSat returns a - b, saturating at the int range instead of wrapping
+func subSat(a, b int) int {
+	diff := a - b
+	switch {
+	case b < 0 && diff < a:
+		return math.MaxInt
+	case b > 0 && diff > a:
+		return math.MinInt
+	}
+	return diff
+}
+
+// mulSat returns a * b, saturating at the int range instead of wrapping
+func mulSat(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		if (a > 0) == (b > 0) {
+			return math.MaxInt
+		}
+		return math.MinInt
+	}
+	return product
+}
+
+// toIntSat truncates f towards zero like int(f), but saturates at the int
+// range where the conversion would be undefined, and maps NaN to 0
+func toIntSat(f float64) int {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt:
+		// float64(math.MaxInt) rounds up to 2^63, which int cannot hold
+		return math.MaxInt
+	case f <= math.MinInt:
+		return math.MinInt
+	}
+	return int(f)
+}
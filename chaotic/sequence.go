@@ -0,0 +1,759 @@
+package chaotic
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// LogEntry is a single step of a generated sequence. The enhanced fields are
+// only set by ChaoticTransactionSequenceExtended, the amount fields only with
+// a Currency, the timestamp only with a Timing, the entity fields only with
+// Entities, the ledger fields only with a Ledger, the regime only with
+// Regimes, the jump mark only with Jumps, the effective volatility only on
+// the chaotic steps with Garch, the clamp mark only when the value left the
+// range, the anomaly fields only on the anomalies injected by AnomalyConfig
+// and the fault marks only on the entries DeliveryFaults dropped or
+// repeated; all are omitted from JSON otherwise.
+type LogEntry struct {
+	Step             int    `json:"step"`
+	Value            int    `json:"value"`
+	Type             string `json:"type"`
+	EnhancedValue    *int   `json:"enhanced_value,omitempty"`
+	EnhancementDelta *int   `json:"enhancement_delta,omitempty"`
+	IsOutlier        bool   `json:"is_outlier,omitempty"`   // set by FlagOutliers
+	AmountMinor      *int64 `json:"amount_minor,omitempty"` // Value as an amount in minor units
+	Amount           string `json:"amount,omitempty"`       // AmountMinor formatted in major units, e.g. "12.34"
+	Timestamp        string `json:"timestamp,omitempty"`    // RFC 3339, never earlier than the previous entry's
+	TransactionID    string `json:"transaction_id,omitempty"`
+	AccountID        string `json:"account_id,omitempty"`
+	Merchant         string `json:"merchant,omitempty"`
+	Direction        string `json:"direction,omitempty"` // DirectionCredit or DirectionDebit
+	Posted           *int   `json:"posted,omitempty"`    // signed change to the balance, after the floor
+	Balance          *int   `json:"balance,omitempty"`   // balance after this entry
+	Rejected         bool   `json:"rejected,omitempty"`  // a debit the floor turned away
+	Regime           string `json:"regime,omitempty"`    // name of the volatility regime the entry was generated in
+	Jump             bool   `json:"jump,omitempty"`      // a jump shocked the value
+	// EffectiveVolatility is the volatility Garch gave the step
+	EffectiveVolatility float64 `json:"effective_volatility,omitempty"`
+	// Clamped is ClampedLow or ClampedHigh when the value left the range by
+	// that bound and the BoundaryMode brought it back
+	Clamped string `json:"clamped,omitempty"`
+	// Anomaly marks a value displaced by an injected anomaly, of the
+	// AnomalyKind AnomalySpike or AnomalyLevelShift
+	Anomaly     bool   `json:"anomaly,omitempty"`
+	AnomalyKind string `json:"anomaly_kind,omitempty"`
+	// Missing marks the placeholder of a dropped entry, which has no value:
+	// Value is 0 and JSON writes it as null
+	Missing     bool `json:"missing,omitempty"`
+	DuplicateOf *int `json:"duplicate_of,omitempty"` // the step a repeated entry is a copy of
+}
+
+// The bounds an entry's value can leave the range by, as recorded in
+// LogEntry.Clamped
+const (
+	ClampedLow  = "low"
+	ClampedHigh = "high"
+)
+
+// StepHook is called with each entry as it is generated, after its value is
+// computed and brought into the range by the BoundaryMode but before the
+// process moves on from it, so it can count clamps, report metrics or veto
+// values. An error stops generation and is returned as is. The hook may
+// change entry.Value, which is brought into the range again the same way
+// and then becomes the value the next steps build on and that the currency,
+// timestamp, entity and ledger layers see unless an injected anomaly
+// displaces it; those fields are not yet set when the hook runs. The map
+// and Lorenz models iterate their own orbit, which a changed value does not
+// steer.
+type StepHook func(entry *LogEntry, state StepState) error
+
+// StepState is the state of the process a StepHook sees with an entry
+type StepState struct {
+	Prev1       int     // the last committed value, 0 before step 1
+	Prev2       int     // the value before it
+	RunningMean float64 // the mean of the committed values
+	Raw         int     // the value of the entry before it was brought into the range
+	Clamped     bool    // the BoundaryMode changed the value
+}
+
+// ctxCheckInterval is how many iterations long-running loops go between context checks
+const ctxCheckInterval = 10000
+
+// ChaoticTransactionSequence generates a chaotic transaction sequence of n steps
+func ChaoticTransactionSequence(n int, config ChaoticConfig) ([]LogEntry, error) {
+	return ChaoticTransactionSequenceCtx(context.Background(), n, config)
+}
+
+// ChaoticTransactionSequenceCtx generates a chaotic transaction sequence of n steps,
+// returning the entries generated so far along with ctx.Err() if ctx is cancelled
+func ChaoticTransactionSequenceCtx(ctx context.Context, n int, config ChaoticConfig) ([]LogEntry, error) {
+	src := config.randSource()
+	return generateSequence(ctx, n, config, src, uniformChaos(src))
+}
+
+// GenerateWithCallback generates a chaotic transaction sequence of n steps,
+// handing each entry to fn as it is produced instead of collecting them, so
+// memory does not grow with n. Seeded runs produce the same entries as
+// ChaoticTransactionSequence. An error from fn stops generation and is
+// returned as is.
+func GenerateWithCallback(n int, config ChaoticConfig, fn func(LogEntry) error) error {
+	return GenerateWithCallbackCtx(context.Background(), n, config, fn)
+}
+
+// GenerateWithCallbackCtx is GenerateWithCallback with cancellation,
+// returning ctx.Err() if ctx is cancelled part way through
+func GenerateWithCallbackCtx(ctx context.Context, n int, config ChaoticConfig, fn func(LogEntry) error) error {
+	it := NewSequenceIterator(n, config)
+	if err := it.Err(); err != nil {
+		return err
+	}
+	for entry, ok := it.Next(); ok; entry, ok = it.Next() {
+		if entry.Step%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// GenerateMirrored generates a pair of streams for paired-ledger simulations,
+// where the second stream's chaos factor is anti-correlated with the first
+// by the given amount (-1 is a perfect mirror, 0 is independent)
+func GenerateMirrored(n int, config ChaoticConfig, correlation float64) ([2][]LogEntry, error) {
+	var pair [2][]LogEntry
+	if correlation < -1 || correlation > 0 {
+		return pair, errors.New("correlation must be between -1 and 0")
+	}
+
+	src := config.randSource()
+
+	// Chaos draws start at step 2, so step i is stored at index i-2
+	var primaryChaos []float64
+	primary, err := generateSequence(context.Background(), n, config, src, func(int) float64 {
+		chaos := src.Float64()*2 - 1
+		primaryChaos = append(primaryChaos, chaos)
+		return chaos
+	})
+	if err != nil {
+		return pair, err
+	}
+
+	// Blend with fresh noise so that corr(primary, mirror) == correlation
+	independence := math.Sqrt(1 - correlation*correlation)
+	mirror, err := generateSequence(context.Background(), n, config, src, func(step int) float64 {
+		return correlation*primaryChaos[step-2] + independence*(src.Float64()*2-1)
+	})
+	if err != nil {
+		return pair, err
+	}
+
+	pair[0], pair[1] = primary, mirror
+	return pair, nil
+}
+
+// ExtendSequence continues the chaotic process of an existing log for k more
+// steps. The process resumes from the last two values and the mean of all
+// values, and the returned log is a copy of the input followed by the new
+// entries, with steps renumbered contiguously from 0. Existing values must
+// lie within the config range. The log holds injected anomalies at their
+// displaced values, which the process then resumes from. The placeholders of
+// dropped entries and the duplicates left by DeliveryFaults count as the
+// steps they stand for, but the process resumes from the values present.
+func ExtendSequence(log []LogEntry, k int, config ChaoticConfig) ([]LogEntry, error) {
+	if len(log) < 2 {
+		return nil, errors.New("sequence to extend must have at least 2 entries")
+	}
+	if k <= 0 {
+		return nil, errors.New("the number of steps must be a positive integer")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var sum float64
+	steps, tail := 0, LogEntry{}
+	present := make([]LogEntry, 0, len(log))
+	for i, entry := range log {
+		if entry.DuplicateOf != nil {
+			continue
+		}
+		steps, tail = steps+1, entry
+		if entry.Missing {
+			continue
+		}
+		if entry.Value < config.MinValue || entry.Value > config.MaxValue {
+			return nil, fmt.Errorf("value %d at step %d is outside the range [%d, %d]",
+				entry.Value, i, config.MinValue, config.MaxValue)
+		}
+		sum += float64(entry.Value)
+		present = append(present, entry)
+	}
+	if len(present) < 2 {
+		return nil, errors.New("sequence to extend must have at least 2 entries with a value")
+	}
+
+	src := config.randSource()
+	state := newSequenceState(config, src, uniformChaos(src))
+	state.step = steps
+	state.prev1 = present[len(present)-1].Value
+	state.prev2 = present[len(present)-2].Value
+	state.runningMean = sum / float64(len(present))
+	if config.Timing != nil {
+		// The new timestamps carry on from the last one
+		last := present[len(present)-1]
+		now, err := time.Parse(time.RFC3339Nano, last.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: cannot continue the timestamps: %w", last.Step, err)
+		}
+		state.clock.now = now
+	}
+	if config.Regimes != nil {
+		// The regime path carries on from the last regime
+		last := present[len(present)-1]
+		regime, ok := config.Regimes.index(last.Regime)
+		if !ok {
+			return nil, fmt.Errorf("step %d: cannot continue the regimes: unknown regime %q", last.Step, last.Regime)
+		}
+		state.switcher.current = regime
+	}
+	if isMapModel(config.Model) {
+		// The exact orbit is not in the log, so the map carries on from the
+		// x the last values map back to
+		state.x = mapState(present[len(present)-1].Value, config)
+		if config.Model == ModelHenon {
+			_, b := config.henonParameters()
+			state.y = b * mapState(present[len(present)-2].Value, config)
+		}
+	}
+	if config.Model == ModelLorenz {
+		// The integration is replayed from the start, which the metadata of
+		// a run records
+		if err := state.lorenz.replay(steps); err != nil {
+			return nil, err
+		}
+		if value := state.lorenz.value(config); value != tail.Value && !tail.Anomaly && !tail.Missing {
+			return nil, fmt.Errorf("cannot continue the Lorenz orbit: it reaches %d at step %d, not %d as logged", value, steps-1, tail.Value)
+		}
+	}
+	if config.Garch != nil {
+		// The variance carries on from the last chaotic step
+		volatility := present[len(present)-1].EffectiveVolatility
+		state.garch.resume(volatility * volatility)
+	}
+	if config.Ledger != nil {
+		// The balance carries on from the last one
+		last := present[len(present)-1]
+		if last.Balance == nil {
+			return nil, fmt.Errorf("step %d: cannot continue the balance: the entry has none", last.Step)
+		}
+		if err := state.book.resume(*last.Balance); err != nil {
+			return nil, err
+		}
+	}
+
+	it := &SequenceIterator{state: state, n: steps + k}
+	extension, err := collectSequence(context.Background(), it, k, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	joined := make([]LogEntry, 0, len(log)+len(extension))
+	joined = append(joined, log...)
+	joined = append(joined, extension...)
+	step := -1
+	for i := range joined {
+		if joined[i].DuplicateOf == nil {
+			step++
+		} else {
+			original := step
+			joined[i].DuplicateOf = &original
+		}
+		joined[i].Step = step
+	}
+	return joined, nil
+}
+
+// StepResult is a single generation step, as yielded by the stream
+type StepResult = LogEntry
+
+// ChaoticTransactionSequenceStream generates n steps one at a time on the returned
+// channel. The channel is closed once all steps are sent or ctx is cancelled.
+func ChaoticTransactionSequenceStream(ctx context.Context, n int, config ChaoticConfig) (<-chan StepResult, error) {
+	it := NewSequenceIterator(n, config)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make(chan StepResult)
+	go func() {
+		defer close(out)
+		streamEntries(ctx, it, out)
+	}()
+	return out, nil
+}
+
+// GenerateStream generates entries indefinitely until ctx is cancelled; use
+// ChaoticTransactionSequenceStream to stop after a fixed number of steps.
+// The entry channel is unbuffered, so generation proceeds at the consumer's
+// pace. Both channels are closed when the goroutine exits, and the error
+// channel carries the configuration error or ctx.Err() that ended the stream.
+func GenerateStream(ctx context.Context, config ChaoticConfig) (<-chan LogEntry, <-chan error) {
+	out := make(chan LogEntry)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		it := NewSequenceIterator(math.MaxInt, config)
+		if err := it.Err(); err != nil {
+			errc <- err
+			return
+		}
+		if err := streamEntries(ctx, it, out); err != nil {
+			errc <- err
+		}
+	}()
+	return out, errc
+}
+
+// streamEntries sends entries from it to out until it is exhausted or ctx is done
+func streamEntries(ctx context.Context, it *SequenceIterator, out chan<- LogEntry) error {
+	for entry, ok := it.Next(); ok; entry, ok = it.Next() {
+		select {
+		case out <- entry:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return it.Err()
+}
+
+// generateSequence runs the chaotic process, drawing the per-step chaos factor from chaos
+func generateSequence(ctx context.Context, n int, config ChaoticConfig, src RandSource, chaos func(step int) float64) ([]LogEntry, error) {
+	it := newSequenceIterator(n, config, src, chaos)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return collectSequence(ctx, it, n, nil)
+}
+
+// collectSequence drains up to n steps from it into a slice, reporting to
+// progress, which may be nil, as it goes. Duplicates delivered by
+// DeliveryFaults are collected but not counted as steps.
+func collectSequence(ctx context.Context, it *SequenceIterator, n int, progress *progressReporter) ([]LogEntry, error) {
+	log := make([]LogEntry, 0, n)
+	steps := 0
+	for entry, ok := it.Next(); ok; entry, ok = it.Next() {
+		if len(log)%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return log, err
+			}
+		}
+		log = append(log, entry)
+		if entry.DuplicateOf != nil {
+			continue
+		}
+		steps++
+		if progress != nil && steps >= progress.next {
+			progress.reportAt(steps)
+		}
+	}
+	return log, it.Err()
+}
+
+// progressReporter calls a progress callback every stride entries of a run
+// of total and at its end
+type progressReporter struct {
+	report        func(done, total int)
+	total, stride int
+	next          int // the count of entries at which to report next
+}
+
+// newProgressReporter reports to report every stride entries of a run of
+// total, or every 1% of it if stride is 0
+func newProgressReporter(report func(done, total int), total, stride int) *progressReporter {
+	if stride == 0 {
+		stride = max(total/100, 1)
+	}
+	return &progressReporter{report: report, total: total, stride: stride, next: min(stride, total)}
+}
+
+// reportAt reports done entries and schedules the next report
+func (p *progressReporter) reportAt(done int) {
+	p.report(done, p.total)
+	p.next = min(addSat(done, p.stride), p.total)
+	if done >= p.total {
+		p.next = math.MaxInt
+	}
+}
+
+// validateSequence checks the sequence length and configuration before
+// generating. Any positive length is valid: a single step is just the
+// initial entry, and the chaotic steps start from the third.
+func validateSequence(n int, config ChaoticConfig) error {
+	if n <= 0 {
+		return errors.New("the number of steps must be a positive integer")
+	}
+	return config.Validate()
+}
+
+// uniformChaos draws chaos factors uniformly from -1 to 1
+func uniformChaos(src RandSource) func(step int) float64 {
+	return func(int) float64 {
+		return src.Float64()*2 - 1
+	}
+}
+
+// sequenceState carries the chaotic process from one step to the next
+type sequenceState struct {
+	config       ChaoticConfig
+	src          RandSource
+	chaos        func(step int) float64
+	steps        []StepGenerator // the built-in step generators, then those of the config
+	thresholds   []float64       // cumulative weights of steps
+	step         int
+	prev1, prev2 int
+	runningMean  float64
+	clock        *arrivalClock    // stamps the entries; nil when untimed
+	tagger       *entityTagger    // sets the entity fields; nil without Entities
+	book         *ledgerBook      // posts the entries; nil without a Ledger
+	switcher     *regimeSwitcher  // switches the dynamics; nil without Regimes
+	jumper       *jumpDrawer      // shocks the values; nil without Jumps
+	garch        *garchFilter     // clusters the volatility; nil without Garch
+	injector     *anomalyInjector // displaces the values; nil without Anomalies
+	faults       *faultInjector   // drops and repeats the entries; nil without Faults
+	x, y         float64          // orbit of a map model
+	x0           *float64         // first x of a map model, nil until step 0 is generated
+	lorenz       *lorenzOrbit     // integrates ModelLorenz; nil for other models
+}
+
+// newSequenceState prepares a chaotic process positioned before step 0
+func newSequenceState(config ChaoticConfig, src RandSource, chaos func(step int) float64) *sequenceState {
+	s := &sequenceState{
+		config: config,
+		src:    src,
+		chaos:  chaos,
+	}
+	s.steps, s.thresholds = stepPicker(config)
+	if config.Timing != nil {
+		s.clock = newArrivalClock(config)
+	}
+	if config.Entities != nil {
+		s.tagger = newEntityTagger(config)
+	}
+	if config.Ledger != nil {
+		s.book = newLedgerBook(config)
+	}
+	if config.Regimes != nil {
+		s.switcher = newRegimeSwitcher(config)
+	}
+	if config.Jumps != nil {
+		s.jumper = newJumpDrawer(config)
+	}
+	if config.Garch != nil {
+		s.garch = newGarchFilter(config)
+	}
+	if config.Model == ModelLorenz {
+		s.lorenz = newLorenzOrbit(config)
+	}
+	if config.Anomalies != nil {
+		s.injector = newAnomalyInjector(config)
+	}
+	if config.Faults != nil {
+		s.faults = newFaultInjector(config)
+	}
+	return s
+}
+
+// snapshot captures the process position and, for seeded sources, the RNG state
+func (s *sequenceState) snapshot() GeneratorState {
+	state := GeneratorState{
+		Step:        s.step,
+		Prev1:       s.prev1,
+		Prev2:       s.prev2,
+		RunningMean: s.runningMean,
+	}
+	if s.clock != nil {
+		state.Clock = s.clock.now
+	}
+	if s.book != nil {
+		state.Balance = s.book.balance
+	}
+	if s.switcher != nil {
+		state.Regime = s.switcher.regime().Name
+	}
+	if s.garch != nil && s.step > 2 {
+		state.Variance = s.garch.variance
+	}
+	if isMapModel(s.config.Model) {
+		state.MapX, state.MapY = s.x, s.y
+	}
+	if s.lorenz != nil {
+		point := s.lorenz.point
+		state.MapX, state.MapY, state.MapZ = point[0], point[1], point[2]
+		state.MapLow, state.MapHigh = s.lorenz.low, s.lorenz.high
+	}
+	if seeded, ok := s.src.(*prngSource); ok && seeded.pcg != nil {
+		// PCG.MarshalBinary cannot fail
+		state.RNG, _ = seeded.pcg.MarshalBinary()
+	}
+	return state
+}
+
+// next advances the process by one step
+func (s *sequenceState) next() (LogEntry, error) {
+	if s.switcher != nil {
+		s.switcher.enter(s.step)
+	}
+	entry, err := s.advance()
+	if err != nil {
+		return entry, err
+	}
+	// Bring into the valid range; the saturating arithmetic of the steps
+	// keeps a huge one from wrapping around to the wrong end first
+	raw := entry.Value
+	entry.Clamped = clampSide(raw, s.config.MinValue, s.config.MaxValue)
+	entry.Value = bound(raw, s.config.MinValue, s.config.MaxValue, s.config.BoundaryMode)
+	if s.switcher != nil {
+		entry.Regime = s.switcher.regime().Name
+	}
+	if hook := s.config.OnStep; hook != nil {
+		state := StepState{
+			Prev1:       s.prev1,
+			Prev2:       s.prev2,
+			RunningMean: s.runningMean,
+			Raw:         raw,
+			Clamped:     entry.Value != raw,
+		}
+		if err := hook(&entry, state); err != nil {
+			return entry, err
+		}
+		if side := clampSide(entry.Value, s.config.MinValue, s.config.MaxValue); side != "" {
+			entry.Clamped = side
+			entry.Value = bound(entry.Value, s.config.MinValue, s.config.MaxValue, s.config.BoundaryMode)
+		}
+	}
+	s.commit(entry.Step, entry.Value)
+	if s.injector != nil {
+		if kind, up := s.injector.anomaly(entry.Step); kind != "" {
+			entry.Anomaly, entry.AnomalyKind = true, kind
+			displaced := s.injector.displace(entry.Value, up, s.config.Currency)
+			entry.Value = bound(displaced, s.config.MinValue, s.config.MaxValue, s.config.BoundaryMode)
+		}
+	}
+	if c := s.config.Currency; c != nil {
+		minor := int64(entry.Value)
+		entry.AmountMinor, entry.Amount = &minor, c.Format(minor)
+	}
+	if s.clock != nil {
+		entry.Timestamp = formatTimestamp(s.clock.stamp(entry.Step))
+	}
+	if s.tagger != nil {
+		s.tagger.tag(&entry)
+	}
+	if s.book != nil {
+		s.book.post(&entry)
+	}
+	return entry, nil
+}
+
+// commit makes value, that of entry i, the last value of the process
+func (s *sequenceState) commit(i, value int) {
+	s.prev1, s.prev2 = value, s.prev1
+	if i == 1 {
+		s.runningMean = (float64(s.prev1) + float64(s.prev2)) / 2.0
+		return
+	}
+	s.runningMean = (s.runningMean*float64(i) + float64(value)) / float64(i+1)
+}
+
+// advance computes the next entry of the process, before it is brought
+// into the range
+func (s *sequenceState) advance() (LogEntry, error) {
+	config := s.config
+	if s.switcher != nil {
+		regime := s.switcher.regime()
+		config.Volatility, config.TrendStrength, config.MeanReversion = regime.Volatility, regime.TrendStrength, regime.MeanReversion
+	}
+	i := s.step
+	s.step++
+	if isMapModel(config.Model) {
+		return s.iterateMap(i)
+	}
+	if config.Model == ModelLorenz {
+		return s.integrateLorenz(i)
+	}
+
+	switch i {
+	case 0:
+		// Initialize with random starting value
+		value := s.src.Intn(config.MaxValue-config.MinValue+1) + config.MinValue
+		return LogEntry{Step: 0, Value: value, Type: "initial"}, nil
+
+	case 1:
+		// Generate second value
+		return LogEntry{Step: 1, Value: addSat(s.prev1, s.noise()), Type: "random_walk"}, nil
+	}
+
+	prev1, prev2 := s.prev1, s.prev2
+	step := s.steps[pickStepType(s.src.Float64(), s.thresholds)]
+	chaosFactor := s.chaos(i)
+	nextValue := step.Generate(StepContext{
+		Step:        i,
+		Prev1:       prev1,
+		Prev2:       prev2,
+		RunningMean: s.runningMean,
+		Chaos:       chaosFactor,
+		RNG:         s.src,
+		Config:      config,
+	})
+
+	// Apply volatility
+	var effective float64
+	if s.garch != nil {
+		effective = s.garch.update(prev1, prev2)
+		config.Volatility = effective
+	}
+	volatilityEffect := s.round(chaosFactor * float64(nextValue) * config.Volatility)
+	nextValue = addSat(nextValue, volatilityEffect)
+
+	jumped := false
+	if s.jumper != nil {
+		if size, ok := s.jumper.jump(i); ok {
+			nextValue = addSat(nextValue, s.round(size*float64(nextValue)))
+			jumped = true
+		}
+	}
+
+	return LogEntry{Step: i, Value: nextValue, Type: step.Name(), Jump: jumped, EffectiveVolatility: effective}, nil
+}
+
+// round converts the fractional result of a step to a value, as
+// StepContext.Round does
+func (s *sequenceState) round(x float64) int {
+	return roundValue(x, s.config.Currency)
+}
+
+// roundValue converts x to a value, truncating it unless currency is set,
+// which rounds it with its rounding mode
+func roundValue(x float64, currency *Currency) int {
+	if currency != nil {
+		x = currency.Round(x)
+	}
+	return toIntSat(x)
+}
+
+// noise draws a uniform integer from -NoiseAmplitude to NoiseAmplitude
+func (s *sequenceState) noise() int {
+	return drawNoise(s.src, s.config.NoiseAmplitude)
+}
+
+// drawNoise draws a uniform integer from -amplitude to amplitude from src
+func drawNoise(src RandSource, amplitude int) int {
+	return src.Intn(2*amplitude+1) - amplitude
+}
+
+// clamp ensures value stays within the lo-hi range
+func clamp[T cmp.Ordered](value, lo, hi T) T {
+	if value < lo {
+		return lo
+	}
+	if value > hi {
+		return hi
+	}
+	return value
+}
+
+// pickStepType returns the index of the step type, such as one of the
+// constants indexing regimeTypes, that a uniform draw from [0, 1) selects
+// under the cumulative weights in thresholds. The same index labels the log
+// entry, so the type recorded is always the branch that produced the value.
+func pickStepType(randomChoice float64, thresholds []float64) int {
+	for k, threshold := range thresholds {
+		if randomChoice < threshold {
+			return k
+		}
+	}
+	return len(thresholds)
+}
+
+// EnhancedChaoticLogic applies sophisticated chaotic transformations using src
+// (nil means crypto/rand)
+func EnhancedChaoticLogic(value int, step int, src RandSource) int {
+	if src == nil {
+		src = cryptoSource{}
+	}
+	chaos := src.Float64()
+
+	switch {
+	case value%11 == 0:
+		// Major transformation for values divisible by 11
+		return addSat(mulSat(value, 3), src.Intn(41)-20)
+	case value%7 == 0:
+		// Moderate transformation
+		return addSat(mulSat(value, 2), src.Intn(21)-10)
+	case value%5 == 0:
+		// Minor transformation
+		return value/2 + src.Intn(11) - 5
+	case step%13 == 0:
+		// Periodic major disruption
+		return addSat(value, src.Intn(101)-50)
+	case chaos < 0.1:
+		// Random major event (10% chance)
+		return addSat(value, src.Intn(201)-100)
+	default:
+		// Normal chaotic adjustment
+		return addSat(value, src.Intn(21)-10)
+	}
+}
+
+// ChaoticTransactionSequenceExtended generates sequence with enhanced chaotic logic
+func ChaoticTransactionSequenceExtended(n int, config ChaoticConfig) ([]LogEntry, error) {
+	return ChaoticTransactionSequenceExtendedCtx(context.Background(), n, config)
+}
+
+// ChaoticTransactionSequenceExtendedCtx generates sequence with enhanced chaotic logic,
+// returning the entries generated so far along with ctx.Err() if ctx is cancelled
+func ChaoticTransactionSequenceExtendedCtx(ctx context.Context, n int, config ChaoticConfig) ([]LogEntry, error) {
+	src := config.randSource()
+	log, err := generateSequence(ctx, n, config, src, uniformChaos(src))
+	if err != nil {
+		return log, err
+	}
+	return log, enhanceSequence(ctx, log, config, src)
+}
+
+// enhanceSequence fills in the enhanced fields of each entry in place. The
+// placeholders of dropped entries have no value to enhance, and a duplicate
+// gets the enhanced fields of the entry it follows.
+func enhanceSequence(ctx context.Context, log []LogEntry, config ChaoticConfig, src RandSource) error {
+	for i := range log {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if log[i].Missing {
+			continue
+		}
+		if log[i].DuplicateOf != nil && i > 0 && log[i-1].EnhancedValue != nil {
+			enhanced, delta := *log[i-1].EnhancedValue, *log[i-1].EnhancementDelta
+			log[i].EnhancedValue, log[i].EnhancementDelta = &enhanced, &delta
+			continue
+		}
+		value := log[i].Value
+		enhancedValue := EnhancedChaoticLogic(value, log[i].Step, src)
+		enhanced := bound(enhancedValue, config.MinValue, mulSat(config.MaxValue, 2), config.BoundaryMode) // Allow larger range for enhanced
+		delta := subSat(enhancedValue, value)
+		log[i].EnhancedValue = &enhanced
+		log[i].EnhancementDelta = &delta
+	}
+	return nil
+}
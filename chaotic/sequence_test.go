@@ -0,0 +1,810 @@
+package chaotic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// valuesOf returns the values of sequence
+func valuesOf(sequence []LogEntry) []int {
+	out := make([]int, len(sequence))
+	for i, entry := range sequence {
+		out[i] = entry.Value
+	}
+	return out
+}
+
+// layeredConfig returns a seeded config with every layer that adds fields to
+// the entries, so round trips cover all of them
+func layeredConfig(seed int64) ChaoticConfig {
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.Currency = &Currency{Code: "USD", Exponent: 2}
+	config.Timing = &Timing{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Interval: time.Minute, Jitter: 0.2}
+	config.Entities = &Entities{Accounts: 20, Merchants: []Merchant{{Label: "grocer", Weight: 2}, {Label: "fuel", Weight: 1}}}
+	floor := 0
+	config.Ledger = &Ledger{OpeningBalance: 5000, DebitProbability: 0.5, Floor: &floor, FloorPolicy: FloorReject}
+	config.Regimes = &RegimeSwitching{
+		States: []VolatilityRegime{
+			{Name: "calm", Volatility: 0.2, TrendStrength: 0.3, MeanReversion: 0.2},
+			{Name: "storm", Volatility: 0.9, TrendStrength: 0.3, MeanReversion: 0.2},
+		},
+		Transitions: [][]float64{{0.95, 0.05}, {0.1, 0.9}},
+	}
+	config.Jumps = &Jumps{Probability: 0.02, Stdev: 0.5}
+	config.Garch = &Garch{Omega: 0.01, Alpha: 0.1, Beta: 0.8}
+	config.Anomalies = &AnomalyConfig{Rate: 0.01, Multiplier: 3}
+	config.Faults = &DeliveryFaults{DropRate: 0.02, DuplicateRate: 0.02}
+	return config
+}
+
+func TestSeededSourceReproducesSequence(t *testing.T) {
+	generate := func(extended bool) []LogEntry {
+		config := DefaultConfig()
+		config.Source = rand.New(rand.NewSource(42))
+		gen := ChaoticTransactionSequence
+		if extended {
+			gen = ChaoticTransactionSequenceExtended
+		}
+		sequence, err := gen(500, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sequence
+	}
+
+	for _, extended := range []bool{false, true} {
+		first, second := generate(extended), generate(extended)
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("extended=%v: two runs from the same seed differ", extended)
+		}
+	}
+
+	a, b := rand.New(rand.NewSource(7)), rand.New(rand.NewSource(7))
+	for step := range 100 {
+		if x, y := EnhancedChaoticLogic(500, step, a), EnhancedChaoticLogic(500, step, b); x != y {
+			t.Fatalf("EnhancedChaoticLogic at step %d: %d and %d from the same seed", step, x, y)
+		}
+	}
+}
+
+func TestDifferentSeedsDiffer(t *testing.T) {
+	generate := func(seed int64) []int {
+		config := DefaultConfig()
+		config.Source = rand.New(rand.NewSource(seed))
+		sequence, err := ChaoticTransactionSequence(200, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return valuesOf(sequence)
+	}
+	if reflect.DeepEqual(generate(1), generate(2)) {
+		t.Error("runs from seeds 1 and 2 are identical")
+	}
+}
+
+func TestSeedProducesByteIdenticalOutput(t *testing.T) {
+	seed := int64(20240607)
+	config := DefaultConfig()
+	config.Seed = &seed
+	tests := []struct {
+		name   string
+		modify func(*ChaoticConfig)
+	}{
+		{"default", func(*ChaoticConfig) {}},
+		{"anomalies", func(c *ChaoticConfig) {
+			c.Anomalies = &AnomalyConfig{Rate: 0.02, Multiplier: 3, LevelShifts: 0.3, Duration: 5}
+		}},
+	}
+	for _, tt := range tests {
+		config := config
+		tt.modify(&config)
+		encode := func(extended bool) ([]byte, []int) {
+			gen := ChaoticTransactionSequence
+			if extended {
+				gen = ChaoticTransactionSequenceExtended
+			}
+			sequence, err := gen(1000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(sequence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return data, AnomalySteps(sequence)
+		}
+		for _, extended := range []bool{false, true} {
+			first, placed := encode(extended)
+			second, again := encode(extended)
+			if !bytes.Equal(first, second) {
+				t.Errorf("%s, extended=%v: two runs from seed %d encode differently", tt.name, extended, seed)
+			}
+			// The anomalies are placed at the same steps, not just as many
+			if !reflect.DeepEqual(placed, again) || (config.Anomalies != nil) != (len(placed) > 0) {
+				t.Errorf("%s, extended=%v: anomalies placed at %v, then at %v", tt.name, extended, placed, again)
+			}
+		}
+	}
+
+	generate := func() []LogEntry {
+		g, err := NewGenerator(WithSeed(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sequence, err := g.Generate(1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sequence
+	}
+	plain, err := ChaoticTransactionSequence(1000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := generate(); !reflect.DeepEqual(first, generate()) || !reflect.DeepEqual(first, plain) {
+		t.Error("a Generator with WithSeed does not reproduce the seeded sequence")
+	}
+}
+
+// TestSeedGolden pins the start of a seeded run. Replay regenerates saved
+// runs from their seed, so a change here breaks every run saved before it.
+func TestSeedGolden(t *testing.T) {
+	seed := int64(42)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(12, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{336, 344, 710, 1000, 1000, 1000, 357, 239, 405, 459, 1000, 155}
+	if got := valuesOf(sequence); !reflect.DeepEqual(got, want) {
+		t.Errorf("seed 42 values\n got %v\nwant %v", got, want)
+	}
+	wantTypes := []string{"initial", "random_walk", "mean_reversion", "mean_reversion", "additive_noise", "additive_noise",
+		"additive_noise", "mean_reversion", "mean_reversion", "trend_following", "multiplicative", "multiplicative"}
+	for i, entry := range sequence {
+		if entry.Type != wantTypes[i] {
+			t.Errorf("step %d has type %s, want %s", i, entry.Type, wantTypes[i])
+		}
+	}
+}
+
+// TestDefaultFactorsAndNoiseGolden pins 5000 steps of seeded runs to hashes
+// recorded when the multiplicative factors and the ±10 noise were still
+// hard-coded, so the defaults that replaced them change nothing
+func TestDefaultFactorsAndNoiseGolden(t *testing.T) {
+	want := map[int64]uint64{1: 0x5d44f895926f626c, 7: 0xae4a9b8277c3e3e8, 42: 0xdbeb89f7e5e79220}
+	for seed, hash := range want {
+		config := DefaultConfig()
+		config.Seed = &seed
+		sequence, err := ChaoticTransactionSequence(5000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := fnv.New64a()
+		for _, entry := range sequence {
+			fmt.Fprintf(h, "%d %s\n", entry.Value, entry.Type)
+		}
+		if got := h.Sum64(); got != hash {
+			t.Errorf("seed %d: values and types hash to %#x, want %#x", seed, got, hash)
+		}
+
+		// The defaults spelled out give the same run
+		explicit := config
+		explicit.MultiplicativeFactors = []float64{0.3, 0.7, 1.3, 1.7, 2.0, -0.5}
+		explicit.NoiseAmplitude = 10
+		again, err := ChaoticTransactionSequence(5000, explicit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(again, sequence) {
+			t.Errorf("seed %d: the explicit defaults give a different run", seed)
+		}
+	}
+}
+
+// waitForGoroutines fails t unless the number of goroutines falls back to
+// at most baseline within a second
+func waitForGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running, %d before the stream started", runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGenerateStreamCancelDoesNotLeak(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	out, errc := GenerateStream(ctx, DefaultConfig())
+	for i := range 100 {
+		entry, ok := <-out
+		if !ok {
+			t.Fatalf("stream closed after %d entries", i)
+		}
+		if entry.Step != i {
+			t.Fatalf("entry %d has step %d", i, entry.Step)
+		}
+	}
+	cancel()
+
+	// Drain whatever was in flight; the channel must then close
+	timeout := time.After(time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-out:
+		case <-timeout:
+			t.Fatal("entry channel not closed after cancellation")
+		}
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("stream ended with %v, want context.Canceled", err)
+	}
+	if _, open := <-errc; open {
+		t.Error("error channel not closed")
+	}
+	waitForGoroutines(t, baseline)
+}
+
+func TestGenerateStreamExitsWhileBlocked(t *testing.T) {
+	// A consumer that stops reading leaves the producer blocked on a send,
+	// which cancellation must still release
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	out, errc := GenerateStream(ctx, DefaultConfig())
+	<-out
+	cancel()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("stream ended with %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("producer did not exit after cancellation")
+	}
+	waitForGoroutines(t, baseline)
+}
+
+func TestGenerateStreamConfigError(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	config := DefaultConfig()
+	config.Volatility = 2
+	out, errc := GenerateStream(context.Background(), config)
+	if err := <-errc; !errors.Is(err, ErrVolatilityOutOfBounds) {
+		t.Errorf("stream ended with %v, want ErrVolatilityOutOfBounds", err)
+	}
+	if _, open := <-out; open {
+		t.Error("entry channel open after a configuration error")
+	}
+	waitForGoroutines(t, baseline)
+}
+
+func TestExtendSequenceRenumbersAndKeepsLog(t *testing.T) {
+	seed := int64(3)
+	config := DefaultConfig()
+	config.Seed = &seed
+	base, err := ChaoticTransactionSequence(1000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined, err := ExtendSequence(base, 500, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(joined) != 1500 {
+		t.Fatalf("joined sequence has %d entries, want 1500", len(joined))
+	}
+	if !reflect.DeepEqual(joined[:1000], base) {
+		t.Error("the entries of the extended log changed")
+	}
+	for i, entry := range joined {
+		if entry.Step != i {
+			t.Fatalf("entry %d has step %d", i, entry.Step)
+		}
+		if entry.Value < config.MinValue || entry.Value > config.MaxValue {
+			t.Fatalf("step %d value %d is outside the range", i, entry.Value)
+		}
+	}
+	if typ := joined[1000].Type; typ == "initial" || typ == "random_walk" {
+		t.Errorf("the extension restarted the process with a %s step", typ)
+	}
+
+	if _, err := ExtendSequence(base[:1], 10, config); err == nil {
+		t.Error("extended a single entry")
+	}
+	outside := append([]LogEntry(nil), base[:10]...)
+	outside[5].Value = config.MaxValue + 1
+	if _, err := ExtendSequence(outside, 10, config); err == nil {
+		t.Error("extended a log with a value outside the range")
+	}
+}
+
+func TestExtendSequenceSeamIsContinuous(t *testing.T) {
+	// Over many runs the change across the seam should look like any other
+	// change. A process restarted from a fresh initial value would average
+	// hundreds across it, ten times an ordinary step.
+	const runs, length = 300, 200
+	var seam, ordinary float64
+	var ordinaryCount int
+	for seed := range int64(runs) {
+		config := DefaultConfig()
+		config.Seed = &seed
+		base, err := ChaoticTransactionSequence(length, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		joined, err := ExtendSequence(base, length/4, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := length - length/4; i < len(joined); i++ {
+			change := math.Abs(float64(joined[i].Value - joined[i-1].Value))
+			if i == length {
+				seam += change
+			} else {
+				ordinary += change
+				ordinaryCount++
+			}
+		}
+
+		stats, err := ComputeStatistics(joined)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Count != length+length/4 {
+			t.Fatalf("statistics count %d, want %d", stats.Count, length+length/4)
+		}
+	}
+	seam /= runs
+	ordinary /= float64(ordinaryCount)
+	if ratio := seam / ordinary; ratio < 0.5 || ratio > 1.5 {
+		t.Errorf("mean change across the seam %.1f, elsewhere %.1f", seam, ordinary)
+	}
+}
+
+func TestGenerateWithCallbackMatchesBatch(t *testing.T) {
+	for name, config := range map[string]ChaoticConfig{"default": DefaultConfig(), "layered": layeredConfig(16)} {
+		t.Run(name, func(t *testing.T) {
+			seed := int64(16)
+			config.Seed = &seed
+			want, err := ChaoticTransactionSequence(3000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []LogEntry
+			err = GenerateWithCallback(3000, config, func(entry LogEntry) error {
+				got = append(got, entry)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Error("the callback saw different entries than the batch run returned")
+			}
+		})
+	}
+}
+
+func TestGenerateWithCallbackErrorAborts(t *testing.T) {
+	stop := errors.New("disk full")
+	calls := 0
+	err := GenerateWithCallback(1000, DefaultConfig(), func(entry LogEntry) error {
+		calls++
+		if entry.Step == 99 {
+			return fmt.Errorf("writing step %d: %w", entry.Step, stop)
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("returned %v, want the callback's error", err)
+	}
+	if calls != 100 {
+		t.Errorf("callback ran %d times, want generation to stop after 100", calls)
+	}
+}
+
+func TestGenerateWithCallbackMemoryIsFlat(t *testing.T) {
+	if testing.Short() {
+		t.Skip("streams 1M entries")
+	}
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	// Sample the live heap as the entries stream past
+	count := 0
+	var peak uint64
+	err := GenerateWithCallback(1_000_000, DefaultConfig(), func(entry LogEntry) error {
+		count++
+		if count%100_000 == 0 {
+			var now runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&now)
+			peak = max(peak, now.HeapAlloc)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1_000_000 {
+		t.Fatalf("callback ran %d times", count)
+	}
+	// Holding a million entries would take over 300 MB; allow a few
+	if growth := int64(peak) - int64(before.HeapAlloc); growth > 4<<20 {
+		t.Errorf("live heap grew by %d bytes while streaming", growth)
+	}
+}
+
+// boundedRun generates a seeded extended run between lo and hi, failing t
+// if any value leaves the range, and returns it with its statistics
+func boundedRun(t *testing.T, seed int64, lo, hi int) ([]LogEntry, Statistics) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.MinValue, config.MaxValue = lo, hi
+	sequence, err := ChaoticTransactionSequenceExtended(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range sequence {
+		if entry.Value < lo || entry.Value > hi {
+			t.Fatalf("step %d: value %d outside [%d, %d]", entry.Step, entry.Value, lo, hi)
+		}
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sequence, stats
+}
+
+// pinned counts the entries at lo and at hi
+func pinned(sequence []LogEntry, lo, hi int) (atLo, atHi int) {
+	for _, entry := range sequence {
+		switch entry.Value {
+		case lo:
+			atLo++
+		case hi:
+			atHi++
+		}
+	}
+	return atLo, atHi
+}
+
+func TestNoWraparoundNearIntLimits(t *testing.T) {
+	// Apart from the ±10 noise the generator scales with its range, so a
+	// seed hits the bounds equally often at MaxInt32 and at MaxInt64/2.
+	// Products wrapping negative would instead pin the larger range at
+	// MinValue far more often.
+	for seed := int64(1); seed <= 3; seed++ {
+		small, smallStats := boundedRun(t, seed, 1, math.MaxInt32)
+		large, largeStats := boundedRun(t, seed, 1, math.MaxInt64/2)
+		smallLo, smallHi := pinned(small, 1, math.MaxInt32)
+		largeLo, largeHi := pinned(large, 1, math.MaxInt64/2)
+		if smallLo != largeLo || smallHi != largeHi {
+			t.Errorf("seed %d: %d at MinValue and %d at MaxValue for MaxInt32, %d and %d for MaxInt64/2",
+				seed, smallLo, smallHi, largeLo, largeHi)
+		}
+		if !closeTo(largeStats.TrendStrength, smallStats.TrendStrength, 0.01) {
+			t.Errorf("seed %d: trend strength %v for MaxInt64/2, %v for MaxInt32",
+				seed, largeStats.TrendStrength, smallStats.TrendStrength)
+		}
+		checkSaneStatistics(t, largeStats)
+	}
+}
+
+func TestStatisticsOfRangeNearMaxInt64(t *testing.T) {
+	// Sums, midpoints and drawdowns of these values all pass math.MaxInt64
+	const lo, hi = math.MaxInt64 / 2, math.MaxInt64 - 1
+	for seed := int64(1); seed <= 3; seed++ {
+		_, stats := boundedRun(t, seed, lo, hi)
+		checkSaneStatistics(t, stats)
+	}
+}
+
+// checkSaneStatistics fails t if stats contradict each other in a way only
+// overflow would explain
+func checkSaneStatistics(t *testing.T, stats Statistics) {
+	t.Helper()
+	width := float64(stats.Max) - float64(stats.Min)
+	if stats.Mean < float64(stats.Min) || stats.Mean > float64(stats.Max) {
+		t.Errorf("mean %g outside [%d, %d]", stats.Mean, stats.Min, stats.Max)
+	}
+	if stats.Median < float64(stats.Min) || stats.Median > float64(stats.Max) {
+		t.Errorf("median %g outside [%d, %d]", stats.Median, stats.Min, stats.Max)
+	}
+	if stats.MAD < 0 || stats.MAD > width {
+		t.Errorf("MAD %g of values spanning %g", stats.MAD, width)
+	}
+	if stats.Stdev < 0 || stats.Stdev > width || math.IsNaN(stats.Stdev) {
+		t.Errorf("stdev %g of values spanning %g", stats.Stdev, width)
+	}
+	for name, move := range map[string]int{"drawdown": stats.MaxDrawdown, "drawup": stats.MaxDrawup} {
+		if move < 0 || float64(move) > width {
+			t.Errorf("max %s %d of values spanning %g", name, move, width)
+		}
+	}
+	if stats.MaxDrawdownFraction < 0 || stats.MaxDrawdownFraction > 1 {
+		t.Errorf("drawdown fraction %g", stats.MaxDrawdownFraction)
+	}
+	if math.IsNaN(stats.HurstExponent) || math.IsInf(stats.HurstExponent, 0) {
+		t.Errorf("Hurst exponent %g", stats.HurstExponent)
+	}
+}
+
+func TestOneAndTwoStepSequences(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if _, err := ChaoticTransactionSequence(n, DefaultConfig()); err == nil {
+			t.Errorf("n = %d succeeded", n)
+		}
+	}
+
+	generators := map[string]func(int, ChaoticConfig) ([]LogEntry, error){
+		"plain":    ChaoticTransactionSequence,
+		"extended": ChaoticTransactionSequenceExtended,
+	}
+	for name, generate := range generators {
+		for seed := int64(1); seed <= 5; seed++ {
+			config := DefaultConfig()
+			config.Seed = &seed
+
+			one, err := generate(1, config)
+			if err != nil {
+				t.Fatalf("%s, n = 1: %v", name, err)
+			}
+			if len(one) != 1 || one[0].Step != 0 || one[0].Type != "initial" {
+				t.Fatalf("%s, n = 1: %+v, want just the initial entry", name, one)
+			}
+			v := one[0].Value
+			stats, err := ComputeStatistics(one)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fv := float64(v)
+			if stats.Count != 1 || stats.Mean != fv || stats.Median != fv || stats.Q1 != fv || stats.Q3 != fv ||
+				stats.Min != v || stats.Max != v || stats.Mode != v {
+				t.Errorf("%s, n = 1: statistics %+v, want every location at %d", name, stats, v)
+			}
+			if stats.Stdev != 0 || stats.Variance != 0 || stats.IQR != 0 || stats.TrendStrength != 0 || stats.Volatility != 0 {
+				t.Errorf("%s, n = 1: stdev %v, variance %v, iqr %v, trend %v, volatility %v; want 0",
+					name, stats.Stdev, stats.Variance, stats.IQR, stats.TrendStrength, stats.Volatility)
+			}
+			if cv := stats.CoefficientOfVariation; cv == nil || *cv != 0 {
+				t.Errorf("%s, n = 1: coefficient of variation %v, want 0", name, cv)
+			}
+			if _, err := json.Marshal(stats); err != nil {
+				t.Errorf("%s, n = 1: %v", name, err)
+			}
+
+			two, err := generate(2, config)
+			if err != nil {
+				t.Fatalf("%s, n = 2: %v", name, err)
+			}
+			if len(two) != 2 || two[0].Value != v || two[1].Step != 1 || two[1].Type != "random_walk" {
+				t.Fatalf("%s, n = 2: %+v, want the same initial entry and a random walk", name, two)
+			}
+			a, b := float64(two[0].Value), float64(two[1].Value)
+			stats, err = ComputeStatistics(two)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Count != 2 || stats.Mean != (a+b)/2 || stats.Median != (a+b)/2 {
+				t.Errorf("%s, n = 2: mean %v, median %v of %v and %v", name, stats.Mean, stats.Median, a, b)
+			}
+			if !closeTo(stats.Stdev, math.Abs(a-b)/math.Sqrt2, 1e-9) || stats.Volatility != math.Abs(a-b) {
+				t.Errorf("%s, n = 2: stdev %v, volatility %v of %v and %v", name, stats.Stdev, stats.Volatility, a, b)
+			}
+			// One step is all up or all down, or flat
+			want := 1.0
+			if a == b {
+				want = 0
+			}
+			if stats.TrendStrength != want {
+				t.Errorf("%s, n = 2: trend strength %v, want %v", name, stats.TrendStrength, want)
+			}
+		}
+	}
+}
+
+func TestOnStepHookAbortsMidSequence(t *testing.T) {
+	errVeto := errors.New("veto")
+	var steps []int
+	seed := int64(37)
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.OnStep = func(entry *LogEntry, state StepState) error {
+		steps = append(steps, entry.Step)
+		if entry.Step == 500 {
+			return fmt.Errorf("step %d: %w", entry.Step, errVeto)
+		}
+		return nil
+	}
+	for name, generate := range map[string]func(int, ChaoticConfig) ([]LogEntry, error){
+		"plain":    ChaoticTransactionSequence,
+		"extended": ChaoticTransactionSequenceExtended,
+	} {
+		steps = steps[:0]
+		sequence, err := generate(1000, config)
+		if !errors.Is(err, errVeto) {
+			t.Fatalf("%s: returned %v, want the hook's error", name, err)
+		}
+		// The hook saw every step up to the veto once, the initial and
+		// random walk steps included, and nothing after it was kept
+		if len(steps) != 501 || steps[0] != 0 || steps[1] != 1 || steps[500] != 500 {
+			t.Errorf("%s: hook called for %d steps, from %v to %v", name, len(steps), steps[:2], steps[len(steps)-1])
+		}
+		if len(sequence) != 500 {
+			t.Errorf("%s: %d entries returned, want the 500 before the veto", name, len(sequence))
+		}
+	}
+}
+
+func TestOnStepHookMutationsReachLogAndStatistics(t *testing.T) {
+	seed := int64(38)
+	config := DefaultConfig()
+	config.Seed = &seed
+	plain, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the value at 500 over steps 1000 to 1099 and push step 1500
+	// above the range, where it is brought back like any other value
+	var prev1s []int
+	config.OnStep = func(entry *LogEntry, state StepState) error {
+		if entry.Step > 1000 && entry.Step <= 1100 {
+			prev1s = append(prev1s, state.Prev1)
+		}
+		switch {
+		case entry.Step >= 1000 && entry.Step < 1100:
+			entry.Value = 500
+		case entry.Step == 1500:
+			entry.Value = config.MaxValue + 250
+		}
+		return nil
+	}
+	sequence, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sequence[:1000], plain[:1000]) {
+		t.Error("entries before the first change differ")
+	}
+	for _, entry := range sequence[1000:1100] {
+		if entry.Value != 500 {
+			t.Fatalf("step %d has value %d, want the hook's 500", entry.Step, entry.Value)
+		}
+	}
+	// The next steps build on the changed values
+	for i, prev1 := range prev1s {
+		if prev1 != 500 {
+			t.Fatalf("step %d saw Prev1 %d, want 500", 1001+i, prev1)
+		}
+	}
+	if e := sequence[1500]; e.Value != config.MaxValue || e.Clamped != ClampedHigh {
+		t.Errorf("step 1500: value %d, clamped %q; want %d and %q", e.Value, e.Clamped, config.MaxValue, ClampedHigh)
+	}
+
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainStats, err := ComputeStatistics(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LongestFlatRun < 100 || stats.LongestFlatRunStart > 1000 || stats.LongestFlatRunStart+stats.LongestFlatRun < 1100 {
+		t.Errorf("longest flat run of %d from step %d, want one covering steps 1000 to 1099",
+			stats.LongestFlatRun, stats.LongestFlatRunStart)
+	}
+	// The run diverges after the first change, so the counts are checked
+	// against the log rather than the unhooked run
+	high := 0
+	for _, entry := range sequence {
+		if entry.Clamped == ClampedHigh {
+			high++
+		}
+	}
+	if stats.ClampedHigh != high {
+		t.Errorf("clamped high %d, %d entries marked high", stats.ClampedHigh, high)
+	}
+	if want := float64(stats.ClampedLow+high) / float64(len(sequence)); stats.ClampRate != want {
+		t.Errorf("clamp rate %v, want %v", stats.ClampRate, want)
+	}
+	if reflect.DeepEqual(stats, plainStats) {
+		t.Error("the statistics match those of the unhooked run")
+	}
+}
+
+func TestProgressReports(t *testing.T) {
+	var reports [][2]int
+	report := func(done, total int) { reports = append(reports, [2]int{done, total}) }
+
+	g, err := NewGenerator(WithSeed(39), WithProgress(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Generate(1050); err != nil {
+		t.Fatal(err)
+	}
+	// Every 1% rounds down to 10 entries, and the last report is the
+	// completed run
+	if len(reports) != 105 || reports[0] != [2]int{10, 1050} || reports[104] != [2]int{1050, 1050} {
+		t.Errorf("%d reports from %v to %v", len(reports), reports[0], reports[len(reports)-1])
+	}
+
+	reports = nil
+	g, err = NewGenerator(WithSeed(39), WithProgress(report), WithProgressStride(400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Generate(1000); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]int{{400, 1000}, {800, 1000}, {1000, 1000}}; !reflect.DeepEqual(reports, want) {
+		t.Errorf("reports %v, want %v", reports, want)
+	}
+
+	// Nothing is reported once a step fails
+	reports = nil
+	errVeto := errors.New("veto")
+	g, err = NewGenerator(WithSeed(39), WithProgress(report), WithProgressStride(100),
+		WithOnStep(func(entry *LogEntry, state StepState) error {
+			if entry.Step == 250 {
+				return errVeto
+			}
+			return nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Generate(1000); !errors.Is(err, errVeto) {
+		t.Fatalf("returned %v, want the hook's error", err)
+	}
+	if want := [][2]int{{100, 1000}, {200, 1000}}; !reflect.DeepEqual(reports, want) {
+		t.Errorf("reports %v after a failure at step 250, want %v", reports, want)
+	}
+}
+
+// BenchmarkProgress compares a 1M-step generation without a progress
+// callback, with one at the default stride and with one on every entry
+func BenchmarkProgress(b *testing.B) {
+	noop := func(done, total int) {}
+	for _, bench := range []struct {
+		name string
+		opts []Option
+	}{
+		{"none", nil},
+		{"default", []Option{WithProgress(noop)}},
+		{"every", []Option{WithProgress(noop), WithProgressStride(1)}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			g, err := NewGenerator(append([]Option{WithSeed(1)}, bench.opts...)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := g.Generate(1_000_000); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// FrequencyBin is one bin of a periodogram
+type FrequencyBin struct {
+	Frequency float64 `json:"frequency"` // cycles per step
+	Period    float64 `json:"period"`    // steps per cycle, 1/Frequency
+	Power     float64 `json:"power"`
+}
+
+// Periodogram returns the power spectrum of values with its mean removed,
+// from a radix-2 FFT. Sequences whose length is not a power of two are
+// zero-padded up to the next one. Padding samples the spectrum on a finer
+// frequency grid but does not sharpen it: frequencies less than about
+// 1/len(values) apart cannot be told apart, and no cycle longer than the
+// sequence can be seen at all. Bins therefore run from the first grid
+// frequency of at least 1/len(values) cycles per step, so no period exceeds
+// the sequence length, up to the Nyquist frequency of 0.5 cycles per step;
+// power is |X(f)|^2 / len(values). A constant sequence has no spectrum and
+// returns ErrConstantSequence.
+func Periodogram(values []int) ([]FrequencyBin, error) {
+	if len(values) < 4 {
+		return nil, fmt.Errorf("need at least 4 values, got %d", len(values))
+	}
+
+	centered, err := centeredSeries(values)
+	if err != nil {
+		return nil, err
+	}
+	size := 1 << bits.Len(uint(len(values)-1))
+	series := make([]complex128, size)
+	for i, x := range centered {
+		series[i] = complex(x, 0)
+	}
+	fft(series)
+
+	// The lowest k with k/size >= 1/len(values)
+	first := (size + len(values) - 1) / len(values)
+	bins := make([]FrequencyBin, 0, size/2-first+1)
+	for k := first; k <= size/2; k++ {
+		frequency := float64(k) / float64(size)
+		magnitude := cmplx.Abs(series[k])
+		bins = append(bins, FrequencyBin{
+			Frequency: frequency,
+			Period:    1 / frequency,
+			Power:     magnitude * magnitude / float64(len(values)),
+		})
+	}
+	return bins, nil
+}
+
+// centeredSeries returns values less their mean, or ErrConstantSequence
+func centeredSeries(values []int) ([]float64, error) {
+	mean := calculateMean(values)
+	centered := make([]float64, len(values))
+	constant := true
+	for i, v := range values {
+		centered[i] = float64(v) - mean
+		constant = constant && v == values[0]
+	}
+	if constant {
+		return nil, ErrConstantSequence
+	}
+	return centered, nil
+}
+
+// dominantRefineSteps is how many frequencies DominantPeriod tries on each
+// side of the strongest periodogram bin
+const dominantRefineSteps = 32
+
+// DominantPeriod returns the period, in steps, of the strongest cycle in
+// values. The strongest periodogram bin only places the peak on the padded
+// FFT grid, so the spectrum of the unpadded values is evaluated directly at
+// finer frequencies out to the neighbouring bins and the strongest of those
+// is reported. The estimate is still limited by the resolution Periodogram
+// describes. The period never exceeds len(values); one close to it reflects
+// a trend rather than a cycle.
+func DominantPeriod(values []int) (float64, error) {
+	bins, err := Periodogram(values)
+	if err != nil {
+		return 0, err
+	}
+	best := bins[0]
+	for _, bin := range bins[1:] {
+		if bin.Power > best.Power {
+			best = bin
+		}
+	}
+
+	centered, err := centeredSeries(values)
+	if err != nil {
+		return 0, err
+	}
+	size := 1 << bits.Len(uint(len(values)-1))
+	spacing := 1 / float64(size)
+	lo := math.Max(best.Frequency-spacing, 1/float64(len(values)))
+	hi := math.Min(best.Frequency+spacing, 0.5)
+	frequency, power := best.Frequency, dftPower(centered, best.Frequency)
+	for i := 0; i <= 2*dominantRefineSteps; i++ {
+		f := lo + (hi-lo)*float64(i)/(2*dominantRefineSteps)
+		if p := dftPower(centered, f); p > power {
+			frequency, power = f, p
+		}
+	}
+	return 1 / frequency, nil
+}
+
+// dftPower is the periodogram power of series at frequency f, in cycles per
+// step, from the discrete-time Fourier transform summed directly
+func dftPower(series []float64, f float64) float64 {
+	var re, im float64
+	for t, x := range series {
+		sin, cos := math.Sincos(2 * math.Pi * f * float64(t))
+		re += x * cos
+		im -= x * sin
+	}
+	return (re*re + im*im) / float64(len(series))
+}
+
+// fft replaces x, whose length must be a power of two, with its discrete
+// Fourier transform using the iterative Cooley-Tukey algorithm
+func fft(x []complex128) {
+	n := len(x)
+	shift := bits.UintSize - bits.Len(uint(n-1))
+	for i := range x {
+		if j := int(bits.Reverse(uint(i)) >> shift); i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
+
+// Thresholds DetectSeasonality requires a candidate period to clear
+const (
+	// seasonalityACFBound is how many white-noise standard errors (1/sqrt(n))
+	// the autocorrelation at the period must reach
+	seasonalityACFBound = 3.0
+	// seasonalitySpectralRatio is how many times the mean periodogram power
+	// the spectrum must reach at the period's frequency
+	seasonalitySpectralRatio = 5.0
+)
+
+// SeasonalityResult describes the strongest cycle DetectSeasonality found
+type SeasonalityResult struct {
+	Period        int     `json:"period"`         // 0 when no candidate was found
+	Strength      float64 `json:"strength"`       // autocorrelation at Period
+	SpectralRatio float64 `json:"spectral_ratio"` // periodogram power near 1/Period over the mean power
+	Significant   bool    `json:"significant"`
+}
+
+// DetectSeasonality looks for a repeating cycle of at most maxPeriod steps.
+// It works on the first differences, so a trend or random walk does not pass
+// for a long cycle, and takes as candidates the lags from 2 to maxPeriod
+// where the autocorrelation has a local peak. A candidate is significant
+// when its autocorrelation is at least 3 standard errors above zero and the
+// periodogram confirms it with a peak at least 5 times the mean power at a
+// period within one step of the lag; the spectral check is what rejects
+// multiples of the true period, whose autocorrelation is just as high.
+//
+// The result is the strongest significant candidate, with Period moved to
+// the nearest whole step of its spectral peak when the autocorrelation there
+// also clears the bound: a cycle that is not a whole number of steps long
+// can put the autocorrelation peak a lag off. Without
+// a significant candidate the strongest one is returned with Significant
+// false, and without any candidate Period is 0.
+func DetectSeasonality(values []int, maxPeriod int) (SeasonalityResult, error) {
+	if maxPeriod < 2 || maxPeriod > len(values)/2 {
+		return SeasonalityResult{}, fmt.Errorf("maxPeriod must be between 2 and len(values)/2, got %d for %d values", maxPeriod, len(values))
+	}
+	diffs := make([]int, len(values)-1)
+	for i := range diffs {
+		diffs[i] = subSat(values[i+1], values[i])
+	}
+
+	acf, err := ComputeACF(diffs, maxPeriod)
+	if err != nil {
+		return SeasonalityResult{}, err
+	}
+	// r(k) is the autocorrelation at lag k
+	r := func(k int) float64 { return acf[k-1] }
+	bins, err := Periodogram(diffs)
+	if err != nil {
+		return SeasonalityResult{}, err
+	}
+	var meanPower float64
+	for _, bin := range bins {
+		meanPower += bin.Power
+	}
+	meanPower /= float64(len(bins))
+	acfBound := seasonalityACFBound / math.Sqrt(float64(len(diffs)))
+
+	var best SeasonalityResult
+	var bestPeak FrequencyBin
+	for lag := 2; lag <= maxPeriod; lag++ {
+		if !(r(lag) > r(lag-1) && (lag == maxPeriod || r(lag) >= r(lag+1))) || r(lag) <= 0 {
+			continue
+		}
+		peak := spectralPeak(bins, float64(lag))
+		candidate := SeasonalityResult{
+			Period:        lag,
+			Strength:      r(lag),
+			SpectralRatio: peak.Power / meanPower,
+		}
+		candidate.Significant = candidate.Strength >= acfBound && candidate.SpectralRatio >= seasonalitySpectralRatio
+		if best.Period == 0 || candidate.Significant && !best.Significant ||
+			candidate.Significant == best.Significant && candidate.Strength > best.Strength {
+			best, bestPeak = candidate, peak
+		}
+	}
+
+	if best.Significant {
+		if period := int(math.Round(bestPeak.Period)); period >= 2 && period <= maxPeriod && r(period) >= acfBound {
+			best.Period, best.Strength = period, r(period)
+		}
+	}
+	return best, nil
+}
+
+// spectralPeak returns the strongest bin whose period is within one step of period
+func spectralPeak(bins []FrequencyBin, period float64) FrequencyBin {
+	var peak FrequencyBin
+	for _, bin := range bins {
+		if math.Abs(bin.Period-period) <= 1 && bin.Power > peak.Power {
+			peak = bin
+		}
+	}
+	return peak
+}
@@ -0,0 +1,145 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// sinusoid returns n values of a sine wave with the given period and
+// amplitude around 1000, rounded
+func sinusoid(n int, period, amplitude float64) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = int(math.Round(1000 + amplitude*math.Sin(2*math.Pi*float64(i)/period)))
+	}
+	return values
+}
+
+func TestDominantPeriodOfSinusoid(t *testing.T) {
+	tests := []struct {
+		n      int
+		period float64
+	}{
+		{1024, 16}, // on the FFT grid
+		{1000, 13}, // between grid points after padding to 1024
+		{1000, 7.5},
+		{50, 10}, // padded to 64, whose grid only has periods 64/k
+	}
+	for _, tt := range tests {
+		got, err := DominantPeriod(sinusoid(tt.n, tt.period, 300))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeTo(got, tt.period, tt.period*0.01) {
+			t.Errorf("%d steps with period %v: dominant period %.3f", tt.n, tt.period, got)
+		}
+	}
+}
+
+func TestPeriodogramOfSinusoid(t *testing.T) {
+	values := sinusoid(1000, 13, 300)
+	bins, err := Periodogram(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best := bins[0]
+	for _, bin := range bins {
+		if bin.Period > float64(len(values)) || bin.Frequency > 0.5 {
+			t.Errorf("bin %+v lies outside the resolvable range", bin)
+		}
+		if bin.Power > best.Power {
+			best = bin
+		}
+	}
+	// The nearest grid periods to 13 are 1024/79 ≈ 12.96 and 1024/78 ≈ 13.13
+	if math.Abs(best.Period-13) > 0.2 {
+		t.Errorf("strongest bin %+v, want a period near 13", best)
+	}
+}
+
+func TestDominantPeriodOfShortTrend(t *testing.T) {
+	// A 50-step ramp has no cycle; its trend must not be reported as a
+	// period longer than the run
+	ramp := make([]int, 50)
+	for i := range ramp {
+		ramp[i] = 10 * i
+	}
+	got, err := DominantPeriod(ramp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got > 50 {
+		t.Errorf("dominant period %.1f of a 50-step run", got)
+	}
+	bins, err := Periodogram(ramp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bins[0].Period > 50 {
+		t.Errorf("first bin has period %.1f, longer than the run", bins[0].Period)
+	}
+}
+
+func TestPeriodogramErrors(t *testing.T) {
+	if _, err := Periodogram([]int{5, 5, 5, 5, 5}); !errors.Is(err, ErrConstantSequence) {
+		t.Errorf("constant sequence returned %v, want ErrConstantSequence", err)
+	}
+	if _, err := Periodogram([]int{1, 2, 3}); err == nil {
+		t.Error("3 values succeeded")
+	}
+}
+
+func TestDetectSeasonalityOfSeasonalSeries(t *testing.T) {
+	for _, period := range []float64{7, 12, 13} {
+		// A cycle riding on a random walk, with noise on top
+		rng := rand.New(rand.NewSource(int64(period)))
+		values := sinusoid(2000, period, 100)
+		walk := 0
+		for i := range values {
+			walk += rng.Intn(41) - 20
+			values[i] += walk + rng.Intn(61)
+		}
+		result, err := DetectSeasonality(values, 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Significant || result.Period != int(period) {
+			t.Errorf("period %v: %+v", period, result)
+		}
+	}
+}
+
+func TestDetectSeasonalityOfUnseasonalSeries(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		config := DefaultConfig()
+		config.Seed = &seed
+		sequence, err := ChaoticTransactionSequence(2000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs := map[string][]int{
+			"generator":   valuesOf(sequence),
+			"white noise": uniformInts(2000, seed, 1, 1000),
+		}
+		for name, values := range inputs {
+			result, err := DetectSeasonality(values, 50)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Significant {
+				t.Errorf("seed %d, %s: spurious cycle %+v", seed, name, result)
+			}
+		}
+	}
+}
+
+func TestDetectSeasonalityErrors(t *testing.T) {
+	values := sinusoid(100, 10, 50)
+	for _, maxPeriod := range []int{1, 51} {
+		if _, err := DetectSeasonality(values, maxPeriod); err == nil {
+			t.Errorf("maxPeriod %d of 100 values succeeded", maxPeriod)
+		}
+	}
+}
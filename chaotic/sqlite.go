@@ -0,0 +1,152 @@
+package chaotic
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrRunExists is returned by SaveToSQLite when the database already holds a
+// run with the same ID
+var ErrRunExists = errors.New("run already exists")
+
+// sqliteBatchSize is how many steps each multi-row INSERT carries. Small
+// batches win: the driver's per-statement bind cost grows with the number of
+// parameters, so large batches end up slower than single-row inserts.
+const sqliteBatchSize = 20
+
+// sqliteSchema creates the runs and steps tables if they are missing
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id       TEXT PRIMARY KEY,
+	generated_at TEXT NOT NULL,
+	config_json  TEXT NOT NULL,
+	stats_json   TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS steps (
+	run_id            TEXT NOT NULL REFERENCES runs(run_id),
+	step              INTEGER NOT NULL,
+	value             INTEGER NOT NULL,
+	type              TEXT NOT NULL,
+	enhanced_value    INTEGER,
+	enhancement_delta INTEGER,
+	PRIMARY KEY (run_id, step)
+);`
+
+// SaveToSQLite stores a run in the SQLite database at path, creating the file
+// and the runs and steps tables as needed. Everything is written in a single
+// transaction. Saving a runID that is already present fails with an error
+// wrapping ErrRunExists and leaves the stored run untouched. Currency
+// amounts are not stored per step: a step's amount is its value in the minor
+// units of the currency recorded in config_json. Timestamps, entity and
+// ledger fields, regimes, jumps, effective volatilities, clamp marks,
+// anomaly marks and fault marks are not stored either; a missing entry is
+// stored with the value 0.
+func SaveToSQLite(path string, runID string, log []LogEntry, stats Statistics, config ChaoticConfig) error {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	statsJSON, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to encode statistics: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM runs WHERE run_id = ?)`, runID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up run: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: %q", ErrRunExists, runID)
+	}
+
+	generatedAt := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`INSERT INTO runs (run_id, generated_at, config_json, stats_json) VALUES (?, ?, ?, ?)`,
+		runID, generatedAt, string(configJSON), string(statsJSON)); err != nil {
+		return fmt.Errorf("failed to insert run: %w", err)
+	}
+	if err := insertSteps(tx, runID, log); err != nil {
+		return fmt.Errorf("failed to insert steps: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertSteps inserts the entries in batches of sqliteBatchSize rows, reusing
+// one prepared statement for every full batch
+func insertSteps(tx *sql.Tx, runID string, log []LogEntry) error {
+	full := len(log) / sqliteBatchSize * sqliteBatchSize
+	if full > 0 {
+		stmt, err := tx.Prepare(stepsInsertSQL(sqliteBatchSize))
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for start := 0; start < full; start += sqliteBatchSize {
+			if _, err := stmt.Exec(stepArgs(runID, log[start:start+sqliteBatchSize])...); err != nil {
+				return err
+			}
+		}
+	}
+	if rest := log[full:]; len(rest) > 0 {
+		if _, err := tx.Exec(stepsInsertSQL(len(rest)), stepArgs(runID, rest)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stepArgs flattens entries into the arguments of a steps INSERT
+func stepArgs(runID string, entries []LogEntry) []any {
+	args := make([]any, 0, len(entries)*6)
+	for _, entry := range entries {
+		args = append(args, runID, entry.Step, entry.Value, entry.Type,
+			nullableInt(entry.EnhancedValue), nullableInt(entry.EnhancementDelta))
+	}
+	return args
+}
+
+// stepsInsertSQL builds an INSERT into steps with rows value tuples
+func stepsInsertSQL(rows int) string {
+	var b strings.Builder
+	b.WriteString(`INSERT INTO steps (run_id, step, value, type, enhanced_value, enhancement_delta) VALUES `)
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(?, ?, ?, ?, ?, ?)")
+	}
+	return b.String()
+}
+
+// nullableInt converts an optional int to a value that stores as NULL when unset
+func nullableInt(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
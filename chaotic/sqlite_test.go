@@ -0,0 +1,111 @@
+package chaotic
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// loadSQLiteSteps reads back the steps saved for runID, as entries
+func loadSQLiteSteps(t *testing.T, db *sql.DB, runID string) []LogEntry {
+	t.Helper()
+	rows, err := db.Query(`SELECT step, value, type, enhanced_value, enhancement_delta FROM steps WHERE run_id = ? ORDER BY step`, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var log []LogEntry
+	for rows.Next() {
+		var entry LogEntry
+		var enhanced, delta sql.NullInt64
+		if err := rows.Scan(&entry.Step, &entry.Value, &entry.Type, &enhanced, &delta); err != nil {
+			t.Fatal(err)
+		}
+		if enhanced.Valid {
+			v := int(enhanced.Int64)
+			entry.EnhancedValue = &v
+		}
+		if delta.Valid {
+			v := int(delta.Int64)
+			entry.EnhancementDelta = &v
+		}
+		log = append(log, entry)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return log
+}
+
+func TestSaveToSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.db")
+	seed := int64(62)
+	config := DefaultConfig()
+	config.Seed = &seed
+	// Lengths around the batch size exercise the full batches and the rest
+	tests := []struct {
+		n        int
+		extended bool
+	}{
+		{3, false},
+		{sqliteBatchSize, false},
+		{2*sqliteBatchSize + 1, true},
+		{1000, true},
+	}
+	for _, tt := range tests {
+		runID := fmt.Sprintf("run-%d-%v", tt.n, tt.extended)
+		generate := ChaoticTransactionSequence
+		if tt.extended {
+			generate = ChaoticTransactionSequenceExtended
+		}
+		sequence, err := generate(tt.n, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := ComputeStatistics(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveToSQLite(path, runID, sequence, stats, config); err != nil {
+			t.Fatalf("%s: %v", runID, err)
+		}
+		// Saving the ID again fails and leaves the run as it was
+		if err := SaveToSQLite(path, runID, sequence[:1], stats, config); !errors.Is(err, ErrRunExists) {
+			t.Errorf("%s: saving again returned %v, want ErrRunExists", runID, err)
+		}
+
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only the columns of the steps table are kept
+		stored := make([]LogEntry, len(sequence))
+		for i, entry := range sequence {
+			stored[i] = LogEntry{Step: entry.Step, Value: entry.Value, Type: entry.Type,
+				EnhancedValue: entry.EnhancedValue, EnhancementDelta: entry.EnhancementDelta}
+		}
+		if got := loadSQLiteSteps(t, db, runID); !reflect.DeepEqual(got, stored) {
+			t.Errorf("%s: read back %d steps that differ from the %d saved", runID, len(got), len(sequence))
+		}
+		var configJSON, statsJSON string
+		if err := db.QueryRow(`SELECT config_json, stats_json FROM runs WHERE run_id = ?`, runID).Scan(&configJSON, &statsJSON); err != nil {
+			t.Fatal(err)
+		}
+		var savedConfig ChaoticConfig
+		var savedStats Statistics
+		if err := json.Unmarshal([]byte(configJSON), &savedConfig); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(statsJSON), &savedStats); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(savedConfig, config) || !reflect.DeepEqual(savedStats, stats) {
+			t.Errorf("%s: the saved config or statistics differ", runID)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,859 @@
+package chaotic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// statKeys lists every statistic ComputeStatistics produces
+var statKeys = []string{
+	"mean", "median", "stdev", "min", "max", "count",
+	"variance", "coefficient_of_variation", "q1", "q3", "iqr",
+	"trend_strength", "volatility",
+	"skewness", "kurtosis", "lag1_autocorrelation",
+	"mode", "geometric_mean", "harmonic_mean",
+	"mad", "mad_normalized", "hurst_exponent", "sample_entropy",
+	"permutation_entropy", "distribution_entropy",
+	"max_drawdown", "max_drawdown_fraction", "max_drawdown_peak_step", "max_drawdown_trough_step",
+	"max_drawup", "max_drawup_fraction", "max_drawup_trough_step", "max_drawup_peak_step",
+	"longest_increasing_run", "longest_increasing_run_start",
+	"longest_decreasing_run", "longest_decreasing_run_start",
+	"longest_flat_run", "longest_flat_run_start",
+	"turning_points", "mean_crossings",
+	"return_mean", "return_volatility",
+	"outlier_count", "changepoints", "by_type",
+	"duration", "events_per_second",
+	"final_balance", "min_balance", "debit_ratio",
+	"by_regime", "jump_count", "diffusion_volatility",
+	"clamped_low", "clamped_high", "clamp_rate",
+	"missing", "duplicates",
+}
+
+// statDependencies lists the statistics each derived statistic is computed from
+var statDependencies = map[string][]string{
+	"stdev":                    {"mean"},
+	"variance":                 {"stdev"},
+	"coefficient_of_variation": {"stdev", "mean"},
+	"iqr":                      {"q1", "q3"},
+	"skewness":                 {"mean"},
+	"kurtosis":                 {"mean"},
+	"lag1_autocorrelation":     {"mean"},
+	"mad_normalized":           {"mad"},
+	"sample_entropy":           {"stdev"},
+	"distribution_entropy":     {"min", "max"},
+	"mean_crossings":           {"mean"},
+	"outlier_count":            {"q1", "q3"},
+}
+
+// sortedStatKeys lists the statistics computed from the sorted values
+var sortedStatKeys = []string{"median", "q1", "q3", "mad"}
+
+// drawdownStatKeys lists the statistics computed by the drawdown pass
+var drawdownStatKeys = []string{
+	"max_drawdown", "max_drawdown_fraction", "max_drawdown_peak_step", "max_drawdown_trough_step",
+	"max_drawup", "max_drawup_fraction", "max_drawup_trough_step", "max_drawup_peak_step",
+}
+
+// runStatKeys lists the statistics computed by the run-length pass
+var runStatKeys = []string{
+	"longest_increasing_run", "longest_increasing_run_start",
+	"longest_decreasing_run", "longest_decreasing_run_start",
+	"longest_flat_run", "longest_flat_run_start",
+}
+
+// stepTypes are the entry types the generator writes; by_type groups any
+// other type, including a missing one, under otherStepType
+var stepTypes = map[string]bool{
+	"initial": true, "random_walk": true,
+	"trend_following": true, "mean_reversion": true, "multiplicative": true, "additive_noise": true,
+	string(ModelLogistic): true, string(ModelHenon): true, string(ModelTent): true, string(ModelLorenz): true,
+}
+
+const otherStepType = "other"
+
+// madScale makes the MAD a consistent estimator of the standard deviation for
+// normally distributed data
+const madScale = 1.4826
+
+// Sample entropy settings: the conventional template length and tolerance
+// (a fraction of the standard deviation). The comparison is quadratic, so
+// only the first sampleEntropyMaxLength values are used.
+const (
+	sampleEntropyM         = 2
+	sampleEntropyTolerance = 0.2
+	sampleEntropyMaxLength = 5000
+)
+
+// permutationEntropyOrder is the embedding order of the permutation_entropy statistic
+const permutationEntropyOrder = 3
+
+// Statistics summarizes a transaction sequence. JSON field names match the
+// keys accepted by ComputeStatisticsFor.
+type Statistics struct {
+	Mean                      float64  `json:"mean"`
+	Median                    float64  `json:"median"`
+	Stdev                     float64  `json:"stdev"`
+	Variance                  float64  `json:"variance"`
+	Min                       int      `json:"min"`
+	Max                       int      `json:"max"`
+	Count                     int      `json:"count"`
+	Q1                        float64  `json:"q1"`
+	Q3                        float64  `json:"q3"`
+	IQR                       float64  `json:"iqr"`
+	CoefficientOfVariation    *float64 `json:"coefficient_of_variation"` // null when the mean is 0
+	TrendStrength             float64  `json:"trend_strength"`
+	Volatility                float64  `json:"volatility"`
+	Skewness                  float64  `json:"skewness"`
+	Kurtosis                  float64  `json:"kurtosis"`
+	Lag1Autocorrelation       float64  `json:"lag1_autocorrelation"`
+	Mode                      int      `json:"mode"`
+	GeometricMean             float64  `json:"geometric_mean"`
+	HarmonicMean              float64  `json:"harmonic_mean"`
+	MAD                       float64  `json:"mad"`
+	MADNormalized             float64  `json:"mad_normalized"`
+	HurstExponent             float64  `json:"hurst_exponent"`
+	SampleEntropy             float64  `json:"sample_entropy"`
+	PermutationEntropy        float64  `json:"permutation_entropy"`
+	DistributionEntropy       float64  `json:"distribution_entropy"`
+	MaxDrawdown               int      `json:"max_drawdown"`
+	MaxDrawdownFraction       float64  `json:"max_drawdown_fraction"`
+	MaxDrawdownPeakStep       int      `json:"max_drawdown_peak_step"`
+	MaxDrawdownTroughStep     int      `json:"max_drawdown_trough_step"`
+	MaxDrawup                 int      `json:"max_drawup"`
+	MaxDrawupFraction         float64  `json:"max_drawup_fraction"`
+	MaxDrawupTroughStep       int      `json:"max_drawup_trough_step"`
+	MaxDrawupPeakStep         int      `json:"max_drawup_peak_step"`
+	LongestIncreasingRun      int      `json:"longest_increasing_run"`
+	LongestIncreasingRunStart int      `json:"longest_increasing_run_start"`
+	LongestDecreasingRun      int      `json:"longest_decreasing_run"`
+	LongestDecreasingRunStart int      `json:"longest_decreasing_run_start"`
+	LongestFlatRun            int      `json:"longest_flat_run"`
+	LongestFlatRunStart       int      `json:"longest_flat_run_start"`
+	TurningPoints             int      `json:"turning_points"`
+	MeanCrossings             int      `json:"mean_crossings"`
+	ReturnMean                float64  `json:"return_mean"`
+	ReturnVolatility          float64  `json:"return_volatility"`
+	OutlierCount              int      `json:"outlier_count"`
+	Changepoints              []int    `json:"changepoints,omitempty"` // steps starting a new mean level
+	Duration                  float64  `json:"duration"`               // seconds from the first timestamp to the last
+	EventsPerSecond           float64  `json:"events_per_second"`      // steps per second over the duration
+	FinalBalance              int      `json:"final_balance"`          // ledger balance after the last entry
+	MinBalance                int      `json:"min_balance"`            // lowest ledger balance after any entry
+	DebitRatio                float64  `json:"debit_ratio"`            // share of ledger entries that are debits
+	JumpCount                 int      `json:"jump_count"`             // entries shocked by a jump
+	DiffusionVolatility       float64  `json:"diffusion_volatility"`   // volatility over the changes into entries without a jump
+	ClampedLow                int      `json:"clamped_low"`            // entries whose value fell below the range
+	ClampedHigh               int      `json:"clamped_high"`           // entries whose value rose above the range
+	ClampRate                 float64  `json:"clamp_rate"`             // share of entries whose value left the range
+	Missing                   int      `json:"missing"`                // placeholders of dropped entries, left out of every other statistic
+	Duplicates                int      `json:"duplicates"`             // entries delivered again as duplicates
+
+	ByType   map[string]TypeStatistics   `json:"by_type"`             // keyed by entry type
+	ByRegime map[string]RegimeStatistics `json:"by_regime,omitempty"` // keyed by regime name, nil without regimes
+
+	// Histogram is not computed by ComputeStatistics; callers that want
+	// it in the output fill it in with Histogram
+	Histogram *HistogramResult `json:"histogram,omitempty"`
+	// Stationarity is likewise left to callers, from TestStationarity
+	Stationarity *StationarityResult `json:"stationarity,omitempty"`
+	// Money is likewise left to callers, from ComputeMoneyStatistics
+	Money *MoneyStatistics `json:"money,omitempty"`
+	// Arrivals is likewise left to callers, from ComputeArrivalStatistics
+	Arrivals *ArrivalStatistics `json:"arrivals,omitempty"`
+	// ByAccount is likewise left to callers, from CountByAccount
+	ByAccount map[string]int `json:"by_account,omitempty"`
+	// TargetDistance is likewise left to callers, from MeanTargetDistance
+	TargetDistance *float64 `json:"target_distance,omitempty"`
+}
+
+// TypeStatistics summarizes the entries of one step type
+type TypeStatistics struct {
+	Count         int     `json:"count"`
+	MeanValue     float64 `json:"mean_value"`
+	MeanAbsChange float64 `json:"mean_abs_change"` // from the previous value, over entries that have one
+	Share         float64 `json:"share"`           // fraction of all entries
+}
+
+// ComputeStatistics computes comprehensive statistics for the transaction sequence
+func ComputeStatistics(sequence []LogEntry) (Statistics, error) {
+	return ComputeStatisticsFor(sequence)
+}
+
+// ComputeStatisticsCtx computes comprehensive statistics, giving up with ctx.Err()
+// if ctx is cancelled part way through
+func ComputeStatisticsCtx(ctx context.Context, sequence []LogEntry) (Statistics, error) {
+	return ComputeStatisticsForCtx(ctx, sequence)
+}
+
+// ComputeStatisticsFor computes only the requested statistics for the sequence.
+// Dependencies are resolved transitively (variance needs stdev, which needs mean)
+// and filled in alongside the requested keys; every other field is left zero.
+// With no keys every statistic is computed, matching ComputeStatistics.
+func ComputeStatisticsFor(sequence []LogEntry, keys ...string) (Statistics, error) {
+	return ComputeStatisticsForCtx(context.Background(), sequence, keys...)
+}
+
+// ComputeStatisticsForCtx is ComputeStatisticsFor with cancellation. The context is
+// checked between each group of statistics; on cancellation the statistics
+// computed so far are returned along with ctx.Err().
+func ComputeStatisticsForCtx(ctx context.Context, sequence []LogEntry, keys ...string) (Statistics, error) {
+	var stats Statistics
+	if len(sequence) == 0 {
+		return stats, errors.New("empty sequence")
+	}
+
+	want, err := resolveStatKeys(keys)
+	if err != nil {
+		return stats, err
+	}
+
+	// Dropped entries have no value to measure
+	missing, duplicates := countFaults(sequence)
+	if want["missing"] || want["duplicates"] {
+		stats.Missing, stats.Duplicates = missing, duplicates
+	}
+	if missing == len(sequence) {
+		return stats, errors.New("every entry is missing")
+	}
+	sequence = ExcludeMissing(sequence)
+
+	values := make([]int, len(sequence))
+	for i, entry := range sequence {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+		}
+		values[i] = entry.Value
+	}
+
+	// Order statistics share one sorted copy
+	var sorted []int
+	if wantAny(want, sortedStatKeys) {
+		sorted = make([]int, len(values))
+		copy(sorted, values)
+		sort.Ints(sorted)
+	}
+
+	// Calculate basic statistics
+	if want["count"] {
+		stats.Count = len(values)
+	}
+	if want["min"] || want["max"] {
+		stats.Min, stats.Max = calculateMinMax(values)
+	}
+	if want["median"] || want["q1"] || want["q3"] {
+		quartiles := Quantiles(sorted, 0.25, 0.5, 0.75)
+		stats.Q1, stats.Q3 = quartiles[0], quartiles[2]
+		if want["median"] {
+			stats.Median = quartiles[1]
+		}
+	}
+	if want["mean"] {
+		stats.Mean = calculateMean(values)
+	}
+	if want["stdev"] {
+		stats.Stdev = calculateStdev(values, stats.Mean)
+	}
+	if want["mode"] {
+		stats.Mode = calculateMode(values)
+	}
+	if want["geometric_mean"] || want["harmonic_mean"] {
+		stats.GeometricMean, stats.HarmonicMean = calculatePositiveMeans(values)
+	}
+
+	// Calculate advanced statistics
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	if want["variance"] {
+		stats.Variance = stats.Stdev * stats.Stdev
+	}
+	if want["coefficient_of_variation"] {
+		stats.CoefficientOfVariation = coefficientOfVariation(stats.Stdev, stats.Mean)
+	}
+	if want["iqr"] {
+		stats.IQR = stats.Q3 - stats.Q1
+	}
+	if want["mad"] {
+		stats.MAD = calculateMAD(sorted)
+	}
+	if want["mad_normalized"] {
+		stats.MADNormalized = madScale * stats.MAD
+	}
+	if want["outlier_count"] {
+		// Tukey's fences, the default FlagOutliers method
+		lo, hi := iqrFences(stats.Q1, stats.Q3, DefaultIQRFence)
+		stats.OutlierCount = countOutside(values, lo, hi)
+	}
+
+	// Trend analysis
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	if want["trend_strength"] {
+		stats.TrendStrength = calculateTrendStrength(values)
+	}
+	if want["volatility"] {
+		stats.Volatility = calculateVolatility(values)
+	}
+	if wantAny(want, drawdownStatKeys) {
+		down, up := calculateDrawdowns(values)
+		stats.MaxDrawdown, stats.MaxDrawdownFraction = down.amount, down.fraction
+		stats.MaxDrawdownPeakStep, stats.MaxDrawdownTroughStep = sequence[down.from].Step, sequence[down.to].Step
+		stats.MaxDrawup, stats.MaxDrawupFraction = up.amount, up.fraction
+		stats.MaxDrawupTroughStep, stats.MaxDrawupPeakStep = sequence[up.from].Step, sequence[up.to].Step
+	}
+	if wantAny(want, runStatKeys) {
+		up, down, flat := calculateRuns(values)
+		stats.LongestIncreasingRun, stats.LongestIncreasingRunStart = up.length, sequence[up.start].Step
+		stats.LongestDecreasingRun, stats.LongestDecreasingRunStart = down.length, sequence[down.start].Step
+		stats.LongestFlatRun, stats.LongestFlatRunStart = flat.length, sequence[flat.start].Step
+	}
+	if want["turning_points"] {
+		stats.TurningPoints = calculateTurningPoints(values)
+	}
+	if want["mean_crossings"] {
+		stats.MeanCrossings = calculateMeanCrossings(values, stats.Mean)
+	}
+	if want["return_mean"] || want["return_volatility"] {
+		// Left at 0 when the sequence touches zero or goes negative
+		stats.ReturnMean, stats.ReturnVolatility = calculateReturnStats(values)
+	}
+	if want["changepoints"] {
+		// The default penalty cannot fail on a non-empty sequence
+		changepoints, _ := DetectChangepoints(values, 0)
+		for _, idx := range changepoints {
+			stats.Changepoints = append(stats.Changepoints, sequence[idx].Step)
+		}
+	}
+	if want["by_type"] {
+		stats.ByType = calculateByType(sequence)
+	}
+	if want["by_regime"] {
+		stats.ByRegime = calculateByRegime(sequence)
+	}
+	if want["jump_count"] || want["diffusion_volatility"] {
+		stats.JumpCount, stats.DiffusionVolatility = calculateJumps(sequence)
+	}
+	if want["clamped_low"] || want["clamped_high"] || want["clamp_rate"] {
+		stats.ClampedLow, stats.ClampedHigh = countClamped(sequence)
+		stats.ClampRate = float64(stats.ClampedLow+stats.ClampedHigh) / float64(len(sequence))
+	}
+	if want["hurst_exponent"] {
+		// Left at 0 for sequences too short or flat to estimate
+		stats.HurstExponent, _ = EstimateHurst(values)
+	}
+	if want["duration"] || want["events_per_second"] {
+		// Left at 0 for untimed sequences
+		if stats.Duration, stats.EventsPerSecond, err = calculateTiming(sequence); err != nil {
+			return stats, err
+		}
+	}
+	if want["final_balance"] || want["min_balance"] || want["debit_ratio"] {
+		// Left at 0 for sequences without a ledger
+		stats.FinalBalance, stats.MinBalance, stats.DebitRatio = calculateLedger(sequence)
+	}
+
+	// Distribution shape and memory
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	if want["skewness"] || want["kurtosis"] {
+		stats.Skewness, stats.Kurtosis = calculateShape(values, stats.Mean)
+	}
+	if want["lag1_autocorrelation"] {
+		stats.Lag1Autocorrelation = calculateLag1Autocorrelation(values, stats.Mean)
+	}
+	if want["sample_entropy"] {
+		stats.SampleEntropy = SampleEntropy(values[:min(len(values), sampleEntropyMaxLength)],
+			sampleEntropyM, sampleEntropyTolerance*stats.Stdev)
+	}
+	if want["permutation_entropy"] {
+		// Left at 0 for sequences shorter than the order
+		stats.PermutationEntropy, _ = PermutationEntropy(values, permutationEntropyOrder)
+	}
+	if want["distribution_entropy"] {
+		// Sturges' rule over the observed range, which always holds the values
+		stats.DistributionEntropy, _ = DistributionEntropy(values, 0, stats.Min, stats.Max)
+	}
+
+	return stats, nil
+}
+
+// resolveStatKeys expands the requested keys with their transitive dependencies
+func resolveStatKeys(keys []string) (map[string]bool, error) {
+	if len(keys) == 0 {
+		keys = statKeys
+	}
+
+	known := make(map[string]bool, len(statKeys))
+	for _, key := range statKeys {
+		known[key] = true
+	}
+
+	want := make(map[string]bool)
+	var add func(key string)
+	add = func(key string) {
+		if want[key] {
+			return
+		}
+		want[key] = true
+		for _, dep := range statDependencies[key] {
+			add(dep)
+		}
+	}
+	for _, key := range keys {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown statistic %q", key)
+		}
+		add(key)
+	}
+	return want, nil
+}
+
+// wantAny reports whether any of keys was requested
+func wantAny(want map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if want[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// number is a value type the statistics helpers accept: the integers of
+// generated sequences or the floats of ChaoticFloatSequence
+type number interface {
+	~int | ~float64
+}
+
+// MeanTargetDistance returns the mean absolute distance of the values of
+// sequence from target, such as a config's MeanReversionTarget, or 0 for an
+// empty sequence
+func MeanTargetDistance(sequence []LogEntry, target int) float64 {
+	if len(sequence) == 0 {
+		return 0
+	}
+	var total float64
+	for _, entry := range sequence {
+		total += math.Abs(float64(entry.Value) - float64(target))
+	}
+	return total / float64(len(sequence))
+}
+
+// calculateMean computes the arithmetic mean. The sum is taken in float64,
+// where ints near the ends of their range would wrap; it is exact as long
+// as it stays within 2^53.
+func calculateMean[T number](values []T) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	return sum / float64(len(values))
+}
+
+// calculateMode returns the most frequent value, the smallest one on ties
+func calculateMode(values []int) int {
+	counts := make(map[int]int)
+	mode, best := values[0], 0
+	for _, v := range values {
+		counts[v]++
+		if c := counts[v]; c > best || (c == best && v < mode) {
+			mode, best = v, c
+		}
+	}
+	return mode
+}
+
+// calculatePositiveMeans computes the geometric and harmonic means. Both are
+// only defined for positive numbers, so zero and negative values are skipped;
+// a sequence with no positive values reports 0 for both.
+func calculatePositiveMeans(values []int) (float64, float64) {
+	var logSum, invSum float64
+	count := 0
+	for _, v := range values {
+		if v <= 0 {
+			continue
+		}
+		logSum += math.Log(float64(v))
+		invSum += 1 / float64(v)
+		count++
+	}
+	if count == 0 {
+		return 0.0, 0.0
+	}
+	return math.Exp(logSum / float64(count)), float64(count) / invSum
+}
+
+// calculateStdev computes the sample standard deviation around mean, 0 for
+// a single value, which has no spread to measure
+func calculateStdev[T number](values []T, mean float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	var variance float64
+	for _, v := range values {
+		diff := float64(v) - mean
+		variance += diff * diff
+	}
+	variance /= float64(len(values) - 1)
+	return math.Sqrt(variance)
+}
+
+// coefficientOfVariation returns stdev relative to mean, or nil when the
+// mean is 0 and the ratio has no finite value. A mean of integers is either
+// 0 or at least 1/len(values) away from it, so no epsilon is needed.
+func coefficientOfVariation(stdev, mean float64) *float64 {
+	if mean == 0 {
+		return nil
+	}
+	cv := stdev / mean
+	return &cv
+}
+
+// calculateMinMax returns the smallest and largest values
+func calculateMinMax[T number](values []T) (T, T) {
+	minVal, maxVal := values[0], values[0]
+	for _, v := range values {
+		if v < minVal {
+			minVal = v
+		}
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+	return minVal, maxVal
+}
+
+// Quantiles returns the quantiles qs, each from 0 to 1, of values already
+// sorted in increasing order, interpolating linearly between the two
+// nearest ranks: q=0 is the minimum, q=1 the maximum and q=0.5 the median.
+// Quantiles outside [0, 1] are clamped to it, and with no values every
+// quantile is 0.
+func Quantiles[T number](sorted []T, qs ...float64) []float64 {
+	result := make([]float64, len(qs))
+	if len(sorted) == 0 {
+		return result
+	}
+	for i, q := range qs {
+		pos := min(max(q, 0), 1) * float64(len(sorted)-1)
+		lower := int(pos)
+		if lower+1 >= len(sorted) {
+			result[i] = float64(sorted[len(sorted)-1])
+			continue
+		}
+		weight := pos - float64(lower)
+		result[i] = float64(sorted[lower])*(1-weight) + float64(sorted[lower+1])*weight
+	}
+	return result
+}
+
+// calculateMAD computes the median absolute deviation from the exact median
+// of sorted values. Deviations shrink towards the median from both sides, so
+// the two halves are merged in order instead of sorting the deviations.
+func calculateMAD[T number](sorted []T) float64 {
+	n := len(sorted)
+	median := (float64(sorted[(n-1)/2]) + float64(sorted[n/2])) / 2
+
+	hi := sort.Search(n, func(i int) bool { return float64(sorted[i]) >= median })
+	lo := hi - 1
+	var prev, cur float64
+	for rank := 0; rank <= n/2; rank++ {
+		prev = cur
+		if lo < 0 || (hi < n && float64(sorted[hi])-median <= median-float64(sorted[lo])) {
+			cur = float64(sorted[hi]) - median
+			hi++
+		} else {
+			cur = median - float64(sorted[lo])
+			lo--
+		}
+	}
+	if n%2 == 0 {
+		return (prev + cur) / 2
+	}
+	return cur
+}
+
+// calculateTrendStrength measures how trending the sequence is, 0 when it
+// has fewer than two values and so no steps
+func calculateTrendStrength[T number](values []T) float64 {
+	if len(values) < 2 {
+		return 0.0
+	}
+
+	up, down := 0, 0
+	for i := 1; i < len(values); i++ {
+		if values[i] > values[i-1] {
+			up++
+		} else if values[i] < values[i-1] {
+			down++
+		}
+	}
+
+	total := up + down
+	if total == 0 {
+		return 0.0
+	}
+	return math.Abs(float64(up-down)) / float64(total)
+}
+
+// calculateVolatility measures the sequence volatility as the mean absolute
+// step, 0 when it has fewer than two values
+func calculateVolatility[T number](values []T) float64 {
+	if len(values) < 2 {
+		return 0.0
+	}
+
+	var sum float64
+	for i := 1; i < len(values); i++ {
+		change := math.Abs(float64(values[i]) - float64(values[i-1]))
+		sum += change
+	}
+	return sum / float64(len(values)-1)
+}
+
+// calculateReturnStats returns the mean and sample standard deviation of the
+// percent returns, or zeros when they are undefined
+func calculateReturnStats(values []int) (float64, float64) {
+	returns, err := ComputeReturns(values, ReturnPercent)
+	if err != nil {
+		return 0, 0
+	}
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	mean := sum / float64(len(returns))
+	if len(returns) < 2 {
+		return mean, 0
+	}
+	var variance float64
+	for _, r := range returns {
+		diff := r - mean
+		variance += diff * diff
+	}
+	return mean, math.Sqrt(variance / float64(len(returns)-1))
+}
+
+// calculateByType groups the entries by type and summarizes each group. The
+// change at an entry is measured from the entry before it, so the first entry
+// counts towards its group's count and mean value but not its mean change.
+func calculateByType(sequence []LogEntry) map[string]TypeStatistics {
+	type totals struct {
+		count, changes    int
+		values, absChange float64
+	}
+	groups := make(map[string]*totals)
+	for i, entry := range sequence {
+		key := entry.Type
+		if !stepTypes[key] {
+			key = otherStepType
+		}
+		group := groups[key]
+		if group == nil {
+			group = &totals{}
+			groups[key] = group
+		}
+		group.count++
+		group.values += float64(entry.Value)
+		if i > 0 {
+			group.changes++
+			group.absChange += math.Abs(float64(entry.Value) - float64(sequence[i-1].Value))
+		}
+	}
+
+	byType := make(map[string]TypeStatistics, len(groups))
+	for key, group := range groups {
+		stats := TypeStatistics{
+			Count:     group.count,
+			MeanValue: group.values / float64(group.count),
+			Share:     float64(group.count) / float64(len(sequence)),
+		}
+		if group.changes > 0 {
+			stats.MeanAbsChange = group.absChange / float64(group.changes)
+		}
+		byType[key] = stats
+	}
+	return byType
+}
+
+// excursion is the largest move in one direction between two points of a sequence
+type excursion[T number] struct {
+	amount   T       // size of the move
+	fraction float64 // amount relative to the starting value, 0 if that is not positive
+	from, to int     // indices where the move starts and ends
+}
+
+// calculateDrawdowns finds, in one pass, the largest peak-to-trough decline
+// and the largest trough-to-peak rise. Ties keep the earliest move, and a
+// sequence that never falls (or rises) reports a zero move at index 0.
+func calculateDrawdowns[T number](values []T) (down, up excursion[T]) {
+	peak, trough := 0, 0
+	for i, v := range values {
+		if v > values[peak] {
+			peak = i
+		}
+		if v < values[trough] {
+			trough = i
+		}
+		if drop := spread(values[peak], v); drop > down.amount {
+			down = excursion[T]{amount: drop, from: peak, to: i}
+		}
+		if rise := spread(v, values[trough]); rise > up.amount {
+			up = excursion[T]{amount: rise, from: trough, to: i}
+		}
+	}
+	if start := values[down.from]; start > 0 {
+		down.fraction = float64(down.amount) / float64(start)
+	}
+	if start := values[up.from]; start > 0 {
+		up.fraction = float64(up.amount) / float64(start)
+	}
+	return down, up
+}
+
+// spread returns hi - lo for hi at least lo. Ints go through subSat, as
+// the distance between two of them can pass the int range.
+func spread[T number](hi, lo T) T {
+	if hi, ok := any(hi).(int); ok {
+		return T(subSat(hi, int(lo)))
+	}
+	return hi - lo
+}
+
+// run is a stretch of consecutive values moving the same way
+type run struct {
+	length int // number of values in the run
+	start  int // index of the first value
+}
+
+// calculateRuns finds the longest strictly increasing, strictly decreasing
+// and flat (equal) runs. Lengths count values, so a run has at least two;
+// a sequence with no such run reports length 0 at index 0. Ties keep the
+// earliest run.
+func calculateRuns(values []int) (up, down, flat run) {
+	var current [3]run // increasing, decreasing and flat runs ending at i
+	best := [3]*run{&up, &down, &flat}
+	for i := 1; i < len(values); i++ {
+		direction := 2
+		if values[i] > values[i-1] {
+			direction = 0
+		} else if values[i] < values[i-1] {
+			direction = 1
+		}
+		for d := range current {
+			switch {
+			case d != direction:
+				current[d] = run{}
+			case current[d].length == 0:
+				current[d] = run{length: 2, start: i - 1}
+			default:
+				current[d].length++
+			}
+			if current[d].length > best[d].length {
+				*best[d] = current[d]
+			}
+		}
+	}
+	return up, down, flat
+}
+
+// calculateTurningPoints counts local maxima and minima. A plateau counts as
+// one point: runs of equal values are treated as a single value, so a flat
+// top between a rise and a fall is one maximum, while a plateau part way up
+// a rise is not a turning point. The first and last values never count.
+func calculateTurningPoints[T number](values []T) int {
+	count := 0
+	direction := 0 // sign of the last non-zero change
+	for i := 1; i < len(values); i++ {
+		change := 0
+		if values[i] > values[i-1] {
+			change = 1
+		} else if values[i] < values[i-1] {
+			change = -1
+		}
+		if change == 0 {
+			continue
+		}
+		if direction != 0 && change != direction {
+			count++
+		}
+		direction = change
+	}
+	return count
+}
+
+// calculateMeanCrossings counts how often the sequence crosses its mean.
+// Values exactly on the mean do not cross by themselves: a crossing is
+// counted when a value lands on the opposite side from the last value that
+// was off the mean.
+func calculateMeanCrossings[T number](values []T, mean float64) int {
+	count := 0
+	side := 0
+	for _, v := range values {
+		current := 0
+		if float64(v) > mean {
+			current = 1
+		} else if float64(v) < mean {
+			current = -1
+		}
+		if current == 0 {
+			continue
+		}
+		if side != 0 && current != side {
+			count++
+		}
+		side = current
+	}
+	return count
+}
+
+// calculateShape computes the bias-corrected sample skewness (G1) and excess
+// kurtosis (G2, normal distribution = 0). Skewness needs at least 3 values and
+// kurtosis at least 4; shorter or flat sequences report 0.
+func calculateShape[T number](values []T, mean float64) (float64, float64) {
+	var m2, m3, m4 float64
+	for _, v := range values {
+		diff := float64(v) - mean
+		sq := diff * diff
+		m2 += sq
+		m3 += sq * diff
+		m4 += sq * sq
+	}
+	n := float64(len(values))
+	m2, m3, m4 = m2/n, m3/n, m4/n
+	if m2 == 0 {
+		return 0.0, 0.0
+	}
+
+	var skewness, kurtosis float64
+	if len(values) >= 3 {
+		g1 := m3 / math.Pow(m2, 1.5)
+		skewness = math.Sqrt(n*(n-1)) / (n - 2) * g1
+	}
+	if len(values) >= 4 {
+		g2 := m4/(m2*m2) - 3
+		kurtosis = (n - 1) / ((n - 2) * (n - 3)) * ((n+1)*g2 + 6)
+	}
+	return skewness, kurtosis
+}
+
+// calculateLag1Autocorrelation measures how strongly each value predicts the next
+func calculateLag1Autocorrelation[T number](values []T, mean float64) float64 {
+	var num, den float64
+	for i, v := range values {
+		diff := float64(v) - mean
+		den += diff * diff
+		if i > 0 {
+			num += diff * (float64(values[i-1]) - mean)
+		}
+	}
+	if den == 0 {
+		return 0.0
+	}
+	return num / den
+}
@@ -0,0 +1,591 @@
+package chaotic
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// entriesOf returns a sequence with the given values at steps 0, 1, ...
+func entriesOf(values ...int) []LogEntry {
+	sequence := make([]LogEntry, len(values))
+	for i, v := range values {
+		sequence[i] = LogEntry{Step: i, Value: v, Type: "test"}
+	}
+	return sequence
+}
+
+// closeTo reports whether got is within tol of want
+func closeTo(got, want, tol float64) bool {
+	return math.Abs(got-want) <= tol
+}
+
+func TestShapeOfConstantSequence(t *testing.T) {
+	stats, err := ComputeStatistics(entriesOf(7, 7, 7, 7, 7, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Skewness != 0 || stats.Kurtosis != 0 || stats.Lag1Autocorrelation != 0 {
+		t.Errorf("skewness %v, kurtosis %v, lag-1 autocorrelation %v; want 0 for a flat sequence",
+			stats.Skewness, stats.Kurtosis, stats.Lag1Autocorrelation)
+	}
+}
+
+func TestShapeOfMonotonicSequence(t *testing.T) {
+	// For 1..5 the deviations from the mean of 3 are -2..2: m2 = 2 and
+	// m4 = 6.8, so g2 = 6.8/4 - 3 = -1.3 and G2 = 4/(3·2)·(6·-1.3 + 6) = -1.2.
+	// The lag-1 products sum to 2 + 0 + 0 + 2 = 4 over squares summing to 10.
+	stats, err := ComputeStatistics(entriesOf(1, 2, 3, 4, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(stats.Skewness, 0, 1e-12) {
+		t.Errorf("skewness %v, want 0", stats.Skewness)
+	}
+	if !closeTo(stats.Kurtosis, -1.2, 1e-12) {
+		t.Errorf("kurtosis %v, want -1.2", stats.Kurtosis)
+	}
+	if !closeTo(stats.Lag1Autocorrelation, 0.4, 1e-12) {
+		t.Errorf("lag-1 autocorrelation %v, want 0.4", stats.Lag1Autocorrelation)
+	}
+}
+
+func TestSkewnessAndKurtosisOfKnownData(t *testing.T) {
+	tests := []struct {
+		name               string
+		values             []int
+		skewness, kurtosis float64
+	}{
+		// Mean 5; m2 = 32/8 = 4, m3 = 42/8 = 5.25, m4 = 356/8 = 44.5, so
+		// g1 = 5.25/8 and G1 = √56/6·g1; g2 = 44.5/16 - 3 = -0.21875 and
+		// G2 = 7/30·(9·g2 + 6) = 0.940625
+		{"textbook", []int{2, 4, 4, 4, 5, 5, 7, 9}, math.Sqrt(56) / 6 * 5.25 / 8, 0.940625},
+		// Mean 2.8; m2 = 12.96, m3 = 69.984 = 1.5·m2^1.5, so G1 = √20/3·1.5
+		// = √5; m4 = 3.25·m2², so G2 = 4/6·(6·0.25 + 6) = 5
+		{"one large value", []int{1, 1, 1, 1, 10}, math.Sqrt(5), 5},
+		{"mirrored", []int{10, 10, 10, 10, 1}, -math.Sqrt(5), 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ComputeStatistics(entriesOf(tt.values...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !closeTo(stats.Skewness, tt.skewness, 1e-9) {
+				t.Errorf("skewness %v, want %v", stats.Skewness, tt.skewness)
+			}
+			if !closeTo(stats.Kurtosis, tt.kurtosis, 1e-9) {
+				t.Errorf("kurtosis %v, want %v", stats.Kurtosis, tt.kurtosis)
+			}
+		})
+	}
+}
+
+func TestSkewnessAndKurtosisOfShortSequences(t *testing.T) {
+	// Skewness needs 3 values and kurtosis 4
+	for _, values := range [][]int{{5}, {1, 9}, {1, 2, 9}} {
+		stats, err := ComputeStatistics(entriesOf(values...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Kurtosis != 0 || len(values) < 3 && stats.Skewness != 0 {
+			t.Errorf("%v: skewness %v, kurtosis %v", values, stats.Skewness, stats.Kurtosis)
+		}
+		if math.IsNaN(stats.Skewness) || math.IsNaN(stats.Kurtosis) {
+			t.Errorf("%v: NaN moments", values)
+		}
+	}
+}
+
+func TestShapeInStatisticsJSON(t *testing.T) {
+	stats, err := ComputeStatistics(entriesOf(2, 4, 4, 4, 5, 5, 7, 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"skewness", "kurtosis"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("the statistics JSON has no %s", key)
+		}
+	}
+}
+
+func TestMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   int
+	}{
+		{"single most frequent", []int{4, 8, 8, 1, 8, 4}, 8},
+		{"tie goes to the smallest", []int{5, 3, 9, 5, 3, 9}, 3},
+		{"tie with the larger value first", []int{9, 9, 2, 2}, 2},
+		{"all distinct", []int{7, 2, 9, 4}, 2},
+		{"negative values", []int{-3, 0, -3, 0}, -3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ComputeStatistics(entriesOf(tt.values...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Mode != tt.want {
+				t.Errorf("mode of %v is %d, want %d", tt.values, stats.Mode, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeometricAndHarmonicMeans(t *testing.T) {
+	tests := []struct {
+		name                string
+		values              []int
+		geometric, harmonic float64
+	}{
+		// ∛(1·2·4) = 2 and 3/(1 + 1/2 + 1/4) = 12/7
+		{"positive", []int{1, 2, 4}, 2, 12.0 / 7},
+		{"zero and negatives skipped", []int{-4, 0, 1, 2, 4}, 2, 12.0 / 7},
+		{"all distinct", []int{3, 12}, 6, 4.8},
+		{"no positive values", []int{-1, 0, -7}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ComputeStatistics(entriesOf(tt.values...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !closeTo(stats.GeometricMean, tt.geometric, 1e-12) {
+				t.Errorf("geometric mean %v, want %v", stats.GeometricMean, tt.geometric)
+			}
+			if !closeTo(stats.HarmonicMean, tt.harmonic, 1e-12) {
+				t.Errorf("harmonic mean %v, want %v", stats.HarmonicMean, tt.harmonic)
+			}
+		})
+	}
+}
+
+func TestMADIgnoresOutlier(t *testing.T) {
+	calm := make([]int, 99)
+	for i := range calm {
+		calm[i] = 100 + i%10
+	}
+	spiked := append(append([]int(nil), calm...), 1_000_000)
+
+	before, err := ComputeStatistics(entriesOf(calm...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := ComputeStatistics(entriesOf(spiked...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The median shifts by half a rank, and the MAD with it
+	if !closeTo(after.MAD, before.MAD, before.MAD/4) {
+		t.Errorf("one outlier moved the MAD from %v to %v", before.MAD, after.MAD)
+	}
+	if after.Stdev < 1000*before.Stdev {
+		t.Errorf("stdev went from %v to only %v", before.Stdev, after.Stdev)
+	}
+	if !closeTo(after.MADNormalized, 1.4826*after.MAD, 1e-9) {
+		t.Errorf("mad_normalized %v is not 1.4826 × %v", after.MADNormalized, after.MAD)
+	}
+}
+
+func TestMADNearIntLimits(t *testing.T) {
+	// The middle values sum past math.MaxInt
+	stats, err := ComputeStatistics(entriesOf(math.MaxInt-4, math.MaxInt-2, math.MaxInt-2, math.MaxInt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.MAD < 0 || stats.MAD > 4 {
+		t.Errorf("MAD %v of values spanning 4", stats.MAD)
+	}
+}
+
+func TestDrawdownsOfKnownPaths(t *testing.T) {
+	type move struct {
+		amount   int
+		fraction float64
+		from, to int
+	}
+	tests := []struct {
+		name     string
+		values   []int
+		down, up move
+	}{
+		// Falls 120 → 60 and rises 60 → 130; the dip to 90 recovers first
+		{"peak to trough", []int{100, 120, 90, 110, 60, 130, 125}, move{60, 0.5, 1, 4}, move{70, 70.0 / 60, 4, 5}},
+		{"equal moves keep the earliest", []int{10, 5, 10, 5}, move{5, 0.5, 0, 1}, move{5, 1, 1, 2}},
+		{"monotone increasing", []int{1, 2, 3, 5, 8}, move{0, 0, 0, 0}, move{7, 7, 0, 4}},
+		{"monotone decreasing", []int{8, 5, 3, 2, 1}, move{7, 7.0 / 8, 0, 4}, move{0, 0, 0, 0}},
+		{"constant", []int{4, 4, 4}, move{0, 0, 0, 0}, move{0, 0, 0, 0}},
+		// No fraction of a peak at or below zero
+		{"negative peak", []int{-5, -20, -10}, move{15, 0, 0, 1}, move{10, 0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ComputeStatistics(entriesOf(tt.values...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			down := move{stats.MaxDrawdown, stats.MaxDrawdownFraction, stats.MaxDrawdownPeakStep, stats.MaxDrawdownTroughStep}
+			up := move{stats.MaxDrawup, stats.MaxDrawupFraction, stats.MaxDrawupTroughStep, stats.MaxDrawupPeakStep}
+			if down.amount != tt.down.amount || !closeTo(down.fraction, tt.down.fraction, 1e-12) ||
+				down.from != tt.down.from || down.to != tt.down.to {
+				t.Errorf("drawdown %+v, want %+v", down, tt.down)
+			}
+			if up.amount != tt.up.amount || !closeTo(up.fraction, tt.up.fraction, 1e-12) ||
+				up.from != tt.up.from || up.to != tt.up.to {
+				t.Errorf("drawup %+v, want %+v", up, tt.up)
+			}
+		})
+	}
+}
+
+func TestRunsOfSequencePinnedAtMaxValue(t *testing.T) {
+	// Clamped at 1000 for five steps; the two rises of three tie
+	stats, err := ComputeStatistics(entriesOf(500, 700, 1000, 1000, 1000, 1000, 1000, 800, 900, 1000, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := [][2]int{
+		{stats.LongestIncreasingRun, stats.LongestIncreasingRunStart},
+		{stats.LongestDecreasingRun, stats.LongestDecreasingRunStart},
+		{stats.LongestFlatRun, stats.LongestFlatRunStart},
+	}
+	want := [][2]int{{3, 0}, {2, 6}, {5, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("increasing, decreasing and flat runs (length, start) %v, want %v", got, want)
+	}
+
+	constant, err := ComputeStatistics(entriesOf(3, 3, 3, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if constant.LongestFlatRun != 4 || constant.LongestIncreasingRun != 0 || constant.LongestDecreasingRun != 0 {
+		t.Errorf("constant sequence: runs %d up, %d down, %d flat", constant.LongestIncreasingRun,
+			constant.LongestDecreasingRun, constant.LongestFlatRun)
+	}
+}
+
+func TestFlatRunOfClampedGeneratorOutput(t *testing.T) {
+	seed := int64(42)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := ComputeStatistics(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The longest stretch pinned at MaxValue, found the slow way
+	pinned, pinnedStart := 0, 0
+	for i := 0; i < len(sequence); {
+		j := i
+		for j < len(sequence) && sequence[j].Value == config.MaxValue {
+			j++
+		}
+		if j-i > pinned {
+			pinned, pinnedStart = j-i, i
+		}
+		i = max(j, i+1)
+	}
+	if pinned < 3 {
+		t.Fatalf("seed %d never stays at MaxValue for three steps", seed)
+	}
+	if stats.LongestFlatRun < pinned {
+		t.Errorf("longest flat run %d is shorter than the %d steps pinned at MaxValue from step %d",
+			stats.LongestFlatRun, pinned, pinnedStart)
+	}
+	start := stats.LongestFlatRunStart
+	for _, entry := range sequence[start : start+stats.LongestFlatRun] {
+		if entry.Value != sequence[start].Value {
+			t.Fatalf("the flat run from step %d is not flat at step %d", start, entry.Step)
+		}
+	}
+}
+
+func TestTurningPointsAndMeanCrossings(t *testing.T) {
+	tests := []struct {
+		name               string
+		values             []int
+		turning, crossings int
+	}{
+		// Maxima at each 3 but the last, minima at each inner 0; the mean
+		// of 1.5 is crossed going up and down every tooth
+		{"sawtooth", []int{0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3}, 4, 5},
+		{"constant", []int{6, 6, 6, 6, 6}, 0, 0},
+		{"ramp", []int{1, 2, 3, 4, 5, 6}, 0, 1},
+		{"flat top is one maximum", []int{1, 3, 3, 3, 1}, 1, 2},
+		{"plateau on a rise", []int{1, 2, 2, 3, 4}, 0, 1},
+		// Values on the mean of 1 do not cross by themselves
+		{"touching the mean", []int{0, 2, 1, 2, 0}, 3, 2},
+		{"passing through the mean", []int{0, 1, 2}, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ComputeStatistics(entriesOf(tt.values...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.TurningPoints != tt.turning {
+				t.Errorf("turning points %d, want %d", stats.TurningPoints, tt.turning)
+			}
+			if stats.MeanCrossings != tt.crossings {
+				t.Errorf("mean crossings %d, want %d", stats.MeanCrossings, tt.crossings)
+			}
+		})
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []int
+		qs     []float64
+		want   []float64
+	}{
+		{"ends", []int{2, 5, 9, 11}, []float64{0, 1}, []float64{2, 11}},
+		// Positions 0.75, 1.5 and 2.25 between ranks
+		{"interpolated", []int{1, 2, 3, 4}, []float64{0.25, 0.5, 0.75}, []float64{1.75, 2.5, 3.25}},
+		{"odd length", []int{1, 3, 4, 8, 20}, []float64{0.25, 0.5, 0.75}, []float64{3, 4, 8}},
+		{"single value", []int{7}, []float64{0, 0.3, 0.5, 1}, []float64{7, 7, 7, 7}},
+		{"two values", []int{3, 4}, []float64{0, 0.5, 1}, []float64{3, 3.5, 4}},
+		{"clamped", []int{3, 4}, []float64{-1, 2}, []float64{3, 4}},
+		{"empty", nil, []float64{0, 0.5, 1}, []float64{0, 0, 0}},
+		{"no quantiles", []int{1, 2}, nil, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Quantiles(tt.sorted, tt.qs...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Quantiles(%v, %v) = %v, want %v", tt.sorted, tt.qs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuartilesOfTinySequences(t *testing.T) {
+	tests := []struct {
+		values         []int
+		q1, median, q3 float64
+	}{
+		{[]int{5}, 5, 5, 5},
+		{[]int{4, 3}, 3.25, 3.5, 3.75},
+		{[]int{9, 1, 4}, 2.5, 4, 6.5},
+	}
+	for _, tt := range tests {
+		stats, err := ComputeStatistics(entriesOf(tt.values...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Q1 != tt.q1 || stats.Median != tt.median || stats.Q3 != tt.q3 || stats.IQR != tt.q3-tt.q1 {
+			t.Errorf("%v: q1 %v, median %v, q3 %v, iqr %v; want %v, %v, %v", tt.values,
+				stats.Q1, stats.Median, stats.Q3, stats.IQR, tt.q1, tt.median, tt.q3)
+		}
+	}
+}
+
+// BenchmarkQuantiles compares computing the quartiles of 1M values from one
+// sorted copy with sorting a fresh copy for each, as ComputeStatistics used to
+func BenchmarkQuantiles(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 1_000_000)
+	for i := range values {
+		values[i] = rng.Intn(1_000_000)
+	}
+	qs := []float64{0.25, 0.5, 0.75}
+
+	b.Run("sort once", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			Quantiles(sortedCopy(values), qs...)
+		}
+	})
+	b.Run("sort per quantile", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, q := range qs {
+				Quantiles(sortedCopy(values), q)
+			}
+		}
+	})
+}
+
+func TestDrawdownsNearIntLimits(t *testing.T) {
+	// Falls and rises wider than the int range saturate instead of wrapping
+	stats, err := ComputeStatistics(entriesOf(math.MaxInt, math.MinInt+1, math.MaxInt-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.MaxDrawdown != math.MaxInt || stats.MaxDrawup != math.MaxInt {
+		t.Errorf("drawdown %d, drawup %d; want both math.MaxInt", stats.MaxDrawdown, stats.MaxDrawup)
+	}
+	// The values sum to about math.MaxInt
+	if !closeTo(stats.Mean, math.MaxInt/3.0, 1e4) {
+		t.Errorf("mean %g, want about %g", stats.Mean, math.MaxInt/3.0)
+	}
+}
+
+func TestSymmetricRangeDocumentMarshals(t *testing.T) {
+	// A mean of exactly 0 leaves the coefficient of variation undefined
+	stats, err := ComputeStatistics(entriesOf(-40, -10, 0, 10, 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Mean != 0 || stats.CoefficientOfVariation != nil {
+		t.Fatalf("mean %v, coefficient of variation %v; want 0 and nil", stats.Mean, stats.CoefficientOfVariation)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if cv, ok := fields["coefficient_of_variation"]; !ok || cv != nil {
+		t.Errorf("coefficient_of_variation is %v in %s, want null", cv, data)
+	}
+
+	for seed := int64(1); seed <= 5; seed++ {
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.MinValue, config.MaxValue = -100, 100
+		doc := seededDocument(t, 1000, config)
+		if seed == 1 {
+			// Give one document the zero mean as well
+			doc.Sequence, doc.Statistics = entriesOf(-40, -10, 0, 10, 40), stats
+		}
+		path := filepath.Join(t.TempDir(), "run.json")
+		if err := SaveToJson(doc, path); err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+		}
+	}
+}
+
+func TestMedianOfEvenLengthWithOddSum(t *testing.T) {
+	// Each pair of middle values has an odd sum, which integer division
+	// would round toward zero
+	tests := []struct {
+		values []int
+		want   float64
+	}{
+		{[]int{3, 4}, 3.5},
+		{[]int{10, 1, 4, 7}, 5.5},
+		{[]int{-3, -2}, -2.5},
+		{[]int{-1, 0, 8, -6}, -0.5},
+		{[]int{1, 2, 2, 3, 3, 40}, 2.5},
+	}
+	for _, tt := range tests {
+		stats, err := ComputeStatistics(entriesOf(tt.values...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Median != tt.want {
+			t.Errorf("%v: median %v, want %v", tt.values, stats.Median, tt.want)
+		}
+		only, err := ComputeStatisticsFor(entriesOf(tt.values...), "median")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if only.Median != tt.want {
+			t.Errorf("%v: median %v alone, want %v", tt.values, only.Median, tt.want)
+		}
+		var streaming StreamingStats
+		for _, v := range tt.values {
+			streaming.Add(v)
+		}
+		streamed, err := streaming.Finalize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if streamed.Median != tt.want {
+			t.Errorf("%v: streaming median %v, want %v", tt.values, streamed.Median, tt.want)
+		}
+
+		// The JSON field stays a number
+		data, err := json.Marshal(stats)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := fields["median"].(float64); !ok || got != tt.want {
+			t.Errorf("%v: median %#v in JSON, want the number %v", tt.values, fields["median"], tt.want)
+		}
+	}
+}
+
+func TestStatisticsWithAndWithoutAnomalies(t *testing.T) {
+	tests := []struct {
+		name      string
+		anomalies AnomalyConfig
+	}{
+		{"spikes", AnomalyConfig{Rate: 0.02, Multiplier: 3}},
+		{"level shifts", AnomalyConfig{Rate: 0.01, Delta: 50_000, LevelShifts: 1, Duration: 10}},
+		{"mixed", AnomalyConfig{Rate: 0.02, Multiplier: 2, LevelShifts: 0.5, Duration: 5}},
+	}
+	for _, tt := range tests {
+		seed := int64(50)
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.MinValue, config.MaxValue = 1, 1_000_000
+		plain, err := ChaoticTransactionSequence(5000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.Anomalies = &tt.anomalies
+		anomalous, err := ChaoticTransactionSequence(5000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The anomalies are laid over the process, so leaving them out
+		// leaves the entries of the run without them at the same steps
+		var unmarked []LogEntry
+		for i, entry := range anomalous {
+			if !entry.Anomaly {
+				unmarked = append(unmarked, plain[i])
+			}
+		}
+		excluded, err := ComputeStatistics(ExcludeAnomalies(anomalous))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ComputeStatistics(unmarked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(excluded, want) {
+			t.Errorf("%s: statistics without the anomalies\n%+v\ndiffer from those of the same steps of the plain run\n%+v", tt.name, excluded, want)
+		}
+
+		included, err := ComputeStatistics(anomalous)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plainStats, err := ComputeStatistics(plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if included.Count != plainStats.Count || included.Volatility <= plainStats.Volatility {
+			t.Errorf("%s: %d entries with volatility %.1f; the plain run has %d with %.1f", tt.name,
+				included.Count, included.Volatility, plainStats.Count, plainStats.Volatility)
+		}
+	}
+}
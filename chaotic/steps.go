@@ -0,0 +1,160 @@
+package chaotic
+
+import (
+	"fmt"
+	"math"
+)
+
+// StepContext is what a StepGenerator sees of the process when it computes
+// a chaotic step
+type StepContext struct {
+	Step        int           // the index of the entry being generated, from 2 on
+	Prev1       int           // the last value
+	Prev2       int           // the value before it
+	RunningMean float64       // the mean of the values so far
+	Chaos       float64       // the chaos factor of the step, in [-1, 1); it also scales the volatility term
+	RNG         RandSource    // the value source; draws from it are part of the run, so a seeded run reproduces them
+	Config      ChaoticConfig // with the Volatility, TrendStrength and MeanReversion of the active regime
+}
+
+// Round converts the fractional result of a step to a value. Plain values
+// are truncated; currency amounts are rounded with the currency's rounding
+// mode, so no fraction of a minor unit is silently dropped.
+func (c StepContext) Round(x float64) int {
+	return roundValue(x, c.Config.Currency)
+}
+
+// half returns d/2 as Round would: integer division truncates it, as it
+// always has for plain values, but a currency rounds the odd half unit
+func (c StepContext) half(d int) int {
+	if c.Config.Currency == nil || d%2 == 0 {
+		return d / 2
+	}
+	return c.Round(float64(d) / 2)
+}
+
+// StepGenerator is a rule for the chaotic steps, the entries from step 2 on.
+// Each step picks one generator by weight, and the value it returns then
+// gets the volatility term, any jump and the clamp to the range, as the
+// built-in rules do. The built-in rules are trend following, mean
+// reversion, multiplicative and additive noise, weighted by RegimeWeights;
+// ChaoticConfig.StepGenerators adds more alongside them.
+type StepGenerator interface {
+	// Name is the Type of the entries the generator produces
+	Name() string
+	// Generate returns the value of the step before the volatility term
+	Generate(ctx StepContext) int
+}
+
+// WeightedStep is a StepGenerator with the weight it is picked by, relative
+// to the RegimeWeights of the built-in rules: with nil RegimeWeights each
+// built-in rule weighs 1, so a weight of 1 picks the generator one step in
+// five.
+type WeightedStep struct {
+	Generator StepGenerator
+	Weight    float64 // not negative
+}
+
+// validateStepGenerators checks steps, the additional step generators of a
+// config, returning an error wrapping ErrInvalidStepGenerators if they are
+// not usable
+func validateStepGenerators(steps []WeightedStep) error {
+	names := make(map[string]bool, len(regimeTypes)+2+len(steps))
+	for _, name := range regimeTypes {
+		names[name] = true
+	}
+	names["initial"], names["random_walk"] = true, true
+	for i, step := range steps {
+		if step.Generator == nil {
+			return fmt.Errorf("%w: StepGenerators[%d] has no generator", ErrInvalidStepGenerators, i)
+		}
+		if !(step.Weight >= 0) || math.IsInf(step.Weight, 1) {
+			return fmt.Errorf("%w: StepGenerators[%d] weight %v must be a non-negative number", ErrInvalidStepGenerators, i, step.Weight)
+		}
+		name := step.Generator.Name()
+		if name == "" {
+			return fmt.Errorf("%w: StepGenerators[%d] has no name", ErrInvalidStepGenerators, i)
+		}
+		if names[name] {
+			return fmt.Errorf("%w: StepGenerators[%d] name %q is already taken", ErrInvalidStepGenerators, i, name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// stepPicker returns the step generators of config, the built-in ones first
+// in regimeTypes order, and the cumulative thresholds pickStepType selects
+// them by
+func stepPicker(config ChaoticConfig) ([]StepGenerator, []float64) {
+	steps := append([]StepGenerator(nil), builtinSteps[:]...)
+	if len(config.StepGenerators) == 0 {
+		thresholds := regimeThresholds(config.RegimeWeights)
+		return steps, thresholds[:]
+	}
+
+	weights := RegimeWeights{1, 1, 1, 1}
+	if config.RegimeWeights != nil {
+		weights = *config.RegimeWeights
+	}
+	all := weights.values()
+	combined := all[:]
+	for _, step := range config.StepGenerators {
+		steps = append(steps, step.Generator)
+		combined = append(combined, step.Weight)
+	}
+	return steps, cumulativeThresholds(combined)
+}
+
+// builtinSteps are the built-in step generators, indexed by the step type
+// constants
+var builtinSteps = [len(regimeTypes)]StepGenerator{
+	trendFollowing: trendFollowingStep{},
+	meanReversion:  meanReversionStep{},
+	multiplicative: multiplicativeStep{},
+	additiveNoise:  additiveNoiseStep{},
+}
+
+// trendFollowingStep continues the last change, scaled by TrendStrength
+type trendFollowingStep struct{}
+
+func (trendFollowingStep) Name() string { return regimeTypes[trendFollowing] }
+
+func (trendFollowingStep) Generate(ctx StepContext) int {
+	trend := subSat(ctx.Prev1, ctx.Prev2)
+	return addSat(addSat(ctx.Prev1, ctx.Round(float64(trend)*ctx.Config.TrendStrength)), ctx.Round(ctx.Chaos*float64(ctx.Prev1)*0.5))
+}
+
+// meanReversionStep pulls the value toward the running mean, or toward the
+// MeanReversionTarget when the config sets one
+type meanReversionStep struct{}
+
+func (meanReversionStep) Name() string { return regimeTypes[meanReversion] }
+
+func (meanReversionStep) Generate(ctx StepContext) int {
+	deviation, reversion := float64(ctx.Prev1)-ctx.RunningMean, ctx.Config.MeanReversion
+	if target := ctx.Config.MeanReversionTarget; target != nil {
+		deviation, reversion = float64(ctx.Prev1)-float64(*target), ctx.Config.ReversionSpeed
+	}
+	return addSat(subSat(ctx.Prev1, ctx.Round(deviation*reversion)), ctx.Round(ctx.Chaos*float64(ctx.Prev1)*0.3))
+}
+
+// multiplicativeStep scales the value by one of the MultiplicativeFactors
+type multiplicativeStep struct{}
+
+func (multiplicativeStep) Name() string { return regimeTypes[multiplicative] }
+
+func (multiplicativeStep) Generate(ctx StepContext) int {
+	factors := ctx.Config.MultiplicativeFactors
+	factor := factors[ctx.RNG.Intn(len(factors))]
+	return addSat(ctx.Round(float64(ctx.Prev1)*factor), ctx.Round(ctx.Chaos*10))
+}
+
+// additiveNoiseStep adds half the last change and uniform noise
+type additiveNoiseStep struct{}
+
+func (additiveNoiseStep) Name() string { return regimeTypes[additiveNoise] }
+
+func (additiveNoiseStep) Generate(ctx StepContext) int {
+	return addSat(addSat(ctx.Prev1, ctx.half(subSat(ctx.Prev1, ctx.Prev2))), drawNoise(ctx.RNG, ctx.Config.NoiseAmplitude))
+}
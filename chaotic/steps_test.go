@@ -0,0 +1,154 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestStrongReversionHugsTarget(t *testing.T) {
+	// The chaos term of a mean-reversion step moves the value by up to 30%
+	// of itself, so even full reversion leaves it about 15% of the target
+	// away on average
+	target := 300
+	for seed := int64(1); seed <= 3; seed++ {
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.Volatility = 0.1
+		config.RegimeWeights = &RegimeWeights{MeanReversion: 1}
+		free, err := ChaoticTransactionSequence(10_000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		config.MeanReversionTarget = &target
+		var distances []float64
+		for _, speed := range []float64{0.5, 0.9} {
+			config.ReversionSpeed = speed
+			sequence, err := ChaoticTransactionSequence(10_000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stats, err := ComputeStatistics(sequence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			distance := MeanTargetDistance(sequence, target)
+			if distance > 0.25*float64(target) {
+				t.Errorf("seed %d, speed %v: mean distance %.1f from the target %d", seed, speed, distance, target)
+			}
+			if !closeTo(stats.Mean, float64(target), 0.05*float64(target)) || !closeTo(stats.Median, float64(target), 0.05*float64(target)) {
+				t.Errorf("seed %d, speed %v: mean %.1f, median %.1f; want about %d", seed, speed, stats.Mean, stats.Median, target)
+			}
+			distances = append(distances, distance)
+		}
+		if distances[1] >= distances[0] {
+			t.Errorf("seed %d: faster reversion left the values further away, %.1f against %.1f", seed, distances[1], distances[0])
+		}
+		// Reverting to the running mean lets the level wander off
+		if d := MeanTargetDistance(free, target); d < 4*distances[1] {
+			t.Errorf("seed %d: without a target the values are only %.1f from it", seed, d)
+		}
+	}
+}
+
+func TestValidateReversionTarget(t *testing.T) {
+	config := DefaultConfig()
+	outside := config.MaxValue + 1
+	config.MeanReversionTarget = &outside
+	if err := config.Validate(); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("a target above the range: Validate returned %v, want ErrInvalidRange", err)
+	}
+	inside := config.MinValue
+	config.MeanReversionTarget = &inside
+	for _, speed := range []float64{-0.1, 1.1} {
+		config.ReversionSpeed = speed
+		if err := config.Validate(); !errors.Is(err, ErrReversionSpeedOutOfBounds) {
+			t.Errorf("speed %v: Validate returned %v, want ErrReversionSpeedOutOfBounds", speed, err)
+		}
+	}
+}
+
+// incrementStep is a custom step rule that adds one to the last value
+type incrementStep struct{}
+
+func (incrementStep) Name() string { return "increment" }
+
+func (incrementStep) Generate(ctx StepContext) int { return ctx.Prev1 + 1 }
+
+func TestCustomStepGenerator(t *testing.T) {
+	// Against the four built-in rules weighing 1 each, a weight of 4 picks
+	// the custom rule half the time
+	const n = 20_000
+	seed := int64(36)
+	config := DefaultConfig()
+	config.Seed = &seed
+	config.Volatility = 0
+	config.MinValue, config.MaxValue = 1, 1_000_000_000
+	config.StepGenerators = []WeightedStep{{Generator: incrementStep{}, Weight: 4}}
+	sequence, err := ChaoticTransactionSequence(n, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for i, entry := range sequence[2:] {
+		counts[entry.Type]++
+		// Without a volatility term the step's value is kept as is
+		if prev := sequence[i+1]; entry.Type == "increment" && entry.Clamped == "" && entry.Value != prev.Value+1 {
+			t.Fatalf("step %d: increment of %d gave %d", entry.Step, prev.Value, entry.Value)
+		}
+	}
+	if got := float64(counts["increment"]) / (n - 2); !closeTo(got, 0.5, 0.02) {
+		t.Errorf("the custom rule was picked %.4f of the time, want about 0.5", got)
+	}
+	for _, name := range regimeTypes {
+		if got := float64(counts[name]) / (n - 2); !closeTo(got, 0.125, 0.02) {
+			t.Errorf("%s was picked %.4f of the time, want about 0.125", name, got)
+		}
+	}
+
+	// A weight of 0 registers the rule without picking it
+	g, err := NewGenerator(WithSeed(seed), WithStepGenerator(incrementStep{}, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unweighted, err := g.Generate(2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config = DefaultConfig()
+	config.Seed = &seed
+	plain, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(valuesOf(unweighted), valuesOf(plain)) {
+		t.Error("a rule with weight 0 changed the run")
+	}
+}
+
+// namedStep is a step rule with a given name
+type namedStep string
+
+func (s namedStep) Name() string { return string(s) }
+
+func (namedStep) Generate(ctx StepContext) int { return ctx.Prev1 }
+
+func TestValidateStepGenerators(t *testing.T) {
+	for _, steps := range [][]WeightedStep{
+		{{Generator: nil, Weight: 1}},
+		{{Generator: incrementStep{}, Weight: -1}},
+		{{Generator: incrementStep{}, Weight: math.NaN()}},
+		{{Generator: namedStep(""), Weight: 1}},
+		{{Generator: namedStep("mean_reversion"), Weight: 1}},
+		{{Generator: namedStep("initial"), Weight: 1}},
+		{{Generator: incrementStep{}, Weight: 1}, {Generator: namedStep("increment"), Weight: 2}},
+	} {
+		config := DefaultConfig()
+		config.StepGenerators = steps
+		if err := config.Validate(); !errors.Is(err, ErrInvalidStepGenerators) {
+			t.Errorf("%+v: Validate returned %v, want ErrInvalidStepGenerators", steps, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package chaotic
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+)
+
+// maxExactDistinct is how many distinct values StreamingStats tallies
+// before it drops the tally and falls back to its P² estimates
+const maxExactDistinct = 1 << 16
+
+// StreamingStats accumulates statistics over values as they arrive, in
+// bounded memory, for sequences too long to hold in full. Mean and variance
+// are exact, from Welford's algorithm. The median and quartiles are exact
+// while the values take at most 65,536 distinct values, as they always do
+// over a range that size, from a tally of each value. Past that they are P²
+// estimates, typically within a small fraction of the spread when values
+// arrive in no particular order but far off after long excursions, such as
+// a run pinned at one bound for a while. The zero value is ready to use.
+type StreamingStats struct {
+	count     int
+	mean, m2  float64 // running mean and sum of squared deviations from it
+	min, max  int
+	prev      int
+	absDiffs  float64     // sum of |change| between consecutive values
+	up, down  int         // counts of rising and falling steps
+	tally     map[int]int // occurrences of each value, until inexact
+	inexact   bool        // too many distinct values were seen to tally
+	quartiles [3]p2Quantile
+}
+
+// Add feeds the next value of the sequence
+func (s *StreamingStats) Add(value int) {
+	if s.count == 0 {
+		s.min, s.max = value, value
+		for i, q := range []float64{0.25, 0.5, 0.75} {
+			s.quartiles[i].p = q
+		}
+	} else {
+		s.min, s.max = min(s.min, value), max(s.max, value)
+		change := float64(value) - float64(s.prev)
+		s.absDiffs += math.Abs(change)
+		switch {
+		case change > 0:
+			s.up++
+		case change < 0:
+			s.down++
+		}
+	}
+	s.prev = value
+
+	s.count++
+	delta := float64(value) - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (float64(value) - s.mean)
+	if !s.inexact {
+		if s.tally == nil {
+			s.tally = make(map[int]int)
+		}
+		s.tally[value]++
+		if len(s.tally) > maxExactDistinct {
+			s.tally, s.inexact = nil, true
+		}
+	}
+	for i := range s.quartiles {
+		s.quartiles[i].add(float64(value))
+	}
+}
+
+// Finalize returns the statistics of the values added so far: count, min,
+// max, mean, stdev, variance, coefficient_of_variation, median, q1, q3, iqr,
+// volatility and trend_strength, computed as ComputeStatistics does except
+// that the order statistics may be estimates. Every other field is left zero.
+// With no values added it returns an error. The accumulator can keep
+// accepting values afterwards.
+func (s *StreamingStats) Finalize() (Statistics, error) {
+	if s.count == 0 {
+		return Statistics{}, errors.New("no values added")
+	}
+
+	stats := Statistics{
+		Count: s.count,
+		Min:   s.min,
+		Max:   s.max,
+		Mean:  s.mean,
+	}
+	if s.count > 1 {
+		stats.Variance = s.m2 / float64(s.count-1)
+		stats.Stdev = math.Sqrt(stats.Variance)
+		stats.Volatility = s.absDiffs / float64(s.count-1)
+		if s.up+s.down > 0 {
+			stats.TrendStrength = math.Abs(float64(s.up-s.down)) / float64(s.up+s.down)
+		}
+	}
+	stats.CoefficientOfVariation = coefficientOfVariation(stats.Stdev, stats.Mean)
+	if s.inexact {
+		stats.Q1 = s.quartiles[0].quantile()
+		stats.Median = s.quartiles[1].quantile()
+		stats.Q3 = s.quartiles[2].quantile()
+	} else {
+		quartiles := tallyQuantiles(s.tally, s.count, 0.25, 0.5, 0.75)
+		stats.Q1, stats.Median, stats.Q3 = quartiles[0], quartiles[1], quartiles[2]
+	}
+	stats.IQR = stats.Q3 - stats.Q1
+	return stats, nil
+}
+
+// GenerateStatistics generates n steps with config and returns their
+// statistics without keeping the log, feeding each entry to a
+// StreamingStats as it is produced, so memory stays constant however long
+// the run. For a seeded config the values are those ChaoticTransactionSequence
+// would return; the statistics are StreamingStats.Finalize's subset.
+func GenerateStatistics(n int, config ChaoticConfig) (Statistics, error) {
+	return GenerateStatisticsCtx(context.Background(), n, config)
+}
+
+// GenerateStatisticsCtx is GenerateStatistics with cancellation, returning
+// ctx.Err() if ctx is cancelled part way through
+func GenerateStatisticsCtx(ctx context.Context, n int, config ChaoticConfig) (Statistics, error) {
+	var stats StreamingStats
+	err := GenerateWithCallbackCtx(ctx, n, config, func(entry LogEntry) error {
+		if !entry.Missing {
+			stats.Add(entry.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return Statistics{}, err
+	}
+	return stats.Finalize()
+}
+
+// tallyQuantiles returns the quantiles qs of the n values counted in tally,
+// interpolated between ranks exactly as Quantiles does
+func tallyQuantiles(tally map[int]int, n int, qs ...float64) []float64 {
+	distinct := make([]int, 0, len(tally))
+	for v := range tally {
+		distinct = append(distinct, v)
+	}
+	sort.Ints(distinct)
+	// ends[i] is the rank just past the last copy of distinct[i]
+	ends := make([]int, len(distinct))
+	rank := 0
+	for i, v := range distinct {
+		rank += tally[v]
+		ends[i] = rank
+	}
+	at := func(rank int) float64 { return float64(distinct[sort.SearchInts(ends, rank+1)]) }
+
+	result := make([]float64, len(qs))
+	for i, q := range qs {
+		pos := min(max(q, 0), 1) * float64(n-1)
+		lower := int(pos)
+		result[i] = at(lower)
+		if weight := pos - float64(lower); weight > 0 {
+			result[i] = at(lower)*(1-weight) + at(lower+1)*weight
+		}
+	}
+	return result
+}
+
+// p2Quantile estimates the p quantile of a stream in constant memory with
+// the P² algorithm of Jain and Chlamtac: five markers track the minimum,
+// the p/2, p and (1+p)/2 quantiles and the maximum, and are nudged towards
+// their ideal ranks with a piecewise-parabolic fit as values arrive. Until
+// the fifth value the markers simply hold the values seen.
+type p2Quantile struct {
+	p      float64
+	height [5]float64
+	rank   [5]int // 0-based rank of each marker among the values seen
+	count  int
+}
+
+// add feeds x to the estimator
+func (e *p2Quantile) add(x float64) {
+	if e.count < len(e.height) {
+		e.height[e.count] = x
+		e.count++
+		if e.count == len(e.height) {
+			sort.Float64s(e.height[:])
+			for i := range e.rank {
+				e.rank[i] = i
+			}
+		}
+		return
+	}
+
+	// Find the cell x falls in, stretching the extremes if needed
+	var k int
+	switch {
+	case x < e.height[0]:
+		e.height[0] = x
+	case x >= e.height[4]:
+		e.height[4] = x
+		k = 3
+	default:
+		for k = 0; x >= e.height[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < len(e.rank); i++ {
+		e.rank[i]++
+	}
+	e.count++
+
+	increments := [5]float64{0, e.p / 2, e.p, (1 + e.p) / 2, 1}
+	for i := 1; i <= 3; i++ {
+		desired := float64(e.count-1) * increments[i]
+		d := desired - float64(e.rank[i])
+		if !(d >= 1 && e.rank[i+1]-e.rank[i] > 1) && !(d <= -1 && e.rank[i-1]-e.rank[i] < -1) {
+			continue
+		}
+		step := 1
+		if d < 0 {
+			step = -1
+		}
+		if height := e.parabolic(i, float64(step)); e.height[i-1] < height && height < e.height[i+1] {
+			e.height[i] = height
+		} else {
+			e.height[i] += float64(step) * (e.height[i+step] - e.height[i]) / float64(e.rank[i+step]-e.rank[i])
+		}
+		e.rank[i] += step
+	}
+}
+
+// parabolic is the P² piecewise-parabolic prediction for marker i moved by d
+func (e *p2Quantile) parabolic(i int, d float64) float64 {
+	q, n := e.height, e.rank
+	below, above := float64(n[i]-n[i-1]), float64(n[i+1]-n[i])
+	return q[i] + d/float64(n[i+1]-n[i-1])*
+		((below+d)*(q[i+1]-q[i])/above+(above-d)*(q[i]-q[i-1])/below)
+}
+
+// quantile returns the current estimate, exact until five values have been seen
+func (e *p2Quantile) quantile() float64 {
+	if e.count > len(e.height) {
+		return e.height[2]
+	}
+	if e.count == 0 {
+		return 0
+	}
+	seen := append([]float64(nil), e.height[:e.count]...)
+	sort.Float64s(seen)
+	return Quantiles(seen, e.p)[0]
+}
@@ -0,0 +1,139 @@
+package chaotic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStreamingStatsMatchesExactStatistics(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3} {
+		config := DefaultConfig()
+		config.Seed = &seed
+		sequence, err := ChaoticTransactionSequence(20000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exact, err := ComputeStatistics(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var streaming StreamingStats
+		for _, entry := range sequence {
+			streaming.Add(entry.Value)
+		}
+		got, err := streaming.Finalize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Count != exact.Count || got.Min != exact.Min || got.Max != exact.Max {
+			t.Errorf("seed %d: count, min, max %d, %d, %d; want %d, %d, %d",
+				seed, got.Count, got.Min, got.Max, exact.Count, exact.Min, exact.Max)
+		}
+		for name, pair := range map[string][2]float64{
+			"mean":           {got.Mean, exact.Mean},
+			"variance":       {got.Variance, exact.Variance},
+			"volatility":     {got.Volatility, exact.Volatility},
+			"trend_strength": {got.TrendStrength, exact.TrendStrength},
+		} {
+			if !closeTo(pair[0], pair[1], 1e-9*math.Max(1, math.Abs(pair[1]))) {
+				t.Errorf("seed %d: %s %v, want %v", seed, name, pair[0], pair[1])
+			}
+		}
+		// Within 1..1000 the values are tallied and the quartiles exact
+		if got.Q1 != exact.Q1 || got.Median != exact.Median || got.Q3 != exact.Q3 {
+			t.Errorf("seed %d: quartiles %v, %v, %v; want %v, %v, %v", seed,
+				got.Q1, got.Median, got.Q3, exact.Q1, exact.Median, exact.Q3)
+		}
+	}
+}
+
+func TestStreamingStatsEstimatesWideRanges(t *testing.T) {
+	// Far more distinct values than are tallied, so the quartiles are P²
+	// estimates
+	values := uniformInts(200_000, 15, 0, 1_000_000_000)
+	var streaming StreamingStats
+	for _, v := range values {
+		streaming.Add(v)
+	}
+	got, err := streaming.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exact, err := ComputeStatistics(entriesOf(values...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(got.Mean, exact.Mean, 1e-6*exact.Mean) || !closeTo(got.Stdev, exact.Stdev, 1e-6*exact.Stdev) {
+		t.Errorf("mean %v, stdev %v; want %v, %v", got.Mean, got.Stdev, exact.Mean, exact.Stdev)
+	}
+	tolerance := 0.01 * float64(exact.Max-exact.Min)
+	for name, pair := range map[string][2]float64{
+		"q1":     {got.Q1, exact.Q1},
+		"median": {got.Median, exact.Median},
+		"q3":     {got.Q3, exact.Q3},
+	} {
+		if !closeTo(pair[0], pair[1], tolerance) {
+			t.Errorf("%s %v, want %v within %v", name, pair[0], pair[1], tolerance)
+		}
+	}
+}
+
+func TestStreamingStatsOfFewValues(t *testing.T) {
+	values := []int{3, 1, 4, 1, 5}
+	var streaming StreamingStats
+	for _, v := range values {
+		streaming.Add(v)
+	}
+	got, err := streaming.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exact, err := ComputeStatistics(entriesOf(values...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Q1 != exact.Q1 || got.Median != exact.Median || got.Q3 != exact.Q3 {
+		t.Errorf("quartiles %v, %v, %v; want %v, %v, %v", got.Q1, got.Median, got.Q3, exact.Q1, exact.Median, exact.Q3)
+	}
+
+	var one StreamingStats
+	one.Add(8)
+	single, err := one.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.Mean != 8 || single.Stdev != 0 || single.Median != 8 {
+		t.Errorf("one value: %+v", single)
+	}
+
+	var empty StreamingStats
+	if _, err := empty.Finalize(); err == nil {
+		t.Error("Finalize with no values succeeded")
+	}
+}
+
+func TestGenerateStatisticsMatchesStoredRun(t *testing.T) {
+	seed := int64(14)
+	config := DefaultConfig()
+	config.Seed = &seed
+	got, err := GenerateStatistics(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sequence, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streaming StreamingStats
+	for _, entry := range sequence {
+		streaming.Add(entry.Value)
+	}
+	want, err := streaming.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != want.Count || got.Mean != want.Mean || got.Median != want.Median || got.Stdev != want.Stdev {
+		t.Errorf("GenerateStatistics %+v\ndiffers from the stored run's %+v", got, want)
+	}
+}
@@ -0,0 +1,294 @@
+package chaotic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ArrivalModel selects how the gaps between consecutive timestamps are drawn
+type ArrivalModel string
+
+// Arrival models for Timing
+const (
+	ArrivalsFixed   ArrivalModel = "fixed"   // Interval apart, spread by Jitter (the default)
+	ArrivalsPoisson ArrivalModel = "poisson" // exponential gaps with mean Interval, as a Poisson process
+)
+
+// hoursPerDay is the length of Timing.HourlyRates
+const hoursPerDay = 24
+
+// Timing gives each generated entry a timestamp. Step 0 is stamped Start and
+// every later step follows the one before by a gap drawn by the Arrivals
+// model: fixed arrivals are Interval apart, scaled by a uniform factor
+// between 1-Jitter and 1+Jitter, while Poisson arrivals have exponentially
+// distributed gaps with mean Interval, sampled by inverting their CDF.
+// HourlyRates can make the Poisson rate follow a daily curve. Gaps are never
+// negative, so timestamps never decrease. Timestamps are written in UTC
+// whatever the location of Start.
+//
+// The draws behind the gaps do not come from the value source, so adding
+// timing to a configuration leaves its values unchanged. With a Seed they
+// are derived from the seed, making the timestamps as reproducible as the
+// values; with an explicit Source they are fixed; otherwise they come from
+// a source of the configured RandomnessMode, crypto/rand by default.
+type Timing struct {
+	Start    time.Time     // timestamp of step 0
+	Interval time.Duration // mean gap between consecutive steps, positive
+	Jitter   float64       // 0.0 to 1.0 - how far a fixed gap may stray from Interval, as a fraction of it
+
+	Arrivals ArrivalModel `json:",omitempty"` // fixed when empty
+	// HourlyRates multiplies the Poisson rate during each hour of the day,
+	// midnight to midnight UTC, so {..., 0, ...} makes an hour with no
+	// arrivals and 2 one with twice as many. Nil keeps the rate constant.
+	HourlyRates []float64 `json:",omitempty"`
+}
+
+// Validate reports whether the timing is usable, returning an error wrapping
+// ErrInvalidTiming if not
+func (t Timing) Validate() error {
+	if t.Interval <= 0 {
+		return fmt.Errorf("%w: Interval %v must be positive", ErrInvalidTiming, t.Interval)
+	}
+	if !(t.Jitter >= 0 && t.Jitter <= 1) {
+		return fmt.Errorf("%w: Jitter %v must be between 0.0 and 1.0", ErrInvalidTiming, t.Jitter)
+	}
+	switch t.Arrivals {
+	case "", ArrivalsFixed:
+		if t.HourlyRates != nil {
+			return fmt.Errorf("%w: HourlyRates need poisson arrivals", ErrInvalidTiming)
+		}
+	case ArrivalsPoisson:
+	default:
+		return fmt.Errorf("%w: unknown arrival model %q", ErrInvalidTiming, t.Arrivals)
+	}
+	if t.HourlyRates == nil {
+		return nil
+	}
+	if len(t.HourlyRates) != hoursPerDay {
+		return fmt.Errorf("%w: HourlyRates has %d entries, not %d", ErrInvalidTiming, len(t.HourlyRates), hoursPerDay)
+	}
+	var total float64
+	for hour, rate := range t.HourlyRates {
+		if !(rate >= 0) || math.IsInf(rate, 1) {
+			return fmt.Errorf("%w: HourlyRates[%d] %v must be finite and not negative", ErrInvalidTiming, hour, rate)
+		}
+		total += rate
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: HourlyRates must allow arrivals in some hour", ErrInvalidTiming)
+	}
+	return nil
+}
+
+// after returns the arrival following one at prev, given a uniform draw u
+// from [0, 1)
+func (t Timing) after(prev time.Time, u float64) time.Time {
+	if t.Arrivals != ArrivalsPoisson {
+		return prev.Add(scaleDuration(t.Interval, 1+t.Jitter*(2*u-1)))
+	}
+	// Inverse CDF of the exponential distribution, in multiples of Interval
+	work := -math.Log1p(-u)
+	if t.HourlyRates == nil {
+		return prev.Add(scaleDuration(t.Interval, work))
+	}
+	return t.spend(prev, work)
+}
+
+// spend returns the time at which a Poisson process with the hourly rate
+// curve, started at from, has done the given amount of work: the integral
+// of the rate multiplier over time, measured in Intervals. Whole days are
+// skipped in one step, so long gaps cost no more than short ones.
+func (t Timing) spend(from time.Time, work float64) time.Time {
+	interval := float64(t.Interval)
+	daily := t.dailyWork()
+	now := from.UTC()
+	for {
+		if now.Hour() == 0 && now.Equal(now.Truncate(time.Hour)) && work >= daily {
+			days := min(math.Floor(work/daily), math.MaxInt32)
+			now = now.AddDate(0, 0, int(days))
+			work -= days * daily
+			continue
+		}
+		rate := t.HourlyRates[now.Hour()]
+		end := now.Truncate(time.Hour).Add(time.Hour)
+		available := rate * float64(end.Sub(now)) / interval
+		if work < available {
+			return now.Add(time.Duration(work / rate * interval))
+		}
+		work -= available
+		now = end
+	}
+}
+
+// expectedArrivals returns how many arrivals the timing expects in the span
+// from start to end, the integral of its rate
+func (t Timing) expectedArrivals(start, end time.Time) float64 {
+	span := float64(end.Sub(start))
+	interval := float64(t.Interval)
+	if t.Arrivals != ArrivalsPoisson || t.HourlyRates == nil {
+		return span / interval
+	}
+
+	var total float64
+	now := start.UTC()
+	if days := math.Floor(span / float64(24*time.Hour)); days > 0 {
+		// Any run of whole days covers each hour of the day once a day
+		total = days * t.dailyWork()
+		now = now.Add(time.Duration(days) * 24 * time.Hour)
+	}
+	for now.Before(end) {
+		next := now.Truncate(time.Hour).Add(time.Hour)
+		if next.After(end) {
+			next = end
+		}
+		total += t.HourlyRates[now.Hour()] * float64(next.Sub(now)) / interval
+		now = next
+	}
+	return total
+}
+
+// dailyWork returns the expected number of arrivals in a whole day under
+// the hourly rate curve
+func (t Timing) dailyWork() float64 {
+	var total float64
+	for _, rate := range t.HourlyRates {
+		total += rate
+	}
+	return total * float64(time.Hour) / float64(t.Interval)
+}
+
+// scaleDuration returns d scaled by a non-negative factor, saturating
+// instead of overflowing
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	scaled := float64(d) * factor
+	if scaled >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(scaled)
+}
+
+// timingStream separates the timing draws from other uses of a seed
+const timingStream = 0x74696d696e67 // "timing"
+
+// arrivalClock stamps the entries of one timed run
+type arrivalClock struct {
+	timing Timing
+	draws  sideDraws
+	now    time.Time // timestamp of the last entry
+}
+
+// newArrivalClock prepares the clock of a run with config, which must have
+// a Timing
+func newArrivalClock(config ChaoticConfig) *arrivalClock {
+	return &arrivalClock{timing: *config.Timing, draws: newSideDraws(config, timingStream)}
+}
+
+// stamp returns the timestamp of step, which must follow the last one stamped
+func (c *arrivalClock) stamp(step int) time.Time {
+	if step == 0 {
+		c.now = c.timing.Start
+	} else {
+		c.now = c.timing.after(c.now, c.draws.uniform(step))
+	}
+	return c.now
+}
+
+// formatTimestamp renders a timestamp as LogEntry.Timestamp holds it
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// parseTimestamps returns the timestamps of the first and last entries of
+// the sequence, and false when either has none
+func parseTimestamps(sequence []LogEntry) (time.Time, time.Time, bool, error) {
+	first, last := sequence[0], sequence[len(sequence)-1]
+	if first.Timestamp == "" || last.Timestamp == "" {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	start, err := time.Parse(time.RFC3339Nano, first.Timestamp)
+	if err != nil {
+		return start, start, false, fmt.Errorf("step %d: invalid timestamp: %w", first.Step, err)
+	}
+	end, err := time.Parse(time.RFC3339Nano, last.Timestamp)
+	if err != nil {
+		return start, end, false, fmt.Errorf("step %d: invalid timestamp: %w", last.Step, err)
+	}
+	return start, end, true, nil
+}
+
+// calculateTiming returns the seconds from the first timestamp of the
+// sequence to the last and the mean rate of steps over that span, one per
+// gap between consecutive entries. Both are 0 when the entries carry no
+// timestamps or all share one.
+func calculateTiming(sequence []LogEntry) (float64, float64, error) {
+	start, end, ok, err := parseTimestamps(sequence)
+	if !ok {
+		return 0, 0, err
+	}
+	duration := end.Sub(start).Seconds()
+	if duration <= 0 {
+		return 0, 0, nil
+	}
+	return duration, float64(len(sequence)-1) / duration, nil
+}
+
+// ArrivalStatistics compares the gaps between the timestamps of a sequence
+// with the timing that produced them. Times are in seconds.
+type ArrivalStatistics struct {
+	Model             ArrivalModel `json:"model"`
+	InterArrivalMean  float64      `json:"inter_arrival_mean"`
+	InterArrivalStdev float64      `json:"inter_arrival_stdev"` // sample standard deviation
+	ExpectedMean      float64      `json:"expected_mean"`       // the mean gap the timing expects over the same span
+	// RateDeviation is the relative deviation of the observed arrival rate
+	// from the configured one: 0.05 means 5% more arrivals than expected
+	RateDeviation float64 `json:"rate_deviation"`
+}
+
+// ComputeArrivalStatistics summarizes the gaps between the timestamps of
+// sequence, generated with timing. The sequence needs at least two entries,
+// all timestamped, and must span some time.
+func ComputeArrivalStatistics(sequence []LogEntry, timing Timing) (ArrivalStatistics, error) {
+	if err := timing.Validate(); err != nil {
+		return ArrivalStatistics{}, err
+	}
+	if len(sequence) < 2 {
+		return ArrivalStatistics{}, fmt.Errorf("need at least 2 entries, have %d", len(sequence))
+	}
+
+	gaps := make([]float64, len(sequence)-1)
+	var prev time.Time
+	for i, entry := range sequence {
+		if entry.Timestamp == "" {
+			return ArrivalStatistics{}, fmt.Errorf("step %d has no timestamp", entry.Step)
+		}
+		now, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			return ArrivalStatistics{}, fmt.Errorf("step %d: invalid timestamp: %w", entry.Step, err)
+		}
+		if i > 0 {
+			gaps[i-1] = now.Sub(prev).Seconds()
+		}
+		prev = now
+	}
+	start, end, _, _ := parseTimestamps(sequence)
+	expected := timing.expectedArrivals(start, end)
+	if !end.After(start) || expected == 0 {
+		return ArrivalStatistics{}, errors.New("the timestamps span no time in which arrivals are expected")
+	}
+
+	model := timing.Arrivals
+	if model == "" {
+		model = ArrivalsFixed
+	}
+	mean := calculateMean(gaps)
+	observed := float64(len(gaps))
+	return ArrivalStatistics{
+		Model:             model,
+		InterArrivalMean:  mean,
+		InterArrivalStdev: calculateStdev(gaps, mean),
+		ExpectedMean:      end.Sub(start).Seconds() / expected,
+		RateDeviation:     observed/expected - 1,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPoissonGapsAreExponential(t *testing.T) {
+	// The mean and standard deviation of an exponential distribution are
+	// both its scale; 200k draws pin each to well under 1%
+	timing := Timing{Interval: 3 * time.Second, Arrivals: ArrivalsPoisson}
+	rng := rand.New(rand.NewSource(1))
+	const draws = 200_000
+	gaps := make([]float64, draws)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range gaps {
+		gaps[i] = timing.after(start, rng.Float64()).Sub(start).Seconds()
+	}
+	mean := calculateMean(gaps)
+	if !closeTo(mean, 3, 0.03) {
+		t.Errorf("mean gap %.4fs, want about 3s", mean)
+	}
+	if stdev := calculateStdev(gaps, mean); !closeTo(stdev, 3, 0.05) {
+		t.Errorf("gap standard deviation %.4fs, want about 3s", stdev)
+	}
+	// About e^-1 of the gaps are longer than the mean
+	longer := 0
+	for _, gap := range gaps {
+		if gap > 3 {
+			longer++
+		}
+	}
+	if share := float64(longer) / draws; !closeTo(share, math.Exp(-1), 0.005) {
+		t.Errorf("%.4f of gaps exceed the mean, want about %.4f", share, math.Exp(-1))
+	}
+}
+
+func TestArrivalStatisticsOfSeededRuns(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	daytime := make([]float64, hoursPerDay)
+	for hour := range daytime {
+		if hour >= 8 && hour < 20 {
+			daytime[hour] = 2
+		}
+	}
+	tests := []struct {
+		name   string
+		timing Timing
+	}{
+		{"fixed", Timing{Start: start, Interval: time.Minute, Jitter: 0.5}},
+		{"poisson", Timing{Start: start, Interval: time.Minute, Arrivals: ArrivalsPoisson}},
+		{"daytime", Timing{Start: start, Interval: time.Minute, Arrivals: ArrivalsPoisson, HourlyRates: daytime}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed := int64(24)
+			config := DefaultConfig()
+			config.Seed = &seed
+			config.Timing = &tt.timing
+			sequence, err := ChaoticTransactionSequence(20_000, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stats, err := ComputeArrivalStatistics(sequence, tt.timing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 20k gaps put the rate within a few percent of the configured one
+			if math.Abs(stats.RateDeviation) > 0.03 {
+				t.Errorf("rate deviation %.4f, want near 0", stats.RateDeviation)
+			}
+			if !closeTo(stats.InterArrivalMean, stats.ExpectedMean, 0.03*stats.ExpectedMean) {
+				t.Errorf("mean gap %.2fs, want about %.2fs", stats.InterArrivalMean, stats.ExpectedMean)
+			}
+			if tt.timing.HourlyRates == nil {
+				return
+			}
+			// At twice the rate for half the day, the mean gap is unchanged
+			// but no arrival falls at night
+			for _, entry := range sequence[1:] {
+				stamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hour := stamp.Hour(); hour < 8 || hour >= 20 {
+					t.Fatalf("step %d arrived at %s, in an hour with no arrivals", entry.Step, entry.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTiming(t *testing.T) {
+	tests := []struct {
+		name   string
+		timing Timing
+	}{
+		{"no interval", Timing{}},
+		{"jitter", Timing{Interval: time.Second, Jitter: 1.5}},
+		{"unknown model", Timing{Interval: time.Second, Arrivals: "bursty"}},
+		{"fixed with rates", Timing{Interval: time.Second, HourlyRates: make([]float64, hoursPerDay)}},
+		{"short rates", Timing{Interval: time.Second, Arrivals: ArrivalsPoisson, HourlyRates: []float64{1}}},
+		{"no arrivals", Timing{Interval: time.Second, Arrivals: ArrivalsPoisson, HourlyRates: make([]float64, hoursPerDay)}},
+	}
+	for _, tt := range tests {
+		if err := tt.timing.Validate(); !errors.Is(err, ErrInvalidTiming) {
+			t.Errorf("%s: Validate returned %v, want ErrInvalidTiming", tt.name, err)
+		}
+	}
+}
+
+func TestTimestampsNeverGoBackwards(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// At full jitter a gap runs from nothing at u = 0 to twice the interval
+	timing := Timing{Start: start, Interval: time.Second, Jitter: 1}
+	if got := timing.after(start, 0); !got.Equal(start) {
+		t.Errorf("u = 0 moved the time to %s", got)
+	}
+	if got := timing.after(start, math.Nextafter(1, 0)); got.Sub(start) >= 2*time.Second || got.Sub(start) < 2*time.Second-time.Nanosecond {
+		t.Errorf("u just under 1 moved the time by %s, want just under 2s", got.Sub(start))
+	}
+
+	// A few nanoseconds between entries round many gaps to nothing
+	for _, interval := range []time.Duration{3 * time.Nanosecond, time.Millisecond} {
+		seed := int64(55)
+		config := DefaultConfig()
+		config.Seed = &seed
+		config.Timing = &Timing{Start: start, Interval: interval, Jitter: 1}
+		sequence, err := ChaoticTransactionSequence(20_000, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev := start
+		for _, entry := range sequence {
+			stamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stamp.Before(prev) {
+				t.Fatalf("interval %s: step %d at %s precedes %s", interval, entry.Step, entry.Timestamp, prev.Format(time.RFC3339Nano))
+			}
+			prev = stamp
+		}
+		stats, err := ComputeStatistics(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := prev.Sub(start).Seconds(); stats.Duration != want || !(stats.EventsPerSecond > 0) {
+			t.Errorf("interval %s: duration %vs, want %vs; %v events per second", interval, stats.Duration, want, stats.EventsPerSecond)
+		}
+	}
+}
+
+func TestPoissonSkipsZeroRateHours(t *testing.T) {
+	// Arrivals only from 03:00 to 04:00, an Interval's work each day
+	rates := make([]float64, hoursPerDay)
+	rates[3] = 1
+	timing := Timing{Interval: time.Hour, Arrivals: ArrivalsPoisson, HourlyRates: rates}
+	day := func(d, hour, minute int) time.Time { return time.Date(2024, 5, d, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		from time.Time
+		work float64
+		want time.Time
+	}{
+		{day(1, 3, 15), 0.25, day(1, 3, 30)},
+		{day(1, 3, 45), 0.5, day(2, 3, 15)},
+		{day(1, 4, 0), 0.5, day(2, 3, 30)},
+		// Whole days of work are skipped from midnight, and an arrival
+		// due in a zero-rate hour waits for the next hour with arrivals
+		{day(1, 4, 0), 2.5, day(4, 3, 30)},
+		{day(1, 0, 0), 3, day(4, 3, 0)},
+	}
+	for _, tt := range tests {
+		if got := timing.spend(tt.from, tt.work); !got.Equal(tt.want) {
+			t.Errorf("%v of work from %s ends at %s, want %s", tt.work, tt.from.Format(time.Kitchen), got, tt.want)
+		}
+	}
+
+	seed := int64(56)
+	config := DefaultConfig()
+	config.Seed = &seed
+	timing.Start, timing.Interval = day(1, 12, 0), time.Minute
+	config.Timing = &timing
+	sequence, err := ChaoticTransactionSequence(2000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := timing.Start
+	for _, entry := range sequence[1:] {
+		stamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stamp.Hour() != 3 || stamp.Before(prev) {
+			t.Fatalf("step %d arrived at %s after %s", entry.Step, entry.Timestamp, prev.Format(time.RFC3339Nano))
+		}
+		prev = stamp
+	}
+}
@@ -0,0 +1,120 @@
+package chaotic
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Power iteration limits for StationaryDistribution
+const (
+	stationaryMaxIterations = 10000
+	stationaryTolerance     = 1e-12
+)
+
+// TransitionMatrix returns the empirical probability of each step type being
+// followed by each other type: matrix[from][to] is the fraction of entries
+// of type from whose next entry has type to. Every type in log has a row,
+// but a type that only appears as the last entry has no observed
+// transitions and its row is empty rather than NaN. Types are taken as
+// written, including the generator's one-off initial and random_walk.
+func TransitionMatrix(log []LogEntry) (map[string]map[string]float64, error) {
+	if len(log) < 2 {
+		return nil, errors.New("need at least 2 entries to observe a transition")
+	}
+
+	counts := make(map[string]map[string]int)
+	for _, entry := range log {
+		if counts[entry.Type] == nil {
+			counts[entry.Type] = make(map[string]int)
+		}
+	}
+	for i := 1; i < len(log); i++ {
+		counts[log[i-1].Type][log[i].Type]++
+	}
+
+	matrix := make(map[string]map[string]float64, len(counts))
+	for from, row := range counts {
+		total := 0
+		for _, count := range row {
+			total += count
+		}
+		matrix[from] = make(map[string]float64, len(row))
+		for to, count := range row {
+			matrix[from][to] = float64(count) / float64(total)
+		}
+	}
+	return matrix, nil
+}
+
+// StationaryDistribution estimates the long-run share of time spent in each
+// state of a transition matrix such as TransitionMatrix returns, by power
+// iteration. A state with an empty row is treated as jumping to every state
+// with equal probability, so no probability leaks away, and the chain is
+// made lazy (staying put half the time, which leaves the answer unchanged)
+// so that periodic chains still converge. States that are only passed
+// through, like the generator's first two step types, end up near 0.
+func StationaryDistribution(matrix map[string]map[string]float64) (map[string]float64, error) {
+	states := make([]string, 0, len(matrix))
+	seen := make(map[string]bool)
+	for from, row := range matrix {
+		if !seen[from] {
+			seen[from] = true
+			states = append(states, from)
+		}
+		for to := range row {
+			if !seen[to] {
+				seen[to] = true
+				states = append(states, to)
+			}
+		}
+	}
+	if len(states) == 0 {
+		return nil, errors.New("empty transition matrix")
+	}
+	sort.Strings(states)
+	index := make(map[string]int, len(states))
+	for i, state := range states {
+		index[state] = i
+	}
+
+	n := len(states)
+	dist := make([]float64, n)
+	next := make([]float64, n)
+	for i := range dist {
+		dist[i] = 1 / float64(n)
+	}
+	for iter := 0; iter < stationaryMaxIterations; iter++ {
+		for i := range next {
+			next[i] = dist[i] / 2
+		}
+		for i, from := range states {
+			mass := dist[i] / 2
+			row := matrix[from]
+			if len(row) == 0 {
+				for j := range next {
+					next[j] += mass / float64(n)
+				}
+				continue
+			}
+			for to, p := range row {
+				next[index[to]] += mass * p
+			}
+		}
+
+		var change float64
+		for i := range dist {
+			change += math.Abs(next[i] - dist[i])
+		}
+		dist, next = next, dist
+		if change < stationaryTolerance {
+			break
+		}
+	}
+
+	result := make(map[string]float64, n)
+	for i, state := range states {
+		result[state] = dist[i]
+	}
+	return result, nil
+}
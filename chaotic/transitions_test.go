@@ -0,0 +1,101 @@
+package chaotic
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// typed returns a sequence with the given step types
+func typed(types ...string) []LogEntry {
+	log := make([]LogEntry, len(types))
+	for i, name := range types {
+		log[i] = LogEntry{Step: i, Value: 1, Type: name}
+	}
+	return log
+}
+
+func TestTransitionMatrix(t *testing.T) {
+	tests := []struct {
+		name string
+		log  []LogEntry
+		want map[string]map[string]float64
+	}{
+		{
+			"alternating",
+			typed("a", "b", "a", "b", "a"),
+			map[string]map[string]float64{"a": {"b": 1}, "b": {"a": 1}},
+		},
+		{
+			"last type seen once",
+			typed("a", "a", "b", "a", "c"),
+			map[string]map[string]float64{"a": {"a": 1.0 / 3, "b": 1.0 / 3, "c": 1.0 / 3}, "b": {"a": 1}, "c": {}},
+		},
+		{
+			"two entries",
+			typed("a", "b"),
+			map[string]map[string]float64{"a": {"b": 1}, "b": {}},
+		},
+	}
+	for _, tt := range tests {
+		matrix, err := TransitionMatrix(tt.log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(matrix, tt.want) {
+			t.Errorf("%s: matrix %v, want %v", tt.name, matrix, tt.want)
+		}
+	}
+
+	if _, err := TransitionMatrix(typed("a")); err == nil {
+		t.Error("a single entry succeeded")
+	}
+}
+
+func TestTransitionMatrixHasNoNaNRows(t *testing.T) {
+	seed := int64(59)
+	config := DefaultConfig()
+	config.Seed = &seed
+	sequence, err := ChaoticTransactionSequence(5000, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ending on a type seen nowhere else leaves it no transitions
+	sequence = append(sequence, LogEntry{Step: len(sequence), Value: 1, Type: "custom"})
+	matrix, err := TransitionMatrix(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for from, row := range matrix {
+		total := 0.0
+		for to, p := range row {
+			if math.IsNaN(p) || p < 0 || p > 1 {
+				t.Errorf("%s to %s: probability %v", from, to, p)
+			}
+			total += p
+		}
+		if from == "custom" {
+			if len(row) != 0 {
+				t.Errorf("the last type has transitions %v", row)
+			}
+		} else if !closeTo(total, 1, 1e-12) {
+			t.Errorf("row %s sums to %v", from, total)
+		}
+	}
+
+	dist, err := StationaryDistribution(matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0.0
+	for state, p := range dist {
+		if math.IsNaN(p) {
+			t.Errorf("%s: stationary probability NaN", state)
+		}
+		total += p
+	}
+	// The four step types are drawn with equal weights
+	if !closeTo(total, 1, 1e-9) || !closeTo(dist["trend_following"], 0.25, 0.02) {
+		t.Errorf("stationary distribution %v", dist)
+	}
+}
@@ -1,417 +1,1015 @@
 package main
 
 import (
-	"crypto/rand"
-	"encoding/binary"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"math"
-	"math/big"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
+
+	"github.com/AScotM/chaotic_sequencer/chaotic"
 )
 
-// ChaoticConfig holds configuration for chaotic sequence generation
-type ChaoticConfig struct {
-	Volatility    float64 // 0.0 to 1.0 - how chaotic the sequence is
-	TrendStrength float64 // 0.0 to 1.0 - tendency to follow trends
-	MeanReversion float64 // 0.0 to 1.0 - tendency to revert to mean
-	MinValue      int
-	MaxValue      int
-}
+// Bollinger band parameters used by deep analysis
+const (
+	bollingerWindow = 20
+	bollingerK      = 2.0
+)
+
+// rsiPeriod is the RSI lookback exported by -indicators, Wilder's standard 14 steps
+const rsiPeriod = 14
 
-// DefaultConfig returns a sensible default configuration
-func DefaultConfig() ChaoticConfig {
-	return ChaoticConfig{
-		Volatility:    0.7,
-		TrendStrength: 0.3,
-		MeanReversion: 0.2,
-		MinValue:      1,
-		MaxValue:      1000,
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		if err := compareFiles(os.Stdout, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stdout, "Error comparing runs: %v\n", err)
+		}
+		return
 	}
-}
 
-// secureRandIntn generates cryptographically secure random numbers
-func secureRandIntn(n int) int {
-	if n <= 0 {
-		return 0
+	format := flag.String("format", "json", "output format: json, csv, ndjson, parquet or cbor")
+	out := flag.String("out", "", "output file, or - for stdout (default chaotic_transaction_analysis.<format>)")
+	sqlitePath := flag.String("sqlite", "", "also store the run in this SQLite database")
+	analysis := flag.String("analysis", "basic", "analysis depth: basic or deep")
+	significance := flag.Float64("significance", 0.05, "significance level for the statistical tests in deep analysis")
+	histogram := flag.Bool("histogram", false, "include a histogram of the values in the statistics")
+	changepoints := flag.Bool("changepoints", false, "mark the detected changepoints on JSON entries")
+	indicators := flag.Bool("indicators", false, "attach trading indicators (rsi) to each JSON entry")
+	emaAlpha := flag.Float64("ema", 0, "attach an exponential moving average with this alpha to each JSON entry (0 disables)")
+	ensemblePaths := flag.Int("ensemble", 0, "also generate this many paths and save their per-step percentiles under \"ensemble\" (0 disables)")
+	correlatedSeries := flag.Int("correlated", 0, "also generate this many series correlated with each other and save them under \"correlated\" (0 disables)")
+	correlation := flag.Float64("correlation", 0.5, "with -correlated, the target correlation of every pair of series")
+	floatMode := flag.Bool("float", false, "generate fractional values instead of integers (JSON only)")
+	decimals := flag.Int("decimals", 4, "with -float, round saved values to this many decimal places (-1 keeps full precision)")
+	currencyCode := flag.String("currency", "", "generate amounts of this currency, e.g. USD, in minor units")
+	currencyExponent := flag.Int("currency-exponent", 2, "with -currency, the number of minor-unit digits (2 for cents)")
+	interval := flag.Duration("interval", 0, "timestamp the entries this far apart on average, e.g. 1s (0 disables)")
+	start := flag.String("start", "", "with -interval, the RFC 3339 timestamp of the first entry (default now)")
+	jitter := flag.Float64("jitter", 0, "with -interval, vary each gap by up to this fraction of the interval (0.0 to 1.0)")
+	arrivals := flag.String("arrivals", "fixed", "with -interval, how the gaps are drawn: fixed or poisson")
+	accounts := flag.Int("accounts", 0, "tag entries with transaction IDs and an account from a pool of this size (0 disables)")
+	merchants := flag.String("merchants", "", "tag entries with transaction IDs and a merchant picked from label:weight pairs, e.g. grocer:3,fuel:1")
+	openingBalance := flag.Int("opening-balance", 0, "post entries to a ledger starting from this balance")
+	debitProbability := flag.Float64("debit-probability", 0.5, "with a ledger, the chance that an entry is a debit (0.0 to 1.0)")
+	floor := flag.Int("floor", 0, "with a ledger, the lowest balance allowed (unbounded unless set)")
+	floorPolicy := flag.String("floor-policy", "resize", "with -floor, what to do with a debit that would cross it: resize or reject")
+	regimes := flag.String("regimes", "", "switch the volatility between name:volatility regimes, e.g. calm:0.2,volatile:0.9")
+	regimeSwitch := flag.Float64("regime-switch", 0.05, "with -regimes, the chance each step of leaving the current regime for another")
+	jumpProbability := flag.Float64("jump-probability", 0, "the chance each step of a jump shock, e.g. 0.005 (0 disables)")
+	jumpMean := flag.Float64("jump-mean", 0, "with -jump-probability, the mean jump size as a fraction of the value")
+	jumpStdev := flag.Float64("jump-stdev", 0.2, "with -jump-probability, the standard deviation of the jump size")
+	target := flag.Int("target", 0, "make mean-reversion steps pull toward this fixed level instead of the running mean (unset by default)")
+	reversionSpeed := flag.Float64("reversion-speed", 0.2, "with -target, the fraction of the distance to it a mean-reversion step closes (0.0 to 1.0)")
+	garch := flag.String("garch", "", "cluster the volatility with GARCH(1,1) parameters omega,alpha,beta, e.g. 0.01,0.1,0.85")
+	model := flag.String("model", "branching", "generation model: branching, logistic, henon, tent or lorenz")
+	logisticR := flag.Float64("logistic-r", 3.9, "with -model logistic, the growth rate of the map, in (0, 4]")
+	tentMu := flag.Float64("tent-mu", 1.99, "with -model tent, the slope of the map, in (0, 2]")
+	henonA := flag.Float64("henon-a", chaotic.DefaultHenonA, "with -model henon, the a parameter of the map")
+	henonB := flag.Float64("henon-b", chaotic.DefaultHenonB, "with -model henon, the b parameter of the map")
+	defaultLorenz := chaotic.DefaultLorenz()
+	lorenzSigma := flag.Float64("lorenz-sigma", defaultLorenz.Sigma, "with -model lorenz, the sigma parameter of the system")
+	lorenzRho := flag.Float64("lorenz-rho", defaultLorenz.Rho, "with -model lorenz, the rho parameter of the system")
+	lorenzBeta := flag.Float64("lorenz-beta", defaultLorenz.Beta, "with -model lorenz, the beta parameter of the system")
+	lorenzDt := flag.Float64("lorenz-dt", defaultLorenz.Dt, "with -model lorenz, the RK4 integration step")
+	lorenzStride := flag.Int("lorenz-stride", defaultLorenz.Stride, "with -model lorenz, the integration steps between samples")
+	lorenzAxis := flag.String("lorenz-axis", "x", "with -model lorenz, the coordinate sampled: x, y or z")
+	excludeJumps := flag.Bool("exclude-jumps", false, "report the volatility of the steps without jumps, the diffusion alone")
+	anomalyRate := flag.Float64("anomaly-rate", 0, "the chance each step of starting an injected anomaly, e.g. 0.01 (0 disables)")
+	anomalyMultiplier := flag.Float64("anomaly-multiplier", 3, "with -anomaly-rate, the factor an anomaly multiplies or divides the value by")
+	anomalyDelta := flag.Int("anomaly-delta", 0, "with -anomaly-rate, move values by this amount instead of -anomaly-multiplier")
+	anomalyShifts := flag.Float64("anomaly-shifts", 0, "with -anomaly-rate, the share of anomalies that are level shifts rather than spikes (0.0 to 1.0)")
+	anomalyDuration := flag.Int("anomaly-duration", 10, "with -anomaly-shifts, the steps a level shift lasts")
+	excludeAnomalies := flag.Bool("exclude-anomalies", false, "compute the statistics without the injected anomalies")
+	dropRate := flag.Float64("drop-rate", 0, "the chance each entry is lost, leaving a placeholder marked missing (0.0 to 1.0)")
+	duplicateRate := flag.Float64("duplicate-rate", 0, "the chance each entry is delivered twice, the copy marked duplicate_of (0.0 to 1.0)")
+	boundary := flag.String("boundary", "clamp", "how values that leave the range are brought back: clamp, reflect, wrap or soft")
+	progress := flag.Bool("progress", false, "show the progress of the generation, with an ETA, on stderr")
+	flag.Parse()
+
+	// Keep stdout clean for the data when it is the output
+	report := os.Stdout
+	if *out == "-" {
+		report = os.Stderr
+	}
+
+	switch *format {
+	case "json", "csv", "ndjson", "parquet", "cbor":
+	default:
+		fmt.Fprintf(report, "Unknown output format %q\n", *format)
+		return
 	}
-	num, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
-	if err != nil {
-		// Fallback to time-based seeding if crypto fails
-		var fallback int64
-		if err := binary.Read(rand.Reader, binary.BigEndian, &fallback); err != nil {
-			return int(time.Now().UnixNano() % int64(n))
+	switch *analysis {
+	case "basic", "deep":
+	default:
+		fmt.Fprintf(report, "Unknown analysis depth %q\n", *analysis)
+		return
+	}
+	if !(*significance > 0 && *significance < 1) {
+		fmt.Fprintf(report, "-significance must be between 0 and 1, got %v\n", *significance)
+		return
+	}
+	if *emaAlpha != 0 && *format != "json" {
+		fmt.Fprintf(report, "-ema is only supported with -format json\n")
+		return
+	}
+	if *indicators && *format != "json" {
+		fmt.Fprintf(report, "-indicators is only supported with -format json\n")
+		return
+	}
+	if *changepoints && *format != "json" {
+		fmt.Fprintf(report, "-changepoints is only supported with -format json\n")
+		return
+	}
+	if *ensemblePaths < 0 {
+		fmt.Fprintf(report, "-ensemble must not be negative, got %d\n", *ensemblePaths)
+		return
+	}
+	if *ensemblePaths != 0 && *format != "json" {
+		fmt.Fprintf(report, "-ensemble is only supported with -format json\n")
+		return
+	}
+	if *correlatedSeries < 0 {
+		fmt.Fprintf(report, "-correlated must not be negative, got %d\n", *correlatedSeries)
+		return
+	}
+	if *correlatedSeries != 0 && *format != "json" {
+		fmt.Fprintf(report, "-correlated is only supported with -format json\n")
+		return
+	}
+
+	// Seed from crypto/rand so the run stays unpredictable but can be replayed
+	seed := chaotic.NewSeed()
+
+	if *floatMode {
+		if *format != "json" {
+			fmt.Fprintf(report, "-float is only supported with -format json\n")
+			return
 		}
-		if fallback < 0 {
-			fallback = -fallback
+		var unsupported []string
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "float", "decimals", "format", "out":
+			default:
+				unsupported = append(unsupported, "-"+f.Name)
+			}
+		})
+		if len(unsupported) > 0 {
+			fmt.Fprintf(report, "-float cannot be combined with %s\n", strings.Join(unsupported, ", "))
+			return
 		}
-		return int(fallback % int64(n))
+		runFloat(report, *out, *decimals, seed)
+		return
 	}
-	return int(num.Int64())
-}
 
-// secureRandFloat64 generates cryptographically secure random float between 0 and 1
-func secureRandFloat64() float64 {
-	var buf [8]byte
-	_, err := rand.Read(buf[:])
+	options := []chaotic.Option{
+		chaotic.WithVolatility(0.8), // More chaotic
+		chaotic.WithRange(1, 500),   // Smaller range for better visualization
+		chaotic.WithSeed(seed),
+	}
+	if *currencyCode != "" {
+		options = append(options, chaotic.WithCurrency(chaotic.Currency{Code: *currencyCode, Exponent: *currencyExponent}))
+	}
+	if *interval != 0 {
+		timing := chaotic.Timing{
+			Start:    time.Now().UTC(),
+			Interval: *interval,
+			Jitter:   *jitter,
+			Arrivals: chaotic.ArrivalModel(*arrivals),
+		}
+		if *start != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, *start)
+			if err != nil {
+				fmt.Fprintf(report, "Error parsing -start: %v\n", err)
+				return
+			}
+			timing.Start = parsed
+		}
+		options = append(options, chaotic.WithTiming(timing))
+	}
+	if *accounts != 0 || *merchants != "" {
+		entities := chaotic.Entities{Accounts: *accounts}
+		if *merchants != "" {
+			parsed, err := parseMerchants(*merchants)
+			if err != nil {
+				fmt.Fprintf(report, "Error parsing -merchants: %v\n", err)
+				return
+			}
+			entities.Merchants = parsed
+		}
+		options = append(options, chaotic.WithEntities(entities))
+	}
+	if *regimes != "" {
+		switching, err := parseRegimes(*regimes, *regimeSwitch)
+		if err != nil {
+			fmt.Fprintf(report, "Error parsing -regimes: %v\n", err)
+			return
+		}
+		options = append(options, chaotic.WithRegimes(switching))
+	}
+	if *jumpProbability != 0 {
+		options = append(options, chaotic.WithJumps(chaotic.Jumps{
+			Probability: *jumpProbability,
+			Mean:        *jumpMean,
+			Stdev:       *jumpStdev,
+		}))
+	}
+	if *anomalyRate != 0 {
+		anomalies := chaotic.AnomalyConfig{
+			Rate:        *anomalyRate,
+			Multiplier:  *anomalyMultiplier,
+			LevelShifts: *anomalyShifts,
+		}
+		if *anomalyDelta != 0 {
+			anomalies.Multiplier, anomalies.Delta = 0, *anomalyDelta
+		}
+		if *anomalyShifts != 0 {
+			anomalies.Duration = *anomalyDuration
+		}
+		options = append(options, chaotic.WithAnomalies(anomalies))
+	}
+	if *dropRate != 0 || *duplicateRate != 0 {
+		options = append(options, chaotic.WithFaults(chaotic.DeliveryFaults{DropRate: *dropRate, DuplicateRate: *duplicateRate}))
+	}
+	switch *model {
+	case "branching":
+	case "logistic":
+		options = append(options, chaotic.WithLogisticMap(*logisticR))
+	case "tent":
+		options = append(options, chaotic.WithTentMap(*tentMu))
+	case "henon":
+		options = append(options, chaotic.WithHenonMap(*henonA, *henonB))
+	case "lorenz":
+		lorenz := defaultLorenz
+		lorenz.Sigma, lorenz.Rho, lorenz.Beta = *lorenzSigma, *lorenzRho, *lorenzBeta
+		lorenz.Dt, lorenz.Stride, lorenz.Axis = *lorenzDt, *lorenzStride, *lorenzAxis
+		options = append(options, chaotic.WithLorenz(lorenz))
+	default:
+		fmt.Fprintf(report, "Unknown model %q\n", *model)
+		return
+	}
+	if *garch != "" {
+		parsed, err := parseGarch(*garch)
+		if err != nil {
+			fmt.Fprintf(report, "Error parsing -garch: %v\n", err)
+			return
+		}
+		options = append(options, chaotic.WithGarch(parsed))
+	}
+	targeted := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "target" || f.Name == "reversion-speed" {
+			targeted = true
+		}
+	})
+	if targeted {
+		options = append(options, chaotic.WithMeanReversionTarget(*target, *reversionSpeed))
+	}
+	ledgered, floored := false, false
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "opening-balance", "debit-probability", "floor-policy":
+			ledgered = true
+		case "floor":
+			ledgered, floored = true, true
+		}
+	})
+	if ledgered {
+		ledger := chaotic.Ledger{
+			OpeningBalance:   *openingBalance,
+			DebitProbability: *debitProbability,
+			FloorPolicy:      chaotic.FloorPolicy(*floorPolicy),
+		}
+		if floored {
+			ledger.Floor = floor
+		}
+		options = append(options, chaotic.WithLedger(ledger))
+	}
+	if *boundary != string(chaotic.BoundaryClamp) {
+		options = append(options, chaotic.WithBoundaryMode(chaotic.BoundaryMode(*boundary)))
+	}
+	if *progress {
+		options = append(options, chaotic.WithProgress(progressLine(os.Stderr, time.Now())))
+	}
+	generator, err := chaotic.NewGenerator(options...)
 	if err != nil {
-		return float64(secureRandIntn(1<<53)) / (1 << 53)
+		fmt.Fprintf(report, "Error configuring generator: %v\n", err)
+		return
 	}
-	return float64(binary.LittleEndian.Uint64(buf[:])&((1<<53)-1)) / (1 << 53)
-}
+	config := generator.Config()
 
-// ChaoticTransactionSequence generates a chaotic transaction sequence of n steps
-func ChaoticTransactionSequence(n int, config ChaoticConfig) ([]map[string]interface{}, error) {
-	if n <= 0 {
-		return nil, errors.New("the number of steps must be a positive integer")
+	// Duplicated entries make the log longer than this
+	length := 50 // Smaller sample for demo
+	log, err := generator.GenerateExtended(length)
+	if err != nil {
+		fmt.Fprintf(report, "Error generating sequence: %v\n", err)
+		return
 	}
-	if n < 2 {
-		return nil, errors.New("sequence length must be at least 2 for proper chaotic behavior")
+	for _, warning := range generator.Warnings() {
+		fmt.Fprintf(report, "Warning: %v\n", warning)
 	}
 
-	sequence := make([]int, n)
-	log := make([]map[string]interface{}, n)
-
-	// Initialize with random starting value
-	sequence[0] = secureRandIntn(config.MaxValue-config.MinValue+1) + config.MinValue
-	log[0] = map[string]interface{}{
-		"step":  0,
-		"value": sequence[0],
-		"type":  "initial",
+	// The value statistics can leave out the injected anomalies, measuring
+	// the process alone
+	measured := log
+	if *excludeAnomalies {
+		measured = chaotic.ExcludeAnomalies(log)
 	}
-
-	// Generate second value
-	sequence[1] = clamp(
-		sequence[0]+secureRandIntn(21)-10,
-		config.MinValue,
-		config.MaxValue,
-	)
-	log[1] = map[string]interface{}{
-		"step":  1,
-		"value": sequence[1],
-		"type":  "random_walk",
+	stats, err := chaotic.ComputeStatistics(measured)
+	if err != nil {
+		fmt.Fprintf(report, "Error computing statistics: %v\n", err)
+		return
 	}
+	// The statistics count the placeholders of dropped entries and skip
+	// them, and so do the analyses below, as they have no value
+	measured, present := chaotic.ExcludeMissing(measured), chaotic.ExcludeMissing(log)
 
-	runningMean := float64(sequence[0]+sequence[1]) / 2.0
-
-	for i := 2; i < n; i++ {
-		prev1 := sequence[i-1]
-		prev2 := sequence[i-2]
-		var nextValue int
-
-		randomChoice := secureRandFloat64()
-		chaosFactor := secureRandFloat64()*2 - 1 // -1 to 1
-
-		switch {
-		case randomChoice < 0.25: // Trend following
-			trend := prev1 - prev2
-			nextValue = prev1 + int(float64(trend)*config.TrendStrength) + int(chaosFactor*float64(prev1)*0.5)
-
-		case randomChoice < 0.5: // Mean reversion
-			deviation := float64(prev1) - runningMean
-			nextValue = prev1 - int(deviation*config.MeanReversion) + int(chaosFactor*float64(prev1)*0.3)
-
-		case randomChoice < 0.75: // Multiplicative change
-			factors := []float64{0.3, 0.7, 1.3, 1.7, 2.0, -0.5}
-			factor := factors[secureRandIntn(len(factors))]
-			nextValue = int(float64(prev1)*factor) + int(chaosFactor*10)
+	// The metadata records the drawn x0 or Lorenz start, so the run can be
+	// read off it
+	metadataConfig := config
+	if x0, ok := generator.MapX0(); ok {
+		metadataConfig.MapX0 = &x0
+	}
+	if start, ok := generator.LorenzStart(); ok {
+		lorenz := defaultLorenz
+		if config.Lorenz != nil {
+			lorenz = *config.Lorenz
+		}
+		lorenz.Start = start
+		metadataConfig.Lorenz = &lorenz
+	}
+	if *histogram {
+		h, err := chaotic.Histogram(values(measured), 0)
+		if err != nil {
+			fmt.Fprintf(report, "Error computing histogram: %v\n", err)
+			return
+		}
+		stats.Histogram = &h
+	}
+	if config.Currency != nil {
+		money, err := chaotic.ComputeMoneyStatistics(measured, *config.Currency)
+		if err != nil {
+			fmt.Fprintf(report, "Error computing money statistics: %v\n", err)
+			return
+		}
+		stats.Money = &money
+	}
+	if config.Timing != nil {
+		arrivals, err := chaotic.ComputeArrivalStatistics(log, *config.Timing)
+		if err != nil {
+			fmt.Fprintf(report, "Error computing arrival statistics: %v\n", err)
+			return
+		}
+		stats.Arrivals = &arrivals
+	}
+	stats.ByAccount = chaotic.CountByAccount(log)
+	if t := config.MeanReversionTarget; t != nil {
+		distance := chaotic.MeanTargetDistance(measured, *t)
+		stats.TargetDistance = &distance
+	}
 
-		default: // Additive noise with memory
-			noise := secureRandIntn(21) - 10
-			nextValue = prev1 + (prev1-prev2)/2 + noise
+	// Print summary
+	fmt.Fprintf(report, "Chaotic Sequence Analysis\n")
+	fmt.Fprintf(report, "========================\n")
+	fmt.Fprintf(report, "Generated %d transactions\n", len(log))
+	fmt.Fprintf(report, "Value Range: %d - %d\n", stats.Min, stats.Max)
+	fmt.Fprintf(report, "Mean: %.2f, Median: %.1f\n", stats.Mean, stats.Median)
+	volatility := stats.Volatility
+	if *excludeJumps {
+		volatility = stats.DiffusionVolatility
+	}
+	fmt.Fprintf(report, "Std Dev: %.2f, Volatility: %.2f\n", stats.Stdev, volatility)
+	fmt.Fprintf(report, "Trend Strength: %.2f\n", stats.TrendStrength)
+	fmt.Fprintf(report, "Skewness: %.2f, Kurtosis: %.2f\n", stats.Skewness, stats.Kurtosis)
+	fmt.Fprintf(report, "Lag-1 Autocorrelation: %.2f\n", stats.Lag1Autocorrelation)
+	fmt.Fprintf(report, "IQR: %.2f (Q1: %.2f, Q3: %.2f)\n", stats.IQR, stats.Q1, stats.Q3)
+	if money := stats.Money; money != nil {
+		fmt.Fprintf(report, "Amounts (%s): total %s, mean %s, range %s - %s\n",
+			money.Currency, money.Total, money.Mean, money.Min, money.Max)
+	}
+	if config.Timing != nil {
+		fmt.Fprintf(report, "Duration: %.3fs, Events/s: %.3f\n", stats.Duration, stats.EventsPerSecond)
+		fmt.Fprintf(report, "Inter-arrival (%s): mean %.3fs, stdev %.3fs, rate deviation %+.1f%%\n",
+			stats.Arrivals.Model, stats.Arrivals.InterArrivalMean, stats.Arrivals.InterArrivalStdev, 100*stats.Arrivals.RateDeviation)
+	}
+	if config.Ledger != nil {
+		fmt.Fprintf(report, "Balance: final %d, lowest %d, %.1f%% debits\n",
+			stats.FinalBalance, stats.MinBalance, 100*stats.DebitRatio)
+	}
+	if x0 := metadataConfig.MapX0; x0 != nil {
+		fmt.Fprintf(report, "Map %s: x0 %.6f\n", config.Model, *x0)
+	}
+	if l := metadataConfig.Lorenz; l != nil && l.Start != nil {
+		axis := l.Axis
+		if axis == "" {
+			axis = "x"
 		}
+		fmt.Fprintf(report, "Lorenz: start (%.4f, %.4f, %.4f), sampling %s every %d steps of %g\n",
+			l.Start[0], l.Start[1], l.Start[2], axis, l.Stride, l.Dt)
+	}
+	if t := config.MeanReversionTarget; t != nil {
+		fmt.Fprintf(report, "Target %d: mean distance %.2f\n", *t, *stats.TargetDistance)
+	}
+	if stats.ClampRate > 0 {
+		fmt.Fprintf(report, "Clamped: %d low, %d high (%.1f%%)\n", stats.ClampedLow, stats.ClampedHigh, 100*stats.ClampRate)
+	}
+	if config.Jumps != nil {
+		fmt.Fprintf(report, "Jumps: %d, diffusion-only volatility %.2f\n", stats.JumpCount, stats.DiffusionVolatility)
+	}
+	if config.Anomalies != nil {
+		excluded := ""
+		if *excludeAnomalies {
+			excluded = ", excluded from the statistics"
+		}
+		fmt.Fprintf(report, "Anomalies: %d anomalous steps%s\n", len(chaotic.AnomalySteps(log)), excluded)
+	}
+	if config.Faults != nil {
+		fmt.Fprintf(report, "Faults: %d missing, %d duplicates\n", stats.Missing, stats.Duplicates)
+	}
+	for _, name := range sortedKeys(stats.ByRegime) {
+		regime := stats.ByRegime[name]
+		fmt.Fprintf(report, "Regime %s: %.1f%% of steps, volatility %.2f\n", name, 100*regime.Share, regime.Volatility)
+	}
+	if len(stats.ByAccount) > 0 {
+		busiest := ""
+		for _, account := range sortedKeys(stats.ByAccount) {
+			if busiest == "" || stats.ByAccount[account] > stats.ByAccount[busiest] {
+				busiest = account
+			}
+		}
+		fmt.Fprintf(report, "Accounts: %d active, busiest %s with %d transactions\n",
+			len(stats.ByAccount), busiest, stats.ByAccount[busiest])
+	}
 
-		// Apply volatility
-		volatilityEffect := int(chaosFactor * float64(nextValue) * config.Volatility)
-		nextValue += volatilityEffect
+	var notes annotations
+	if *changepoints {
+		notes.changepoints = stats.Changepoints
+	}
+	notes.steps = steps(present)
+	if *analysis == "deep" {
+		notes.bands = printDeepAnalysis(report, present, *significance)
+		stats.Stationarity = printStationarity(report, present)
+	}
+	if *emaAlpha != 0 {
+		if notes.ema, err = chaotic.EMA(values(present), *emaAlpha); err != nil {
+			fmt.Fprintf(report, "Error computing EMA: %v\n", err)
+			return
+		}
+	}
+	if *indicators {
+		if notes.rsi, err = chaotic.RSI(values(present), rsiPeriod); err != nil {
+			fmt.Fprintf(report, "Error computing RSI: %v\n", err)
+			return
+		}
+	}
 
-		// Clamp to valid range
-		nextValue = clamp(nextValue, config.MinValue, config.MaxValue)
+	// Save detailed data
+	output := chaotic.RunDocument{
+		Metadata: chaotic.RunMetadata{
+			GeneratedAt:      time.Now().Format(time.RFC3339),
+			Config:           metadataConfig,
+			SequenceLength:   length,
+			Seed:             &seed,
+			GeneratorVersion: chaotic.GeneratorVersion,
+			FormatVersion:    chaotic.FormatVersion,
+			Extended:         true,
+			Anomalies:        chaotic.AnomalySteps(log),
+		},
+		Statistics: stats,
+		Sequence:   log,
+	}
+	if *ensemblePaths > 0 {
+		// Only the summaries are saved, so the paths need not be kept
+		ensemble, err := chaotic.GenerateEnsembleWithOptions(*ensemblePaths, length, config,
+			chaotic.EnsembleOptions{DiscardPaths: true})
+		if err != nil {
+			fmt.Fprintf(report, "Error generating ensemble: %v\n", err)
+			return
+		}
+		output.Ensemble = &ensemble
+		fmt.Fprintf(report, "Ensemble: %d paths, final step median %.1f (90%% band %.1f - %.1f)\n", ensemble.Paths,
+			ensemble.Steps[length-1].Median, ensemble.Steps[length-1].P5, ensemble.Steps[length-1].P95)
+	}
+	if *correlatedSeries > 0 {
+		correlated, err := generateCorrelated(*correlatedSeries, length, config, *correlation)
+		if err != nil {
+			fmt.Fprintf(report, "Error generating correlated series: %v\n", err)
+			return
+		}
+		output.Correlated = &correlated
+		if len(correlated.Names) > 1 {
+			fmt.Fprintf(report, "Correlated: %d series, target %.2f, realized %.2f between %s and %s\n", len(correlated.Names),
+				*correlation, correlated.Realized[1][0], correlated.Names[0], correlated.Names[1])
+		}
+	}
 
-		sequence[i] = nextValue
-		runningMean = (runningMean*float64(i) + float64(nextValue)) / float64(i+1)
+	filename := *out
+	if filename == "" {
+		filename = "chaotic_transaction_analysis." + *format
+	}
+	err = saveOutput(filename, func(w io.Writer) error {
+		return writeOutput(output, notes, *format, w)
+	})
+	if err != nil {
+		fmt.Fprintf(report, "Error saving output: %v\n", err)
+		return
+	}
+	if filename != "-" {
+		fmt.Fprintf(report, "\nDetailed analysis saved to %s\n", filename)
+	}
 
-		log[i] = map[string]interface{}{
-			"step":  i,
-			"value": nextValue,
-			"type":  getStepType(randomChoice),
+	if *sqlitePath != "" {
+		// The seed doubles as the run ID, so the row can be replayed later
+		runID := strconv.FormatInt(seed, 10)
+		if err := chaotic.SaveToSQLite(*sqlitePath, runID, log, stats, config); err != nil {
+			fmt.Fprintf(report, "Error saving to SQLite: %v\n", err)
+			return
 		}
+		fmt.Fprintf(report, "Run %s saved to %s\n", runID, *sqlitePath)
 	}
 
-	return log, nil
+	// Print first 10 entries as sample
+	fmt.Fprintln(report, "\nFirst 10 transactions:")
+	sample, _ := json.MarshalIndent(log[:10], "", "  ")
+	fmt.Fprintln(report, string(sample))
 }
 
-// clamp ensures value stays within min-max range
-func clamp(value, min, max int) int {
-	if value < min {
-		return min
+// runFloat generates, summarizes and saves a float run, the -float
+// counterpart of the integer run in main
+func runFloat(report io.Writer, filename string, decimals int, seed int64) {
+	config := chaotic.DefaultFloatConfig()
+	config.Volatility = 0.8
+	config.MinValue, config.MaxValue = 1, 500
+	config.Seed = &seed
+
+	log, err := chaotic.ChaoticFloatSequence(50, config)
+	if err != nil {
+		fmt.Fprintf(report, "Error generating sequence: %v\n", err)
+		return
 	}
-	if value > max {
-		return max
+	stats, err := chaotic.ComputeFloatStatistics(log)
+	if err != nil {
+		fmt.Fprintf(report, "Error computing statistics: %v\n", err)
+		return
 	}
-	return value
-}
 
-// getStepType returns a descriptive type for the generation step
-func getStepType(randomChoice float64) string {
-	switch {
-	case randomChoice < 0.25:
-		return "trend_following"
-	case randomChoice < 0.5:
-		return "mean_reversion"
-	case randomChoice < 0.75:
-		return "multiplicative"
-	default:
-		return "additive_noise"
+	fmt.Fprintf(report, "Chaotic Float Sequence Analysis\n")
+	fmt.Fprintf(report, "===============================\n")
+	fmt.Fprintf(report, "Generated %d values\n", len(log))
+	fmt.Fprintf(report, "Value Range: %.4f - %.4f\n", stats.Min, stats.Max)
+	fmt.Fprintf(report, "Mean: %.4f, Median: %.4f\n", stats.Mean, stats.Median)
+	fmt.Fprintf(report, "Std Dev: %.4f, Volatility: %.4f\n", stats.Stdev, stats.Volatility)
+	fmt.Fprintf(report, "Trend Strength: %.2f\n", stats.TrendStrength)
+
+	doc := chaotic.FloatRunDocument{
+		Metadata: chaotic.FloatRunMetadata{
+			GeneratedAt:      time.Now().Format(time.RFC3339),
+			Config:           config,
+			SequenceLength:   len(log),
+			Seed:             &seed,
+			GeneratorVersion: chaotic.GeneratorVersion,
+			FormatVersion:    chaotic.FormatVersion,
+		},
+		Statistics: stats,
+		Sequence:   log,
+	}.Rounded(decimals)
+
+	if filename == "" {
+		filename = "chaotic_float_analysis.json"
+	}
+	if err := saveOutput(filename, func(w io.Writer) error { return chaotic.WriteJSON(doc, w) }); err != nil {
+		fmt.Fprintf(report, "Error saving output: %v\n", err)
+		return
+	}
+	if filename != "-" {
+		fmt.Fprintf(report, "\nDetailed analysis saved to %s\n", filename)
 	}
 }
 
-// ComputeStatistics computes comprehensive statistics for the transaction sequence
-func ComputeStatistics(sequence []map[string]interface{}) (map[string]interface{}, error) {
-	if len(sequence) == 0 {
-		return nil, errors.New("empty sequence")
+// printDeepAnalysis prints the slower diagnostics that are not part of the
+// statistics block. It returns the Bollinger bands so they can annotate the
+// output, or nil when the run is too short for them.
+func printDeepAnalysis(w io.Writer, log []chaotic.LogEntry, significance float64) []chaotic.BollingerPoint {
+	fmt.Fprintf(w, "\nDeep Analysis\n")
+	fmt.Fprintf(w, "=============\n")
+	if lyapunov, err := chaotic.EstimateLyapunov(values(log), chaotic.LyapunovOptions{}); err != nil {
+		fmt.Fprintf(w, "Lyapunov Exponent: unavailable (%v)\n", err)
+	} else {
+		fmt.Fprintf(w, "Lyapunov Exponent: %.4f\n", lyapunov)
+	}
+	if period, err := chaotic.DominantPeriod(values(log)); err != nil {
+		fmt.Fprintf(w, "Dominant Period: unavailable (%v)\n", err)
+	} else {
+		fmt.Fprintf(w, "Dominant Period: %.1f steps\n", period)
 	}
 
-	// Extract values safely
-	values := make([]int, len(sequence))
-	for i, entry := range sequence {
-		if val, ok := entry["value"].(int); ok {
-			values[i] = val
-		} else {
-			return nil, fmt.Errorf("invalid value type at step %d", i)
+	bands, err := chaotic.BollingerBands(values(log), bollingerWindow, bollingerK)
+	if err != nil {
+		fmt.Fprintf(w, "Outside Bollinger Bands: unavailable (%v)\n", err)
+		return nil
+	}
+	outside := 0
+	for _, band := range bands {
+		if band.OutsideBand {
+			outside++
 		}
 	}
+	fmt.Fprintf(w, "Outside Bollinger Bands (%d steps, %.1f stdev): %d of %d steps\n",
+		bollingerWindow, bollingerK, outside, len(bands))
+	printTransitions(w, log)
 
-	// Calculate basic statistics
-	stats := calculateBasicStats(values)
-	
-	// Calculate advanced statistics
-	stats["variance"] = stats["stdev"].(float64) * stats["stdev"].(float64)
-	stats["coefficient_of_variation"] = stats["stdev"].(float64) / stats["mean"].(float64)
-	stats["q1"] = calculateQuantile(values, 0.25)
-	stats["q3"] = calculateQuantile(values, 0.75)
-	stats["iqr"] = stats["q3"].(int) - stats["q1"].(int)
-
-	// Trend analysis
-	stats["trend_strength"] = calculateTrendStrength(values)
-	stats["volatility"] = calculateVolatility(values)
-
-	return stats, nil
+	if result, err := chaotic.BranchFrequencyTest(log, nil); err != nil {
+		fmt.Fprintf(w, "Branch Frequency Test: unavailable (%v)\n", err)
+	} else {
+		fmt.Fprintf(w, "Branch Frequency Test: chi-square %.2f (df %d), p = %.4f, %s at %g\n",
+			result.Statistic, result.DegreesOfFreedom, result.PValue, verdict(result.Pass(significance)), significance)
+	}
+	return bands
 }
 
-// calculateBasicStats computes mean, median, standard deviation, min, max
-func calculateBasicStats(values []int) map[string]interface{} {
-	stats := make(map[string]interface{})
-	
-	// Sort copy for median calculation
-	sorted := make([]int, len(values))
-	copy(sorted, values)
-	sort.Ints(sorted)
-
-	// Calculate mean and min/max
-	sum := 0
-	minVal, maxVal := sorted[0], sorted[0]
-	for _, v := range values {
-		sum += v
-		if v < minVal {
-			minVal = v
-		}
-		if v > maxVal {
-			maxVal = v
-		}
-	}
-
-	mean := float64(sum) / float64(len(values))
-	
-	// Calculate standard deviation
-	var variance float64
-	for _, v := range values {
-		diff := float64(v) - mean
-		variance += diff * diff
-	}
-	variance /= float64(len(values) - 1)
-	stdev := math.Sqrt(variance)
-
-	// Calculate median
-	median := 0
-	if len(sorted)%2 == 0 {
-		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
-	} else {
-		median = sorted[len(sorted)/2]
+// printStationarity prints the stationarity test and returns its result for
+// the statistics block, or nil when the run is too short or flat to test
+func printStationarity(w io.Writer, log []chaotic.LogEntry) *chaotic.StationarityResult {
+	result, err := chaotic.TestStationarity(values(log))
+	if err != nil {
+		fmt.Fprintf(w, "Stationarity: unavailable (%v)\n", err)
+		return nil
 	}
+	fmt.Fprintf(w, "Stationarity: %s (halves: t %.2f, p = %.4f; F %.2f, p = %.4f; ADF %.2f)\n",
+		verdict(result.Stationary), result.MeanT, result.MeanPValue, result.VarianceF, result.VariancePValue, result.ADFStatistic)
+	return &result
+}
 
-	stats["mean"] = mean
-	stats["median"] = median
-	stats["stdev"] = stdev
-	stats["min"] = minVal
-	stats["max"] = maxVal
-	stats["count"] = len(values)
+// compareFiles implements the compare subcommand: it loads the two run
+// documents named in args and prints how the second differs from the first
+func compareFiles(w io.Writer, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: compare file1.json file2.json")
+	}
+	var runs [2][]chaotic.LogEntry
+	for i, path := range args {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		runs[i], _, _, err = chaotic.LoadSequenceFromJSON(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	comparison, err := chaotic.CompareSequences(runs[0], runs[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Comparison of %s (a) and %s (b)\n", args[0], args[1])
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Length\t%d vs %d\n", comparison.LengthA, comparison.LengthB)
+	if comparison.Truncated {
+		fmt.Fprintf(tw, "\t(compared the first %d entries)\n", comparison.Compared)
+	}
+	fmt.Fprintf(tw, "Correlation\t%.4f\n", comparison.Correlation)
+	fmt.Fprintf(tw, "RMSE\t%.2f\n", comparison.RMSE)
+	fmt.Fprintf(tw, "MAE\t%.2f\n", comparison.MAE)
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Statistic\tb - a\n")
+	fmt.Fprintf(tw, "mean\t%+.4f\n", comparison.MeanDiff)
+	fmt.Fprintf(tw, "stdev\t%+.4f\n", comparison.StdevDiff)
+	fmt.Fprintf(tw, "volatility\t%+.4f\n", comparison.VolatilityDiff)
+	fmt.Fprintf(tw, "trend_strength\t%+.4f\n", comparison.TrendStrengthDiff)
+	return tw.Flush()
+}
 
-	return stats
+// verdict describes a test outcome
+func verdict(pass bool) string {
+	if pass {
+		return "pass"
+	}
+	return "fail"
 }
 
-// calculateQuantile computes the specified quantile (0.0 to 1.0)
-func calculateQuantile(values []int, quantile float64) int {
-	sorted := make([]int, len(values))
-	copy(sorted, values)
-	sort.Ints(sorted)
+// printTransitions prints the step-type transition matrix, one row per type,
+// and its stationary distribution
+func printTransitions(w io.Writer, log []chaotic.LogEntry) {
+	matrix, err := chaotic.TransitionMatrix(log)
+	if err != nil {
+		fmt.Fprintf(w, "Step-Type Transitions: unavailable (%v)\n", err)
+		return
+	}
+	fmt.Fprintf(w, "Step-Type Transitions:\n")
+	for _, from := range sortedKeys(matrix) {
+		fmt.Fprintf(w, "  %s -> %s\n", from, formatShares(matrix[from]))
+	}
+	if stationary, err := chaotic.StationaryDistribution(matrix); err == nil {
+		fmt.Fprintf(w, "Stationary Distribution: %s\n", formatShares(stationary))
+	}
+}
 
-	pos := quantile * float64(len(sorted)-1)
-	lower := int(pos)
-	upper := lower + 1
-	weight := pos - float64(lower)
+// formatShares formats a map of probabilities as "name 0.25, other 0.75",
+// sorted by name, or "none" when it is empty
+func formatShares(shares map[string]float64) string {
+	if len(shares) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(shares))
+	for _, key := range sortedKeys(shares) {
+		parts = append(parts, fmt.Sprintf("%s %.2f", key, shares[key]))
+	}
+	return strings.Join(parts, ", ")
+}
 
-	if upper >= len(sorted) {
-		return sorted[lower]
+// sortedKeys returns the keys of m in increasing order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-	return int(float64(sorted[lower])*(1-weight) + float64(sorted[upper])*weight)
+	sort.Strings(keys)
+	return keys
 }
 
-// calculateTrendStrength measures how trending the sequence is
-func calculateTrendStrength(values []int) float64 {
-	if len(values) < 2 {
-		return 0.0
+// parseMerchants parses a comma-separated list of label:weight pairs; a
+// label without a weight gets weight 1
+func parseMerchants(list string) ([]chaotic.Merchant, error) {
+	var merchants []chaotic.Merchant
+	for _, pair := range strings.Split(list, ",") {
+		label, weight, found := strings.Cut(pair, ":")
+		merchant := chaotic.Merchant{Label: strings.TrimSpace(label), Weight: 1}
+		if found {
+			w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil {
+				return nil, fmt.Errorf("weight of %q: %w", merchant.Label, err)
+			}
+			merchant.Weight = w
+		}
+		merchants = append(merchants, merchant)
 	}
+	return merchants, nil
+}
 
-	up, down := 0, 0
-	for i := 1; i < len(values); i++ {
-		if values[i] > values[i-1] {
-			up++
-		} else if values[i] < values[i-1] {
-			down++
+// progressLine returns a progress callback that redraws a percentage and
+// ETA line on w for a run started at start, ending it when the run completes
+func progressLine(w io.Writer, start time.Time) func(done, total int) {
+	return func(done, total int) {
+		elapsed := time.Since(start)
+		eta := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+		fmt.Fprintf(w, "\rGenerating: %3.0f%% (%d/%d), ETA %s ", 100*float64(done)/float64(total), done, total, eta.Round(time.Second))
+		if done == total {
+			fmt.Fprintln(w)
 		}
 	}
+}
 
-	total := up + down
-	if total == 0 {
-		return 0.0
+// parseGarch parses the comma-separated omega, alpha and beta of a GARCH(1,1)
+// volatility
+func parseGarch(list string) (chaotic.Garch, error) {
+	parts := strings.Split(list, ",")
+	if len(parts) != 3 {
+		return chaotic.Garch{}, fmt.Errorf("want omega,alpha,beta, got %d values", len(parts))
+	}
+	var params [3]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return chaotic.Garch{}, err
+		}
+		params[i] = v
 	}
-	return math.Abs(float64(up-down)) / float64(total)
+	return chaotic.Garch{Omega: params[0], Alpha: params[1], Beta: params[2]}, nil
 }
 
-// calculateVolatility measures the sequence volatility
-func calculateVolatility(values []int) float64 {
-	if len(values) < 2 {
-		return 0.0
+// parseRegimes parses a comma-separated list of name:volatility pairs into
+// regimes that keep the default trend strength and mean reversion. Each
+// step leaves the current regime with probability leave, for any other
+// regime equally.
+func parseRegimes(list string, leave float64) (chaotic.RegimeSwitching, error) {
+	defaults := chaotic.DefaultConfig()
+	var switching chaotic.RegimeSwitching
+	for _, pair := range strings.Split(list, ",") {
+		name, volatility, found := strings.Cut(pair, ":")
+		if !found {
+			return switching, fmt.Errorf("regime %q has no volatility", name)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(volatility), 64)
+		if err != nil {
+			return switching, fmt.Errorf("volatility of %q: %w", name, err)
+		}
+		switching.States = append(switching.States, chaotic.VolatilityRegime{
+			Name:          strings.TrimSpace(name),
+			Volatility:    v,
+			TrendStrength: defaults.TrendStrength,
+			MeanReversion: defaults.MeanReversion,
+		})
+	}
+	n := len(switching.States)
+	for i := range switching.States {
+		row := make([]float64, n)
+		for j := range row {
+			if j == i {
+				row[j] = 1 - leave
+			} else if n > 1 {
+				row[j] = leave / float64(n-1)
+			}
+		}
+		switching.Transitions = append(switching.Transitions, row)
 	}
+	return switching, nil
+}
 
-	var sum float64
-	for i := 1; i < len(values); i++ {
-		change := math.Abs(float64(values[i]) - float64(values[i-1]))
-		sum += change
+// values returns the value of each entry in log
+func values(log []chaotic.LogEntry) []int {
+	vals := make([]int, len(log))
+	for i, entry := range log {
+		vals[i] = entry.Value
 	}
-	return sum / float64(len(values)-1)
+	return vals
 }
 
-// EnhancedChaoticLogic applies sophisticated chaotic transformations
-func EnhancedChaoticLogic(value int, step int) int {
-	chaos := secureRandFloat64()
-	
-	switch {
-	case value%11 == 0:
-		// Major transformation for values divisible by 11
-		return value*3 + secureRandIntn(41) - 20
-	case value%7 == 0:
-		// Moderate transformation
-		return value*2 + secureRandIntn(21) - 10
-	case value%5 == 0:
-		// Minor transformation
-		return value/2 + secureRandIntn(11) - 5
-	case step%13 == 0:
-		// Periodic major disruption
-		return value + secureRandIntn(101) - 50
-	case chaos < 0.1:
-		// Random major event (10% chance)
-		return value + secureRandIntn(201) - 100
-	default:
-		// Normal chaotic adjustment
-		return value + secureRandIntn(21) - 10
+// steps returns the step of each entry in log
+func steps(log []chaotic.LogEntry) []int {
+	steps := make([]int, len(log))
+	for i, entry := range log {
+		steps[i] = entry.Step
 	}
+	return steps
 }
 
-// ChaoticTransactionSequenceExtended generates sequence with enhanced chaotic logic
-func ChaoticTransactionSequenceExtended(n int, config ChaoticConfig) ([]map[string]interface{}, error) {
-	log, err := ChaoticTransactionSequence(n, config)
+// annotations holds optional per-step values attached to the entries of JSON output
+type annotations struct {
+	steps []int // the step each position of the series below annotates
+	ema   []float64
+	bands []chaotic.BollingerPoint // steps before the first full window are left unannotated
+	rsi   []float64                // NaN, and left unannotated, until the first full period
+
+	changepoints []int // steps to mark
+}
+
+// annotatedEntry is a log entry with its annotations
+type annotatedEntry struct {
+	chaotic.LogEntry
+	entryNotes
+}
+
+// entryNotes are the annotations of one entry
+type entryNotes struct {
+	EMA         *float64 `json:"ema,omitempty"`
+	OutsideBand *bool    `json:"outside_band,omitempty"`
+	RSI         *float64 `json:"rsi,omitempty"`
+	Changepoint bool     `json:"changepoint,omitempty"`
+}
+
+// MarshalJSON writes the fields of the entry, as its own MarshalJSON does,
+// followed by the annotations
+func (e annotatedEntry) MarshalJSON() ([]byte, error) {
+	entry, err := json.Marshal(e.LogEntry)
 	if err != nil {
 		return nil, err
 	}
-
-	for i, entry := range log {
-		value := entry["value"].(int)
-		enhancedValue := EnhancedChaoticLogic(value, i)
-		entry["enhanced_value"] = clamp(enhancedValue, config.MinValue, config.MaxValue*2) // Allow larger range for enhanced
-		entry["enhancement_delta"] = enhancedValue - value
-		log[i] = entry
+	notes, err := json.Marshal(e.entryNotes)
+	if err != nil || string(notes) == "{}" {
+		return entry, err
 	}
+	return append(append(entry[:len(entry)-1], ','), notes[1:]...), nil
+}
 
-	return log, nil
+// annotatedDocument is a chaotic.RunDocument whose entries carry annotations
+type annotatedDocument struct {
+	Metadata   chaotic.RunMetadata    `json:"metadata"`
+	Statistics chaotic.Statistics     `json:"statistics"`
+	Sequence   []annotatedEntry       `json:"sequence"`
+	Ensemble   *chaotic.Ensemble      `json:"ensemble,omitempty"`
+	Correlated *chaotic.CorrelatedRun `json:"correlated,omitempty"`
 }
 
-// SaveToJson saves data to a JSON file with proper error handling
-func SaveToJson(data interface{}, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+// empty reports whether there is nothing to attach
+func (a annotations) empty() bool {
+	return a.ema == nil && a.bands == nil && a.rsi == nil && a.changepoints == nil
+}
+
+// apply attaches the annotations to the entries of doc. The series are
+// matched to the entries by step, so a duplicate gets the annotations of the
+// entry it repeats and the placeholder of a dropped entry gets none.
+func (a annotations) apply(doc chaotic.RunDocument) annotatedDocument {
+	position := make(map[int]int, len(a.steps))
+	for k, step := range a.steps {
+		if _, seen := position[step]; !seen {
+			position[step] = k
+		}
+	}
+	outside := make(map[int]*bool, len(a.bands))
+	for i := range a.bands {
+		outside[a.bands[i].Step] = &a.bands[i].OutsideBand
+	}
+	marked := make(map[int]bool, len(a.changepoints))
+	for _, step := range a.changepoints {
+		marked[step] = true
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(data); err != nil {
-		return fmt.Errorf("failed to encode JSON: %w", err)
+	entries := make([]annotatedEntry, len(doc.Sequence))
+	for i, entry := range doc.Sequence {
+		entries[i].LogEntry = entry
+		entries[i].Changepoint = marked[entry.Step]
+		k, ok := position[entry.Step]
+		if !ok || entry.Missing {
+			continue
+		}
+		if a.ema != nil {
+			entries[i].EMA = &a.ema[k]
+		}
+		if a.rsi != nil && !math.IsNaN(a.rsi[k]) {
+			entries[i].RSI = &a.rsi[k]
+		}
+		entries[i].OutsideBand = outside[k]
 	}
-	return nil
+	return annotatedDocument{Metadata: doc.Metadata, Statistics: doc.Statistics, Sequence: entries,
+		Ensemble: doc.Ensemble, Correlated: doc.Correlated}
 }
 
-func main() {
-	config := DefaultConfig()
-	config.Volatility = 0.8 // More chaotic
-	config.MaxValue = 500   // Smaller range for better visualization
-
-	log, err := ChaoticTransactionSequenceExtended(50, config) // Smaller sample for demo
-	if err != nil {
-		fmt.Printf("Error generating sequence: %v\n", err)
-		return
+// saveOutput writes the output produced by write to filename, or to stdout
+// when filename is "-". A filename ending in .gz (e.g. out.json.gz) is gzipped.
+func saveOutput(filename string, write func(io.Writer) error) error {
+	if filename == "-" {
+		return write(os.Stdout)
 	}
 
-	stats, err := ComputeStatistics(log)
+	file, err := os.Create(filename)
 	if err != nil {
-		fmt.Printf("Error computing statistics: %v\n", err)
-		return
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if !strings.HasSuffix(filename, ".gz") {
+		if err := write(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to close file: %w", err)
+		}
+		return nil
 	}
 
-	// Print summary
-	fmt.Printf("Chaotic Sequence Analysis\n")
-	fmt.Printf("========================\n")
-	fmt.Printf("Generated %d transactions\n", len(log))
-	fmt.Printf("Value Range: %d - %d\n", stats["min"].(int), stats["max"].(int))
-	fmt.Printf("Mean: %.2f, Median: %d\n", stats["mean"].(float64), stats["median"].(int))
-	fmt.Printf("Std Dev: %.2f, Volatility: %.2f\n", stats["stdev"].(float64), stats["volatility"].(float64))
-	fmt.Printf("Trend Strength: %.2f\n", stats["trend_strength"].(float64))
-	fmt.Printf("IQR: %d (Q1: %d, Q3: %d)\n", stats["iqr"].(int), stats["q1"].(int), stats["q3"].(int))
-
-	// Save detailed data
-	output := map[string]interface{}{
-		"metadata": map[string]interface{}{
-			"generated_at": time.Now().Format(time.RFC3339),
-			"config":       config,
-			"sequence_length": len(log),
-		},
-		"statistics": stats,
-		"sequence":   log,
+	// The gzip footer is written on Close, so close it before the file
+	compressed := gzip.NewWriter(file)
+	writeErr := write(compressed)
+	gzipErr := compressed.Close()
+	fileErr := file.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	if gzipErr != nil {
+		gzipErr = fmt.Errorf("failed to compress output: %w", gzipErr)
 	}
+	if fileErr != nil {
+		fileErr = fmt.Errorf("failed to close file: %w", fileErr)
+	}
+	return errors.Join(gzipErr, fileErr)
+}
 
-	if err := SaveToJson(output, "chaotic_transaction_analysis.json"); err != nil {
-		fmt.Printf("Error saving JSON: %v\n", err)
-		return
+// writeOutput encodes the run in the requested format; CSV and Parquet hold only
+// the sequence, and only JSON carries annotations
+func writeOutput(doc chaotic.RunDocument, notes annotations, format string, w io.Writer) error {
+	switch format {
+	case "csv":
+		return chaotic.SaveToCSV(doc.Sequence, w)
+	case "ndjson":
+		return chaotic.SaveRunToNDJSON(doc, w)
+	case "parquet":
+		return chaotic.SaveToParquet(doc.Sequence, w)
+	case "cbor":
+		return chaotic.SaveToCBOR(doc, w)
+	default:
+		if !notes.empty() {
+			return chaotic.WriteJSON(notes.apply(doc), w)
+		}
+		return chaotic.WriteJSON(doc, w)
 	}
-	fmt.Println("\nDetailed analysis saved to chaotic_transaction_analysis.json")
+}
 
-	// Print first 10 entries as sample
-	fmt.Println("\nFirst 10 transactions:")
-	sample, _ := json.MarshalIndent(log[:10], "", "  ")
-	fmt.Println(string(sample))
+// generateCorrelated generates m series of n steps with config, every pair
+// correlated by rho, and names them series_1 to series_m
+func generateCorrelated(m, n int, config chaotic.ChaoticConfig, rho float64) (chaotic.CorrelatedRun, error) {
+	configs := make([]chaotic.ChaoticConfig, m)
+	names := make([]string, m)
+	corr := make([][]float64, m)
+	for i := range corr {
+		configs[i] = config
+		names[i] = fmt.Sprintf("series_%d", i+1)
+		corr[i] = make([]float64, m)
+		for j := range corr[i] {
+			corr[i][j] = rho
+		}
+		corr[i][i] = 1
+	}
+	series, err := chaotic.GenerateCorrelated(n, configs, corr)
+	if err != nil {
+		return chaotic.CorrelatedRun{}, err
+	}
+	return chaotic.NewCorrelatedRun(names, series, corr)
 }